@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -18,12 +20,16 @@ import (
 	"github.com/beckn-one/beckn-onix/core/module/handler"
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/response"
 	"github.com/beckn-one/beckn-onix/pkg/telemetry"
 )
 
 // ApplicationPlugins holds application-level plugin configurations.
 type ApplicationPlugins struct {
 	OtelSetup *plugin.Config `yaml:"otelsetup,omitempty"`
+	// Steps are step plugins created once and shared by every module that lists their
+	// ID in its steps.
+	Steps []plugin.Config `yaml:"steps,omitempty"`
 }
 
 // Config struct holds all configurations.
@@ -34,11 +40,15 @@ type Config struct {
 	PluginManager *plugin.ManagerConfig `yaml:"pluginManager"`
 	Modules       []module.Config       `yaml:"modules"`
 	HTTP          httpConfig            `yaml:"http"`
+	// AckStatus sets the ack.status values of ACK and NACK responses for networks that
+	// do not use the Beckn defaults.
+	AckStatus response.StatusTokens `yaml:"ackStatus"`
 }
 
 type httpConfig struct {
-	Port     string        `yaml:"port"`
-	Timeouts timeoutConfig `yaml:"timeout"`
+	Port      string                   `yaml:"port"`
+	Timeouts  timeoutConfig            `yaml:"timeout"`
+	AdminAuth *handler.AdminAuthConfig `yaml:"adminAuth"`
 }
 
 type timeoutConfig struct {
@@ -50,11 +60,32 @@ type timeoutConfig struct {
 var configPath string
 var runFunc = run
 
+// Flags of the validate-only mode.
+var (
+	validatePath   string
+	validateModule string
+	validateURL    string
+)
+
 func main() {
 	// Define and parse command-line flags.
 	flag.StringVar(&configPath, "config", "../../config/onix/adapter.yaml", "Path to the configuration file")
+	flag.StringVar(&validatePath, "validate", "", "Validate the payload in this file with the configured schema and ONDC steps, print the results and exit")
+	flag.StringVar(&validateModule, "module", "", "Module whose steps -validate runs (default: the first module)")
+	flag.StringVar(&validateURL, "path", "", "Request path -validate validates the payload for (default: /<context.action>)")
 	flag.Parse()
 
+	if validatePath != "" {
+		valid, err := validate(context.Background(), configPath, validateModule, validatePath, validateURL, os.Stdout)
+		if err != nil {
+			log.Fatalf(context.Background(), err, "Validation failed: %v", err)
+		}
+		if !valid {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Use custom log for initial setup messages.
 	log.Infof(context.Background(), "Starting application with config: %s", configPath)
 
@@ -127,15 +158,75 @@ func initAppPlugins(ctx context.Context, mgr *plugin.Manager, cfg ApplicationPlu
 	return nil
 }
 
+// server serves the registered modules and drains them on shutdown.
+type server struct {
+	http.Handler
+	handler.Drainer
+}
+
+// withSharedSteps registers the shared step plugins in cfgs once and returns mgr extended
+// with them, or mgr itself when none are configured.
+func withSharedSteps(ctx context.Context, mgr handler.PluginManager, cfgs []plugin.Config) (handler.PluginManager, error) {
+	if len(cfgs) == 0 {
+		return mgr, nil
+	}
+	steps := handler.NewStepRegistry()
+	if err := steps.Load(ctx, mgr, cfgs); err != nil {
+		return nil, err
+	}
+	return handler.WithSharedSteps(mgr, steps), nil
+}
+
 // newServer creates and initializes the HTTP server.
 func newServer(ctx context.Context, mgr handler.PluginManager, cfg *Config) (http.Handler, error) {
+	auth, err := handler.AdminAuthMiddleware(cfg.HTTP.AdminAuth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http config: %w", err)
+	}
 	mux := http.NewServeMux()
 
-	if err := module.Register(ctx, cfg.Modules, mux, mgr); err != nil {
+	drainer, err := module.Register(ctx, cfg.Modules, mux, mgr)
+	if err != nil {
 		return nil, fmt.Errorf("failed to register modules: %w", err)
 	}
 	registerProfilingEndpoints(ctx, mux)
-	return mux, nil
+	return &server{Handler: protectInternalEndpoints(mux, auth, cfg.HTTP.AdminAuth != nil), Drainer: drainer}, nil
+}
+
+// internalPrefix is the path prefix of the internal endpoints guarded by adminAuth.
+const internalPrefix = "/debug/"
+
+// adminOnlyEndpoints are the internal endpoints that change how the adapter handles
+// traffic or expose its configuration, which are only usable with adminAuth configured,
+// mapped to whether their GET requests are served without it.
+var adminOnlyEndpoints = map[string]bool{
+	"/debug/maintenance":       true,
+	"/debug/subscriber-access": true,
+	"/debug/config":            false,
+}
+
+// protectInternalEndpoints applies auth to requests that mux routes to an internal endpoint,
+// leaving module and probe endpoints untouched. Unless authenticated, requests to
+// adminOnlyEndpoints other than the reads they allow are refused with 403 Forbidden.
+func protectInternalEndpoints(mux *http.ServeMux, auth func(http.Handler) http.Handler, authenticated bool) http.Handler {
+	protected := auth(mux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if !strings.HasPrefix(pattern, internalPrefix) {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		if reads, ok := adminOnlyEndpoints[pattern]; ok && !authenticated && !(reads && isRead(r)) {
+			http.Error(w, "Forbidden: configure http.adminAuth to use this endpoint", http.StatusForbidden)
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
+}
+
+// isRead reports whether r only reads, with a GET or HEAD request.
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
 }
 
 func registerProfilingEndpoints(ctx context.Context, mux *http.ServeMux) {
@@ -156,6 +247,48 @@ func registerProfilingEndpoints(ctx context.Context, mux *http.ServeMux) {
 
 var newManagerFunc = plugin.NewManager
 var newServerFunc = newServer
+var validateFileFunc = handler.ValidateFile
+
+// validate validates the payload in file with the validation steps of the named module,
+// or of the first module when moduleName is empty, and writes the results to w as JSON.
+// It reports whether the payload is valid.
+func validate(ctx context.Context, configPath, moduleName, file, path string, w io.Writer) (bool, error) {
+	cfg, err := initConfig(ctx, configPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to initialize config: %w", err)
+	}
+	if len(cfg.Modules) == 0 {
+		return false, fmt.Errorf("no modules configured")
+	}
+	mCfg := &cfg.Modules[0]
+	if moduleName != "" {
+		mCfg = nil
+		for i := range cfg.Modules {
+			if cfg.Modules[i].Name == moduleName {
+				mCfg = &cfg.Modules[i]
+				break
+			}
+		}
+		if mCfg == nil {
+			return false, fmt.Errorf("module %s not found", moduleName)
+		}
+	}
+	mgr, closer, err := newManagerFunc(ctx, cfg.PluginManager)
+	if err != nil {
+		return false, fmt.Errorf("failed to create plugin manager: %w", err)
+	}
+	defer closer()
+	report, err := validateFileFunc(ctx, mgr, &mCfg.Handler, file, path)
+	if err != nil {
+		return false, fmt.Errorf("module %s: %w", mCfg.Name, err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return false, fmt.Errorf("failed to write results: %w", err)
+	}
+	return report.Valid, nil
+}
 
 // run encapsulates the application logic.
 func run(ctx context.Context, configPath string) error {
@@ -169,6 +302,9 @@ func run(ctx context.Context, configPath string) error {
 	if err := log.InitLogger(cfg.Log); err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
+	if err := response.SetStatusTokens(cfg.AckStatus); err != nil {
+		return fmt.Errorf("failed to initialize config: %w", err)
+	}
 
 	// Initialize plugin manager.
 	log.Infof(ctx, "Initializing plugin manager")
@@ -184,9 +320,14 @@ func run(ctx context.Context, configPath string) error {
 		return fmt.Errorf("failed to initialize plugins: %w", err)
 	}
 
+	hmgr, err := withSharedSteps(ctx, mgr, cfg.Plugins.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to initialize plugins: %w", err)
+	}
+
 	// Initialize HTTP server.
 	log.Infof(ctx, "Initializing HTTP server")
-	srv, err := newServerFunc(ctx, mgr, cfg)
+	srv, err := newServerFunc(ctx, hmgr, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to initialize server: %w", err)
 	}
@@ -212,14 +353,15 @@ func run(ctx context.Context, configPath string) error {
 	}()
 
 	// Handle shutdown.
-	shutdown(ctx, httpServer, &wg, closers)
+	shutdown(ctx, httpServer, srv, &wg, closers)
 	wg.Wait()
 	log.Infof(ctx, "Server shutdown complete")
 	return nil
 }
 
-// shutdown handles server shutdown.
-func shutdown(ctx context.Context, httpServer *http.Server, wg *sync.WaitGroup, closers []func()) {
+// shutdown handles server shutdown. Once the HTTP server stops, handlers implementing
+// handler.Drainer finish their pending async work before the closers run.
+func shutdown(ctx context.Context, httpServer *http.Server, srv http.Handler, wg *sync.WaitGroup, closers []func()) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -230,6 +372,11 @@ func shutdown(ctx context.Context, httpServer *http.Server, wg *sync.WaitGroup,
 		if err := httpServer.Shutdown(shutdownCtx); err != nil {
 			log.Errorf(ctx, fmt.Errorf("http server Shutdown: %w", err), "error shutting down http server")
 		}
+		if d, ok := srv.(handler.Drainer); ok {
+			if err := d.Shutdown(shutdownCtx); err != nil {
+				log.Errorf(ctx, err, "error draining modules")
+			}
+		}
 
 		// Call all closer functions.
 		for _, closer := range closers {
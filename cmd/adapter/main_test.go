@@ -94,6 +94,11 @@ func (m *MockPluginManager) OndcWorkbench(ctx context.Context, cache definition.
 	return nil, nil
 }
 
+// Auditor returns a mock implementation of the Auditor interface.
+func (m *MockPluginManager) Auditor(ctx context.Context, cfg *plugin.Config) (definition.Auditor, error) {
+	return nil, nil
+}
+
 // mockRun is a mock implementation of the `run` function, simulating a successful run.
 func mockRun(ctx context.Context, configPath string) error {
 	return nil // Simulate a successful run
@@ -393,11 +398,179 @@ func TestNewServerProfilingEndpoints(t *testing.T) {
 	}
 }
 
+func TestNewServerAdminAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		requestPath  string
+		token        string
+		expectedCode int
+	}{
+		{
+			name:         "Profiling endpoint without token",
+			requestPath:  "/debug/pprof/",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Profiling endpoint with token",
+			requestPath:  "/debug/pprof/",
+			token:        "secret",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Plugins endpoint without token",
+			requestPath:  "/debug/plugins",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Plugins endpoint with wrong token",
+			requestPath:  "/debug/plugins",
+			token:        "guess",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Health endpoint stays open",
+			requestPath:  "/health",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Readiness endpoint stays open",
+			requestPath:  "/readyz",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	mockMgr := new(MockPluginManager)
+	cfg := &Config{
+		Modules: []module.Config{},
+		HTTP: httpConfig{
+			Port:      "8080",
+			AdminAuth: &handler.AdminAuthConfig{BearerToken: "secret"},
+		},
+	}
+	srv, err := newServer(context.Background(), mockMgr, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.requestPath, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestNewServerAdminOnlyEndpoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		adminAuth    *handler.AdminAuthConfig
+		method       string
+		requestPath  string
+		token        string
+		expectedCode int
+	}{
+		{
+			name:         "Maintenance status without adminAuth",
+			method:       http.MethodGet,
+			requestPath:  "/debug/maintenance",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Maintenance toggle without adminAuth",
+			method:       http.MethodPost,
+			requestPath:  "/debug/maintenance",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Subscriber access lists without adminAuth",
+			method:       http.MethodGet,
+			requestPath:  "/debug/subscriber-access",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Subscriber access update without adminAuth",
+			method:       http.MethodPost,
+			requestPath:  "/debug/subscriber-access",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Subscriber access update with token",
+			adminAuth:    &handler.AdminAuthConfig{BearerToken: "secret"},
+			method:       http.MethodPost,
+			requestPath:  "/debug/subscriber-access",
+			token:        "secret",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Maintenance toggle without token",
+			adminAuth:    &handler.AdminAuthConfig{BearerToken: "secret"},
+			method:       http.MethodPost,
+			requestPath:  "/debug/maintenance",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "Maintenance toggle with token",
+			adminAuth:    &handler.AdminAuthConfig{BearerToken: "secret"},
+			method:       http.MethodPost,
+			requestPath:  "/debug/maintenance",
+			token:        "secret",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Effective config without adminAuth",
+			method:       http.MethodGet,
+			requestPath:  "/debug/config",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "Effective config with token",
+			adminAuth:    &handler.AdminAuthConfig{BearerToken: "secret"},
+			method:       http.MethodGet,
+			requestPath:  "/debug/config",
+			token:        "secret",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	mockMgr := new(MockPluginManager)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Modules: []module.Config{},
+				HTTP:    httpConfig{Port: "8080", AdminAuth: tt.adminAuth},
+			}
+			srv, err := newServer(context.Background(), mockMgr, cfg)
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			req := httptest.NewRequest(tt.method, tt.requestPath, strings.NewReader(`{"enabled":true}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
 // TestNewServerFailure tests failure scenarios when creating a server.
 func TestNewServerFailure(t *testing.T) {
 	tests := []struct {
-		name    string
-		modules []module.Config
+		name      string
+		modules   []module.Config
+		adminAuth *handler.AdminAuthConfig
 	}{
 		{
 			name: "Module registration failure",
@@ -408,6 +581,11 @@ func TestNewServerFailure(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "Admin auth without credentials",
+			modules:   []module.Config{},
+			adminAuth: &handler.AdminAuthConfig{},
+		},
 	}
 
 	mockMgr := new(MockPluginManager) // Mocking PluginManager
@@ -423,6 +601,7 @@ func TestNewServerFailure(t *testing.T) {
 						Write: 5,
 						Idle:  10,
 					},
+					AdminAuth: tt.adminAuth,
 				},
 			}
 
@@ -505,3 +684,71 @@ func TestValidateConfigFailure(t *testing.T) {
 		})
 	}
 }
+
+// TestValidate tests the validate-only mode against a stubbed payload validation.
+func TestValidate(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "adapter.yaml")
+	config := `appName: testAdapter
+http:
+  port: 8080
+modules:
+  - name: bapTxnReceiver
+    handler:
+      steps: [validateSign, validateSchema]
+  - name: bppTxnReceiver
+    handler:
+      steps: [validateOndcPayload]
+`
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	originalNewManager := newManagerFunc
+	newManagerFunc = func(ctx context.Context, cfg *plugin.ManagerConfig) (*plugin.Manager, func(), error) {
+		return &plugin.Manager{}, func() {}, nil
+	}
+	defer func() { newManagerFunc = originalNewManager }()
+	originalValidateFile := validateFileFunc
+	defer func() { validateFileFunc = originalValidateFile }()
+
+	tests := []struct {
+		name      string
+		module    string
+		valid     bool
+		wantSteps string
+		wantErr   string
+	}{
+		{name: "first module by default", valid: true, wantSteps: "validateSign,validateSchema"},
+		{name: "named module", module: "bppTxnReceiver", wantSteps: "validateOndcPayload"},
+		{name: "unknown module", module: "gateway", wantErr: "module gateway not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSteps string
+			validateFileFunc = func(_ context.Context, _ handler.PluginManager, cfg *handler.Config, file, path string) (*handler.ValidationReport, error) {
+				gotSteps = strings.Join(cfg.Steps, ",")
+				return &handler.ValidationReport{File: file, Path: path, Valid: tt.valid}, nil
+			}
+			var out strings.Builder
+			valid, err := validate(context.Background(), configPath, tt.module, "search.json", "/search", &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if valid != tt.valid {
+				t.Errorf("Expected valid %v, got %v", tt.valid, valid)
+			}
+			if gotSteps != tt.wantSteps {
+				t.Errorf("Expected steps %q, got %q", tt.wantSteps, gotSteps)
+			}
+			if !strings.Contains(out.String(), `"file": "search.json"`) {
+				t.Errorf("Expected JSON results, got %s", out.String())
+			}
+		})
+	}
+}
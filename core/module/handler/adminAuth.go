@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// AdminAuthConfig configures authentication of the adapter's internal endpoints,
+// independently of the Beckn signature checks applied to network traffic.
+// A request is authorized when it satisfies any of the configured methods.
+type AdminAuthConfig struct {
+	// BearerToken is the token expected in an "Authorization: Bearer" header.
+	BearerToken string `yaml:"bearerToken"`
+	// ClientCertSubjects lists the common names of verified TLS client
+	// certificates that are allowed.
+	ClientCertSubjects []string `yaml:"clientCertSubjects"`
+}
+
+var errAdminAuthNotConfigured = errors.New("adminAuth requires a bearerToken or clientCertSubjects")
+
+// AdminAuthMiddleware returns a middleware that rejects requests not authorized by cfg
+// with 401 Unauthorized. A nil cfg disables authentication.
+func AdminAuthMiddleware(cfg *AdminAuthConfig) (func(http.Handler) http.Handler, error) {
+	if cfg == nil {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+	token := strings.TrimSpace(cfg.BearerToken)
+	if token == "" && len(cfg.ClientCertSubjects) == 0 {
+		return nil, errAdminAuthNotConfigured
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (token != "" && hasBearerToken(r, token)) || hasClientCert(r, cfg.ClientCertSubjects) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if token != "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		})
+	}, nil
+}
+
+// hasBearerToken reports whether r carries token as its bearer token.
+func hasBearerToken(r *http.Request, token string) bool {
+	scheme, got, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) == 1
+}
+
+// hasClientCert reports whether r was made over TLS with a verified client
+// certificate whose common name is one of subjects.
+func hasClientCert(r *http.Request, subjects []string) bool {
+	if len(subjects) == 0 || r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return false
+	}
+	return slices.Contains(subjects, r.TLS.VerifiedChains[0][0].Subject.CommonName)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func verifiedTLS(cn string) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: cn}}}},
+	}
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *AdminAuthConfig
+		header   string
+		tls      *tls.ConnectionState
+		wantCode int
+	}{
+		{
+			name:     "auth disabled",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "valid bearer token",
+			cfg:      &AdminAuthConfig{BearerToken: "secret"},
+			header:   "Bearer secret",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "bearer scheme is case-insensitive",
+			cfg:      &AdminAuthConfig{BearerToken: "secret"},
+			header:   "bearer secret",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "wrong bearer token",
+			cfg:      &AdminAuthConfig{BearerToken: "secret"},
+			header:   "Bearer guess",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "missing bearer token",
+			cfg:      &AdminAuthConfig{BearerToken: "secret"},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "basic credentials",
+			cfg:      &AdminAuthConfig{BearerToken: "secret"},
+			header:   "Basic secret",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "allowed client certificate",
+			cfg:      &AdminAuthConfig{ClientCertSubjects: []string{"ops"}},
+			tls:      verifiedTLS("ops"),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "unknown client certificate",
+			cfg:      &AdminAuthConfig{ClientCertSubjects: []string{"ops"}},
+			tls:      verifiedTLS("someone"),
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unverified client certificate",
+			cfg:      &AdminAuthConfig{ClientCertSubjects: []string{"ops"}},
+			tls:      &tls.ConnectionState{},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "plain HTTP with client certificate auth",
+			cfg:      &AdminAuthConfig{ClientCertSubjects: []string{"ops"}},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "client certificate when bearer token also configured",
+			cfg:      &AdminAuthConfig{BearerToken: "secret", ClientCertSubjects: []string{"ops"}},
+			tls:      verifiedTLS("ops"),
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw, err := AdminAuthMiddleware(tt.cfg)
+			require.NoError(t, err)
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/plugins", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			req.TLS = tt.tls
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestAdminAuthMiddlewareMisconfigured(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *AdminAuthConfig
+	}{
+		{name: "empty config", cfg: &AdminAuthConfig{}},
+		{name: "blank bearer token", cfg: &AdminAuthConfig{BearerToken: "  "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw, err := AdminAuthMiddleware(tt.cfg)
+			assert.ErrorIs(t, err, errAdminAuthNotConfigured)
+			assert.Nil(t, mw)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// auditor passes an audit record of every request to the Auditor plugin once the
+// response has been sent.
+type auditor struct {
+	sink   definition.Auditor
+	module string
+}
+
+// newAuditor creates an auditor recording to sink. It returns nil when sink is nil.
+func newAuditor(sink definition.Auditor, module string) *auditor {
+	if sink == nil {
+		return nil
+	}
+	return &auditor{sink: sink, module: module}
+}
+
+// auditState collects what serve learns about a request for its audit record.
+type auditState struct {
+	stepCtx *model.StepContext
+	err     error
+}
+
+// auditStateKey is the context key of the auditState of a request.
+type auditStateKey struct{}
+
+// begin returns r carrying a new auditState for serve to fill, or r unchanged when
+// auditing is off.
+func (a *auditor) begin(r *http.Request) (*http.Request, *auditState) {
+	if a == nil {
+		return r, nil
+	}
+	state := &auditState{}
+	return r.WithContext(context.WithValue(r.Context(), auditStateKey{}, state)), state
+}
+
+// noteAudit records the step context of a request and the error a step failed it with,
+// if any, in the request's auditState.
+func noteAudit(ctx *model.StepContext, err error) {
+	state, ok := ctx.Value(auditStateKey{}).(*auditState)
+	if !ok {
+		return
+	}
+	state.stepCtx = ctx
+	if err != nil {
+		state.err = err
+	}
+}
+
+// record builds the audit record of a served request and passes it to the Auditor plugin
+// from a post-response hook, or at once if r has no hook list.
+func (a *auditor) record(r *http.Request, state *auditState, start time.Time, entry log.AccessEntry) {
+	if a == nil {
+		return
+	}
+	rec := model.AuditRecord{
+		Time:         start,
+		Module:       a.module,
+		Method:       entry.Method,
+		Path:         entry.Path,
+		SubscriberID: entry.SubscriberID,
+		Status:       entry.Status,
+		Outcome:      entry.Outcome,
+		Signature:    model.SignatureUnchecked,
+		Duration:     entry.Duration,
+	}
+	if ctx := state.stepCtx; ctx != nil {
+		if bCtx := ctx.BecknContext; bCtx != nil {
+			rec.Action = bCtx.Action
+			rec.TransactionID = bCtx.TransactionID
+			rec.MessageID = bCtx.MessageID
+		}
+		if ctx.Signer != "" {
+			rec.Signature = model.SignatureValid
+			rec.Signer = ctx.Signer
+		}
+	}
+	if state.err != nil {
+		rec.Error = state.err.Error()
+		var signErr *model.SignValidationErr
+		if errors.As(state.err, &signErr) {
+			rec.Signature = model.SignatureInvalid
+		}
+	}
+
+	ctx := r.Context()
+	audit := func() {
+		if err := a.sink.Audit(ctx, rec); err != nil {
+			log.Errorf(ctx, err, "Failed to record audit of %s %s", rec.Method, rec.Path)
+		}
+	}
+	if !RegisterPostResponseHook(r, audit) {
+		audit()
+	}
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// chanAuditor is an Auditor that sends the records it receives on a channel.
+type chanAuditor chan model.AuditRecord
+
+func (a chanAuditor) Audit(_ context.Context, rec model.AuditRecord) error {
+	a <- rec
+	return nil
+}
+
+func TestServeHTTPAudit(t *testing.T) {
+	body := `{"context":{"action":"search","transaction_id":"t1","message_id":"m1"}}`
+	signed := stepFunc(func(ctx *model.StepContext) error {
+		ctx.Signer = "bap.example.com"
+		return nil
+	})
+	tests := []struct {
+		name  string
+		steps []definition.Step
+		want  model.AuditRecord
+	}{
+		{
+			name:  "ack",
+			steps: []definition.Step{signed},
+			want: model.AuditRecord{
+				Status:    http.StatusOK,
+				Outcome:   outcomeAck,
+				Signature: model.SignatureValid,
+				Signer:    "bap.example.com",
+			},
+		},
+		{
+			name: "nack for the signature",
+			steps: []definition.Step{stepFunc(func(*model.StepContext) error {
+				return model.NewSignValidationErr(errors.New("signature mismatch"))
+			})},
+			want: model.AuditRecord{
+				Status:    http.StatusUnauthorized,
+				Outcome:   outcomeNack,
+				Error:     "signature mismatch",
+				Signature: model.SignatureInvalid,
+			},
+		},
+		{
+			name: "nack after the signature",
+			steps: []definition.Step{signed, stepFunc(func(*model.StepContext) error {
+				return model.NewBadReqErr(errors.New("missing items"))
+			})},
+			want: model.AuditRecord{
+				Status:    http.StatusBadRequest,
+				Outcome:   outcomeNack,
+				Error:     "missing items",
+				Signature: model.SignatureValid,
+				Signer:    "bap.example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := make(chanAuditor, 1)
+			h := &stdHandler{
+				SubscriberID: "bpp.example.com",
+				auditor:      newAuditor(records, "bppTxnReceiver"),
+				steps:        tt.steps,
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(body)))
+
+			require.Len(t, records, 1)
+			got := <-records
+			assert.Equal(t, rec.Code, got.Status)
+			assert.False(t, got.Time.IsZero())
+			assert.Positive(t, got.Duration)
+			tt.want.Module = "bppTxnReceiver"
+			tt.want.Method = http.MethodPost
+			tt.want.Path = "/bpp/receiver/search"
+			tt.want.SubscriberID = "bpp.example.com"
+			tt.want.Action = "search"
+			tt.want.TransactionID = "t1"
+			tt.want.MessageID = "m1"
+			tt.want.Time, tt.want.Duration = got.Time, got.Duration
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServeHTTPAuditsAfterResponse(t *testing.T) {
+	records := make(chanAuditor)
+	h := PostResponseMiddleware()(&stdHandler{auditor: newAuditor(records, "bapTxnReceiver")})
+	rec := httptest.NewRecorder()
+
+	served := make(chan struct{})
+	go func() {
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/receiver/on_search", strings.NewReader(`{}`)))
+		close(served)
+	}()
+
+	select {
+	case <-served:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the response waited for the audit record to be taken")
+	}
+	select {
+	case got := <-records:
+		assert.Equal(t, outcomeAck, got.Outcome)
+		assert.Equal(t, model.SignatureUnchecked, got.Signature)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no audit record")
+	}
+}
+
+func TestNewAuditor(t *testing.T) {
+	assert.Nil(t, newAuditor(nil, "m"))
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	var a *auditor
+	got, state := a.begin(r)
+	assert.Same(t, r, got)
+	assert.Nil(t, state)
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// errEmptyBody is returned for requests whose body is empty or only whitespace.
+var errEmptyBody = errors.New("empty request body")
+
+// requestBody is a buffered request body, held either in memory or in a temporary file.
+type requestBody struct {
+	mem   []byte
+	spill *spilledBody
+	size  int
+}
+
+// spilledBody is a request body written to a temporary file.
+type spilledBody struct {
+	*os.File
+}
+
+// Close closes and removes the temporary file.
+func (b *spilledBody) Close() error {
+	return errors.Join(b.File.Close(), os.Remove(b.Name()))
+}
+
+// bufferBody reads r into memory, or into a temporary file once it grows beyond
+// cfg.SpillThreshold. A spilled body must be closed to remove its file.
+func bufferBody(r io.Reader, cfg *BodyBufferConfig) (*requestBody, error) {
+	if cfg.SpillThreshold <= 0 {
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, r); err != nil {
+			return nil, err
+		}
+		return &requestBody{mem: buf.Bytes(), size: buf.Len()}, nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(r, cfg.SpillThreshold+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(head)) <= cfg.SpillThreshold {
+		return &requestBody{mem: head, size: len(head)}, nil
+	}
+
+	f, err := os.CreateTemp(cfg.TempDir, "onix-body-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create body file: %w", err)
+	}
+	spill := &spilledBody{File: f}
+	n, err := io.Copy(f, io.MultiReader(bytes.NewReader(head), r))
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to spill body: %w", err), spill.Close())
+	}
+	return &requestBody{spill: spill, size: int(n)}, nil
+}
+
+// empty reports whether the body is empty or only whitespace. Spilled bodies, larger
+// than the spill threshold, are not checked.
+func (b *requestBody) empty() bool {
+	return b.spill == nil && len(bytes.TrimSpace(b.mem)) == 0
+}
+
+// reader returns a seekable reader over the body.
+func (b *requestBody) reader() io.ReadSeeker {
+	if b.spill != nil {
+		return b.spill
+	}
+	return bytes.NewReader(b.mem)
+}
+
+// context parses the Beckn context of the body, streaming it from disk if it was spilled.
+func (b *requestBody) context() (*model.BecknContext, error) {
+	if b.spill == nil {
+		return model.ParseBecknContext(b.mem)
+	}
+	bCtx, err := model.DecodeBecknContext(b.spill)
+	if _, seekErr := b.spill.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, fmt.Errorf("failed to rewind body: %w", seekErr)
+	}
+	return bCtx, err
+}
+
+// forwardBody returns a reader over the body to forward: Body if it is loaded, since a
+// step may have replaced it, and otherwise BodyReader rewound to the start.
+func forwardBody(ctx *model.StepContext) (io.ReadSeeker, error) {
+	if ctx.Body != nil || ctx.BodyReader == nil {
+		return bytes.NewReader(ctx.Body), nil
+	}
+	if _, err := ctx.BodyReader.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind body: %w", err)
+	}
+	return ctx.BodyReader, nil
+}
+
+// closeAfterResponse closes c once the post-response hooks registered so far have run,
+// or immediately if r has no hook list.
+func closeAfterResponse(r *http.Request, c io.Closer) {
+	closeBody := func() {
+		if err := c.Close(); err != nil {
+			log.Warnf(r.Context(), "Failed to release request body: %v", err)
+		}
+	}
+	if !RegisterPostResponseHook(r, closeBody) {
+		closeBody()
+	}
+}
+
+// bodyLength returns the number of bytes left to read from body.
+func bodyLength(body io.Seeker) (int64, error) {
+	cur, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	end, err := body.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := body.Seek(cur, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - cur, nil
+}
+
+// setBodyFraming frames r, whose buffered body is forwarded from body, with its
+// Content-Length. A request received with Transfer-Encoding: chunked would otherwise be
+// forwarded chunked, with framing that no longer matches the body it carries.
+func setBodyFraming(r *http.Request, body io.ReadSeeker) error {
+	n, err := bodyLength(body)
+	if err != nil {
+		return fmt.Errorf("failed to measure body: %w", err)
+	}
+	r.TransferEncoding = nil
+	r.Header.Del("Transfer-Encoding")
+	r.ContentLength = n
+	r.Header.Set("Content-Length", strconv.FormatInt(n, 10))
+	return nil
+}
+
+// syncBodyHeaders drops the Content-Encoding header of r when a step replaced the body
+// it received, e.g. by decompressing it, so that it describes the body that is forwarded.
+// received is nil for a spilled body, which is then compared against its file when a
+// step loaded it.
+func (h *stdHandler) syncBodyHeaders(r *http.Request, ctx *model.StepContext, received []byte) {
+	if ctx.Body == nil || h.keepEncoding || r.Header.Get("Content-Encoding") == "" {
+		return
+	}
+	if received == nil && ctx.BodyReader != nil {
+		if same, err := readerEquals(ctx.BodyReader, ctx.Body); err != nil || same {
+			return
+		}
+	} else if bytes.Equal(ctx.Body, received) {
+		return
+	}
+	r.Header.Del("Content-Encoding")
+}
+
+// readerEquals reports whether rs holds exactly b, reading it from the start.
+func readerEquals(rs io.ReadSeeker, b []byte) (bool, error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+	buf := make([]byte, 32<<10)
+	off := 0
+	for {
+		n, err := rs.Read(buf)
+		if n > 0 {
+			if off+n > len(b) || !bytes.Equal(buf[:n], b[off:off+n]) {
+				return false, nil
+			}
+			off += n
+		}
+		if err == io.EOF {
+			return off == len(b), nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// contextSteps are the built-in steps that only read headers and the Beckn context, which
+// is decoded from a spilled body without loading it. They run without loading the body.
+var contextSteps = map[string]bool{
+	"validateContentType":           true,
+	"validateTimestamp":             true,
+	"enrichRegistry":                true,
+	"checkSubscriberAccess":         true,
+	"injectFault":                   true,
+	"validateActionForRole":         true,
+	"validateSubscriberConsistency": true,
+	"globalRateLimit":               true,
+	"dedup":                         true,
+	"validateCallbackCorrelation":   true,
+}
+
+// bodyStep loads a spilled body into memory before running its step.
+type bodyStep struct {
+	step definition.Step
+}
+
+// withBody wraps the step configured as name so that a spilled body is loaded before it
+// runs. Steps in contextSteps are returned unchanged.
+func withBody(name string, step definition.Step) definition.Step {
+	if contextSteps[name] {
+		return step
+	}
+	return &bodyStep{step: step}
+}
+
+// Run loads the body and executes the wrapped step.
+func (s *bodyStep) Run(ctx *model.StepContext) error {
+	if _, err := ctx.BodyBytes(); err != nil {
+		return err
+	}
+	return s.step.Run(ctx)
+}
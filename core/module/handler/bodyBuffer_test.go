@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const spillTestBody = `{"context":{"action":"search","domain":"ONDC:RET10"},"message":{"intent":{"item":{"descriptor":{"name":"coffee"}}}}}`
+
+func TestStepCtxBodyInMemory(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int64
+	}{
+		{name: "spilling disabled", threshold: 0},
+		{name: "below threshold", threshold: int64(len(spillTestBody))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &stdHandler{bodyBuffer: BodyBufferConfig{SpillThreshold: tt.threshold, TempDir: t.TempDir()}}
+			ctx, err := h.stepCtx(httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(spillTestBody)), http.Header{})
+			require.NoError(t, err)
+
+			assert.Equal(t, spillTestBody, string(ctx.Body))
+			_, isFile := ctx.BodyReader.(io.Closer)
+			assert.False(t, isFile, "body should be kept in memory")
+			got, err := io.ReadAll(ctx.BodyReader)
+			require.NoError(t, err)
+			assert.Equal(t, spillTestBody, string(got))
+		})
+	}
+}
+
+func TestStepCtxBodySpill(t *testing.T) {
+	dir := t.TempDir()
+	h := &stdHandler{bodyBuffer: BodyBufferConfig{SpillThreshold: 16, TempDir: dir}}
+	ctx, err := h.stepCtx(httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(spillTestBody)), http.Header{})
+	require.NoError(t, err)
+	spill, ok := ctx.BodyReader.(*spilledBody)
+	require.True(t, ok, "body should be spilled to a file")
+	t.Cleanup(func() { _ = spill.Close() })
+
+	assert.Nil(t, ctx.Body, "spilled body should not be loaded until needed")
+	require.NotNil(t, ctx.BecknContext)
+	assert.Equal(t, "search", ctx.BecknContext.Action)
+	assert.Equal(t, "ONDC:RET10", ctx.BecknContext.Domain)
+
+	fwd, err := forwardBody(ctx)
+	require.NoError(t, err)
+	got, err := io.ReadAll(fwd)
+	require.NoError(t, err)
+	assert.Equal(t, spillTestBody, string(got))
+
+	body, err := ctx.BodyBytes()
+	require.NoError(t, err)
+	assert.Equal(t, spillTestBody, string(body))
+}
+
+func TestServeHTTPRemovesSpilledBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks bool
+	}{
+		{name: "after post-response hooks", hooks: true},
+		{name: "without post-response middleware"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			var stepBody []byte
+			h := &stdHandler{
+				bodyBuffer: BodyBufferConfig{SpillThreshold: 16, TempDir: t.TempDir()},
+				steps: []definition.Step{withBody("custom", stepFunc(func(ctx *model.StepContext) error {
+					path = ctx.BodyReader.(*spilledBody).Name()
+					stepBody = ctx.Body
+					return nil
+				}))},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(spillTestBody))
+			var hooks *[]PostResponseHook
+			if tt.hooks {
+				req, hooks = withPostResponseHooks(req)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, spillTestBody, string(stepBody), "steps should see the loaded body")
+
+			if tt.hooks {
+				_, err := os.Stat(path)
+				require.NoError(t, err, "spilled body should be kept until post-response hooks run")
+				for _, hook := range *hooks {
+					hook()
+				}
+			}
+			_, err := os.Stat(path)
+			assert.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestServeHTTPSpilledBodyLoadedOnlyForBodySteps(t *testing.T) {
+	var contextStepBody, bodyStepBody []byte
+	var contextStepAction string
+	h := &stdHandler{
+		bodyBuffer: BodyBufferConfig{SpillThreshold: 16, TempDir: t.TempDir()},
+		steps: []definition.Step{
+			withBody("validateTimestamp", stepFunc(func(ctx *model.StepContext) error {
+				contextStepBody = ctx.Body
+				contextStepAction = ctx.BecknContext.Action
+				return nil
+			})),
+			withBody("validateSchema", stepFunc(func(ctx *model.StepContext) error {
+				bodyStepBody = ctx.Body
+				return nil
+			})),
+		},
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(spillTestBody)))
+
+	assert.Nil(t, contextStepBody, "steps reading only the context should not load a spilled body")
+	assert.Equal(t, "search", contextStepAction)
+	assert.Equal(t, spillTestBody, string(bodyStepBody), "steps reading the body should see it loaded")
+}
+
+func gzipBody(t *testing.T, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(body))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestServeHTTPSyncsForwardedBodyHeaders(t *testing.T) {
+	const transformed = `{"context":{"action":"search"}}`
+	replaceBody := func(ctx *model.StepContext) error {
+		ctx.Body = []byte(transformed)
+		return nil
+	}
+	decompress := func(ctx *model.StepContext) error {
+		zr, err := gzip.NewReader(bytes.NewReader(ctx.Body))
+		if err != nil {
+			return err
+		}
+		ctx.Body, err = io.ReadAll(zr)
+		return err
+	}
+
+	tests := []struct {
+		name         string
+		body         []byte
+		encoding     string
+		step         func(*model.StepContext) error
+		keepEncoding bool
+		spill        bool
+		wantBody     string
+		wantEncoding string
+	}{
+		{
+			name:     "body-modifying step",
+			body:     []byte(spillTestBody),
+			step:     replaceBody,
+			wantBody: transformed,
+		},
+		{
+			name:     "decompressing step",
+			body:     gzipBody(t, spillTestBody),
+			encoding: "gzip",
+			step:     decompress,
+			wantBody: spillTestBody,
+		},
+		{
+			name:         "decompressing step with encoding preserved",
+			body:         gzipBody(t, spillTestBody),
+			encoding:     "gzip",
+			step:         decompress,
+			keepEncoding: true,
+			wantBody:     spillTestBody,
+			wantEncoding: "gzip",
+		},
+		{
+			name:         "unmodified body",
+			body:         []byte(spillTestBody),
+			encoding:     "identity",
+			step:         func(*model.StepContext) error { return nil },
+			wantBody:     spillTestBody,
+			wantEncoding: "identity",
+		},
+		{
+			name:     "decompressing step on a spilled body",
+			body:     gzipBody(t, spillTestBody),
+			encoding: "gzip",
+			step:     decompress,
+			spill:    true,
+			wantBody: spillTestBody,
+		},
+		{
+			name:         "unmodified spilled body",
+			body:         []byte(spillTestBody),
+			encoding:     "identity",
+			step:         func(*model.StepContext) error { return nil },
+			spill:        true,
+			wantBody:     spillTestBody,
+			wantEncoding: "identity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLength int64
+			var gotEncoding, gotBody string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotLength = r.ContentLength
+				gotEncoding = r.Header.Get("Content-Encoding")
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+			}))
+			defer upstream.Close()
+			target, err := url.Parse(upstream.URL)
+			require.NoError(t, err)
+
+			h := &stdHandler{
+				httpClient:   upstream.Client(),
+				keepEncoding: tt.keepEncoding,
+				steps: []definition.Step{withBody("transform", stepFunc(func(ctx *model.StepContext) error {
+					ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+					return tt.step(ctx)
+				}))},
+			}
+			if tt.spill {
+				h.bodyBuffer = BodyBufferConfig{SpillThreshold: 16, TempDir: t.TempDir()}
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantBody, gotBody)
+			assert.Equal(t, int64(len(tt.wantBody)), gotLength)
+			assert.Equal(t, tt.wantEncoding, gotEncoding)
+		})
+	}
+}
+
+func TestServeHTTPRejectsEmptyBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantRouted bool
+	}{
+		{name: "empty", body: ""},
+		{name: "whitespace only", body: " \n\t\r\n "},
+		{name: "valid", body: spillTestBody, wantRouted: true},
+		{name: "malformed JSON is left to the steps", body: `{"context":`, wantRouted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var routed bool
+			h := &stdHandler{steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+				routed = true
+				return nil
+			})}}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantRouted, routed)
+			if tt.wantRouted {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.JSONEq(t, `{"message":{"ack":{"status":"NACK"}},"error":{"code":"Bad Request","message":"BAD Request: empty request body"}}`, rec.Body.String())
+		})
+	}
+}
+
+func TestServeHTTPForwardsChunkedBodyWithContentLength(t *testing.T) {
+	tests := []struct {
+		name       string
+		bodyBuffer BodyBufferConfig
+	}{
+		{name: "in memory"},
+		{name: "spilled", bodyBuffer: BodyBufferConfig{SpillThreshold: 16, TempDir: t.TempDir()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLength int64
+			var gotEncoding []string
+			var gotBody string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotLength = r.ContentLength
+				gotEncoding = r.TransferEncoding
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+			}))
+			defer upstream.Close()
+			target, err := url.Parse(upstream.URL)
+			require.NoError(t, err)
+			var receivedEncoding []string
+			h := &stdHandler{
+				httpClient: upstream.Client(),
+				bodyBuffer: tt.bodyBuffer,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					receivedEncoding = ctx.Request.TransferEncoding
+					ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+					return nil
+				})},
+			}
+			front := httptest.NewServer(h)
+			defer front.Close()
+
+			// A reader of unknown length makes the client send the body chunked.
+			req, err := http.NewRequest(http.MethodPost, front.URL+"/bap/caller/search", io.MultiReader(strings.NewReader(spillTestBody)))
+			require.NoError(t, err)
+			resp, err := front.Client().Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, []string{"chunked"}, receivedEncoding, "the request should reach the handler chunked")
+			assert.Equal(t, spillTestBody, gotBody)
+			assert.Equal(t, int64(len(spillTestBody)), gotLength)
+			assert.Empty(t, gotEncoding, "the buffered body should not be forwarded chunked")
+		})
+	}
+}
+
+func TestSetBodyFraming(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.TransferEncoding = []string{"chunked"}
+	r.ContentLength = -1
+	r.Header.Set("Transfer-Encoding", "chunked")
+	body := strings.NewReader(spillTestBody)
+	_, err := body.Seek(2, io.SeekStart)
+	require.NoError(t, err)
+
+	require.NoError(t, setBodyFraming(r, body))
+
+	assert.Nil(t, r.TransferEncoding)
+	assert.Empty(t, r.Header.Get("Transfer-Encoding"))
+	assert.Equal(t, int64(len(spillTestBody)-2), r.ContentLength, "only the bytes left to read are forwarded")
+	assert.Equal(t, strconv.Itoa(len(spillTestBody)-2), r.Header.Get("Content-Length"))
+	rest, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, spillTestBody[2:], string(rest), "measuring the body should not move its offset")
+}
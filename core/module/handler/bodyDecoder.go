@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// BodyDecoder turns a request body in a non-JSON format into its canonical JSON form,
+// which steps validate and route on.
+type BodyDecoder interface {
+	// Decode returns the canonical JSON form of body.
+	Decode(body []byte) ([]byte, error)
+}
+
+// bodyDecoders are the decoders of the body formats that can be enabled, by media type.
+var bodyDecoders = map[string]BodyDecoder{
+	"application/cbor": cborDecoder{},
+}
+
+// newBodyDecoders returns the decoders of the formats listed by media type. JSON bodies
+// need no decoder and are always accepted.
+func newBodyDecoders(formats []string) (map[string]BodyDecoder, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+	decoders := make(map[string]BodyDecoder, len(formats))
+	for _, f := range formats {
+		d, ok := bodyDecoders[f]
+		if !ok {
+			return nil, fmt.Errorf("invalid config: unsupported body format %q", f)
+		}
+		decoders[f] = d
+	}
+	return decoders, nil
+}
+
+// decodeBody returns the payloadBody of r holding the JSON form of body, or nil if r is
+// not in an enabled non-JSON format.
+func (h *stdHandler) decodeBody(r *http.Request, body *requestBody) (*payloadBody, error) {
+	if len(h.decoders) == 0 {
+		return nil, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil
+	}
+	d, ok := h.decoders[mediaType]
+	if !ok {
+		return nil, nil
+	}
+	raw := body.mem
+	if body.spill != nil {
+		src := body.reader()
+		raw, err = io.ReadAll(src)
+		if _, seekErr := src.Seek(0, io.SeekStart); seekErr != nil {
+			return nil, fmt.Errorf("failed to rewind body: %w", seekErr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %w", err)
+		}
+	}
+	payload, err := d.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s body: %w", mediaType, err)
+	}
+	return &payloadBody{payload: payload}, nil
+}
+
+// maxCBORDepth bounds the nesting of arrays and maps in a CBOR body.
+const maxCBORDepth = 100
+
+// cborBreak ends an indefinite-length CBOR item.
+const cborBreak = 0xff
+
+// errCBORTruncated is returned for CBOR bodies that end within an item.
+var errCBORTruncated = errors.New("unexpected end of CBOR data")
+
+// cborDecoder decodes CBOR (RFC 8949) bodies. Maps must have text keys, byte strings
+// become base64 strings and tags are dropped, keeping their content.
+type cborDecoder struct{}
+
+// Decode returns the JSON form of the single CBOR item in body.
+func (cborDecoder) Decode(body []byte) ([]byte, error) {
+	d := &cborReader{data: body}
+	v, err := d.item(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("%d trailing bytes after CBOR item", len(d.data)-d.pos)
+	}
+	return marshalJSON(v)
+}
+
+// cborReader reads CBOR items from data.
+type cborReader struct {
+	data []byte
+	pos  int
+}
+
+// next returns the next n bytes.
+func (d *cborReader) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errCBORTruncated
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// head reads the initial byte and argument of an item. indefinite is set for additional
+// information 31, which has no argument.
+func (d *cborReader) head() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info <= 27:
+		n := uint64(1) << (info - 24)
+		b, err := d.next(n)
+		if err != nil {
+			return 0, 0, 0, false, err
+		}
+		for _, c := range b {
+			arg = arg<<8 | uint64(c)
+		}
+		return major, info, arg, false, nil
+	case info == 31:
+		return major, info, 0, true, nil
+	default:
+		return 0, 0, 0, false, fmt.Errorf("reserved CBOR additional information %d", info)
+	}
+}
+
+// item decodes the next item into a value json.Marshal accepts.
+func (d *cborReader) item(depth int) (any, error) {
+	if depth > maxCBORDepth {
+		return nil, fmt.Errorf("CBOR nesting exceeds %d levels", maxCBORDepth)
+	}
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == 0 || major == 1 || major == 6) {
+		return nil, fmt.Errorf("indefinite length not allowed for CBOR major type %d", major)
+	}
+	switch major {
+	case 0:
+		return json.Number(strconv.FormatUint(arg, 10)), nil
+	case 1:
+		if arg == math.MaxUint64 {
+			return json.Number("-18446744073709551616"), nil
+		}
+		return json.Number("-" + strconv.FormatUint(arg+1, 10)), nil
+	case 2:
+		b, err := d.bytes(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case 3:
+		b, err := d.bytes(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case 4:
+		arr := []any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			v, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case 5:
+		obj := map[string]any{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			if d.pos < len(d.data) && d.data[d.pos]>>5 != 3 {
+				return nil, fmt.Errorf("CBOR map key of major type %d is not a text string", d.data[d.pos]>>5)
+			}
+			k, err := d.item(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			key := k.(string)
+			if obj[key], err = d.item(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	case 6:
+		return d.item(depth + 1)
+	default:
+		return d.simple(info, arg, indefinite)
+	}
+}
+
+// atBreak consumes the break byte ending an indefinite-length item, reporting whether
+// it was there.
+func (d *cborReader) atBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == cborBreak {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// bytes reads the content of a byte or text string, joining the chunks of an
+// indefinite-length string.
+func (d *cborReader) bytes(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.next(arg)
+	}
+	var out []byte
+	for !d.atBreak() {
+		m, _, n, chunkIndefinite, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if m != major || chunkIndefinite {
+			return nil, errors.New("invalid chunk in indefinite-length CBOR string")
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// simple decodes the simple values and floats of major type 7.
+func (d *cborReader) simple(info byte, arg uint64, indefinite bool) (any, error) {
+	if indefinite {
+		return nil, errors.New("unexpected CBOR break")
+	}
+	var f float64
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		f = halfToFloat(uint16(arg))
+	case 26:
+		f = float64(math.Float32frombits(uint32(arg)))
+	case 27:
+		f = math.Float64frombits(arg)
+	default:
+		return nil, fmt.Errorf("unsupported CBOR simple value %d", arg)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("CBOR float %v has no JSON form", f)
+	}
+	return f, nil
+}
+
+// halfToFloat converts an IEEE 754 half-precision float.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if mant != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
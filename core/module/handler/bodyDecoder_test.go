@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// cborHead appends the head of a CBOR item with the given major type and argument.
+func cborHead(b []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(b, major<<5|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, major<<5|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major<<5|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major<<5|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major<<5|27), arg)
+	}
+}
+
+// encodeCBOR encodes maps, slices, strings, ints and bools as CBOR.
+func encodeCBOR(t *testing.T, v any) []byte {
+	t.Helper()
+	var b []byte
+	switch v := v.(type) {
+	case map[string]any:
+		b = cborHead(b, 5, uint64(len(v)))
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b = append(b, encodeCBOR(t, k)...)
+			b = append(b, encodeCBOR(t, v[k])...)
+		}
+	case []any:
+		b = cborHead(b, 4, uint64(len(v)))
+		for _, e := range v {
+			b = append(b, encodeCBOR(t, e)...)
+		}
+	case string:
+		b = append(cborHead(b, 3, uint64(len(v))), v...)
+	case int:
+		if v < 0 {
+			return cborHead(b, 1, uint64(-1-v))
+		}
+		b = cborHead(b, 0, uint64(v))
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	default:
+		t.Fatalf("cannot encode %T", v)
+	}
+	return b
+}
+
+func TestCBORDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		want    string
+		wantErr string
+	}{
+		{name: "unsigned", hex: "1903e8", want: `1000`},
+		{name: "negative", hex: "3903e7", want: `-1000`},
+		{name: "largest negative", hex: "3bffffffffffffffff", want: `-18446744073709551616`},
+		{name: "half float", hex: "f93e00", want: `1.5`},
+		{name: "single float", hex: "fa47c35000", want: `100000`},
+		{name: "double float", hex: "fb3ff199999999999a", want: `1.1`},
+		{name: "simple values", hex: "84f4f5f6f7", want: `[false,true,null,null]`},
+		{name: "byte string", hex: "4401020304", want: `"AQIDBA=="`},
+		{name: "tagged text", hex: "c074323031332d30332d32315432303a30343a30305a", want: `"2013-03-21T20:04:00Z"`},
+		{name: "indefinite text", hex: "7f657374726561646d696e67ff", want: `"streaming"`},
+		{name: "indefinite array and map", hex: "bf61610161629f0203ffff", want: `{"a":1,"b":[2,3]}`},
+		{name: "html is not escaped", hex: "633c263e", want: `"<&>"`},
+		{name: "truncated argument", hex: "19", wantErr: "unexpected end of CBOR data"},
+		{name: "truncated string", hex: "6461", wantErr: "unexpected end of CBOR data"},
+		{name: "trailing bytes", hex: "0101", wantErr: "1 trailing bytes"},
+		{name: "non-text key", hex: "a10102", wantErr: "not a text string"},
+		{name: "nan", hex: "f97e00", wantErr: "has no JSON form"},
+		{name: "stray break", hex: "ff", wantErr: "unexpected CBOR break"},
+		{name: "reserved info", hex: "1c", wantErr: "reserved CBOR additional information"},
+		{name: "indefinite integer", hex: "1f", wantErr: "indefinite length not allowed"},
+		{name: "bad chunk", hex: "7f4161ff", wantErr: "invalid chunk"},
+		{name: "too deep", hex: string(bytes.Repeat([]byte("81"), maxCBORDepth+1)) + "01", wantErr: "nesting exceeds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.hex)
+			require.NoError(t, err)
+			got, err := cborDecoder{}.Decode(data)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewBodyDecoders(t *testing.T) {
+	d, err := newBodyDecoders(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+
+	d, err = newBodyDecoders([]string{"application/cbor"})
+	require.NoError(t, err)
+	assert.Contains(t, d, "application/cbor")
+
+	_, err = newBodyDecoders([]string{"application/protobuf"})
+	assert.ErrorContains(t, err, `unsupported body format "application/protobuf"`)
+}
+
+func TestServeHTTPDecodesBodyFormats(t *testing.T) {
+	payload := map[string]any{
+		"context": map[string]any{
+			"domain":         "ONDC:RET10",
+			"action":         "search",
+			"version":        "2.0.0",
+			"bap_id":         "bap.example.com",
+			"transaction_id": "t1",
+			"message_id":     "m1",
+		},
+		"message": map[string]any{"intent": map[string]any{"item": map[string]any{"descriptor": map[string]any{"name": "tea"}}}, "count": 2, "open": true},
+	}
+	jsonBody, err := marshalJSON(payload)
+	require.NoError(t, err)
+	decoders, err := newBodyDecoders([]string{"application/cbor"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{name: "json", contentType: "application/json", body: jsonBody},
+		{name: "cbor", contentType: "application/cbor", body: encodeCBOR(t, payload)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen, forwarded []byte
+			var bCtx *model.BecknContext
+			h := &stdHandler{
+				decoders: decoders,
+				steps: []definition.Step{
+					withPayload(stepFunc(func(ctx *model.StepContext) error {
+						seen = append([]byte(nil), ctx.Body...)
+						bCtx = ctx.BecknContext
+						return nil
+					}), true),
+					stepFunc(func(ctx *model.StepContext) error {
+						forwarded = ctx.Body
+						return nil
+					}),
+				},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.JSONEq(t, string(jsonBody), string(seen))
+			require.NotNil(t, bCtx)
+			assert.Equal(t, model.BecknContext{
+				Domain:        "ONDC:RET10",
+				Action:        "search",
+				Version:       "2.0.0",
+				BapID:         "bap.example.com",
+				TransactionID: "t1",
+				MessageID:     "m1",
+			}, *bCtx)
+			assert.Equal(t, tt.body, forwarded, "the body is forwarded in its own format")
+		})
+	}
+}
+
+func TestServeHTTPRejectsUndecodableBody(t *testing.T) {
+	decoders, err := newBodyDecoders([]string{"application/cbor"})
+	require.NoError(t, err)
+	h := &stdHandler{decoders: decoders}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", bytes.NewReader([]byte{0xa1, 0x01}))
+	req.Header.Set("Content-Type", "application/cbor")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid application/cbor body")
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// bodySampler selects the requests whose body is included in request logs.
+type bodySampler struct {
+	rate uint64
+	n    atomic.Uint64
+}
+
+// newBodySampler returns a sampler that selects 1 in rate requests. Rates below 1 select none.
+func newBodySampler(rate int) *bodySampler {
+	if rate < 1 {
+		rate = 0
+	}
+	return &bodySampler{rate: uint64(rate)}
+}
+
+// sample reports whether the next request should have its body logged.
+func (s *bodySampler) sample() bool {
+	if s == nil || s.rate == 0 {
+		return false
+	}
+	return (s.n.Add(1)-1)%s.rate == 0
+}
+
+type bodyLoggingKey struct{}
+
+// withBodyLogging marks ctx as belonging to a request sampled for body logging.
+func withBodyLogging(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bodyLoggingKey{}, true)
+}
+
+// loggedBody returns body, redacted, if the request was sampled for body logging and nil otherwise.
+func loggedBody(ctx context.Context, body []byte) []byte {
+	if sampled, _ := ctx.Value(bodyLoggingKey{}).(bool); sampled {
+		return redactedBody(ctx, body)
+	}
+	return nil
+}
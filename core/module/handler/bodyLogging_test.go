@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodySampler(t *testing.T) {
+	tests := []struct {
+		name string
+		rate int
+		want []bool
+	}{
+		{name: "disabled", rate: 0, want: []bool{false, false, false}},
+		{name: "negative rate", rate: -1, want: []bool{false, false, false}},
+		{name: "every request", rate: 1, want: []bool{true, true, true}},
+		{name: "one in three", rate: 3, want: []bool{true, false, false, true, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newBodySampler(tt.rate)
+			got := make([]bool, len(tt.want))
+			for i := range got {
+				got[i] = s.sample()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// captureRequestLogs replaces the request log functions and records the logged bodies.
+func captureRequestLogs(t *testing.T) (bodies *[][]byte, errBodies *[][]byte) {
+	t.Helper()
+	bodies, errBodies = &[][]byte{}, &[][]byte{}
+	origReq, origErr := requestLogFunc, requestErrorLogFunc
+	requestLogFunc = func(_ context.Context, _ *http.Request, body []byte) { *bodies = append(*bodies, body) }
+	requestErrorLogFunc = func(_ context.Context, _ *http.Request, body []byte, _ error) {
+		*errBodies = append(*errBodies, body)
+	}
+	t.Cleanup(func() { requestLogFunc, requestErrorLogFunc = origReq, origErr })
+	return bodies, errBodies
+}
+
+func TestServeBodyLogSampling(t *testing.T) {
+	bodies, errBodies := captureRequestLogs(t)
+	h := &stdHandler{bodySampler: newBodySampler(2)}
+
+	for i := 0; i < 4; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{"n":1}`)))
+	}
+
+	body := []byte(`{"n":1}`)
+	assert.Equal(t, [][]byte{body, nil, body, nil}, *bodies)
+	assert.Empty(t, *errBodies)
+}
+
+func TestServeBodyLogOnError(t *testing.T) {
+	bodies, errBodies := captureRequestLogs(t)
+	h := &stdHandler{
+		bodySampler: newBodySampler(0),
+		steps: []definition.Step{stepFunc(func(*model.StepContext) error {
+			return model.NewBadReqErr(errors.New("bad"))
+		})},
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{"n":1}`)))
+
+	assert.Equal(t, [][]byte{nil}, *bodies, "successful-path log should drop the body")
+	assert.Equal(t, [][]byte{[]byte(`{"n":1}`)}, *errBodies, "error log should keep the body")
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// defaultCorrelationTTL is used when CallbackCorrelationConfig.TTL is unset.
+const defaultCorrelationTTL = time.Hour
+
+// correlationKeyPrefix prefixes the cache keys of the requests seen. The keys are shared
+// by all modules, so that a callback received by one module matches the request sent
+// through another.
+const correlationKeyPrefix = "onix:correlation:"
+
+// validateCallbackCorrelationStep checks transaction continuity: it records the requests
+// it sees and flags on_* callbacks whose transaction_id and message_id match no
+// recorded request of the corresponding action.
+type validateCallbackCorrelationStep struct {
+	cache definition.Cache
+	ttl   time.Duration
+	nack  bool
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg CallbackCorrelationConfig) withDefaults() CallbackCorrelationConfig {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultCorrelationTTL
+	}
+	return cfg
+}
+
+// newValidateCallbackCorrelationStep creates and returns the validateCallbackCorrelation
+// step. It requires the Cache plugin.
+func newValidateCallbackCorrelationStep(cache definition.Cache, cfg CallbackCorrelationConfig) (definition.Step, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("invalid config: Cache plugin not configured")
+	}
+	s := &validateCallbackCorrelationStep{cache: cache, ttl: cfg.withDefaults().TTL}
+	switch cfg.OrphanCallbacks {
+	case "", OrphanCallbackLog:
+	case OrphanCallbackNack:
+		s.nack = true
+	default:
+		return nil, fmt.Errorf("invalid config: unknown callbackCorrelation orphanCallbacks action %q", cfg.OrphanCallbacks)
+	}
+	return s, nil
+}
+
+// Run records a request for its callbacks, or checks that a callback answers a recorded
+// request. Requests are recorded on a best-effort basis; they pass when the cache cannot
+// be written.
+func (s *validateCallbackCorrelationStep) Run(ctx *model.StepContext) error {
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	requestAction, callback := strings.CutPrefix(bCtx.Action, "on_")
+	if !callback {
+		if bCtx.TransactionID == "" || bCtx.MessageID == "" {
+			return nil
+		}
+		if err := s.cache.Set(ctx, correlationKey(bCtx, requestAction), bCtx.Action, s.ttl); err != nil {
+			log.Warnf(ctx, "Failed to record message_id %s for callback correlation: %v", bCtx.MessageID, err)
+		}
+		return nil
+	}
+	if bCtx.TransactionID != "" && bCtx.MessageID != "" {
+		if v, err := s.cache.Get(ctx, correlationKey(bCtx, requestAction)); err == nil && v != "" {
+			return nil
+		}
+	}
+	err = fmt.Errorf("orphan callback: no %s request seen for transaction_id %s and message_id %s", requestAction, bCtx.TransactionID, bCtx.MessageID)
+	if s.nack {
+		return model.NewBadReqErr(err)
+	}
+	log.Warnf(ctx, "Processing %s: %v", bCtx.Action, err)
+	return nil
+}
+
+// correlationKey returns the cache key of the request of action answered by bCtx.
+func correlationKey(bCtx *model.BecknContext, action string) string {
+	return correlationKeyPrefix + bCtx.BapID + ":" + bCtx.TransactionID + ":" + bCtx.MessageID + ":" + action
+}
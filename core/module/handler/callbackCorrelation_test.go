@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+func TestValidateCallbackCorrelationStep(t *testing.T) {
+	const (
+		search   = `{"context":{"action":"search","bap_id":"bap.example.com","transaction_id":"t1","message_id":"m1"}}`
+		onSearch = `{"context":{"action":"on_search","bap_id":"bap.example.com","bpp_id":"bpp.example.com","transaction_id":"t1","message_id":"m1"}}`
+	)
+	type call struct {
+		body    string
+		advance time.Duration
+		orphan  bool
+	}
+	tests := []struct {
+		name  string
+		cfg   CallbackCorrelationConfig
+		calls []call
+	}{
+		{
+			name:  "correlated callback",
+			calls: []call{{body: search}, {body: onSearch}},
+		},
+		{
+			name:  "every callback to a request is correlated",
+			calls: []call{{body: search}, {body: onSearch}, {body: onSearch}},
+		},
+		{
+			name:  "callback without request",
+			calls: []call{{body: onSearch, orphan: true}},
+		},
+		{
+			name: "callback to another message",
+			calls: []call{
+				{body: search},
+				{body: `{"context":{"action":"on_search","bap_id":"bap.example.com","transaction_id":"t1","message_id":"m2"}}`, orphan: true},
+			},
+		},
+		{
+			name: "callback to another transaction",
+			calls: []call{
+				{body: search},
+				{body: `{"context":{"action":"on_search","bap_id":"bap.example.com","transaction_id":"t2","message_id":"m1"}}`, orphan: true},
+			},
+		},
+		{
+			name: "callback of another action",
+			calls: []call{
+				{body: search},
+				{body: `{"context":{"action":"on_select","bap_id":"bap.example.com","transaction_id":"t1","message_id":"m1"}}`, orphan: true},
+			},
+		},
+		{
+			name:  "callback after the ttl",
+			cfg:   CallbackCorrelationConfig{TTL: time.Minute},
+			calls: []call{{body: search}, {body: onSearch, advance: time.Minute, orphan: true}},
+		},
+		{
+			name:  "callback without ids",
+			calls: []call{{body: `{"context":{"action":"on_search"}}`, orphan: true}},
+		},
+		{
+			name:  "requests without ids pass",
+			calls: []call{{body: `{"context":{"action":"search"}}`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, action := range []OrphanCallbackAction{OrphanCallbackLog, OrphanCallbackNack} {
+				cache := newMemCache()
+				cfg := tt.cfg
+				cfg.OrphanCallbacks = action
+				step, err := newValidateCallbackCorrelationStep(cache, cfg)
+				require.NoError(t, err)
+				for i, c := range tt.calls {
+					cache.now = cache.now.Add(c.advance)
+					err := step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(c.body)))
+					if !c.orphan || action == OrphanCallbackLog {
+						assert.NoError(t, err, "%s: call %d", action, i)
+						continue
+					}
+					var badReq *model.BadReqErr
+					require.True(t, errors.As(err, &badReq), "%s: call %d: got %v", action, i, err)
+					assert.ErrorContains(t, err, "orphan callback")
+				}
+			}
+		})
+	}
+}
+
+func TestValidateCallbackCorrelationAcrossModules(t *testing.T) {
+	cache := newMemCache()
+	cfg := CallbackCorrelationConfig{OrphanCallbacks: OrphanCallbackNack}
+	caller, err := newValidateCallbackCorrelationStep(cache, cfg)
+	require.NoError(t, err)
+	receiver, err := newValidateCallbackCorrelationStep(cache, cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, caller.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/init", nil),
+		[]byte(`{"context":{"action":"init","bap_id":"bap.example.com","transaction_id":"t1","message_id":"m1"}}`))))
+	err = receiver.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/receiver/on_init", nil),
+		[]byte(`{"context":{"action":"on_init","bap_id":"bap.example.com","transaction_id":"t1","message_id":"m1"}}`)))
+
+	assert.NoError(t, err, "a callback received by one module matches the request sent through another")
+}
+
+func TestNewValidateCallbackCorrelationStep(t *testing.T) {
+	_, err := newValidateCallbackCorrelationStep(nil, CallbackCorrelationConfig{})
+	assert.ErrorContains(t, err, "Cache plugin not configured")
+
+	_, err = newValidateCallbackCorrelationStep(newMemCache(), CallbackCorrelationConfig{OrphanCallbacks: "drop"})
+	assert.ErrorContains(t, err, `unknown callbackCorrelation orphanCallbacks action "drop"`)
+
+	step, err := newValidateCallbackCorrelationStep(newMemCache(), CallbackCorrelationConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, defaultCorrelationTTL, step.(*validateCallbackCorrelationStep).ttl)
+}
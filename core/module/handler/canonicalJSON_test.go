@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signer"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signvalidator"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			// RFC 8785, section 3.2.2.
+			name: "rfc 8785 sample",
+			in:   `{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "literals": [null, true, false]}`,
+			want: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			// RFC 8785, section 3.2.3: members are sorted by UTF-16 code units.
+			name: "utf-16 member order",
+			in:   `{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh", "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+			want: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			name: "numbers",
+			in:   `[0, -0, 1e21, 1e-7, 123456789012345680000, -1.5, 100, 5e-324]`,
+			want: `[0,0,1e+21,1e-7,123456789012345680000,-1.5,100,5e-324]`,
+		},
+		{
+			name: "nested objects and whitespace",
+			in:   "{\n  \"message\": {\"b\": [1, {\"y\": 1, \"x\": 2}], \"a\": \"<&>\"},\n  \"context\": {}\n}",
+			want: `{"context":{},"message":{"a":"<&>","b":[1,{"x":2,"y":1}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := canonicalJSON([]byte(tt.in))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestCanonicalJSONErrors(t *testing.T) {
+	for _, in := range []string{`{"a":`, `{"a":1} {"b":2}`, `[1e400]`} {
+		_, err := canonicalJSON([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
+
+// ed25519KeyManager serves a single generated ed25519 key set.
+type ed25519KeyManager struct {
+	definition.KeyManager
+	keyset *model.Keyset
+}
+
+func newEd25519KeyManager(t *testing.T) *ed25519KeyManager {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return &ed25519KeyManager{keyset: &model.Keyset{
+		UniqueKeyID:    "k1",
+		SigningPrivate: base64.StdEncoding.EncodeToString(priv.Seed()),
+		SigningPublic:  base64.StdEncoding.EncodeToString(pub),
+	}}
+}
+
+func (m *ed25519KeyManager) Keyset(context.Context, string) (*model.Keyset, error) {
+	return m.keyset, nil
+}
+
+func (m *ed25519KeyManager) LookupNPKeys(_ context.Context, _, uniqueID string) (string, string, error) {
+	if uniqueID != m.keyset.UniqueKeyID {
+		return "", "", errors.New("key not found")
+	}
+	return m.keyset.SigningPublic, "", nil
+}
+
+func TestSignatureOverCanonicalJSON(t *testing.T) {
+	const (
+		raw         = `{"message": {"order": {"id": "o1", "items": [1, 2]}}, "context": {"action": "confirm"}}`
+		reformatted = "{\n  \"context\": {\"action\": \"confirm\"},\n  \"message\": {\"order\": {\"items\": [1, 2], \"id\": \"o1\"}}\n}"
+		tampered    = `{"context": {"action": "confirm"}, "message": {"order": {"id": "o2", "items": [1, 2]}}}`
+	)
+	ctx := context.Background()
+	sgn, _, err := signer.New(ctx, &signer.Config{})
+	require.NoError(t, err)
+	sv, _, err := signvalidator.New(ctx, &signvalidator.Config{})
+	require.NoError(t, err)
+	km := newEd25519KeyManager(t)
+
+	// sign signs body and returns the signed body and its Authorization header.
+	sign := func(t *testing.T, canonical bool, body string) (string, string) {
+		t.Helper()
+		step, err := newSignStep(sgn, km, SignatureConfig{CanonicalJSON: canonical}, nil)
+		require.NoError(t, err)
+		sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", nil), []byte(body))
+		sctx.SubID = "bap.example.com"
+		require.NoError(t, step.Run(sctx))
+		return string(sctx.Body), sctx.Request.Header.Get(model.AuthHeaderSubscriber)
+	}
+	validate := func(t *testing.T, canonical bool, body, auth string) error {
+		t.Helper()
+		step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{CanonicalJSON: canonical}, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/confirm", nil)
+		req.Header.Set(model.AuthHeaderSubscriber, auth)
+		return step.Run(newTestStepCtx(req, []byte(body)))
+	}
+
+	t.Run("canonical signing forwards the signed form", func(t *testing.T) {
+		signed, auth := sign(t, true, raw)
+		assert.Equal(t, `{"context":{"action":"confirm"},"message":{"order":{"id":"o1","items":[1,2]}}}`, signed)
+		assert.NoError(t, validate(t, false, signed, auth), "receivers without canonicalization verify the forwarded body")
+	})
+
+	t.Run("re-serialized body", func(t *testing.T) {
+		_, auth := sign(t, true, raw)
+		assert.Error(t, validate(t, false, reformatted, auth), "raw verification depends on formatting")
+		assert.NoError(t, validate(t, true, reformatted, auth), "canonical verification ignores formatting")
+	})
+
+	t.Run("raw signature in canonical mode", func(t *testing.T) {
+		signed, auth := sign(t, false, raw)
+		assert.Equal(t, raw, signed)
+		assert.NoError(t, validate(t, true, raw, auth))
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		_, auth := sign(t, true, raw)
+		assert.Error(t, validate(t, false, tampered, auth))
+		assert.Error(t, validate(t, true, tampered, auth))
+	})
+
+	t.Run("non-JSON body", func(t *testing.T) {
+		step, err := newSignStep(sgn, km, SignatureConfig{CanonicalJSON: true}, nil)
+		require.NoError(t, err)
+		sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", nil), []byte("not json"))
+		sctx.SubID = "bap.example.com"
+		err = step.Run(sctx)
+		var badReq *model.BadReqErr
+		assert.ErrorAs(t, err, &badReq)
+	})
+}
@@ -0,0 +1,26 @@
+package handler
+
+import "time"
+
+// Clock tells the time to steps whose behavior depends on it, so that tests and
+// simulated-time deployments can control it.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// SystemClock is the Clock that reports the real time.
+type SystemClock struct{}
+
+// Now returns time.Now().
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// orSystemClock returns c, or SystemClock when c is nil.
+func orSystemClock(c Clock) Clock {
+	if c == nil {
+		return SystemClock{}
+	}
+	return c
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// fakeClock is a Clock stopped at a fixed time.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
+// windowSigner records the validity window it is asked to sign with.
+type windowSigner struct {
+	createdAt, expiresAt int64
+}
+
+func (s *windowSigner) Sign(_ context.Context, _ []byte, _ string, createdAt, expiresAt int64) (string, error) {
+	s.createdAt, s.expiresAt = createdAt, expiresAt
+	return "sig", nil
+}
+
+func TestSignStepUsesClock(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	sgn := &windowSigner{}
+	step, err := newSignStep(sgn, newEd25519KeyManager(t), SignatureConfig{}, fakeClock{now})
+	require.NoError(t, err)
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(`{}`))
+	ctx.SubID = "bap.example.com"
+
+	require.NoError(t, step.Run(ctx))
+
+	assert.Equal(t, now.Unix(), sgn.createdAt)
+	assert.Equal(t, now.Add(5*time.Minute).Unix(), sgn.expiresAt)
+	auth := ctx.Request.Header.Get(model.AuthHeaderSubscriber)
+	assert.Contains(t, auth, fmt.Sprintf(`created="%d",expires="%d"`, now.Unix(), now.Add(5*time.Minute).Unix()))
+}
+
+func TestOrSystemClock(t *testing.T) {
+	assert.Equal(t, Clock(SystemClock{}), orSystemClock(nil))
+	c := fakeClock{time.Unix(0, 0)}
+	assert.Equal(t, Clock(c), orSystemClock(c))
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+// defaultRetryAfter is advertised to rejected clients when ConcurrencyConfig.RetryAfter is unset.
+const defaultRetryAfter = time.Second
+
+// errTooManyRequests is reported for requests rejected by the concurrency limit.
+var errTooManyRequests = errors.New("too many concurrent requests")
+
+// concurrencyLimiter caps the number of requests served at the same time.
+type concurrencyLimiter struct {
+	slots      chan struct{}
+	retryAfter string
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ConcurrencyConfig) withDefaults() ConcurrencyConfig {
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = defaultRetryAfter
+	}
+	return cfg
+}
+
+// newConcurrencyLimiter creates a concurrencyLimiter from cfg.
+// It returns nil when no limit is configured.
+func newConcurrencyLimiter(cfg ConcurrencyConfig) *concurrencyLimiter {
+	if cfg.MaxRequests <= 0 {
+		return nil
+	}
+	retryAfter := cfg.withDefaults().RetryAfter
+	// Retry-After is in whole seconds; round up so clients never retry early.
+	secs := int64((retryAfter + time.Second - 1) / time.Second)
+	return &concurrencyLimiter{
+		slots:      make(chan struct{}, cfg.MaxRequests),
+		retryAfter: strconv.FormatInt(secs, 10),
+	}
+}
+
+// acquire takes a slot without waiting and reports whether one was free.
+func (l *concurrencyLimiter) acquire() bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release frees a slot taken by acquire.
+func (l *concurrencyLimiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
+
+// reject NACKs a request that found no free slot with a retryable 503 status and
+// Retry-After, in the format r accepts.
+func (l *concurrencyLimiter) reject(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Retry-After", l.retryAfter)
+	ctx := response.WithAccept(r.Context(), r.Header.Get("Accept"))
+	nackErr := &model.Error{Code: strconv.Itoa(http.StatusServiceUnavailable), Message: errTooManyRequests.Error()}
+	response.SendNackStatus(ctx, w, nackErr, http.StatusServiceUnavailable)
+}
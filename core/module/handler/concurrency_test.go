@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPConcurrencyLimit(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var blocking sync.Map
+	h := &stdHandler{
+		limiter: newConcurrencyLimiter(ConcurrencyConfig{MaxRequests: 2, RetryAfter: 1500 * time.Millisecond}),
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			if _, ok := blocking.Load(ctx.Request.URL.Path); ok {
+				entered <- struct{}{}
+				<-release
+			}
+			return nil
+		})},
+	}
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`)))
+		return rec
+	}
+
+	// Fill both slots with requests blocked in a step.
+	var wg sync.WaitGroup
+	for _, path := range []string{"/slow/1", "/slow/2"} {
+		blocking.Store(path, true)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, serve(path).Code)
+		}()
+		<-entered
+	}
+
+	rec := serve("/fast")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, http.StatusOK, serve("/fast").Code, "capacity must be released when requests complete")
+}
+
+func TestServeHTTPConcurrencyLimitRejection(t *testing.T) {
+	var entries []log.AccessEntry
+	orig := accessLogFunc
+	accessLogFunc = func(_ context.Context, e log.AccessEntry) { entries = append(entries, e) }
+	t.Cleanup(func() { accessLogFunc = orig })
+	h := &stdHandler{SubscriberID: "bpp.example.com", limiter: newConcurrencyLimiter(ConcurrencyConfig{MaxRequests: 1})}
+	require.True(t, h.limiter.acquire())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	var resp model.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, model.StatusNACK, resp.Message.Ack.Status)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "503", resp.Error.Code)
+	assert.Equal(t, errTooManyRequests.Error(), resp.Error.Message)
+	require.Len(t, entries, 1)
+	assert.Equal(t, http.StatusServiceUnavailable, entries[0].Status)
+	assert.Equal(t, outcomeNack, entries[0].Outcome)
+	assert.Equal(t, "bpp.example.com", entries[0].SubscriberID)
+}
+
+func TestServeHTTPConcurrencyLimitReleasedOnFailure(t *testing.T) {
+	h := &stdHandler{
+		limiter: newConcurrencyLimiter(ConcurrencyConfig{MaxRequests: 1}),
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			return model.NewBadReqErr(assert.AnError)
+		})},
+	}
+	for range 3 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestNewConcurrencyLimiter(t *testing.T) {
+	assert.Nil(t, newConcurrencyLimiter(ConcurrencyConfig{}), "zero limit must be unlimited")
+	assert.Equal(t, "1", newConcurrencyLimiter(ConcurrencyConfig{MaxRequests: 1}).retryAfter)
+	assert.Equal(t, "30", newConcurrencyLimiter(ConcurrencyConfig{MaxRequests: 1, RetryAfter: 30 * time.Second}).retryAfter)
+}
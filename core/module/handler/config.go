@@ -26,6 +26,7 @@ type PluginManager interface {
 	SchemaValidator(ctx context.Context, cfg *plugin.Config) (definition.SchemaValidator, error)
 	OndcValidator(ctx context.Context, cache definition.Cache, cfg *plugin.Config) (definition.OndcValidator, error)
 	OndcWorkbench(ctx context.Context, cache definition.Cache, cfg *plugin.Config) (definition.OndcWorkbench, error)
+	Auditor(ctx context.Context, cfg *plugin.Config) (definition.Auditor, error)
 }
 
 // Type defines different handler types for processing requests.
@@ -51,6 +52,33 @@ type PluginCfg struct {
 	OndcValidator    *plugin.Config  `yaml:"ondcValidator,omitempty"`
 	OndcWorkbench    *plugin.Config  `yaml:"ondcWorkbench,omitempty"`
 	Steps            []plugin.Config
+
+	// OndcValidators holds per-domain OndcValidator plugins, keyed by context.domain.
+	// Domains without an entry use OndcValidator.
+	OndcValidators map[string]*plugin.Config `yaml:"ondcValidators,omitempty"`
+
+	// SignValidationRegistries are further registries whose keys validateSign tries, in
+	// order, when the keys from Registry do not validate a signature. Each is queried
+	// through its own instance of the KeyManager plugin.
+	SignValidationRegistries []SignRegistryConfig `yaml:"signValidationRegistries,omitempty"`
+
+	// ResponseSchemaValidator validates the responses of requests forwarded in proxy
+	// mode. When unset, responseValidation uses SchemaValidator.
+	ResponseSchemaValidator *plugin.Config `yaml:"responseSchemaValidator,omitempty"`
+
+	// FallbackSchemaValidators are further SchemaValidator plugins tried, in order, for
+	// payloads that SchemaValidator and the fallbacks before them have no schema for.
+	FallbackSchemaValidators []plugin.Config `yaml:"fallbackSchemaValidators,omitempty"`
+
+	// Auditor receives an audit record of every request once its response is sent.
+	Auditor *plugin.Config `yaml:"auditor,omitempty"`
+}
+
+// SignRegistryConfig is a named registry tried by validateSign.
+type SignRegistryConfig struct {
+	// Name identifies the registry in logs and metrics, e.g. "staging".
+	Name     string        `yaml:"name"`
+	Registry plugin.Config `yaml:"registry"`
 }
 
 // HttpClientConfig defines the configuration for the HTTP transport layer.
@@ -72,6 +100,761 @@ type HttpClientConfig struct {
 	ResponseHeaderTimeout time.Duration `yaml:"responseHeaderTimeout"`
 }
 
+// PublisherRule maps a Beckn action and/or domain to a publisher ID.
+// An empty Action or Domain matches any value.
+type PublisherRule struct {
+	Action      string `yaml:"action,omitempty"`
+	Domain      string `yaml:"domain,omitempty"`
+	PublisherID string `yaml:"publisherId"`
+}
+
+// PublishRetryConfig defines retry behaviour for asynchronous publishes.
+type PublishRetryConfig struct {
+	// MaxAttempts is the total number of publish attempts. Values below 2 disable retries.
+	MaxAttempts int `yaml:"maxAttempts"`
+
+	// InitialBackoff is the wait before the first retry; it doubles on each
+	// subsequent attempt. Defaults to 100ms.
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+
+	// MaxBackoff caps the wait between attempts. Defaults to 5s.
+	MaxBackoff time.Duration `yaml:"maxBackoff"`
+
+	// DLQPublisherID, if set, receives the message once all attempts fail.
+	DLQPublisherID string `yaml:"dlqPublisherId"`
+}
+
+// OndcSaveMode defines when validation data is persisted by the validateOndcCallSave step.
+type OndcSaveMode string
+
+const (
+	// OndcSaveModeSync persists validation data inline, before the response is sent.
+	OndcSaveModeSync OndcSaveMode = "sync"
+	// OndcSaveModeAsync persists validation data after the response has been sent.
+	OndcSaveModeAsync OndcSaveMode = "async"
+)
+
+// OndcConfig holds settings for the ONDC validation steps.
+type OndcConfig struct {
+	// SaveMode controls when validateOndcCallSave persists data. Defaults to sync.
+	SaveMode OndcSaveMode `yaml:"saveMode"`
+
+	// SaveOnValidationFailure makes validateOndcAndSave persist validation data
+	// even when payload validation fails.
+	SaveOnValidationFailure bool `yaml:"saveOnValidationFailure"`
+}
+
+// WorkbenchFailureBehavior defines how ondcWorkbenchReceiver responds when the workbench rejects a request.
+type WorkbenchFailureBehavior string
+
+const (
+	// WorkbenchFailureAck acknowledges the request and drops it.
+	WorkbenchFailureAck WorkbenchFailureBehavior = "ack"
+	// WorkbenchFailureNack responds with a NACK and HTTP 200.
+	WorkbenchFailureNack WorkbenchFailureBehavior = "nack"
+	// WorkbenchFailureHTTP responds with a NACK and the configured HTTP status.
+	WorkbenchFailureHTTP WorkbenchFailureBehavior = "http"
+)
+
+// WorkbenchConfig holds settings for the ONDC workbench steps.
+type WorkbenchConfig struct {
+	// ReceiverFailure selects the response when WorkbenchReceiver returns an error
+	// that is not already a *model.WorkbenchErr. Empty keeps the default internal error.
+	ReceiverFailure WorkbenchFailureBehavior `yaml:"receiverFailure"`
+
+	// ReceiverFailureStatus is the error code used for nack and http failures. Defaults to 500.
+	ReceiverFailureStatus int `yaml:"receiverFailureStatus"`
+
+	// When restricts the workbench steps to matching requests. Requests that do not
+	// match skip the workbench entirely.
+	When WorkbenchCondition `yaml:"when"`
+}
+
+// WorkbenchCondition selects the requests the workbench steps run for. Empty lists match all requests.
+type WorkbenchCondition struct {
+	Actions        []string `yaml:"actions,omitempty"`
+	Domains        []string `yaml:"domains,omitempty"`
+	ExcludeActions []string `yaml:"excludeActions,omitempty"`
+}
+
+// BodyLogConfig controls how often request bodies are included in request logs and
+// which of their fields are masked.
+type BodyLogConfig struct {
+	// SampleRate logs the body of 1 in SampleRate requests. Zero logs no bodies for
+	// successful requests; failed requests always log their body.
+	SampleRate int `yaml:"sampleRate"`
+
+	// Redact selects fields, such as "message.order.payments[*].params", whose values
+	// are masked in logged bodies. Forwarded bodies are not affected.
+	Redact []string `yaml:"redact,omitempty"`
+}
+
+// BodyBufferConfig controls how request bodies are buffered while a request is processed.
+type BodyBufferConfig struct {
+	// SpillThreshold is the body size in bytes above which the body is written to a
+	// temporary file instead of memory. Zero keeps every body in memory.
+	SpillThreshold int64 `yaml:"spillThreshold"`
+
+	// TempDir is the directory for spilled bodies. Defaults to the system temp directory.
+	TempDir string `yaml:"tempDir"`
+}
+
+// RequestDeadlineConfig derives a deadline for processing a request from a header
+// in which the sender states how long it waits for a response.
+type RequestDeadlineConfig struct {
+	// Header carries the wait time, in seconds or as a duration such as "1500ms".
+	// Empty disables request deadlines.
+	Header string `yaml:"header"`
+
+	// Max caps the derived deadline. Zero leaves it uncapped.
+	Max time.Duration `yaml:"max"`
+}
+
+// PathRoleConfig sets the role, and optionally the subscriber, of the requests to
+// paths under Prefix, so that one module can serve the endpoints of several roles.
+type PathRoleConfig struct {
+	// Prefix is the path prefix, matched on whole segments, e.g. /bpp/receiver.
+	Prefix string `yaml:"prefix"`
+
+	// Role is the role of the requests, which also selects the header requests are
+	// signed in.
+	Role model.Role `yaml:"role"`
+
+	// SubscriberID is the subscriber the requests are processed for, whose keys sign
+	// them. Defaults to the handler's subscriberId.
+	SubscriberID string `yaml:"subscriberId,omitempty"`
+}
+
+// TrustedHeadersConfig lets a trusted upstream proxy, such as a multi-tenant gateway,
+// set the subscriber ID and role of individual requests. Requests without the headers
+// use the handler's subscriberId and role.
+type TrustedHeadersConfig struct {
+	// SubscriberID is the header carrying the subscriber ID. Empty disables the override.
+	SubscriberID string `yaml:"subscriberId"`
+
+	// Role is the header carrying the role. Empty disables the override.
+	Role string `yaml:"role"`
+
+	// SchemaVersion is the header carrying a schema version, such as 2.0.1, that schema
+	// validators use instead of the version in the request body, so that a new schema
+	// version can be tried on live traffic. Empty disables the override.
+	SchemaVersion string `yaml:"schemaVersion"`
+
+	// TrustedNetworks are the CIDRs of the sources whose headers are honoured.
+	// Headers from other sources are ignored.
+	TrustedNetworks []string `yaml:"trustedNetworks,omitempty"`
+}
+
+// ConcurrencyConfig caps the requests a module serves at the same time. Requests
+// beyond the cap are rejected with 503 and a Retry-After header.
+type ConcurrencyConfig struct {
+	// MaxRequests is the number of requests served concurrently. Zero means unlimited.
+	MaxRequests int `yaml:"maxRequests"`
+
+	// RetryAfter is advertised to rejected clients, rounded up to whole seconds. Defaults to 1s.
+	RetryAfter time.Duration `yaml:"retryAfter"`
+}
+
+// ShadowConfig mirrors every routed request to a second target, e.g. a new BPP
+// implementation under test. Mirroring happens after the response is sent, and its
+// responses and failures never affect the primary route.
+type ShadowConfig struct {
+	// URL receives the copies. Empty disables mirroring.
+	URL string `yaml:"url"`
+
+	// Timeout bounds each mirrored request. Defaults to 30s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// MaintenanceConfig sets how the module answers traffic in maintenance mode, which is
+// toggled at runtime through the /debug/maintenance endpoint.
+type MaintenanceConfig struct {
+	// Enabled starts the module in maintenance mode.
+	Enabled bool `yaml:"enabled"`
+
+	// Status is the HTTP status of the maintenance NACK. Defaults to 503.
+	Status int `yaml:"status"`
+
+	// Code is the error code of the maintenance NACK. Defaults to Status.
+	Code string `yaml:"code"`
+
+	// Message is the error message of the maintenance NACK.
+	Message string `yaml:"message"`
+}
+
+// ResponseCacheConfig caches full responses of idempotent flows in the Cache plugin.
+// A cached response is served on repeat once the validateSign and checkSubscriberAccess
+// steps have run, without running the others. Responses are kept per subscriber, signer
+// and message.
+type ResponseCacheConfig struct {
+	// Actions lists the cacheable context actions. Empty disables caching.
+	Actions []string `yaml:"actions"`
+
+	// KeyFields are the context fields whose values form the cache key, besides the
+	// subscriber, signer and message. Defaults to domain and action.
+	KeyFields []string `yaml:"keyFields"`
+
+	// TTL is how long a response is served from the cache. Defaults to 30s.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// InstrumentationHeadersConfig names the request headers that carry the module name
+// and role to instrumentation while a request is processed. They are removed before
+// the request is forwarded.
+type InstrumentationHeadersConfig struct {
+	// ModuleName carries the module name. Defaults to X-Module-Name.
+	ModuleName string `yaml:"moduleName"`
+
+	// Role carries the request role. Defaults to X-Role.
+	Role string `yaml:"role"`
+
+	// Disabled stops the headers from being set.
+	Disabled bool `yaml:"disabled"`
+}
+
+// MultipartConfig sets how multipart/form-data requests, e.g. document uploads, are
+// processed. The multipart body is always forwarded unchanged.
+type MultipartConfig struct {
+	// PayloadPart is the form field holding the Beckn JSON payload. Payload steps such as
+	// validateSchema and addRoute see this part as the body. When empty, validation steps
+	// are skipped for multipart requests.
+	PayloadPart string `yaml:"payloadPart"`
+}
+
+// TimestampConfig bounds the context timestamp accepted by the validateTimestamp step.
+type TimestampConfig struct {
+	// MaxAge is how old a timestamp may be. Defaults to 5m.
+	MaxAge time.Duration `yaml:"maxAge"`
+
+	// MaxSkew is how far in the future a timestamp may be, allowing for clock drift. Defaults to 30s.
+	MaxSkew time.Duration `yaml:"maxSkew"`
+}
+
+// ErrorDetailConfig limits who sees the detail of NACK errors, such as schema and
+// signature validation messages. Other callers get the error code and a generic
+// message; the detail is still logged.
+type ErrorDetailConfig struct {
+	// Restrict hides error detail from callers that are not trusted.
+	Restrict bool `yaml:"restrict"`
+
+	// TrustedNetworks are the CIDRs of the sources that get detailed errors.
+	TrustedNetworks []string `yaml:"trustedNetworks,omitempty"`
+
+	// DebugToken gives detailed errors to requests carrying it in DebugTokenHeader.
+	DebugToken string `yaml:"debugToken"`
+
+	// DebugTokenHeader is the header carrying the debug token. Defaults to X-Debug-Token.
+	DebugTokenHeader string `yaml:"debugTokenHeader"`
+}
+
+// SignatureMode selects the kind of signature the sign step produces.
+type SignatureMode string
+
+const (
+	// SignatureModeBeckn signs with the Beckn Signature Authorization header.
+	SignatureModeBeckn SignatureMode = "beckn"
+	// SignatureModeJWS signs with a detached JWS (RFC 7515) over the canonical JSON form
+	// of the body.
+	SignatureModeJWS SignatureMode = "jws"
+)
+
+// SignatureConfig holds settings for the sign and validateSign steps.
+type SignatureConfig struct {
+	// CanonicalJSON signs the canonical JSON form (RFC 8785) of the body, which is also
+	// what gets forwarded, and lets validateSign accept signatures over that form. It
+	// changes the signed bytes, so counterparties must agree on it.
+	CanonicalJSON bool `yaml:"canonicalJSON"`
+
+	// Path selects the sub-document of the body that is signed and validated, e.g.
+	// "message". The forwarded body is left intact. Empty signs the whole body.
+	Path string `yaml:"path"`
+
+	// SubscriberStatus makes validateSign reject signers whose registry record is not in
+	// an allowed status.
+	SubscriberStatus SubscriberStatusConfig `yaml:"subscriberStatus"`
+
+	// FailureTracking counts validateSign failures per subscriber and source address
+	// and can block the ones that fail repeatedly.
+	FailureTracking SignFailureConfig `yaml:"failureTracking"`
+
+	// Algorithm is the algorithm the sign step uses when the request does not negotiate
+	// another one through an Accept-Signature header. Defaults to ed25519.
+	Algorithm string `yaml:"algorithm"`
+
+	// SubscriberFromSignature makes validateSign set the subscriber ID of a request to the
+	// subscriber whose signature it validated, for later steps such as sign.
+	SubscriberFromSignature bool `yaml:"subscriberFromSignature"`
+
+	// Mode is the kind of signature the sign step produces. In jws mode validateSign
+	// also validates the detached JWS of requests that carry one. Defaults to beckn.
+	Mode SignatureMode `yaml:"mode"`
+
+	// JWSHeader is the header carrying detached JWS signatures. Defaults to X-JWS-Signature.
+	JWSHeader string `yaml:"jwsHeader"`
+}
+
+// SignFailureConfig holds settings for tracking signature validation failures per subscriber
+// and source address. Counts are kept in memory, per module and instance.
+type SignFailureConfig struct {
+	// Enabled turns tracking on.
+	Enabled bool `yaml:"enabled"`
+
+	// Window is the period over which failures are counted. The count of a subscriber
+	// restarts once Window has passed since its first counted failure. Defaults to 1m.
+	Window time.Duration `yaml:"window"`
+
+	// Threshold is the number of failures within Window after which the subscriber is
+	// blocked. Zero only counts failures.
+	Threshold int `yaml:"threshold"`
+
+	// BlockFor is how long a blocked subscriber's requests from the failing source
+	// address are rejected without validation. Defaults to 5m. Failing signatures are
+	// not authenticated, so behind a proxy that hides client addresses anyone sending
+	// forged signatures through it can block the subscriber they name.
+	BlockFor time.Duration `yaml:"blockFor"`
+}
+
+// SubscriberStatusConfig holds settings for the registry status check of validateSign.
+// The check needs the registry and cache plugins.
+type SubscriberStatusConfig struct {
+	// Enabled turns the check on.
+	Enabled bool `yaml:"enabled"`
+
+	// Allowed lists the accepted registry statuses, compared case-insensitively.
+	// Defaults to SUBSCRIBED.
+	Allowed []string `yaml:"allowed"`
+
+	// TTL is how long a subscriber record is cached. Defaults to 5m.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// EnrichRegistryConfig holds settings for the enrichRegistry step.
+type EnrichRegistryConfig struct {
+	// TTL is how long a subscriber record is cached. Defaults to 5m.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// NoRouteBehavior defines how the module answers a request that no step routed.
+type NoRouteBehavior string
+
+const (
+	// NoRouteAck acknowledges the request and drops it.
+	NoRouteAck NoRouteBehavior = "ack"
+	// NoRouteNotFound responds with a NotFound NACK and HTTP 404.
+	NoRouteNotFound NoRouteBehavior = "notFound"
+	// NoRouteStatus responds with a NACK and the configured HTTP status.
+	NoRouteStatus NoRouteBehavior = "status"
+)
+
+// NoRouteConfig sets the response to requests left without a route once all steps have run.
+type NoRouteConfig struct {
+	// Behavior selects the response. Defaults to ack.
+	Behavior NoRouteBehavior `yaml:"behavior"`
+
+	// Status is the HTTP status of the status NACK. Required for the status behavior.
+	Status int `yaml:"status"`
+
+	// Code is the error code of the status NACK. Defaults to Status.
+	Code string `yaml:"code"`
+
+	// Message is the error message of the notFound and status NACKs.
+	Message string `yaml:"message"`
+}
+
+// ContextCase selects the letter case a context field is normalized to.
+type ContextCase string
+
+const (
+	// ContextCaseUpper upper-cases the field.
+	ContextCaseUpper ContextCase = "upper"
+	// ContextCaseLower lower-cases the field.
+	ContextCaseLower ContextCase = "lower"
+	// ContextCaseKeep leaves the case of the field unchanged.
+	ContextCaseKeep ContextCase = "keep"
+)
+
+// NormalizeContextConfig holds the rules the normalizeContext step applies to
+// context.domain, context.version and context.core_version. Surrounding whitespace is
+// always trimmed.
+type NormalizeContextConfig struct {
+	// DomainCase is the case domains are rewritten to. Defaults to upper.
+	DomainCase ContextCase `yaml:"domainCase"`
+
+	// KeepVersionPrefix keeps a leading "v" on versions, which is stripped by default.
+	KeepVersionPrefix bool `yaml:"keepVersionPrefix"`
+
+	// Domains maps domains, after DomainCase is applied, to their canonical form.
+	Domains map[string]string `yaml:"domains"`
+
+	// Versions maps versions, after the prefix is handled, to their canonical form.
+	Versions map[string]string `yaml:"versions"`
+}
+
+// SubscriberConsistencyLevel selects how strictly the validateSubscriberConsistency step
+// checks the identity a request claims.
+type SubscriberConsistencyLevel string
+
+const (
+	// SubscriberConsistencyID requires the signer to be the claimed bap_id or bpp_id.
+	SubscriberConsistencyID SubscriberConsistencyLevel = "id"
+	// SubscriberConsistencyRegistry also requires the signer's registry record to have
+	// the claimed role and URI.
+	SubscriberConsistencyRegistry SubscriberConsistencyLevel = "registry"
+)
+
+// SubscriberConsistencyConfig holds settings for the validateSubscriberConsistency step.
+type SubscriberConsistencyConfig struct {
+	// Level selects the checks made. Defaults to id.
+	Level SubscriberConsistencyLevel `yaml:"level"`
+
+	// ReportOnly logs mismatches instead of NACKing the request.
+	ReportOnly bool `yaml:"reportOnly"`
+
+	// RequireSigner NACKs requests whose signature was not validated, for example
+	// because validateSign was skipped. By default such requests are not checked.
+	RequireSigner bool `yaml:"requireSigner"`
+
+	// TTL is how long a registry record is cached at the registry level. Defaults to 5m.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// ResponseValidationConfig holds settings for validating the responses of requests
+// forwarded in proxy mode before they are returned to the client.
+type ResponseValidationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Endpoint names the schema responses are validated against. Defaults to
+	// on_<action>; responses to on_* requests are only validated when it is set.
+	Endpoint string `yaml:"endpoint"`
+	// RewriteErrors replaces downstream error responses that are not NACKs with a NACK
+	// carrying the same status.
+	RewriteErrors bool `yaml:"rewriteErrors"`
+	// MaxBodySize bounds the response body buffered for validation, in bytes. Larger
+	// responses are rejected. Defaults to 1 MiB.
+	MaxBodySize int64 `yaml:"maxBodySize"`
+}
+
+// ProxyStreamingConfig holds settings for streaming the responses of requests forwarded
+// in proxy mode to the client as they arrive, without buffering them.
+type ProxyStreamingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// BufferSize is the size of the buffer the response body is copied through, in
+	// bytes. Defaults to 32 KiB.
+	BufferSize int `yaml:"bufferSize"`
+	// FlushInterval is how often the copied response is flushed to the client. A
+	// negative value flushes after each write. Defaults to 100ms.
+	FlushInterval time.Duration `yaml:"flushInterval"`
+}
+
+// TimestampAction defines how the normalizeTimestamp step treats a context timestamp
+// that is not an RFC 3339 time in UTC.
+type TimestampAction string
+
+const (
+	// TimestampRewrite rewrites the timestamp in RFC 3339 form in UTC and logs a warning.
+	TimestampRewrite TimestampAction = "rewrite"
+	// TimestampReject rejects the request with a BadRequest NACK.
+	TimestampReject TimestampAction = "reject"
+)
+
+// NormalizeTimestampConfig holds settings for the normalizeTimestamp step, which
+// rewrites context.timestamp in RFC 3339 form in UTC.
+type NormalizeTimestampConfig struct {
+	// NonConforming selects how timestamps that are not RFC 3339 times in UTC are
+	// treated. Defaults to rewrite. Timestamps that cannot be parsed are always rejected.
+	NonConforming TimestampAction `yaml:"nonConforming"`
+}
+
+// DedupAction defines how the dedup step answers a duplicate message.
+type DedupAction string
+
+const (
+	// DedupAck acknowledges the duplicate without processing it.
+	DedupAck DedupAction = "ack"
+	// DedupNack rejects the duplicate with a BadRequest NACK.
+	DedupNack DedupAction = "nack"
+)
+
+// DedupConfig holds settings for the dedup step.
+type DedupConfig struct {
+	// Window is how long a message_id is remembered. Defaults to 10s.
+	Window time.Duration `yaml:"window"`
+
+	// Duplicates selects the response to duplicates. Defaults to ack.
+	Duplicates DedupAction `yaml:"duplicates"`
+}
+
+// OrphanCallbackAction defines how the validateCallbackCorrelation step treats a
+// callback with no matching request.
+type OrphanCallbackAction string
+
+const (
+	// OrphanCallbackLog logs the orphan callback and processes it.
+	OrphanCallbackLog OrphanCallbackAction = "log"
+	// OrphanCallbackNack rejects the orphan callback with a NACK.
+	OrphanCallbackNack OrphanCallbackAction = "nack"
+)
+
+// CallbackCorrelationConfig holds settings for the validateCallbackCorrelation step.
+type CallbackCorrelationConfig struct {
+	// TTL is how long a request is remembered for its callbacks. Defaults to 1h.
+	TTL time.Duration `yaml:"ttl"`
+
+	// OrphanCallbacks selects the treatment of callbacks with no matching request.
+	// Defaults to log.
+	OrphanCallbacks OrphanCallbackAction `yaml:"orphanCallbacks"`
+}
+
+// PublishBackpressureConfig bounds how long a request publishing in proxy mode waits for
+// room in a full publisher queue before it is NACKed.
+type PublishBackpressureConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Timeout is how long to wait for the queue to take the message. Zero NACKs as soon
+	// as the queue is full.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// PublishQueueConfig holds settings for publishing in proxy mode from a pool of
+// background workers, so that requests are ACKed as soon as their message is queued.
+type PublishQueueConfig struct {
+	// Workers is the number of workers publishing queued messages. Zero turns the queue off.
+	Workers int `yaml:"workers"`
+
+	// Depth is the number of messages the queue holds. Requests finding it full are
+	// NACKed with 503. Defaults to 100 per worker.
+	Depth int `yaml:"depth"`
+}
+
+// ResponseSigningConfig holds settings for signing the responses of requests forwarded
+// in proxy mode with the keys of the module's subscriber.
+type ResponseSigningConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Algorithm is the signature algorithm used when the request's Accept-Signature
+	// header names none the signer supports. Defaults to ed25519.
+	Algorithm string `yaml:"algorithm"`
+	// MaxBodySize bounds the response body buffered for signing, in bytes. Larger
+	// responses are rejected. Defaults to 1 MiB.
+	MaxBodySize int64 `yaml:"maxBodySize"`
+}
+
+// SubscriberAccessConfig holds the subscriber allow and deny lists of the
+// checkSubscriberAccess step. Entries are subscriber IDs or glob patterns such as
+// "*.example.com", matched with path.Match. A subscriber matching the deny list is
+// always rejected; when Allow is not empty, only subscribers matching it are let in.
+type SubscriberAccessConfig struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// GlobalRateLimitConfig holds settings for the globalRateLimit step, which caps the
+// request rate of the whole process with a token bucket shared by every module that
+// names it.
+type GlobalRateLimitConfig struct {
+	// Rate is the sustained number of requests per second let through.
+	Rate float64 `yaml:"rate"`
+
+	// Burst is the number of requests let through at once after a quiet period. Defaults
+	// to Rate, rounded up.
+	Burst int `yaml:"burst"`
+
+	// Bucket names the token bucket. Modules naming the same bucket share it and must
+	// configure the same rate and burst. Defaults to "global".
+	Bucket string `yaml:"bucket"`
+}
+
+// ProxyRetryConfig defines retries of requests forwarded in proxy mode whose downstream
+// responds with a retryable status. Only idempotent requests are retried.
+type ProxyRetryConfig struct {
+	// MaxAttempts is the total number of attempts. Values below 2 disable retries.
+	MaxAttempts int `yaml:"maxAttempts"`
+
+	// RetryableStatuses are the downstream statuses retried. Defaults to 502, 503 and
+	// 504; transport errors are answered with 502.
+	RetryableStatuses []int `yaml:"retryableStatuses,omitempty"`
+
+	// Actions are the Beckn actions safe to send more than once. Requests are also
+	// retried when their method is idempotent or they carry an Idempotency-Key header.
+	Actions []string `yaml:"actions,omitempty"`
+
+	// InitialBackoff is the wait before the first retry; it doubles on each
+	// subsequent attempt. Defaults to 100ms.
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+
+	// MaxBackoff caps the wait between attempts. Defaults to 2s.
+	MaxBackoff time.Duration `yaml:"maxBackoff"`
+}
+
+// ForwardTimeoutRule overrides the timeout of requests forwarded on matching routes.
+// An empty URL or Action matches any value.
+type ForwardTimeoutRule struct {
+	// URL matches target URLs starting with it, such as the base URL of a BPP.
+	URL string `yaml:"url,omitempty"`
+
+	// Action matches the Beckn action of the request.
+	Action string `yaml:"action,omitempty"`
+
+	// Timeout bounds matching forwarded requests.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// ForwardTimeoutsConfig bounds requests forwarded to URL targets, in proxy mode and
+// asynchronously, so that endpoints with different latency profiles get different
+// timeouts. A request deadline still applies to proxied requests.
+type ForwardTimeoutsConfig struct {
+	// Default bounds forwarded requests no rule matches. Zero leaves them unbounded.
+	Default time.Duration `yaml:"default"`
+
+	// Rules override the default; the first matching rule wins.
+	Rules []ForwardTimeoutRule `yaml:"rules,omitempty"`
+}
+
+// HeaderLimitsConfig bounds the headers of incoming requests. Requests exceeding a limit
+// are rejected with 400 before their body is read.
+type HeaderLimitsConfig struct {
+	// MaxSize caps the total size of the header fields in bytes, counting each name and
+	// value. Zero means unlimited.
+	MaxSize int `yaml:"maxSize"`
+
+	// MaxCount caps the number of header fields, counting each value of a repeated
+	// header. Zero means unlimited.
+	MaxCount int `yaml:"maxCount"`
+}
+
+// ContentTypeConfig holds settings for the validateContentType step.
+type ContentTypeConfig struct {
+	// Allowed lists the accepted media types. Defaults to application/json.
+	Allowed []string `yaml:"allowed,omitempty"`
+
+	// Charsets lists the accepted charset parameters, compared case-insensitively.
+	// Requests without a charset are accepted. Defaults to utf-8.
+	Charsets []string `yaml:"charsets,omitempty"`
+
+	// AllowMissing accepts requests without a Content-Type header.
+	AllowMissing bool `yaml:"allowMissing"`
+}
+
+// RoleActionsConfig holds settings for the validateActionForRole step, which rejects
+// actions the role a request is processed for must not receive.
+type RoleActionsConfig struct {
+	// Allowed lists the actions each role accepts. An entry ending in * matches every
+	// action starting with the rest of it, such as on_* for all callbacks. Roles without
+	// a list accept no actions.
+	Allowed map[model.Role][]string `yaml:"allowed"`
+}
+
+// ServiceabilityConfig holds settings for the validateServiceability step, which
+// rejects requests for regions the network participant does not serve.
+type ServiceabilityConfig struct {
+	// Regions lists the served regions. Requests whose context names a country or
+	// city not listed are rejected.
+	Regions []ServedRegion `yaml:"regions"`
+}
+
+// ServedRegion is a country served in whole or in the listed cities.
+type ServedRegion struct {
+	// Country is the country code, as in context.location.country.code, e.g. IND.
+	Country string `yaml:"country"`
+
+	// Cities lists the city codes served in the country, as in
+	// context.location.city.code, e.g. std:080. Empty or * serves every city.
+	Cities []string `yaml:"cities,omitempty"`
+}
+
+// Fault types injected by the injectFault step.
+const (
+	// FaultDelay holds matching requests for FaultRule.Delay before processing them.
+	FaultDelay = "delay"
+	// FaultNack answers matching requests with a 400 NACK.
+	FaultNack = "nack"
+	// FaultError answers matching requests with a 500 internal server error.
+	FaultError = "error"
+)
+
+// FaultInjectionConfig holds settings for the injectFault step, which injects faults
+// into a share of matching requests so that clients can be tested against them. It is
+// meant for test environments only and injects nothing unless Enabled is set.
+type FaultInjectionConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Rules are tried in order; the first matching a request decides its fault.
+	Rules []FaultRule `yaml:"rules"`
+}
+
+// FaultRule injects a fault into a share of the requests it matches.
+type FaultRule struct {
+	// Actions limits the rule to requests of these context actions; empty matches all.
+	Actions []string `yaml:"actions,omitempty"`
+
+	// Subscribers limits the rule to requests whose context names one of these senders,
+	// bap_id for requests and bpp_id for on_* callbacks. Entries may be glob patterns
+	// such as "*.example.com"; empty matches all.
+	Subscribers []string `yaml:"subscribers,omitempty"`
+
+	// Type is the fault injected: delay, nack or error.
+	Type string `yaml:"type"`
+
+	// Probability is the share of matching requests the fault is injected into, from 0
+	// to 1. Faults are spread evenly rather than at random, so that exactly that share
+	// of any run of matching requests is affected.
+	Probability float64 `yaml:"probability"`
+
+	// Delay is how long delay faults hold requests.
+	Delay time.Duration `yaml:"delay,omitempty"`
+}
+
+// WarmUpConfig lists the work done before the handler accepts traffic. Until it
+// completes the handler responds 503 and is not ready; failed targets are logged
+// and do not keep the handler closed.
+type WarmUpConfig struct {
+	// Schemas are passed to a SchemaValidator implementing definition.Warmer,
+	// e.g. the extended schema @context URLs compiled by schemav2validator.
+	Schemas []string `yaml:"schemas,omitempty"`
+
+	// Keys are the subscriber keys prefetched through the KeyManager.
+	Keys []WarmUpKey `yaml:"keys,omitempty"`
+
+	// Timeout bounds the warm-up; once it expires the handler accepts traffic. Defaults to 30s.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// WarmUpKey identifies a subscriber key to prefetch.
+type WarmUpKey struct {
+	SubscriberID string `yaml:"subscriberId"`
+	KeyID        string `yaml:"keyId"`
+}
+
+// Default cookie names set by the workbench to steer request processing.
+const (
+	DefaultSubscriberIDCookie       = "subscriber_id"
+	DefaultHeaderValidationCookie   = "header_validation"
+	DefaultProtocolValidationCookie = "protocol_validation"
+)
+
+// CookieConfig holds the names of the request cookies read by the processing steps.
+// Empty fields fall back to the Default*Cookie names.
+type CookieConfig struct {
+	// SubscriberID carries the subscriber ID extracted by ondcWorkbenchReceiver.
+	SubscriberID string `yaml:"subscriberId"`
+	// HeaderValidation disables validateSign when set to "false".
+	HeaderValidation string `yaml:"headerValidation"`
+	// ProtocolValidation disables validateOndcPayload when set to "false".
+	ProtocolValidation string `yaml:"protocolValidation"`
+}
+
+// withDefaults returns a copy of c with empty cookie names set to their defaults.
+func (c CookieConfig) withDefaults() CookieConfig {
+	if c.SubscriberID == "" {
+		c.SubscriberID = DefaultSubscriberIDCookie
+	}
+	if c.HeaderValidation == "" {
+		c.HeaderValidation = DefaultHeaderValidationCookie
+	}
+	if c.ProtocolValidation == "" {
+		c.ProtocolValidation = DefaultProtocolValidationCookie
+	}
+	return c
+}
+
 // Config holds the configuration for request processing handlers.
 type Config struct {
 	Plugins          PluginCfg `yaml:"plugins"`
@@ -81,4 +864,52 @@ type Config struct {
 	Role             model.Role
 	SubscriberID     string           `yaml:"subscriberId"`
 	HttpClientConfig HttpClientConfig `yaml:"httpClientConfig"`
+	// PublisherRules resolve the publisher ID when the router leaves it unset.
+	PublisherRules []PublisherRule    `yaml:"publisherRules,omitempty"`
+	PublishRetry   PublishRetryConfig `yaml:"publishRetry"`
+	Ondc           OndcConfig         `yaml:"ondc"`
+	Cookies        CookieConfig       `yaml:"cookies"`
+	Workbench      WorkbenchConfig    `yaml:"workbench"`
+	BodyLogging    BodyLogConfig      `yaml:"bodyLogging"`
+	WarmUp         WarmUpConfig       `yaml:"warmUp"`
+	BodyBuffer     BodyBufferConfig   `yaml:"bodyBuffer"`
+	// PreserveContentEncoding keeps the Content-Encoding header of a request whose body
+	// a step modified. By default it is dropped, as steps work on decoded bodies.
+	PreserveContentEncoding bool                         `yaml:"preserveContentEncoding"`
+	RequestDeadline         RequestDeadlineConfig        `yaml:"requestDeadline"`
+	TrustedHeaders          TrustedHeadersConfig         `yaml:"trustedHeaders"`
+	Concurrency             ConcurrencyConfig            `yaml:"concurrency"`
+	Shadow                  ShadowConfig                 `yaml:"shadow"`
+	Maintenance             MaintenanceConfig            `yaml:"maintenance"`
+	ResponseCache           ResponseCacheConfig          `yaml:"responseCache"`
+	InstrumentationHeaders  InstrumentationHeadersConfig `yaml:"instrumentationHeaders"`
+	Multipart               MultipartConfig              `yaml:"multipart"`
+	Timestamp               TimestampConfig              `yaml:"timestamp"`
+	ErrorDetail             ErrorDetailConfig            `yaml:"errorDetail"`
+	Signature               SignatureConfig              `yaml:"signature"`
+	EnrichRegistry          EnrichRegistryConfig         `yaml:"enrichRegistry"`
+	NoRoute                 NoRouteConfig                `yaml:"noRoute"`
+	NormalizeContext        NormalizeContextConfig       `yaml:"normalizeContext"`
+	// BodyFormats lists the non-JSON body formats accepted, by media type, such as
+	// "application/cbor". Steps see such bodies in their JSON form.
+	BodyFormats           []string                    `yaml:"bodyFormats"`
+	SubscriberConsistency SubscriberConsistencyConfig `yaml:"subscriberConsistency"`
+	ResponseValidation    ResponseValidationConfig    `yaml:"responseValidation"`
+	Dedup                 DedupConfig                 `yaml:"dedup"`
+	PublishBackpressure   PublishBackpressureConfig   `yaml:"publishBackpressure"`
+	GlobalRateLimit       GlobalRateLimitConfig       `yaml:"globalRateLimit"`
+	ProxyRetry            ProxyRetryConfig            `yaml:"proxyRetry"`
+	RoleActions           RoleActionsConfig           `yaml:"roleActions"`
+	ForwardTimeouts       ForwardTimeoutsConfig       `yaml:"forwardTimeouts"`
+	HeaderLimits          HeaderLimitsConfig          `yaml:"headerLimits"`
+	CallbackCorrelation   CallbackCorrelationConfig   `yaml:"callbackCorrelation"`
+	ContentType           ContentTypeConfig           `yaml:"contentType"`
+	PublishQueue          PublishQueueConfig          `yaml:"publishQueue"`
+	ResponseSigning       ResponseSigningConfig       `yaml:"responseSigning"`
+	SubscriberAccess      SubscriberAccessConfig      `yaml:"subscriberAccess"`
+	Serviceability        ServiceabilityConfig        `yaml:"serviceability"`
+	FaultInjection        FaultInjectionConfig        `yaml:"faultInjection"`
+	PathRoles             []PathRoleConfig            `yaml:"pathRoles,omitempty"`
+	NormalizeTimestamp    NormalizeTimestampConfig    `yaml:"normalizeTimestamp"`
+	ProxyStreaming        ProxyStreamingConfig        `yaml:"proxyStreaming"`
 }
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// Defaults of the validateContentType step.
+var (
+	defaultContentTypes = []string{"application/json"}
+	defaultCharsets     = []string{"utf-8"}
+)
+
+// validateContentTypeStep rejects requests whose Content-Type is not an accepted media
+// type and charset, so that bodies in another format are not mistaken for JSON.
+type validateContentTypeStep struct {
+	mediaTypes   map[string]bool
+	charsets     map[string]bool
+	allowMissing bool
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ContentTypeConfig) withDefaults() ContentTypeConfig {
+	if len(cfg.Allowed) == 0 {
+		cfg.Allowed = defaultContentTypes
+	}
+	if len(cfg.Charsets) == 0 {
+		cfg.Charsets = defaultCharsets
+	}
+	return cfg
+}
+
+// newValidateContentTypeStep creates and returns the validateContentType step.
+func newValidateContentTypeStep(cfg ContentTypeConfig) (definition.Step, error) {
+	cfg = cfg.withDefaults()
+	allowed, charsets := cfg.Allowed, cfg.Charsets
+	s := &validateContentTypeStep{
+		mediaTypes:   make(map[string]bool, len(allowed)),
+		charsets:     make(map[string]bool, len(charsets)),
+		allowMissing: cfg.AllowMissing,
+	}
+	for _, ct := range allowed {
+		mediaType, params, err := mime.ParseMediaType(ct)
+		if err != nil || len(params) > 0 || !strings.Contains(mediaType, "/") {
+			return nil, fmt.Errorf("invalid config: contentType allowed: invalid media type %q", ct)
+		}
+		s.mediaTypes[mediaType] = true
+	}
+	for _, c := range charsets {
+		s.charsets[strings.ToLower(c)] = true
+	}
+	return s, nil
+}
+
+// Run checks the Content-Type header of the request.
+func (s *validateContentTypeStep) Run(ctx *model.StepContext) error {
+	contentType := ctx.Request.Header.Get("Content-Type")
+	if contentType == "" {
+		if s.allowMissing {
+			return nil
+		}
+		return model.NewBadReqErr(errors.New("missing Content-Type header"))
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return model.NewBadReqErr(fmt.Errorf("invalid Content-Type %q: %w", contentType, err))
+	}
+	if !s.mediaTypes[mediaType] {
+		return model.NewBadReqErr(fmt.Errorf("unsupported Content-Type %s", mediaType))
+	}
+	if charset, ok := params["charset"]; ok && !s.charsets[strings.ToLower(charset)] {
+		return model.NewBadReqErr(fmt.Errorf("unsupported charset %s", charset))
+	}
+	return nil
+}
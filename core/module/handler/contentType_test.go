@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+func TestValidateContentTypeStep(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         ContentTypeConfig
+		contentType string
+		wantErr     string
+	}{
+		{name: "json", contentType: "application/json"},
+		{name: "json with charset", contentType: "application/json; charset=UTF-8"},
+		{name: "media type is case-insensitive", contentType: "Application/JSON"},
+		{name: "unacceptable type", contentType: "text/plain", wantErr: "unsupported Content-Type text/plain"},
+		{name: "form", contentType: "application/x-www-form-urlencoded", wantErr: "unsupported Content-Type application/x-www-form-urlencoded"},
+		{name: "unacceptable charset", contentType: "application/json; charset=iso-8859-1", wantErr: "unsupported charset iso-8859-1"},
+		{name: "malformed", contentType: "application/json; charset", wantErr: "invalid Content-Type"},
+		{name: "missing", wantErr: "missing Content-Type header"},
+		{name: "missing allowed", cfg: ContentTypeConfig{AllowMissing: true}},
+		{
+			name:        "configured types",
+			cfg:         ContentTypeConfig{Allowed: []string{"application/json", "application/cbor"}},
+			contentType: "application/cbor",
+		},
+		{
+			name:        "configured charsets",
+			cfg:         ContentTypeConfig{Charsets: []string{"UTF-8", "us-ascii"}},
+			contentType: "application/json; charset=US-ASCII",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newValidateContentTypeStep(tt.cfg)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			err = step.Run(newTestStepCtx(req, []byte(`{}`)))
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var badReq *model.BadReqErr
+			require.True(t, errors.As(err, &badReq), "got %v", err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewValidateContentTypeStep(t *testing.T) {
+	_, err := newValidateContentTypeStep(ContentTypeConfig{Allowed: []string{"application/json; charset=utf-8"}})
+	assert.ErrorContains(t, err, `invalid media type "application/json; charset=utf-8"`)
+
+	_, err = newValidateContentTypeStep(ContentTypeConfig{Allowed: []string{"json"}})
+	assert.ErrorContains(t, err, `invalid media type "json"`)
+}
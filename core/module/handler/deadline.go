@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+)
+
+// withRequestDeadline returns r with a context that expires when the sender stops waiting,
+// as stated in the configured deadline header and capped at the configured maximum.
+// Requests without a valid header are returned unchanged. The returned cancel func
+// must be called once the request has been served.
+func (h *stdHandler) withRequestDeadline(r *http.Request) (*http.Request, context.CancelFunc) {
+	if h.deadline.Header == "" {
+		return r, func() {}
+	}
+	v := r.Header.Get(h.deadline.Header)
+	if v == "" {
+		return r, func() {}
+	}
+	timeout, err := parseRequestTimeout(v)
+	if err != nil {
+		log.Debugf(r.Context(), "Ignoring %s header: %v", h.deadline.Header, err)
+		return r, func() {}
+	}
+	if h.deadline.Max > 0 && timeout > h.deadline.Max {
+		timeout = h.deadline.Max
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return r.WithContext(ctx), cancel
+}
+
+// parseRequestTimeout parses a positive timeout given in seconds or as a duration string.
+func parseRequestTimeout(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		secs, serr := strconv.ParseFloat(v, 64)
+		if serr != nil || math.IsNaN(secs) || secs > math.MaxInt64/float64(time.Second) {
+			return 0, fmt.Errorf("invalid timeout %q", v)
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("non-positive timeout %q", v)
+	}
+	return d, nil
+}
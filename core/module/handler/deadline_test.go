@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPRequestDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         RequestDeadlineConfig
+		header      string
+		wantTimeout time.Duration // zero means no deadline
+	}{
+		{
+			name:        "seconds",
+			cfg:         RequestDeadlineConfig{Header: "X-Request-Timeout"},
+			header:      "2",
+			wantTimeout: 2 * time.Second,
+		},
+		{
+			name:        "duration",
+			cfg:         RequestDeadlineConfig{Header: "X-Request-Timeout"},
+			header:      "1500ms",
+			wantTimeout: 1500 * time.Millisecond,
+		},
+		{
+			name:        "capped at max",
+			cfg:         RequestDeadlineConfig{Header: "X-Request-Timeout", Max: time.Second},
+			header:      "60",
+			wantTimeout: time.Second,
+		},
+		{
+			name:        "below max",
+			cfg:         RequestDeadlineConfig{Header: "X-Request-Timeout", Max: time.Minute},
+			header:      "3",
+			wantTimeout: 3 * time.Second,
+		},
+		{
+			name: "header missing",
+			cfg:  RequestDeadlineConfig{Header: "X-Request-Timeout", Max: time.Second},
+		},
+		{
+			name:   "invalid header",
+			cfg:    RequestDeadlineConfig{Header: "X-Request-Timeout", Max: time.Second},
+			header: "soon",
+		},
+		{
+			name:   "non-positive header",
+			cfg:    RequestDeadlineConfig{Header: "X-Request-Timeout"},
+			header: "-5",
+		},
+		{
+			name:   "disabled",
+			header: "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline time.Time
+			var hasDeadline bool
+			h := &stdHandler{
+				deadline: tt.cfg,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					deadline, hasDeadline = ctx.Deadline()
+					return nil
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+			if tt.header != "" {
+				req.Header.Set("X-Request-Timeout", tt.header)
+			}
+			start := time.Now()
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantTimeout == 0 {
+				assert.False(t, hasDeadline)
+				return
+			}
+			require.True(t, hasDeadline)
+			assert.WithinDuration(t, start.Add(tt.wantTimeout), deadline, 500*time.Millisecond)
+		})
+	}
+}
+
+func TestServeHTTPRequestDeadlineAbortsForward(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer upstream.Close()
+	defer close(release)
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	h := &stdHandler{
+		httpClient: upstream.Client(),
+		deadline:   RequestDeadlineConfig{Header: "X-Request-Timeout", Max: 50 * time.Millisecond},
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+			return nil
+		})},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+	req.Header.Set("X-Request-Timeout", "30")
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(rec, req)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("forward was not aborted at the request deadline")
+	}
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestServeHTTPRequestDeadlineSparesAsyncForward(t *testing.T) {
+	forwarded := make(chan struct{}, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded <- struct{}{}
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	h := &stdHandler{
+		httpClient: upstream.Client(),
+		deadline:   RequestDeadlineConfig{Header: "X-Request-Timeout"},
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: target}
+			return nil
+		})},
+	}
+	req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`)))
+	req.Header.Set("X-Request-Timeout", "1ms")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Run the hooks once the request deadline has passed.
+	time.Sleep(10 * time.Millisecond)
+	for _, hook := range *hooks {
+		hook()
+	}
+	select {
+	case <-forwarded:
+	default:
+		t.Fatal("async forward did not reach the target")
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// defaultDedupWindow is used when DedupConfig.Window is unset.
+const defaultDedupWindow = 10 * time.Second
+
+// errAcked is returned by steps that answer the request with an ACK and stop it from
+// being processed further.
+var errAcked = errors.New("request acknowledged without processing")
+
+// dedupStep drops repeats of a message_id seen within a short window, such as the
+// double-fires of a sender retrying too eagerly. Unlike the response cache it keeps no
+// response; a duplicate is answered with a bare ACK or NACK.
+type dedupStep struct {
+	cache  definition.Cache
+	window time.Duration
+	nack   bool
+	prefix string
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg DedupConfig) withDefaults() DedupConfig {
+	if cfg.Window <= 0 {
+		cfg.Window = defaultDedupWindow
+	}
+	return cfg
+}
+
+// newDedupStep creates and returns the dedup step. It requires the Cache plugin.
+func newDedupStep(cache definition.Cache, cfg DedupConfig, moduleName string) (definition.Step, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("invalid config: Cache plugin not configured")
+	}
+	cfg = cfg.withDefaults()
+	s := &dedupStep{cache: cache, window: cfg.Window, prefix: "onix:dedup:" + moduleName + ":"}
+	switch cfg.Duplicates {
+	case "", DedupAck:
+	case DedupNack:
+		s.nack = true
+	default:
+		return nil, fmt.Errorf("invalid config: unknown dedup duplicates action %q", cfg.Duplicates)
+	}
+	return s, nil
+}
+
+// Run records the message_id of the request, rejecting it if it was already recorded
+// within the window. The message_id is scoped to the action and sender, since every
+// callback to a request shares its message_id. Requests pass when the cache cannot be
+// written.
+func (s *dedupStep) Run(ctx *model.StepContext) error {
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	if bCtx.MessageID == "" {
+		return nil
+	}
+	sender := bCtx.BapID
+	if strings.HasPrefix(bCtx.Action, "on_") {
+		sender = bCtx.BppID
+	}
+	key := s.prefix + sender + ":" + bCtx.Action + ":" + bCtx.MessageID
+	if v, err := s.cache.Get(ctx, key); err == nil && v != "" {
+		if s.nack {
+			return model.NewBadReqErr(fmt.Errorf("duplicate message_id %s", bCtx.MessageID))
+		}
+		log.Infof(ctx, "Acknowledging duplicate message_id %s without processing it", bCtx.MessageID)
+		return errAcked
+	}
+	if err := s.cache.Set(ctx, key, "1", s.window); err != nil {
+		log.Warnf(ctx, "Failed to record message_id %s for deduplication: %v", bCtx.MessageID, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+func TestDedupStep(t *testing.T) {
+	search := `{"context":{"action":"search","bap_id":"bap.example.com","message_id":"m1"}}`
+	type call struct {
+		body    string
+		advance time.Duration
+		wantErr error
+	}
+	tests := []struct {
+		name  string
+		cfg   DedupConfig
+		calls []call
+	}{
+		{
+			name:  "first seen",
+			calls: []call{{body: search}},
+		},
+		{
+			name:  "duplicate within window is acknowledged",
+			cfg:   DedupConfig{Window: time.Minute},
+			calls: []call{{body: search}, {body: search, advance: 30 * time.Second, wantErr: errAcked}},
+		},
+		{
+			name:  "duplicate after window is processed",
+			cfg:   DedupConfig{Window: time.Minute},
+			calls: []call{{body: search}, {body: search, advance: time.Minute}},
+		},
+		{
+			name:  "default window",
+			calls: []call{{body: search}, {body: search, advance: defaultDedupWindow - time.Second, wantErr: errAcked}},
+		},
+		{
+			name: "callbacks from different senders are distinct",
+			calls: []call{
+				{body: `{"context":{"action":"on_search","bpp_id":"bpp1.example.com","message_id":"m1"}}`},
+				{body: `{"context":{"action":"on_search","bpp_id":"bpp2.example.com","message_id":"m1"}}`},
+			},
+		},
+		{
+			name: "callbacks reuse the message_id of the request",
+			calls: []call{
+				{body: search},
+				{body: `{"context":{"action":"on_search","bap_id":"bap.example.com","bpp_id":"bpp.example.com","message_id":"m1"}}`},
+			},
+		},
+		{
+			name:  "requests without message_id pass",
+			calls: []call{{body: `{"context":{"action":"search"}}`}, {body: `{"context":{"action":"search"}}`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newMemCache()
+			step, err := newDedupStep(cache, tt.cfg, "bapTxnReceiver")
+			require.NoError(t, err)
+			for i, c := range tt.calls {
+				cache.now = cache.now.Add(c.advance)
+				err := step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(c.body)))
+				if c.wantErr == nil {
+					assert.NoError(t, err, "call %d", i)
+				} else {
+					assert.ErrorIs(t, err, c.wantErr, "call %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDedupStepNack(t *testing.T) {
+	step, err := newDedupStep(newMemCache(), DedupConfig{Duplicates: DedupNack}, "bapTxnReceiver")
+	require.NoError(t, err)
+	body := []byte(`{"context":{"action":"search","bap_id":"bap.example.com","message_id":"m1"}}`)
+
+	require.NoError(t, step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), body)))
+	err = step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), body))
+
+	var badReq *model.BadReqErr
+	require.True(t, errors.As(err, &badReq))
+	assert.ErrorContains(t, err, "duplicate message_id m1")
+}
+
+func TestNewDedupStep(t *testing.T) {
+	_, err := newDedupStep(nil, DedupConfig{}, "m")
+	assert.ErrorContains(t, err, "Cache plugin not configured")
+
+	_, err = newDedupStep(newMemCache(), DedupConfig{Duplicates: "drop"}, "m")
+	assert.ErrorContains(t, err, `unknown dedup duplicates action "drop"`)
+}
+
+func TestServeHTTPAcksDuplicates(t *testing.T) {
+	step, err := newDedupStep(newMemCache(), DedupConfig{}, "bapTxnReceiver")
+	require.NoError(t, err)
+	var routed int
+	h := &stdHandler{steps: []definition.Step{step, stepFunc(func(ctx *model.StepContext) error {
+		routed++
+		return nil
+	})}}
+	body := `{"context":{"action":"search","bap_id":"bap.example.com","message_id":"m1"}}`
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"message":{"ack":{"status":"ACK"}}}`, rec.Body.String())
+	}
+	assert.Equal(t, 1, routed, "the duplicate must not be processed")
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigReporter is implemented by handlers that can report the configuration they run
+// with.
+type ConfigReporter interface {
+	// EffectiveConfig returns the configuration with defaults filled in, runtime changes
+	// applied and secrets redacted, keyed as in the config file.
+	EffectiveConfig() (map[string]any, error)
+}
+
+// withDefaults returns a copy of cfg with the defaults the handler applies to unset
+// settings filled in, as resolved by each component.
+func withDefaults(cfg Config) Config {
+	cfg.Maintenance = cfg.Maintenance.withDefaults()
+	cfg.ErrorDetail = cfg.ErrorDetail.withDefaults()
+	cfg.InstrumentationHeaders = cfg.InstrumentationHeaders.withDefaults()
+	cfg.NoRoute = cfg.NoRoute.withDefaults()
+	cfg.ContentType = cfg.ContentType.withDefaults()
+	cfg.Dedup = cfg.Dedup.withDefaults()
+	cfg.CallbackCorrelation = cfg.CallbackCorrelation.withDefaults()
+	cfg.Concurrency = cfg.Concurrency.withDefaults()
+	cfg.Shadow = cfg.Shadow.withDefaults()
+	cfg.WarmUp = cfg.WarmUp.withDefaults()
+	cfg.Timestamp = cfg.Timestamp.withDefaults()
+	cfg.EnrichRegistry = cfg.EnrichRegistry.withDefaults()
+	cfg.SubscriberConsistency = cfg.SubscriberConsistency.withDefaults()
+	cfg.ProxyRetry = cfg.ProxyRetry.withDefaults()
+	cfg.PublishRetry = cfg.PublishRetry.withDefaults()
+	cfg.ResponseCache = cfg.ResponseCache.withDefaults()
+	cfg.ResponseValidation = cfg.ResponseValidation.withDefaults()
+	cfg.ResponseSigning = cfg.ResponseSigning.withDefaults()
+	cfg.NormalizeTimestamp = cfg.NormalizeTimestamp.withDefaults()
+	cfg.ProxyStreaming = cfg.ProxyStreaming.withDefaults()
+	cfg.Signature = cfg.Signature.withDefaults()
+	return cfg
+}
+
+// EffectiveConfig returns the configuration of the handler with defaults filled in, the
+// runtime state of maintenance mode and the subscriber access lists applied, and secrets
+// redacted.
+func (h *stdHandler) EffectiveConfig() (map[string]any, error) {
+	cfg := h.resolvedCfg
+	cfg.Maintenance.Enabled = h.Maintenance()
+	if access, ok := h.SubscriberAccess(); ok {
+		cfg.SubscriberAccess = access
+	}
+	return exportConfig(cfg)
+}
+
+// exportConfig converts cfg to a tree of maps keyed as in the config file, which
+// marshals to JSON, and redacts its secrets.
+func exportConfig(cfg Config) (map[string]any, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	redactConfig(tree)
+	return tree, nil
+}
+
+// redactConfig replaces secrets in a config tree with redactedValue: sensitive plugin
+// config values, the debug token and passwords in URLs.
+func redactConfig(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			switch val := val.(type) {
+			case string:
+				v[k] = redactURLPassword(val)
+			case map[string]any:
+				if k == "config" {
+					redactPluginConfigTree(val)
+					continue
+				}
+				redactConfig(val)
+			default:
+				redactConfig(val)
+			}
+		}
+		if token, ok := v["debugToken"].(string); ok && token != "" {
+			v["debugToken"] = redactedValue
+		}
+	case []any:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				v[i] = redactURLPassword(s)
+				continue
+			}
+			redactConfig(item)
+		}
+	}
+}
+
+// redactPluginConfigTree redacts the sensitive values of a plugin config in place.
+func redactPluginConfigTree(cfg map[string]any) {
+	values := make(map[string]string, len(cfg))
+	for k, v := range cfg {
+		values[k] = fmt.Sprint(v)
+	}
+	for k, v := range redactPluginConfig(values) {
+		if v == redactedValue {
+			cfg[k] = v
+		} else if s, ok := cfg[k].(string); ok {
+			cfg[k] = redactURLPassword(s)
+		}
+	}
+}
+
+// redactURLPassword returns s with the password redacted if it is a URL carrying one.
+func redactURLPassword(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	return u.Redacted()
+}
+
+// ConfigHandler returns an http.Handler for the /debug/config endpoint that reports
+// the effective configuration of each module, with secrets redacted.
+func ConfigHandler(reporters map[string]ConfigReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp := make(map[string]map[string]any, len(reporters))
+		for name, rep := range reporters {
+			cfg, err := rep.EffectiveConfig()
+			if err != nil {
+				http.Error(w, name+": "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+			resp[name] = cfg
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/netip"
+
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+// defaultDebugTokenHeader carries the debug token when ErrorDetailConfig.DebugTokenHeader is unset.
+const defaultDebugTokenHeader = "X-Debug-Token"
+
+// errorDetail decides which callers get detailed NACK errors.
+type errorDetail struct {
+	networks []netip.Prefix
+	token    string
+	header   string
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ErrorDetailConfig) withDefaults() ErrorDetailConfig {
+	if cfg.DebugTokenHeader == "" {
+		cfg.DebugTokenHeader = defaultDebugTokenHeader
+	}
+	return cfg
+}
+
+// newErrorDetail creates an errorDetail from cfg. It returns nil when error detail is not restricted.
+func newErrorDetail(cfg ErrorDetailConfig) (*errorDetail, error) {
+	if !cfg.Restrict {
+		return nil, nil
+	}
+	networks, err := parseTrustedNetworks(cfg.TrustedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	return &errorDetail{networks: networks, token: cfg.DebugToken, header: cfg.withDefaults().DebugTokenHeader}, nil
+}
+
+// scope returns r marked for generic NACK errors unless it comes from a trusted network
+// or carries the debug token. The debug token header is removed so it is not forwarded.
+func (d *errorDetail) scope(r *http.Request) *http.Request {
+	if d == nil {
+		return r
+	}
+	token := r.Header.Get(d.header)
+	r.Header.Del(d.header)
+	if inNetworks(d.networks, r.RemoteAddr) {
+		return r
+	}
+	if d.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(d.token)) == 1 {
+		return r
+	}
+	return r.WithContext(response.WithGenericErrors(r.Context()))
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPErrorDetail(t *testing.T) {
+	restricted := ErrorDetailConfig{Restrict: true, TrustedNetworks: []string{"10.0.0.0/8"}, DebugToken: "s3cret"}
+	tests := []struct {
+		name         string
+		cfg          ErrorDetailConfig
+		remoteAddr   string
+		token        string
+		wantDetailed bool
+	}{
+		{name: "unrestricted", remoteAddr: "203.0.113.7:443", wantDetailed: true},
+		{name: "trusted network", cfg: restricted, remoteAddr: "10.1.2.3:443", wantDetailed: true},
+		{name: "debug token", cfg: restricted, remoteAddr: "203.0.113.7:443", token: "s3cret", wantDetailed: true},
+		{name: "untrusted", cfg: restricted, remoteAddr: "203.0.113.7:443"},
+		{name: "wrong debug token", cfg: restricted, remoteAddr: "203.0.113.7:443", token: "guess"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, err := newErrorDetail(tt.cfg)
+			require.NoError(t, err)
+			var forwardedToken string
+			h := &stdHandler{
+				errorDetail: detail,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					forwardedToken = ctx.Request.Header.Get(defaultDebugTokenHeader)
+					return &model.SchemaValidationErr{Errors: []model.Error{
+						{Paths: "message.order.items", Message: "items are required"},
+					}}
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`))
+			req.RemoteAddr = tt.remoteAddr
+			if tt.token != "" {
+				req.Header.Set(defaultDebugTokenHeader, tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			var resp model.Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, model.StatusNACK, resp.Message.Ack.Status)
+			assert.Equal(t, http.StatusText(http.StatusBadRequest), resp.Error.Code)
+			if tt.wantDetailed {
+				assert.Equal(t, "message.order.items", resp.Error.Paths)
+				assert.Equal(t, "items are required", resp.Error.Message)
+			} else {
+				assert.Empty(t, resp.Error.Paths)
+				assert.NotContains(t, resp.Error.Message, "items are required")
+				assert.Contains(t, resp.Error.Message, "Request could not be processed")
+			}
+			if tt.cfg.Restrict {
+				assert.Empty(t, forwardedToken, "debug token must not be forwarded")
+			}
+		})
+	}
+}
+
+func TestNewErrorDetail(t *testing.T) {
+	d, err := newErrorDetail(ErrorDetailConfig{TrustedNetworks: []string{"10.0.0.0/8"}})
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+
+	_, err = newErrorDetail(ErrorDetailConfig{Restrict: true, TrustedNetworks: []string{"10.0.0.1"}})
+	assert.ErrorContains(t, err, "invalid trusted network")
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sync/atomic"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// faultRule is a FaultRule with the count of the requests it matched.
+type faultRule struct {
+	FaultRule
+	matched atomic.Uint64
+}
+
+// match reports whether the rule applies to requests of action sent by sender.
+func (r *faultRule) match(action, sender string) bool {
+	if len(r.Actions) > 0 && !slices.Contains(r.Actions, action) {
+		return false
+	}
+	return len(r.Subscribers) == 0 || matchSubscriber(r.Subscribers, sender)
+}
+
+// hit counts a matching request, reporting whether the fault is injected into it. Faults
+// are injected whenever the count times the probability reaches the next integer, so
+// that they are spread evenly over the matching requests.
+func (r *faultRule) hit() bool {
+	n := r.matched.Add(1)
+	return math.Floor(float64(n)*r.Probability) > math.Floor(float64(n-1)*r.Probability)
+}
+
+// injectFaultStep injects configured faults into matching requests.
+type injectFaultStep struct {
+	rules []*faultRule
+}
+
+// newInjectFaultStep creates and returns the injectFault step. It injects nothing unless
+// cfg is enabled.
+func newInjectFaultStep(cfg FaultInjectionConfig) (definition.Step, error) {
+	s := &injectFaultStep{}
+	if !cfg.Enabled {
+		return s, nil
+	}
+	if len(cfg.Rules) == 0 {
+		return nil, errors.New("invalid config: injectFault requires faultInjection.rules")
+	}
+	for i, rule := range cfg.Rules {
+		switch rule.Type {
+		case FaultDelay:
+			if rule.Delay <= 0 {
+				return nil, fmt.Errorf("invalid config: faultInjection rule %d: delay faults require a positive delay", i)
+			}
+		case FaultNack, FaultError:
+		default:
+			return nil, fmt.Errorf("invalid config: faultInjection rule %d: unknown fault type %q", i, rule.Type)
+		}
+		if rule.Probability <= 0 || rule.Probability > 1 {
+			return nil, fmt.Errorf("invalid config: faultInjection rule %d: probability must be above 0 and at most 1", i)
+		}
+		for _, pattern := range rule.Subscribers {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid config: faultInjection rule %d: subscriber pattern %q: %w", i, pattern, err)
+			}
+		}
+		s.rules = append(s.rules, &faultRule{FaultRule: rule})
+	}
+	log.Warnf(context.Background(), "Fault injection is enabled with %d rules", len(s.rules))
+	return s, nil
+}
+
+// Run injects the fault of the first rule matching the request, if the request is among
+// the share of matching requests the rule affects.
+func (s *injectFaultStep) Run(ctx *model.StepContext) error {
+	if len(s.rules) == 0 {
+		return nil
+	}
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	sender := contextSender(bCtx)
+	for _, rule := range s.rules {
+		if !rule.match(bCtx.Action, sender) {
+			continue
+		}
+		if !rule.hit() {
+			return nil
+		}
+		log.Warnf(ctx, "Injecting %s fault into %s request from %s", rule.Type, bCtx.Action, sender)
+		switch rule.Type {
+		case FaultDelay:
+			return wait(ctx, rule.Delay)
+		case FaultNack:
+			return model.NewBadReqErr(errors.New("injected fault"))
+		default:
+			return errors.New("injected fault")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// faultRequestBody returns a request body of action sent by bapID.
+func faultRequestBody(action, bapID string) string {
+	return `{"context":{"action":"` + action + `","bap_id":"` + bapID + `","bpp_id":"bpp.example.com"}}`
+}
+
+func TestInjectFaultStep(t *testing.T) {
+	step, err := newInjectFaultStep(FaultInjectionConfig{Enabled: true, Rules: []FaultRule{
+		{Actions: []string{"search"}, Subscribers: []string{"*.test.com"}, Type: FaultNack, Probability: 0.25},
+		{Actions: []string{"confirm"}, Type: FaultError, Probability: 1},
+		{Actions: []string{"on_search"}, Subscribers: []string{"bpp.example.com"}, Type: FaultDelay, Probability: 0.5, Delay: time.Millisecond},
+	}})
+	require.NoError(t, err)
+	run := func(body string) error {
+		return step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(body)))
+	}
+
+	var nacks int
+	for i := 0; i < 100; i++ {
+		err := run(faultRequestBody("search", "bap.test.com"))
+		if err != nil {
+			var badReq *model.BadReqErr
+			require.ErrorAs(t, err, &badReq)
+			nacks++
+		}
+	}
+	assert.Equal(t, 25, nacks, "the fault rate is applied exactly")
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, run(faultRequestBody("search", "bap.example.com")), "other subscribers are not matched")
+		assert.NoError(t, run(faultRequestBody("select", "bap.test.com")), "other actions are not matched")
+	}
+
+	err = run(faultRequestBody("confirm", "bap.example.com"))
+	require.Error(t, err)
+	var badReq *model.BadReqErr
+	assert.NotErrorAs(t, err, &badReq, "error faults are answered with a 500")
+
+	var delayed int
+	for i := 0; i < 4; i++ {
+		start := time.Now()
+		require.NoError(t, run(faultRequestBody("on_search", "bap.example.com")))
+		if time.Since(start) >= time.Millisecond {
+			delayed++
+		}
+	}
+	assert.GreaterOrEqual(t, delayed, 2, "half of the callbacks from the BPP are delayed")
+}
+
+func TestInjectFaultStepDisabled(t *testing.T) {
+	step, err := newInjectFaultStep(FaultInjectionConfig{Rules: []FaultRule{{Type: FaultError, Probability: 1}}})
+	require.NoError(t, err)
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(faultRequestBody("search", "bap.example.com")))
+
+	assert.NoError(t, step.Run(ctx), "faults are only injected when enabled")
+}
+
+func TestNewInjectFaultStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []FaultRule
+		wantErr string
+	}{
+		{name: "no rules", wantErr: "requires faultInjection.rules"},
+		{name: "unknown type", rules: []FaultRule{{Type: "drop", Probability: 1}}, wantErr: `unknown fault type "drop"`},
+		{name: "delay without duration", rules: []FaultRule{{Type: FaultDelay, Probability: 1}}, wantErr: "require a positive delay"},
+		{name: "zero probability", rules: []FaultRule{{Type: FaultNack}}, wantErr: "probability must be above 0"},
+		{name: "probability above 1", rules: []FaultRule{{Type: FaultNack, Probability: 1.5}}, wantErr: "at most 1"},
+		{name: "invalid pattern", rules: []FaultRule{{Type: FaultNack, Probability: 1, Subscribers: []string{"["}}}, wantErr: `subscriber pattern "["`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newInjectFaultStep(FaultInjectionConfig{Enabled: true, Rules: tt.rules})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestServeHTTPInjectFault(t *testing.T) {
+	step, err := newInjectFaultStep(FaultInjectionConfig{Enabled: true, Rules: []FaultRule{
+		{Actions: []string{"search"}, Type: FaultNack, Probability: 1},
+		{Actions: []string{"select"}, Type: FaultError, Probability: 1},
+	}})
+	require.NoError(t, err)
+	h := &stdHandler{steps: []definition.Step{step}}
+	serve := func(action string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/"+action, strings.NewReader(faultRequestBody(action, "bap.example.com"))))
+		return rec
+	}
+
+	rec := serve("search")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"NACK"`)
+	assert.Contains(t, rec.Body.String(), "injected fault")
+
+	assert.Equal(t, http.StatusInternalServerError, serve("select").Code)
+	assert.Equal(t, http.StatusOK, serve("init").Code)
+}
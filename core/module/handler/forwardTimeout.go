@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// forwardTimeouts picks the timeout of requests forwarded to URL targets.
+type forwardTimeouts struct {
+	def   time.Duration
+	rules []ForwardTimeoutRule
+}
+
+// newForwardTimeouts creates forwardTimeouts from cfg. It returns nil when neither a
+// default nor rules are configured.
+func newForwardTimeouts(cfg ForwardTimeoutsConfig) (*forwardTimeouts, error) {
+	if cfg.Default < 0 {
+		return nil, fmt.Errorf("invalid config: forwardTimeouts default %s is negative", cfg.Default)
+	}
+	for i, rule := range cfg.Rules {
+		if rule.Timeout <= 0 {
+			return nil, fmt.Errorf("invalid config: forwardTimeouts rule %d: timeout must be positive", i)
+		}
+	}
+	if cfg.Default == 0 && len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+	return &forwardTimeouts{def: cfg.Default, rules: cfg.Rules}, nil
+}
+
+// timeout returns the timeout of the request routed by ctx: that of the first rule
+// matching its target URL and action, or the default. Zero means unbounded.
+func (t *forwardTimeouts) timeout(ctx *model.StepContext) time.Duration {
+	var action string
+	if ctx.BecknContext != nil {
+		action = ctx.BecknContext.Action
+	}
+	target := ctx.Route.URL.String()
+	for _, rule := range t.rules {
+		if rule.URL != "" && !strings.HasPrefix(target, rule.URL) {
+			continue
+		}
+		if rule.Action != "" && rule.Action != action {
+			continue
+		}
+		return rule.Timeout
+	}
+	return t.def
+}
+
+// withTimeout returns parent bounded by the timeout of the request routed by ctx. The
+// returned cancel func must be called once the forwarded request is done.
+func (t *forwardTimeouts) withTimeout(parent context.Context, ctx *model.StepContext) (context.Context, context.CancelFunc) {
+	if t == nil {
+		return parent, func() {}
+	}
+	timeout := t.timeout(ctx)
+	if timeout == 0 {
+		return parent, func() {}
+	}
+	return context.WithTimeout(parent, timeout)
+}
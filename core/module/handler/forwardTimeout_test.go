@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// testForwardTimeouts gives the slow BPP 10s, confirms 5s and everything else 2s.
+var testForwardTimeouts = ForwardTimeoutsConfig{
+	Default: 2 * time.Second,
+	Rules: []ForwardTimeoutRule{
+		{URL: "https://slow-bpp.example.com/", Timeout: 10 * time.Second},
+		{Action: "confirm", Timeout: 5 * time.Second},
+	},
+}
+
+// roundTripFunc is an http.RoundTripper calling itself.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// forwardTimeoutRoutes are the routes exercised against testForwardTimeouts with the
+// timeout each must get.
+var forwardTimeoutRoutes = []struct {
+	name   string
+	target string
+	action string
+	want   time.Duration
+}{
+	{name: "url override", target: "https://slow-bpp.example.com/search", action: "search", want: 10 * time.Second},
+	{name: "url override wins over later rules", target: "https://slow-bpp.example.com/confirm", action: "confirm", want: 10 * time.Second},
+	{name: "action override", target: "https://bpp.example.com/confirm", action: "confirm", want: 5 * time.Second},
+	{name: "default", target: "https://bpp.example.com/search", action: "search", want: 2 * time.Second},
+}
+
+// forwardTimeoutHandler returns a handler routing every request to target in proxy or
+// async mode.
+func forwardTimeoutHandler(t *testing.T, target string, proxied bool, client *http.Client) *stdHandler {
+	t.Helper()
+	u, err := url.Parse(target)
+	require.NoError(t, err)
+	timeouts, err := newForwardTimeouts(testForwardTimeouts)
+	require.NoError(t, err)
+	return &stdHandler{
+		httpClient:  client,
+		fwdTimeouts: timeouts,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: u, ActAsProxy: proxied}
+			return nil
+		})},
+	}
+}
+
+func TestServeHTTPForwardTimeoutProxy(t *testing.T) {
+	for _, tt := range forwardTimeoutRoutes {
+		t.Run(tt.name, func(t *testing.T) {
+			var remaining time.Duration
+			orig := proxyFunc
+			proxyFunc = func(_ *model.StepContext, r *http.Request, w http.ResponseWriter, _ *http.Client, _ *proxyStreamer) {
+				deadline, ok := r.Context().Deadline()
+				require.True(t, ok, "the proxied request must have a deadline")
+				remaining = time.Until(deadline)
+				w.WriteHeader(http.StatusOK)
+			}
+			t.Cleanup(func() { proxyFunc = orig })
+			h := forwardTimeoutHandler(t, tt.target, true, nil)
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bap/caller/"+tt.action, strings.NewReader(`{"context":{"action":"`+tt.action+`"}}`)))
+
+			assert.InDelta(t, tt.want, remaining, float64(time.Second))
+		})
+	}
+}
+
+func TestServeHTTPForwardTimeoutAsync(t *testing.T) {
+	for _, tt := range forwardTimeoutRoutes {
+		t.Run(tt.name, func(t *testing.T) {
+			var remaining time.Duration
+			client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				deadline, ok := r.Context().Deadline()
+				require.True(t, ok, "the async request must have a deadline")
+				remaining = time.Until(deadline)
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+			})}
+			h := forwardTimeoutHandler(t, tt.target, false, client)
+			req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/caller/"+tt.action, strings.NewReader(`{"context":{"action":"`+tt.action+`"}}`)))
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			for _, hook := range *hooks {
+				hook()
+			}
+
+			assert.InDelta(t, tt.want, remaining, float64(time.Second))
+		})
+	}
+}
+
+func TestServeHTTPForwardTimeoutAbortsSlowTarget(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer upstream.Close()
+	defer close(release)
+	timeouts, err := newForwardTimeouts(ForwardTimeoutsConfig{Default: time.Minute, Rules: []ForwardTimeoutRule{{URL: upstream.URL, Timeout: 20 * time.Millisecond}}})
+	require.NoError(t, err)
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	h := &stdHandler{
+		httpClient:  upstream.Client(),
+		fwdTimeouts: timeouts,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+			return nil
+		})},
+	}
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`)))
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestNewForwardTimeouts(t *testing.T) {
+	timeouts, err := newForwardTimeouts(ForwardTimeoutsConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, timeouts)
+
+	_, err = newForwardTimeouts(ForwardTimeoutsConfig{Default: -time.Second})
+	assert.ErrorContains(t, err, "default -1s is negative")
+
+	_, err = newForwardTimeouts(ForwardTimeoutsConfig{Rules: []ForwardTimeoutRule{{Action: "search"}}})
+	assert.ErrorContains(t, err, "rule 0: timeout must be positive")
+}
@@ -14,6 +14,14 @@ type HandlerMetrics struct {
 	SignatureValidationsTotal metric.Int64Counter
 	SchemaValidationsTotal    metric.Int64Counter
 	RoutingDecisionsTotal     metric.Int64Counter
+	OndcValidationsTotal      metric.Int64Counter
+	WorkbenchOperationsTotal  metric.Int64Counter
+	WorkbenchDuration         metric.Float64Histogram
+	RequestDuration           metric.Float64Histogram
+	RequestsInFlight          metric.Int64UpDownCounter
+	RequestBodySize           metric.Int64Histogram
+	SignatureFailuresTotal    metric.Int64Counter
+	RateLimitDecisionsTotal   metric.Int64Counter
 }
 
 var (
@@ -25,13 +33,13 @@ var (
 // GetHandlerMetrics lazily initializes handler metric instruments and returns a cached reference.
 func GetHandlerMetrics(ctx context.Context) (*HandlerMetrics, error) {
 	handlerMetricsOnce.Do(func() {
-		handlerMetricsInstance, handlerMetricsErr = newHandlerMetrics()
+		handlerMetricsInstance, handlerMetricsErr = newHandlerMetrics(otel.GetMeterProvider())
 	})
 	return handlerMetricsInstance, handlerMetricsErr
 }
 
-func newHandlerMetrics() (*HandlerMetrics, error) {
-	meter := otel.GetMeterProvider().Meter(
+func newHandlerMetrics(mp metric.MeterProvider) (*HandlerMetrics, error) {
+	meter := mp.Meter(
 		"github.com/beckn-one/beckn-onix/handler",
 		metric.WithInstrumentationVersion("1.0.0"),
 	)
@@ -63,6 +71,73 @@ func newHandlerMetrics() (*HandlerMetrics, error) {
 		return nil, fmt.Errorf("onix_routing_decisions_total: %w", err)
 	}
 
+	if m.OndcValidationsTotal, err = meter.Int64Counter(
+		"onix_ondc_validations_total",
+		metric.WithDescription("ONDC payload validations executed or skipped"),
+		metric.WithUnit("{validation}"),
+	); err != nil {
+		return nil, fmt.Errorf("onix_ondc_validations_total: %w", err)
+	}
+
+	if m.WorkbenchOperationsTotal, err = meter.Int64Counter(
+		"onix_workbench_operations_total",
+		metric.WithDescription("ONDC workbench receive and context validation attempts"),
+		metric.WithUnit("{operation}"),
+	); err != nil {
+		return nil, fmt.Errorf("onix_workbench_operations_total: %w", err)
+	}
+
+	if m.WorkbenchDuration, err = meter.Float64Histogram(
+		"onix_workbench_duration_seconds",
+		metric.WithDescription("Duration of ONDC workbench receive and context validation"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5),
+	); err != nil {
+		return nil, fmt.Errorf("onix_workbench_duration_seconds: %w", err)
+	}
+
+	if m.RequestDuration, err = meter.Float64Histogram(
+		"onix_request_duration_seconds",
+		metric.WithDescription("End-to-end duration of requests served by the handler"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5),
+	); err != nil {
+		return nil, fmt.Errorf("onix_request_duration_seconds: %w", err)
+	}
+
+	if m.RequestsInFlight, err = meter.Int64UpDownCounter(
+		"onix_requests_in_flight",
+		metric.WithDescription("Requests currently being served by the handler"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		return nil, fmt.Errorf("onix_requests_in_flight: %w", err)
+	}
+
+	if m.RequestBodySize, err = meter.Int64Histogram(
+		"onix_request_body_size_bytes",
+		metric.WithDescription("Size of request bodies received by the handler"),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304),
+	); err != nil {
+		return nil, fmt.Errorf("onix_request_body_size_bytes: %w", err)
+	}
+
+	if m.SignatureFailuresTotal, err = meter.Int64Counter(
+		"onix_signature_failures_total",
+		metric.WithDescription("Signature validation failures and blocked requests per subscriber"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		return nil, fmt.Errorf("onix_signature_failures_total: %w", err)
+	}
+
+	if m.RateLimitDecisionsTotal, err = meter.Int64Counter(
+		"onix_rate_limit_decisions_total",
+		metric.WithDescription("Requests allowed and throttled by the global rate limit, per bucket"),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		return nil, fmt.Errorf("onix_rate_limit_decisions_total: %w", err)
+	}
+
 	return m, nil
 }
 
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// newTestHandlerMetrics creates HandlerMetrics backed by a manual reader so tests can
+// inspect recorded values without touching the global meter provider.
+func newTestHandlerMetrics(t *testing.T) (*HandlerMetrics, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	m, err := newHandlerMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	require.NoError(t, err)
+	return m, reader
+}
+
+// counterValue returns the value of the int64 counter data point with the given attributes.
+func counterValue(t *testing.T, reader *sdkmetric.ManualReader, name string, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	want := attribute.NewSet(attrs...)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok, "metric %s is not an int64 sum", name)
+			for _, dp := range sum.DataPoints {
+				if dp.Attributes.Equals(&want) {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// histogramCount returns the number of recordings in the float64 histogram data point with the given attributes.
+func histogramCount(t *testing.T, reader *sdkmetric.ManualReader, name string, attrs ...attribute.KeyValue) uint64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	want := attribute.NewSet(attrs...)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			require.True(t, ok, "metric %s is not a float64 histogram", name)
+			for _, dp := range hist.DataPoints {
+				if dp.Attributes.Equals(&want) {
+					return dp.Count
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestNewHandlerMetrics(t *testing.T) {
+	m, _ := newTestHandlerMetrics(t)
+	require.NotNil(t, m.SignatureValidationsTotal)
+	require.NotNil(t, m.SchemaValidationsTotal)
+	require.NotNil(t, m.RoutingDecisionsTotal)
+	require.NotNil(t, m.OndcValidationsTotal)
+	require.NotNil(t, m.WorkbenchOperationsTotal)
+	require.NotNil(t, m.WorkbenchDuration)
+}
+
+func TestHandlerMetricsPrometheusExport(t *testing.T) {
+	mp, metricsHandler, err := telemetry.NewPrometheusMeterProvider(resource.Empty())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	m, err := newHandlerMetrics(mp)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	m.SignatureValidationsTotal.Add(ctx, 1)
+	m.SchemaValidationsTotal.Add(ctx, 1)
+	m.RoutingDecisionsTotal.Add(ctx, 1)
+	m.OndcValidationsTotal.Add(ctx, 1)
+	m.WorkbenchOperationsTotal.Add(ctx, 1)
+	m.WorkbenchDuration.Record(ctx, 0.01)
+
+	rec := httptest.NewRecorder()
+	metricsHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	for _, name := range []string{
+		"beckn_signature_validations_total",
+		"beckn_schema_validations_total",
+		"onix_routing_decisions_total",
+		"onix_ondc_validations_total",
+		"onix_workbench_operations_total",
+		"onix_workbench_duration_seconds_bucket",
+	} {
+		assert.Contains(t, body, name)
+	}
+}
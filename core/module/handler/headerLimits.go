@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// checkHeaderLimits returns an error when header has more fields, or larger ones in
+// total, than limits allow.
+func checkHeaderLimits(header http.Header, limits HeaderLimitsConfig) error {
+	if limits.MaxSize <= 0 && limits.MaxCount <= 0 {
+		return nil
+	}
+	count, size := 0, 0
+	for name, values := range header {
+		for _, v := range values {
+			count++
+			size += len(name) + len(v)
+		}
+	}
+	if limits.MaxCount > 0 && count > limits.MaxCount {
+		return fmt.Errorf("request has %d header fields, more than the %d allowed", count, limits.MaxCount)
+	}
+	if limits.MaxSize > 0 && size > limits.MaxSize {
+		return fmt.Errorf("request headers total %d bytes, more than the %d allowed", size, limits.MaxSize)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+func TestServeHTTPHeaderLimits(t *testing.T) {
+	limits := HeaderLimitsConfig{MaxSize: 1024, MaxCount: 10}
+	tests := []struct {
+		name    string
+		limits  HeaderLimitsConfig
+		header  func(http.Header)
+		wantErr string
+	}{
+		{
+			name:   "under limits",
+			limits: limits,
+			header: func(h http.Header) {
+				h.Set("Authorization", "Signature keyId=\"bap.example.com|k1|ed25519\"")
+				h.Add("X-Trace", "a")
+				h.Add("X-Trace", "b")
+			},
+		},
+		{
+			name:    "oversized single header",
+			limits:  limits,
+			header:  func(h http.Header) { h.Set("X-Large", strings.Repeat("a", 1024)) },
+			wantErr: "more than the 1024 allowed",
+		},
+		{
+			name:   "too many headers",
+			limits: limits,
+			header: func(h http.Header) {
+				for i := 0; i < 11; i++ {
+					h.Set("X-Header-"+strconv.Itoa(i), "v")
+				}
+			},
+			wantErr: "request has 11 header fields, more than the 10 allowed",
+		},
+		{
+			name:    "repeated values count",
+			limits:  limits,
+			header:  func(h http.Header) { h["X-Trace"] = strings.Split(strings.Repeat("v,", 11), ",")[:11] },
+			wantErr: "request has 11 header fields",
+		},
+		{
+			name:   "unlimited",
+			header: func(h http.Header) { h.Set("X-Large", strings.Repeat("a", 1<<16)) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var routed bool
+			h := &stdHandler{headerLimits: tt.limits, steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+				routed = true
+				return nil
+			})}}
+			req := httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`))
+			tt.header(req.Header)
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			if tt.wantErr == "" {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.True(t, routed)
+				return
+			}
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"NACK"`)
+			assert.Contains(t, rec.Body.String(), tt.wantErr)
+			assert.False(t, routed, "steps must not run")
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// hopByHopHeaders are the headers meaningful only for a single connection, which a
+// proxy must not forward (RFC 7230, section 6.1).
+var hopByHopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// removeHopByHopHeaders deletes the hop-by-hop headers from header, including those
+// listed in its Connection header.
+func removeHopByHopHeaders(header http.Header) {
+	for _, v := range header.Values("Connection") {
+		for _, name := range strings.Split(v, ",") {
+			if name = textproto.TrimString(name); name != "" {
+				header.Del(name)
+			}
+		}
+	}
+	for _, name := range hopByHopHeaders {
+		header.Del(name)
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// assertHopByHopStripped checks that header kept its end-to-end headers and lost the
+// hop-by-hop ones, including X-Hop, which the Connection header names.
+func assertHopByHopStripped(t *testing.T, header http.Header) {
+	t.Helper()
+	for _, name := range []string{"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Trailer", "Upgrade", "Transfer-Encoding", "X-Hop"} {
+		assert.Empty(t, header.Values(name), "%s must not be forwarded", name)
+	}
+	assert.Equal(t, "e2e", header.Get("X-End-To-End"))
+}
+
+// setHopByHopHeaders sets hop-by-hop headers and an end-to-end header on header.
+func setHopByHopHeaders(header http.Header) {
+	header.Set("Connection", "keep-alive, X-Hop")
+	header.Set("Keep-Alive", "timeout=5")
+	header.Set("Proxy-Connection", "keep-alive")
+	header.Set("Te", "trailers")
+	header.Set("Trailer", "X-Checksum")
+	header.Set("Upgrade", "websocket")
+	header.Set("X-Hop", "1")
+	header.Set("X-End-To-End", "e2e")
+}
+
+func TestRemoveHopByHopHeaders(t *testing.T) {
+	header := http.Header{}
+	setHopByHopHeaders(header)
+	header.Set("Transfer-Encoding", "chunked")
+	header.Add("Connection", " x-other ,")
+	header.Set("X-Other", "1")
+
+	removeHopByHopHeaders(header)
+
+	assertHopByHopStripped(t, header)
+	assert.Empty(t, header.Values("X-Other"), "every Connection header is honoured")
+}
+
+func TestServeHTTPProxyStripsHopByHopHeaders(t *testing.T) {
+	var got http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	h := &stdHandler{
+		httpClient: upstream.Client(),
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+			return nil
+		})},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+	setHopByHopHeaders(req.Header)
+	// httputil.ReverseProxy passes on "Te: trailers" by design, for gRPC.
+	req.Header.Del("Te")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assertHopByHopStripped(t, got)
+}
+
+func TestMakeAsyncRequestStripsHopByHopHeaders(t *testing.T) {
+	var got http.Header
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.Header.Clone()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	})}
+	target, err := url.Parse("https://bpp.example.com/search")
+	require.NoError(t, err)
+	headers := http.Header{}
+	setHopByHopHeaders(headers)
+	route := &model.Route{TargetType: "url", URL: target, Headers: map[string]string{}}
+	for name := range headers {
+		route.Headers[name] = headers.Get(name)
+	}
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(`{}`))
+	ctx.Route = route
+
+	require.NoError(t, makeAsyncRequest(ctx, ctx, client))
+
+	assertHopByHopStripped(t, got)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import "net/http"
+
+// Default names of the headers that carry the module name and role to instrumentation.
+const (
+	defaultModuleNameHeader = "X-Module-Name"
+	defaultRoleHeader       = "X-Role"
+)
+
+// withDefaults returns c with the defaults for unset settings filled in.
+func (c InstrumentationHeadersConfig) withDefaults() InstrumentationHeadersConfig {
+	if c.ModuleName == "" {
+		c.ModuleName = defaultModuleNameHeader
+	}
+	if c.Role == "" {
+		c.Role = defaultRoleHeader
+	}
+	return c
+}
+
+// names returns the module name and role header names, applying defaults. Both are
+// empty when the headers are disabled.
+func (c InstrumentationHeadersConfig) names() (module, role string) {
+	if c.Disabled {
+		return "", ""
+	}
+	c = c.withDefaults()
+	return c.ModuleName, c.Role
+}
+
+// setInstrumentHeaders sets the module name and role headers on r.
+func (h *stdHandler) setInstrumentHeaders(r *http.Request, role string) {
+	module, roleName := h.instrumentHeaders.names()
+	if module != "" {
+		r.Header.Set(module, h.moduleName)
+	}
+	if roleName != "" {
+		r.Header.Set(roleName, role)
+	}
+}
+
+// clearInstrumentHeaders removes the module name and role headers from r so they
+// do not leak downstream.
+func (h *stdHandler) clearInstrumentHeaders(r *http.Request) {
+	module, role := h.instrumentHeaders.names()
+	if module != "" {
+		r.Header.Del(module)
+	}
+	if role != "" {
+		r.Header.Del(role)
+	}
+}
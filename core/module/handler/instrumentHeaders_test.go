@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPInstrumentationHeaders(t *testing.T) {
+	var upstreamHeader http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHeader = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"message":{"ack":{"status":"ACK"}}}`))
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		cfg      InstrumentationHeadersConfig
+		wantSeen map[string]string
+	}{
+		{
+			name:     "defaults",
+			wantSeen: map[string]string{"X-Module-Name": "bapTxnCaller", "X-Role": "bap"},
+		},
+		{
+			name:     "custom names",
+			cfg:      InstrumentationHeadersConfig{ModuleName: "X-Onix-Module", Role: "X-Onix-Role"},
+			wantSeen: map[string]string{"X-Onix-Module": "bapTxnCaller", "X-Onix-Role": "bap", "X-Module-Name": "", "X-Role": ""},
+		},
+		{
+			name:     "disabled",
+			cfg:      InstrumentationHeadersConfig{Disabled: true},
+			wantSeen: map[string]string{"X-Module-Name": "", "X-Role": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstreamHeader = nil
+			var seen http.Header
+			h := &stdHandler{
+				moduleName:        "bapTxnCaller",
+				role:              model.RoleBAP,
+				httpClient:        upstream.Client(),
+				instrumentHeaders: tt.cfg,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					seen = ctx.Request.Header.Clone()
+					ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+					return nil
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.NotNil(t, upstreamHeader, "request was not forwarded")
+			for name, want := range tt.wantSeen {
+				assert.Equal(t, want, seen.Get(name), "header %s seen by steps", name)
+				assert.Empty(t, upstreamHeader.Get(name), "header %s forwarded", name)
+				assert.Empty(t, req.Header.Get(name), "header %s left on the request", name)
+			}
+		})
+	}
+}
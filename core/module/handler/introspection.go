@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+)
+
+// PluginStatus describes one plugin slot of a handler.
+type PluginStatus struct {
+	Name string `json:"name"`
+	// ID is the configured plugin ID, empty if the slot is not configured.
+	ID string `json:"id,omitempty"`
+	// Enabled reports whether a plugin instance is loaded in the slot.
+	Enabled bool `json:"enabled"`
+}
+
+// PluginReporter is implemented by handlers that can report which plugins they loaded.
+type PluginReporter interface {
+	Plugins() []PluginStatus
+}
+
+// Plugins reports the plugin slots of the handler with their configured IDs and load state.
+func (h *stdHandler) Plugins() []PluginStatus {
+	cfg := h.pluginCfg
+	ondcValidator := h.ondcValidator != nil
+	var domainValidators map[string]bool
+	if dv, ok := h.ondcValidator.(*domainOndcValidator); ok {
+		ondcValidator = dv.fallback != nil
+		domainValidators = make(map[string]bool, len(dv.byDomain))
+		for domain, v := range dv.byDomain {
+			domainValidators[domain] = v != nil
+		}
+	}
+
+	statuses := []PluginStatus{
+		pluginStatus("Cache", cfg.Cache, h.cache != nil),
+		pluginStatus("Registry", cfg.Registry, h.registry != nil),
+		pluginStatus("KeyManager", cfg.KeyManager, h.km != nil),
+		pluginStatus("SignValidator", cfg.SignValidator, h.signValidator != nil),
+		pluginStatus("SchemaValidator", cfg.SchemaValidator, h.schemaValidator != nil),
+		pluginStatus("Router", cfg.Router, h.router != nil),
+		pluginStatus("Publisher", cfg.Publisher, h.publisher != nil),
+		pluginStatus("Signer", cfg.Signer, h.signer != nil),
+		pluginStatus("TransportWrapper", cfg.TransportWrapper, h.transportWrapper != nil),
+		pluginStatus("OndcValidator", cfg.OndcValidator, ondcValidator),
+		pluginStatus("OndcWorkbench", cfg.OndcWorkbench, h.ondcWorkbench != nil),
+		pluginStatus("Auditor", cfg.Auditor, h.auditor != nil),
+	}
+
+	domains := make([]string, 0, len(cfg.OndcValidators))
+	for domain := range cfg.OndcValidators {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		statuses = append(statuses, pluginStatus("OndcValidator["+domain+"]", cfg.OndcValidators[domain], domainValidators[domain]))
+	}
+	// Middleware and step plugins fail handler construction if they cannot be loaded.
+	for i := range cfg.Middleware {
+		statuses = append(statuses, pluginStatus("Middleware", &cfg.Middleware[i], true))
+	}
+	for i := range cfg.Steps {
+		statuses = append(statuses, pluginStatus("Step", &cfg.Steps[i], true))
+	}
+	return statuses
+}
+
+// pluginStatus builds the status of a plugin slot from its config and load state.
+func pluginStatus(name string, cfg *plugin.Config, loaded bool) PluginStatus {
+	s := PluginStatus{Name: name, Enabled: loaded}
+	if cfg != nil {
+		s.ID = cfg.ID
+	}
+	return s
+}
+
+// PluginsHandler returns an http.Handler for the /debug/plugins endpoint that reports
+// the plugins of each module as JSON.
+func PluginsHandler(reporters map[string]PluginReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp := make(map[string][]PluginStatus, len(reporters))
+		for name, rep := range reporters {
+			resp[name] = rep.Plugins()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdHandlerPlugins(t *testing.T) {
+	mgr := &stubPluginManager{ondcValidator: &mockOndcValidator{}, ondcWorkbench: &mockWorkbench{}}
+	cfg := &Config{Plugins: PluginCfg{
+		// The stub manager returns no Cache instance, so the slot is configured but not enabled.
+		Cache:         &plugin.Config{ID: "cache"},
+		OndcWorkbench: &plugin.Config{ID: "ondcworkbench"},
+		OndcValidators: map[string]*plugin.Config{
+			"ONDC:TRV11": {ID: "ondcvalidator"},
+			"ONDC:RET10": {ID: "ondcvalidator"},
+		},
+		Steps: []plugin.Config{{ID: "customstep"}},
+	}}
+	h, err := NewStdHandler(context.Background(), mgr, cfg, "test-module")
+	require.NoError(t, err)
+
+	pr, ok := h.(PluginReporter)
+	require.True(t, ok, "stdHandler should implement PluginReporter")
+	assert.Equal(t, []PluginStatus{
+		{Name: "Cache", ID: "cache", Enabled: false},
+		{Name: "Registry"},
+		{Name: "KeyManager"},
+		{Name: "SignValidator"},
+		{Name: "SchemaValidator"},
+		{Name: "Router"},
+		{Name: "Publisher"},
+		{Name: "Signer"},
+		{Name: "TransportWrapper"},
+		{Name: "OndcValidator"},
+		{Name: "OndcWorkbench", ID: "ondcworkbench", Enabled: true},
+		{Name: "Auditor"},
+		{Name: "OndcValidator[ONDC:RET10]", ID: "ondcvalidator", Enabled: true},
+		{Name: "OndcValidator[ONDC:TRV11]", ID: "ondcvalidator", Enabled: true},
+		{Name: "Step", ID: "customstep", Enabled: true},
+	}, pr.Plugins())
+}
+
+func TestPluginsHandler(t *testing.T) {
+	reporters := map[string]PluginReporter{
+		"bapTxnReceiver": &stdHandler{
+			publisher: &mockPublisher{},
+			pluginCfg: PluginCfg{Publisher: &plugin.Config{ID: "publisher"}},
+		},
+	}
+	h := PluginsHandler(reporters)
+
+	t.Run("GET", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/plugins", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var body map[string][]PluginStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.Contains(t, body, "bapTxnReceiver")
+		assert.Contains(t, body["bapTxnReceiver"], PluginStatus{Name: "Publisher", ID: "publisher", Enabled: true})
+		assert.Contains(t, body["bapTxnReceiver"], PluginStatus{Name: "Router"})
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/plugins", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
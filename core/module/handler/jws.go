@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// defaultJWSHeader carries detached JWS signatures when SignatureConfig.JWSHeader is unset.
+const defaultJWSHeader = "X-JWS-Signature"
+
+// errJWSKeyRef is returned when a signing key held by reference is used in jws mode.
+var errJWSKeyRef = errors.New("signing key is held in a KMS, which detached JWS signing does not support")
+
+// jwsHeaderName returns the header carrying detached JWS signatures in cfg's signature
+// mode, or "" in beckn mode.
+func jwsHeaderName(cfg SignatureConfig) (string, error) {
+	switch cfg.Mode {
+	case "", SignatureModeBeckn:
+		return "", nil
+	case SignatureModeJWS:
+		if cfg.JWSHeader == "" {
+			return defaultJWSHeader, nil
+		}
+		return http.CanonicalHeaderKey(cfg.JWSHeader), nil
+	default:
+		return "", fmt.Errorf("invalid config: unknown signature mode %q", cfg.Mode)
+	}
+}
+
+// jwsPayload returns what a detached JWS over body covers: the canonical JSON form
+// (RFC 8785) of its signed part, so that reformatting the body in transit does not
+// break the signature.
+func jwsPayload(part signedPart, body []byte) ([]byte, error) {
+	signed, err := part.extract(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select signed part: %w", err)
+	}
+	payload, err := canonicalJSON(signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize body: %w", err)
+	}
+	return payload, nil
+}
+
+// jwsKeyID returns the kid of a detached JWS, which names the signing key like the
+// keyId of the Authorization header: "{subscriber_id}|{unique_key_id}".
+func jwsKeyID(subID, keyID string) string {
+	return subID + "|" + keyID
+}
+
+// parseJWSKeyID extracts the subscriber and unique key IDs from the kid in the protected
+// header of a detached JWS.
+func parseJWSKeyID(jws string) (*authHeader, error) {
+	protected, _, _ := strings.Cut(jws, ".")
+	data, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS header: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("invalid JWS header: %w", err)
+	}
+	subID, keyID, ok := strings.Cut(header.Kid, "|")
+	if !ok || subID == "" || keyID == "" {
+		return nil, fmt.Errorf("invalid JWS kid %q", header.Kid)
+	}
+	return &authHeader{SubscriberID: subID, UniqueID: keyID, Algorithm: header.Alg}, nil
+}
+
+// signJWS signs the request with a detached JWS, set in the JWS header.
+func (s *signStep) signJWS(ctx *model.StepContext, keySet *model.Keyset) error {
+	if keySet.SigningKeyRef != "" {
+		return fmt.Errorf("failed to sign request: %w", errJWSKeyRef)
+	}
+	payload, err := jwsPayload(s.part, ctx.Body)
+	if err != nil {
+		return model.NewBadReqErr(err)
+	}
+	now := s.clock.Now()
+	jws, err := s.jwsSigner.SignDetachedJWS(ctx, payload, keySet.SigningPrivate, jwsKeyID(ctx.SubID, keySet.UniqueKeyID), now.Unix(), now.Add(signatureValidity).Unix())
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	log.Debugf(ctx, "Detached JWS generated: %v", jws)
+	ctx.Request.Header.Set(s.jwsHeader, jws)
+	return nil
+}
+
+// validateJWS checks the detached JWS, whose kid is parsed into headerVals, against the
+// keys of each registry in order, returning the first registry whose keys validate it.
+func (s *validateSignStep) validateJWS(ctx *model.StepContext, headerVals *authHeader, jws string) (string, error) {
+	payload, err := jwsPayload(s.part, ctx.Body)
+	if err != nil {
+		return "", err
+	}
+	return s.validateKeys(ctx, headerVals, func(publicKey string) error {
+		return s.jwsValidator.ValidateDetachedJWS(ctx, payload, jws, publicKey)
+	})
+}
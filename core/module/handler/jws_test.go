@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signer"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signvalidator"
+)
+
+func TestDetachedJWSRoundTrip(t *testing.T) {
+	const (
+		raw         = `{"message": {"order": {"id": "o1", "items": [1, 2]}}, "context": {"action": "confirm"}}`
+		reformatted = "{\n  \"context\": {\"action\": \"confirm\"},\n  \"message\": {\"order\": {\"items\": [1, 2], \"id\": \"o1\"}}\n}"
+		tampered    = `{"context": {"action": "confirm"}, "message": {"order": {"id": "o2", "items": [1, 2]}}}`
+	)
+	ctx := context.Background()
+	sgn, _, err := signer.New(ctx, &signer.Config{})
+	require.NoError(t, err)
+	sv, _, err := signvalidator.New(ctx, &signvalidator.Config{})
+	require.NoError(t, err)
+	km := newEd25519KeyManager(t)
+
+	// sign signs body in jws mode and returns the signed request.
+	sign := func(t *testing.T, cfg SignatureConfig, body string) *model.StepContext {
+		t.Helper()
+		step, err := newSignStep(sgn, km, cfg, nil)
+		require.NoError(t, err)
+		sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", nil), []byte(body))
+		sctx.SubID = "bap.example.com"
+		require.NoError(t, step.Run(sctx))
+		return sctx
+	}
+	// validate validates body carrying the given header in jws mode.
+	validate := func(t *testing.T, cfg SignatureConfig, body string, header http.Header) (*model.StepContext, error) {
+		t.Helper()
+		step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, cfg, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/confirm", nil)
+		req.Header = header
+		vctx := newTestStepCtx(req, []byte(body))
+		return vctx, step.Run(vctx)
+	}
+	jwsMode := SignatureConfig{Mode: SignatureModeJWS}
+
+	t.Run("round trip", func(t *testing.T) {
+		sctx := sign(t, jwsMode, raw)
+		assert.Equal(t, raw, string(sctx.Body), "the body is forwarded as received")
+		assert.NotEmpty(t, sctx.Request.Header.Get(defaultJWSHeader))
+		assert.Empty(t, sctx.Request.Header.Get(model.AuthHeaderSubscriber), "jws mode does not set the Beckn signature")
+
+		vctx, err := validate(t, jwsMode, raw, sctx.Request.Header)
+		require.NoError(t, err)
+		assert.Equal(t, "bap.example.com", vctx.Signer)
+	})
+
+	t.Run("re-serialized body", func(t *testing.T) {
+		sctx := sign(t, jwsMode, raw)
+		_, err := validate(t, jwsMode, reformatted, sctx.Request.Header)
+		assert.NoError(t, err, "the JWS covers the canonical form of the body")
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		sctx := sign(t, jwsMode, raw)
+		vctx, err := validate(t, jwsMode, tampered, sctx.Request.Header)
+		var signErr *model.SignValidationErr
+		require.ErrorAs(t, err, &signErr)
+		assert.ErrorContains(t, err, "failed to validate "+defaultJWSHeader)
+		assert.NotEmpty(t, vctx.RespHeader.Get(model.UnaAuthorizedHeaderGateway))
+	})
+
+	t.Run("custom header and signed part", func(t *testing.T) {
+		cfg := SignatureConfig{Mode: SignatureModeJWS, JWSHeader: "x-signature-jws", Path: "message"}
+		sctx := sign(t, cfg, raw)
+		require.NotEmpty(t, sctx.Request.Header.Get("X-Signature-JWS"))
+
+		_, err := validate(t, cfg, `{"context":{"action":"search"},"message":{"order":{"items":[1,2],"id":"o1"}}}`, sctx.Request.Header)
+		assert.NoError(t, err, "only the signed part is covered")
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		other := newEd25519KeyManager(t)
+		other.keyset.UniqueKeyID = "k2"
+		step, err := newSignStep(sgn, other, jwsMode, nil)
+		require.NoError(t, err)
+		sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", nil), []byte(raw))
+		sctx.SubID = "bap.example.com"
+		require.NoError(t, step.Run(sctx))
+
+		_, err = validate(t, jwsMode, raw, sctx.Request.Header)
+		assert.ErrorContains(t, err, "failed to get validation key")
+	})
+
+	t.Run("beckn signatures are still accepted", func(t *testing.T) {
+		sctx := sign(t, SignatureConfig{}, raw)
+		_, err := validate(t, jwsMode, raw, sctx.Request.Header)
+		assert.NoError(t, err)
+	})
+}
+
+func TestParseJWSKeyID(t *testing.T) {
+	got, err := parseJWSKeyID("eyJhbGciOiJFZERTQSIsImtpZCI6ImJhcC5leGFtcGxlLmNvbXxrMSJ9..c2ln")
+	require.NoError(t, err)
+	assert.Equal(t, &authHeader{SubscriberID: "bap.example.com", UniqueID: "k1", Algorithm: "EdDSA"}, got)
+
+	for _, jws := range []string{"", "!!..c2ln", "bm90IGpzb24..c2ln", "eyJhbGciOiJFZERTQSIsImtpZCI6ImsxIn0..c2ln"} {
+		_, err := parseJWSKeyID(jws)
+		assert.Error(t, err, jws)
+	}
+}
+
+func TestNewSignStepJWSMode(t *testing.T) {
+	km := newEd25519KeyManager(t)
+
+	_, err := newSignStep(&windowSigner{}, km, SignatureConfig{Mode: SignatureModeJWS}, nil)
+	assert.ErrorContains(t, err, "requires a Signer plugin supporting detached JWS")
+
+	_, err = newValidateSignStep(&keySignValidator{}, km, DefaultHeaderValidationCookie, SignatureConfig{Mode: SignatureModeJWS}, nil, nil)
+	assert.ErrorContains(t, err, "requires a SignValidator plugin supporting detached JWS")
+
+	_, err = newSignStep(&windowSigner{}, km, SignatureConfig{Mode: "x509"}, nil)
+	assert.ErrorContains(t, err, `unknown signature mode "x509"`)
+
+	sgn, _, err := signer.New(context.Background(), &signer.Config{})
+	require.NoError(t, err)
+	step, err := newSignStep(sgn, km, SignatureConfig{Mode: SignatureModeJWS}, nil)
+	require.NoError(t, err)
+	km.keyset.SigningKeyRef = "projects/p/keys/k1"
+	sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", nil), []byte(`{}`))
+	sctx.SubID = "bap.example.com"
+	assert.ErrorIs(t, step.Run(sctx), errJWSKeyRef)
+}
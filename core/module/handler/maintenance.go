@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+// defaultMaintenanceMessage is the NACK message when MaintenanceConfig.Message is unset.
+const defaultMaintenanceMessage = "Service is under maintenance"
+
+// MaintenanceSwitch is implemented by handlers that can be put into maintenance mode at runtime.
+type MaintenanceSwitch interface {
+	// SetMaintenance turns maintenance mode on or off.
+	SetMaintenance(on bool)
+	// Maintenance reports whether maintenance mode is on.
+	Maintenance() bool
+}
+
+// maintenanceMode answers all traffic with a fixed NACK while it is on.
+type maintenanceMode struct {
+	on     atomic.Bool
+	status int
+	err    model.Error
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg MaintenanceConfig) withDefaults() MaintenanceConfig {
+	if cfg.Status == 0 {
+		cfg.Status = http.StatusServiceUnavailable
+	}
+	if cfg.Code == "" {
+		cfg.Code = strconv.Itoa(cfg.Status)
+	}
+	if cfg.Message == "" {
+		cfg.Message = defaultMaintenanceMessage
+	}
+	return cfg
+}
+
+// newMaintenanceMode creates a maintenanceMode from cfg, filling in defaults.
+func newMaintenanceMode(cfg MaintenanceConfig) *maintenanceMode {
+	cfg = cfg.withDefaults()
+	m := &maintenanceMode{status: cfg.Status, err: model.Error{Code: cfg.Code, Message: cfg.Message}}
+	m.on.Store(cfg.Enabled)
+	return m
+}
+
+// active reports whether requests should be answered with the maintenance NACK.
+func (m *maintenanceMode) active() bool {
+	return m != nil && m.on.Load()
+}
+
+// respond sends the maintenance NACK.
+func (m *maintenanceMode) respond(ctx context.Context, w http.ResponseWriter) {
+	nackErr := m.err
+	response.SendNackStatus(ctx, w, &nackErr, m.status)
+}
+
+// SetMaintenance turns maintenance mode on or off.
+func (h *stdHandler) SetMaintenance(on bool) {
+	h.maintenance.on.Store(on)
+}
+
+// Maintenance reports whether maintenance mode is on.
+func (h *stdHandler) Maintenance() bool {
+	return h.maintenance.active()
+}
+
+// maintenanceRequest is the body of a request to the /debug/maintenance endpoint.
+type maintenanceRequest struct {
+	Enabled bool `json:"enabled"`
+	// Module limits the change to one module; empty applies it to all modules.
+	Module string `json:"module,omitempty"`
+}
+
+// MaintenanceHandler returns an http.Handler for the /debug/maintenance endpoint. GET
+// reports whether each module is in maintenance mode; POST turns it on or off for one
+// or all modules and reports the resulting state.
+func MaintenanceHandler(switches map[string]MaintenanceSwitch) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req maintenanceRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Module != "" {
+				s, ok := switches[req.Module]
+				if !ok {
+					http.Error(w, "unknown module: "+req.Module, http.StatusNotFound)
+					return
+				}
+				s.SetMaintenance(req.Enabled)
+				break
+			}
+			for _, s := range switches {
+				s.SetMaintenance(req.Enabled)
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp := make(map[string]bool, len(switches))
+		for name, s := range switches {
+			resp[name] = s.Maintenance()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPMaintenanceMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         MaintenanceConfig
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:        "defaults",
+			cfg:         MaintenanceConfig{Enabled: true},
+			wantStatus:  http.StatusServiceUnavailable,
+			wantCode:    "503",
+			wantMessage: defaultMaintenanceMessage,
+		},
+		{
+			name:        "custom NACK",
+			cfg:         MaintenanceConfig{Enabled: true, Status: http.StatusOK, Code: "20000", Message: "Back at 06:00 IST"},
+			wantStatus:  http.StatusOK,
+			wantCode:    "20000",
+			wantMessage: "Back at 06:00 IST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := 0
+			h := &stdHandler{
+				maintenance: newMaintenanceMode(tt.cfg),
+				steps: []definition.Step{stepFunc(func(*model.StepContext) error {
+					ran++
+					return nil
+				})},
+			}
+			serve := func() *httptest.ResponseRecorder {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`)))
+				return rec
+			}
+
+			rec := serve()
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var resp model.Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, model.StatusNACK, resp.Message.Ack.Status)
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, tt.wantCode, resp.Error.Code)
+			assert.Equal(t, tt.wantMessage, resp.Error.Message)
+			assert.Zero(t, ran, "steps must not run in maintenance mode")
+
+			h.SetMaintenance(false)
+			rec = serve()
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"ACK"`)
+			assert.Equal(t, 1, ran)
+		})
+	}
+}
+
+func TestMaintenanceHandler(t *testing.T) {
+	newSwitches := func() map[string]MaintenanceSwitch {
+		return map[string]MaintenanceSwitch{
+			"bapTxnReceiver": &stdHandler{maintenance: newMaintenanceMode(MaintenanceConfig{})},
+			"bapTxnCaller":   &stdHandler{maintenance: newMaintenanceMode(MaintenanceConfig{Enabled: true})},
+		}
+	}
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		wantCode int
+		want     map[string]bool
+	}{
+		{
+			name:     "report state",
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+			want:     map[string]bool{"bapTxnReceiver": false, "bapTxnCaller": true},
+		},
+		{
+			name:     "enable all",
+			method:   http.MethodPost,
+			body:     `{"enabled":true}`,
+			wantCode: http.StatusOK,
+			want:     map[string]bool{"bapTxnReceiver": true, "bapTxnCaller": true},
+		},
+		{
+			name:     "disable one module",
+			method:   http.MethodPost,
+			body:     `{"enabled":false,"module":"bapTxnCaller"}`,
+			wantCode: http.StatusOK,
+			want:     map[string]bool{"bapTxnReceiver": false, "bapTxnCaller": false},
+		},
+		{
+			name:     "unknown module",
+			method:   http.MethodPost,
+			body:     `{"enabled":true,"module":"nope"}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid body",
+			method:   http.MethodPost,
+			body:     `on`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodDelete,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			MaintenanceHandler(newSwitches()).ServeHTTP(rec, httptest.NewRequest(tt.method, "/debug/maintenance", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.want == nil {
+				return
+			}
+			var got map[string]bool
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// assignMessageIDStep gives requests without a context.message_id a server-generated
+// UUID, for lenient flows that leave assigning it to the gateway.
+type assignMessageIDStep struct{}
+
+// Run writes a new message ID into ctx.Body when context.message_id is missing or
+// empty, and records it in ctx.BecknContext and as the message ID of the request context,
+// so it is logged and echoed in responses. Other requests are left untouched.
+func (assignMessageIDStep) Run(ctx *model.StepContext) error {
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return err
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to parse body: %w", err))
+	}
+	var bCtx map[string]json.RawMessage
+	if raw, ok := payload["context"]; !ok || json.Unmarshal(raw, &bCtx) != nil || bCtx == nil {
+		return model.NewBadReqErr(errors.New("context is missing or not an object"))
+	}
+	if raw, ok := bCtx["message_id"]; ok {
+		var id string
+		if err := json.Unmarshal(raw, &id); err != nil {
+			return model.NewBadReqErr(errors.New("context.message_id is not a string"))
+		}
+		if id != "" {
+			return nil
+		}
+	}
+
+	id := uuid.NewString()
+	if bCtx["message_id"], err = marshalJSON(id); err != nil {
+		return err
+	}
+	if payload["context"], err = marshalJSON(bCtx); err != nil {
+		return err
+	}
+	if ctx.Body, err = marshalJSON(payload); err != nil {
+		return err
+	}
+	if ctx.BecknContext != nil {
+		ctx.BecknContext.MessageID = id
+	}
+	ctx.Context = context.WithValue(ctx.Context, model.ContextKeyMsgID, id)
+	return nil
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+func TestAssignMessageIDStepGenerates(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "absent", body: `{"context":{"action":"search","transaction_id":"t1"},"message":{"q":"<a&b>"}}`},
+		{name: "empty", body: `{"context":{"action":"search","message_id":"","transaction_id":"t1"},"message":{"q":"<a&b>"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(tt.body))
+			ctx.BecknContext = &model.BecknContext{Action: "search", TransactionID: "t1"}
+
+			require.NoError(t, assignMessageIDStep{}.Run(ctx))
+
+			var got struct {
+				Context map[string]string `json:"context"`
+				Message map[string]string `json:"message"`
+			}
+			require.NoError(t, json.Unmarshal(ctx.Body, &got))
+			id := got.Context["message_id"]
+			_, err := uuid.Parse(id)
+			require.NoError(t, err, "message_id %q is not a UUID", id)
+			assert.Equal(t, map[string]string{"action": "search", "message_id": id, "transaction_id": "t1"}, got.Context)
+			assert.Equal(t, map[string]string{"q": "<a&b>"}, got.Message)
+			assert.Equal(t, id, ctx.BecknContext.MessageID)
+			assert.Equal(t, id, ctx.Value(model.ContextKeyMsgID))
+		})
+	}
+}
+
+func TestAssignMessageIDStepKeepsPresentID(t *testing.T) {
+	body := `{"context": {"action": "search", "message_id": "m1"}, "message": {}}`
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(body))
+
+	require.NoError(t, assignMessageIDStep{}.Run(ctx))
+
+	assert.Equal(t, body, string(ctx.Body), "the body is left untouched")
+	assert.Nil(t, ctx.Value(model.ContextKeyMsgID))
+}
+
+func TestAssignMessageIDStepRejectsInvalidContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "not JSON", body: `{`, wantErr: "failed to parse body"},
+		{name: "no context", body: `{"message":{}}`, wantErr: "context is missing or not an object"},
+		{name: "message_id not a string", body: `{"context":{"message_id":42}}`, wantErr: "context.message_id is not a string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(tt.body))
+
+			err := assignMessageIDStep{}.Run(ctx)
+
+			var badReq *model.BadReqErr
+			require.ErrorAs(t, err, &badReq)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
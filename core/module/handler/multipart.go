@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// maxMultipartPayloadSize bounds the payload part read from a multipart body.
+const maxMultipartPayloadSize = 10 << 20
+
+// payloadSteps lists the steps that read the Beckn payload, and whether each is
+// skipped for multipart requests without a configured payload part.
+var payloadSteps = map[string]bool{
+	"validateSchema":               true,
+	"validateOndcPayload":          true,
+	"validateOndcCallSave":         true,
+	"validateOndcAndSave":          true,
+	"ondcWorkbenchValidateContext": true,
+	"ondcWorkbenchReceiver":        false,
+	"addRoute":                     false,
+}
+
+// payloadKey is the context key of the *payloadBody of a request.
+type payloadKey struct{}
+
+// payloadBody is the Beckn payload of a request whose body is not the JSON payload
+// itself: a multipart/form-data request or a body in a non-JSON format.
+type payloadBody struct {
+	// payload is the content of the configured payload part, or the JSON form of a
+	// decoded body; nil for multipart requests without a configured payload part.
+	payload []byte
+}
+
+// readMultipart returns the payloadBody of r, or nil if r is not multipart/form-data.
+// body is rewound after the payload part is read.
+func (h *stdHandler) readMultipart(r *http.Request, body *requestBody) (*payloadBody, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return nil, nil
+	}
+	if params["boundary"] == "" {
+		return nil, errors.New("multipart body has no boundary")
+	}
+	if h.multipart.PayloadPart == "" {
+		return &payloadBody{}, nil
+	}
+	src := body.reader()
+	payload, err := readFormPart(src, params["boundary"], h.multipart.PayloadPart)
+	if _, seekErr := src.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, fmt.Errorf("failed to rewind body: %w", seekErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &payloadBody{payload: payload}, nil
+}
+
+// readFormPart returns the content of the form part called name in the multipart body r.
+func readFormPart(r io.Reader, boundary, name string) ([]byte, error) {
+	mr := multipart.NewReader(r, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("multipart body has no %q part", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart body: %w", err)
+		}
+		if part.FormName() != name {
+			continue
+		}
+		payload, err := io.ReadAll(io.LimitReader(part, maxMultipartPayloadSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read multipart %q part: %w", name, err)
+		}
+		if len(payload) > maxMultipartPayloadSize {
+			return nil, fmt.Errorf("multipart %q part exceeds %d bytes", name, maxMultipartPayloadSize)
+		}
+		return payload, nil
+	}
+}
+
+// payloadStep runs a payload step against the payloadBody of a request.
+type payloadStep struct {
+	step definition.Step
+	// skip bypasses the step for multipart requests without a payload part.
+	skip bool
+}
+
+// withPayload wraps step so that for requests with a payloadBody it sees the payload
+// as the body. The request body itself is restored afterwards and forwarded unchanged.
+func withPayload(step definition.Step, skip bool) definition.Step {
+	return &payloadStep{step: step, skip: skip}
+}
+
+// Run executes the wrapped step, swapping in the payloadBody of the request.
+func (s *payloadStep) Run(ctx *model.StepContext) error {
+	mp, ok := ctx.Value(payloadKey{}).(*payloadBody)
+	if !ok {
+		return s.step.Run(ctx)
+	}
+	if mp.payload == nil {
+		if s.skip {
+			log.Debugf(ctx, "Bypassing %T for multipart request without a payload part", s.step)
+			return nil
+		}
+		return s.step.Run(ctx)
+	}
+	body := ctx.Body
+	ctx.Body = mp.payload
+	defer func() { ctx.Body = body }()
+	return s.step.Run(ctx)
+}
+
+// withPayloadBody attaches mp to ctx. It returns ctx unchanged when mp is nil.
+func withPayloadBody(ctx context.Context, mp *payloadBody) context.Context {
+	if mp == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, payloadKey{}, mp)
+}
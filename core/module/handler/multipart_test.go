@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multipartPayload = `{"context":{"domain":"retail","action":"update"},"message":{}}`
+
+// newMultipartBody builds a form with a payload part and a file part.
+func newMultipartBody(t *testing.T) (body []byte, contentType string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("payload", multipartPayload))
+	fw, err := mw.CreateFormFile("document", "invoice.pdf")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("%PDF-1.4 binary \x00\x01\x02 content"))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	return buf.Bytes(), mw.FormDataContentType()
+}
+
+func TestServeHTTPForwardsMultipartUnchanged(t *testing.T) {
+	var gotBody []byte
+	var gotType string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotType = r.Header.Get("Content-Type")
+		_, _ = w.Write([]byte(`{"message":{"ack":{"status":"ACK"}}}`))
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	body, contentType := newMultipartBody(t)
+
+	tests := []struct {
+		name          string
+		cfg           MultipartConfig
+		buffer        BodyBufferConfig
+		wantValidated string
+		wantAction    string
+	}{
+		{
+			name:          "payload part validated",
+			cfg:           MultipartConfig{PayloadPart: "payload"},
+			wantValidated: multipartPayload,
+			wantAction:    "update",
+		},
+		{
+			name:          "payload part of a spilled body",
+			cfg:           MultipartConfig{PayloadPart: "payload"},
+			buffer:        BodyBufferConfig{SpillThreshold: 16, TempDir: t.TempDir()},
+			wantValidated: multipartPayload,
+			wantAction:    "update",
+		},
+		{
+			name: "validation skipped without a payload part",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBody, gotType = nil, ""
+			var validated, routed []byte
+			var bCtx *model.BecknContext
+			h := &stdHandler{
+				httpClient: upstream.Client(),
+				multipart:  tt.cfg,
+				bodyBuffer: tt.buffer,
+				steps: []definition.Step{
+					withPayload(stepFunc(func(ctx *model.StepContext) error {
+						validated = ctx.Body
+						ctx.Body = []byte(`{"modified":true}`)
+						return nil
+					}), true),
+					withPayload(stepFunc(func(ctx *model.StepContext) error {
+						routed, bCtx = ctx.Body, ctx.BecknContext
+						ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+						return nil
+					}), false),
+				},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/update", bytes.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, body, gotBody, "multipart body must be forwarded unchanged")
+			assert.Equal(t, contentType, gotType)
+			if tt.wantValidated == "" {
+				assert.Nil(t, validated, "validation step must be skipped")
+				assert.Equal(t, body, routed)
+				return
+			}
+			assert.Equal(t, tt.wantValidated, string(validated))
+			assert.Equal(t, tt.wantValidated, string(routed))
+			require.NotNil(t, bCtx)
+			assert.Equal(t, tt.wantAction, bCtx.Action)
+		})
+	}
+}
+
+func TestServeHTTPRejectsInvalidMultipart(t *testing.T) {
+	body, contentType := newMultipartBody(t)
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+	}{
+		{name: "missing payload part", body: body, contentType: "multipart/form-data; boundary=other"},
+		{name: "missing boundary", body: body, contentType: "multipart/form-data"},
+		{name: "malformed body", body: []byte("not multipart"), contentType: contentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			h := &stdHandler{
+				multipart: MultipartConfig{PayloadPart: "payload"},
+				steps: []definition.Step{stepFunc(func(*model.StepContext) error {
+					ran = true
+					return nil
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/update", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"NACK"`)
+			assert.False(t, ran)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+// defaultNoRouteMessage is the NACK message for unrouted requests when NoRouteConfig.Message is unset.
+const defaultNoRouteMessage = "no route for request"
+
+// noRoute answers requests that no step routed.
+type noRoute struct {
+	behavior NoRouteBehavior
+	status   int
+	err      model.Error
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg NoRouteConfig) withDefaults() NoRouteConfig {
+	if cfg.Message == "" {
+		cfg.Message = defaultNoRouteMessage
+	}
+	return cfg
+}
+
+// newNoRoute creates a noRoute from cfg, filling in defaults.
+func newNoRoute(cfg NoRouteConfig) (*noRoute, error) {
+	cfg = cfg.withDefaults()
+	n := &noRoute{behavior: cfg.Behavior, status: cfg.Status, err: model.Error{Code: cfg.Code, Message: cfg.Message}}
+	switch n.behavior {
+	case "", NoRouteAck:
+		n.behavior = NoRouteAck
+	case NoRouteNotFound:
+	case NoRouteStatus:
+		if n.status < 400 || n.status > 599 {
+			return nil, fmt.Errorf("invalid config: noRoute status %d is not an error status", n.status)
+		}
+		if n.err.Code == "" {
+			n.err.Code = strconv.Itoa(n.status)
+		}
+	default:
+		return nil, fmt.Errorf("invalid config: unknown noRoute behavior %q", n.behavior)
+	}
+	return n, nil
+}
+
+// respond answers a request that no step routed according to the configured behavior.
+func (n *noRoute) respond(ctx context.Context, w http.ResponseWriter) {
+	switch {
+	case n == nil || n.behavior == NoRouteAck:
+		response.SendAck(ctx, w)
+	case n.behavior == NoRouteNotFound:
+		response.SendNack(ctx, w, model.NewNotFoundErr(errors.New(n.err.Message)))
+	default:
+		nackErr := n.err
+		response.SendNackStatus(ctx, w, &nackErr, n.status)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPNoRoute(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         NoRouteConfig
+		wantStatus  int
+		wantAck     model.Status
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:       "default acks",
+			wantStatus: http.StatusOK,
+			wantAck:    model.StatusACK,
+		},
+		{
+			name:       "explicit ack",
+			cfg:        NoRouteConfig{Behavior: NoRouteAck},
+			wantStatus: http.StatusOK,
+			wantAck:    model.StatusACK,
+		},
+		{
+			name:        "notFound nacks",
+			cfg:         NoRouteConfig{Behavior: NoRouteNotFound},
+			wantStatus:  http.StatusNotFound,
+			wantAck:     model.StatusNACK,
+			wantCode:    http.StatusText(http.StatusNotFound),
+			wantMessage: "Endpoint not found: " + defaultNoRouteMessage,
+		},
+		{
+			name:        "custom status",
+			cfg:         NoRouteConfig{Behavior: NoRouteStatus, Status: http.StatusBadGateway, Message: "no upstream for action"},
+			wantStatus:  http.StatusBadGateway,
+			wantAck:     model.StatusNACK,
+			wantCode:    "502",
+			wantMessage: "no upstream for action",
+		},
+		{
+			name:        "custom status and code",
+			cfg:         NoRouteConfig{Behavior: NoRouteStatus, Status: http.StatusNotImplemented, Code: "30004"},
+			wantStatus:  http.StatusNotImplemented,
+			wantAck:     model.StatusNACK,
+			wantCode:    "30004",
+			wantMessage: defaultNoRouteMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nr, err := newNoRoute(tt.cfg)
+			require.NoError(t, err)
+			h := &stdHandler{noRoute: nr}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`)))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var resp model.Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantAck, resp.Message.Ack.Status)
+			if tt.wantCode == "" {
+				assert.Nil(t, resp.Error)
+				return
+			}
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, tt.wantCode, resp.Error.Code)
+			assert.Equal(t, tt.wantMessage, resp.Error.Message)
+		})
+	}
+}
+
+func TestNewNoRouteInvalid(t *testing.T) {
+	_, err := newNoRoute(NoRouteConfig{Behavior: "drop"})
+	assert.ErrorContains(t, err, `unknown noRoute behavior "drop"`)
+
+	_, err = newNoRoute(NoRouteConfig{Behavior: NoRouteStatus})
+	assert.ErrorContains(t, err, "noRoute status 0 is not an error status")
+
+	_, err = newNoRoute(NoRouteConfig{Behavior: NoRouteStatus, Status: http.StatusOK})
+	assert.ErrorContains(t, err, "noRoute status 200 is not an error status")
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// normalizeContextStep rewrites context.domain, context.version and context.core_version
+// of the request body to their canonical forms, so later steps and the forwarded request
+// see the same values.
+type normalizeContextStep struct {
+	domainCase        ContextCase
+	keepVersionPrefix bool
+	domains           map[string]string
+	versions          map[string]string
+}
+
+// newNormalizeContextStep creates and returns the normalizeContext step.
+func newNormalizeContextStep(cfg NormalizeContextConfig) (definition.Step, error) {
+	s := &normalizeContextStep{
+		domainCase:        cfg.DomainCase,
+		keepVersionPrefix: cfg.KeepVersionPrefix,
+		domains:           cfg.Domains,
+		versions:          cfg.Versions,
+	}
+	switch s.domainCase {
+	case "":
+		s.domainCase = ContextCaseUpper
+	case ContextCaseUpper, ContextCaseLower, ContextCaseKeep:
+	default:
+		return nil, fmt.Errorf("invalid config: unknown normalizeContext domainCase %q", s.domainCase)
+	}
+	return s, nil
+}
+
+// Run rewrites the context fields in ctx.Body and ctx.BecknContext. The body is left
+// untouched when every field is already canonical.
+func (s *normalizeContextStep) Run(ctx *model.StepContext) error {
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return err
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to parse body: %w", err))
+	}
+	var bCtx map[string]json.RawMessage
+	if raw, ok := payload["context"]; !ok || json.Unmarshal(raw, &bCtx) != nil || bCtx == nil {
+		return model.NewBadReqErr(errors.New("context is missing or not an object"))
+	}
+
+	changed := false
+	for field, normalize := range map[string]func(string) string{
+		"domain":       s.domain,
+		"version":      s.version,
+		"core_version": s.version,
+	} {
+		raw, ok := bCtx[field]
+		if !ok {
+			continue
+		}
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return model.NewBadReqErr(fmt.Errorf("context.%s is not a string", field))
+		}
+		n := normalize(v)
+		if n == v {
+			continue
+		}
+		if bCtx[field], err = marshalJSON(n); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if payload["context"], err = marshalJSON(bCtx); err != nil {
+		return err
+	}
+	if ctx.Body, err = marshalJSON(payload); err != nil {
+		return err
+	}
+	if ctx.BecknContext != nil {
+		ctx.BecknContext.Domain = s.domain(ctx.BecknContext.Domain)
+		ctx.BecknContext.Version = s.version(ctx.BecknContext.Version)
+		ctx.BecknContext.CoreVersion = s.version(ctx.BecknContext.CoreVersion)
+	}
+	return nil
+}
+
+// domain returns the canonical form of a context domain.
+func (s *normalizeContextStep) domain(d string) string {
+	d = strings.TrimSpace(d)
+	switch s.domainCase {
+	case ContextCaseUpper:
+		d = strings.ToUpper(d)
+	case ContextCaseLower:
+		d = strings.ToLower(d)
+	}
+	if c, ok := s.domains[d]; ok {
+		return c
+	}
+	return d
+}
+
+// version returns the canonical form of a context version.
+func (s *normalizeContextStep) version(v string) string {
+	v = strings.TrimSpace(v)
+	if !s.keepVersionPrefix && len(v) > 1 && (v[0] == 'v' || v[0] == 'V') {
+		v = v[1:]
+	}
+	if c, ok := s.versions[v]; ok {
+		return c
+	}
+	return v
+}
+
+// marshalJSON encodes v without escaping HTML characters, so values copied from the
+// request are forwarded as received.
+func marshalJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeContextStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     NormalizeContextConfig
+		body    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			body: `{"context":{"domain":" ondc:ret10 ","version":"v2.0.0","core_version":"V1.2.0","action":"search"},"message":{"q":"<a&b>"}}`,
+			want: `{"context":{"action":"search","core_version":"1.2.0","domain":"ONDC:RET10","version":"2.0.0"},"message":{"q":"<a&b>"}}`,
+		},
+		{
+			name: "lower case and aliases",
+			cfg: NormalizeContextConfig{
+				DomainCase: ContextCaseLower,
+				Domains:    map[string]string{"ondc:retail10": "ondc:ret10"},
+				Versions:   map[string]string{"1.2": "1.2.0"},
+			},
+			body: `{"context":{"domain":"ONDC:RETAIL10","core_version":"v1.2"}}`,
+			want: `{"context":{"core_version":"1.2.0","domain":"ondc:ret10"}}`,
+		},
+		{
+			name: "keep case and prefix",
+			cfg:  NormalizeContextConfig{DomainCase: ContextCaseKeep, KeepVersionPrefix: true},
+			body: `{"context":{"domain":"Retail ","version":"v2.0.0"}}`,
+			want: `{"context":{"domain":"Retail","version":"v2.0.0"}}`,
+		},
+		{
+			name: "canonical body is left untouched",
+			body: `{"message":{}, "context":{"version":"2.0.0","domain":"ONDC:RET10"}}`,
+			want: `{"message":{}, "context":{"version":"2.0.0","domain":"ONDC:RET10"}}`,
+		},
+		{
+			name:    "missing context",
+			body:    `{"message":{}}`,
+			wantErr: "context is missing",
+		},
+		{
+			name:    "non-string domain",
+			body:    `{"context":{"domain":10}}`,
+			wantErr: "context.domain is not a string",
+		},
+		{
+			name:    "not json",
+			body:    `not json`,
+			wantErr: "failed to parse body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newNormalizeContextStep(tt.cfg)
+			require.NoError(t, err)
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(tt.body))
+
+			err = step.Run(ctx)
+
+			if tt.wantErr != "" {
+				var badReq *model.BadReqErr
+				assert.ErrorAs(t, err, &badReq)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(ctx.Body))
+		})
+	}
+}
+
+func TestNewNormalizeContextStepInvalidCase(t *testing.T) {
+	_, err := newNormalizeContextStep(NormalizeContextConfig{DomainCase: "title"})
+	assert.ErrorContains(t, err, `unknown normalizeContext domainCase "title"`)
+}
+
+func TestServeHTTPNormalizeContextVisibleToLaterSteps(t *testing.T) {
+	normalize, err := newNormalizeContextStep(NormalizeContextConfig{})
+	require.NoError(t, err)
+	var body string
+	var bCtx model.BecknContext
+	h := &stdHandler{
+		steps: []definition.Step{normalize, stepFunc(func(ctx *model.StepContext) error {
+			body = string(ctx.Body)
+			require.NotNil(t, ctx.BecknContext)
+			bCtx = *ctx.BecknContext
+			return nil
+		})},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search",
+		strings.NewReader(`{"context":{"domain":"ondc:ret10","action":"search","version":"v2.0.0"},"message":{}}`))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, `{"context":{"action":"search","domain":"ONDC:RET10","version":"2.0.0"},"message":{}}`, body)
+	assert.Equal(t, "ONDC:RET10", bCtx.Domain)
+	assert.Equal(t, "2.0.0", bCtx.Version)
+	assert.Equal(t, "search", bCtx.Action)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// timestampLayouts are the layouts context timestamps are parsed with, in order. Times
+// without a zone are taken to be in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// parseTimestamp parses a context timestamp in any of timestampLayouts.
+func parseTimestamp(v string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, v); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("context.timestamp %q is not a recognised time", v)
+}
+
+// canonicalTimestamp returns ts in RFC 3339 form in UTC, with as many fractional second
+// digits as it needs.
+func canonicalTimestamp(ts time.Time) string {
+	return ts.UTC().Format(time.RFC3339Nano)
+}
+
+// conformingTimestamp reports whether v is an RFC 3339 time in UTC, such as
+// 2024-05-01T10:00:00.000Z, which is left as it is.
+func conformingTimestamp(v string) bool {
+	_, err := time.Parse(time.RFC3339Nano, v)
+	return err == nil && strings.HasSuffix(v, "Z")
+}
+
+// normalizeTimestampStep rewrites context.timestamp of the request body in RFC 3339 form
+// in UTC, so later steps and the forwarded request see it in one form.
+type normalizeTimestampStep struct {
+	reject bool
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg NormalizeTimestampConfig) withDefaults() NormalizeTimestampConfig {
+	if cfg.NonConforming == "" {
+		cfg.NonConforming = TimestampRewrite
+	}
+	return cfg
+}
+
+// newNormalizeTimestampStep creates and returns the normalizeTimestamp step.
+func newNormalizeTimestampStep(cfg NormalizeTimestampConfig) (definition.Step, error) {
+	switch cfg = cfg.withDefaults(); cfg.NonConforming {
+	case TimestampRewrite:
+		return &normalizeTimestampStep{}, nil
+	case TimestampReject:
+		return &normalizeTimestampStep{reject: true}, nil
+	default:
+		return nil, fmt.Errorf("invalid config: unknown normalizeTimestamp nonConforming %q", cfg.NonConforming)
+	}
+}
+
+// Run rewrites context.timestamp in ctx.Body and ctx.BecknContext, or rejects the request
+// if the step is set to reject timestamps that do not conform. The body is left
+// untouched when the timestamp already conforms.
+func (s *normalizeTimestampStep) Run(ctx *model.StepContext) error {
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return err
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to parse body: %w", err))
+	}
+	var bCtx map[string]json.RawMessage
+	if raw, ok := payload["context"]; !ok || json.Unmarshal(raw, &bCtx) != nil || bCtx == nil {
+		return model.NewBadReqErr(errors.New("context is missing or not an object"))
+	}
+	raw, ok := bCtx["timestamp"]
+	if !ok {
+		return model.NewBadReqErr(errors.New("context.timestamp is missing"))
+	}
+	var v string
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return model.NewBadReqErr(errors.New("context.timestamp is not a string"))
+	}
+	if conformingTimestamp(v) {
+		return nil
+	}
+	ts, err := parseTimestamp(v)
+	if err != nil {
+		return model.NewBadReqErr(err)
+	}
+	canonical := canonicalTimestamp(ts)
+	if s.reject {
+		return model.NewBadReqErr(fmt.Errorf("context.timestamp %q is not an RFC 3339 UTC time such as %s", v, canonical))
+	}
+	log.Warnf(ctx, "Normalized context.timestamp %q to %s", v, canonical)
+
+	if bCtx["timestamp"], err = marshalJSON(canonical); err != nil {
+		return err
+	}
+	if payload["context"], err = marshalJSON(bCtx); err != nil {
+		return err
+	}
+	if ctx.Body, err = marshalJSON(payload); err != nil {
+		return err
+	}
+	if ctx.BecknContext != nil {
+		ctx.BecknContext.Timestamp = canonical
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTimestampStep(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp string
+		want      string
+	}{
+		{name: "UTC is left untouched", timestamp: "2024-05-01T10:00:00.000Z", want: "2024-05-01T10:00:00.000Z"},
+		{name: "UTC without fraction", timestamp: "2024-05-01T10:00:00Z", want: "2024-05-01T10:00:00Z"},
+		{name: "offset", timestamp: "2024-05-01T15:30:00.123+05:30", want: "2024-05-01T10:00:00.123Z"},
+		{name: "zero offset", timestamp: "2024-05-01T10:00:00+00:00", want: "2024-05-01T10:00:00Z"},
+		{name: "offset without colon", timestamp: "2024-05-01T06:00:00-0400", want: "2024-05-01T10:00:00Z"},
+		{name: "space separator", timestamp: "2024-05-01 12:00:00+02:00", want: "2024-05-01T10:00:00Z"},
+		{name: "no zone is UTC", timestamp: "2024-05-01T10:00:00.5", want: "2024-05-01T10:00:00.5Z"},
+		{name: "RFC 1123", timestamp: "Wed, 01 May 2024 10:00:00 GMT", want: "2024-05-01T10:00:00Z"},
+		{name: "surrounding whitespace", timestamp: " 2024-05-01T10:00:00Z ", want: "2024-05-01T10:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newNormalizeTimestampStep(NormalizeTimestampConfig{})
+			require.NoError(t, err)
+			body := `{"context":{"action":"search","timestamp":"` + tt.timestamp + `"},"message":{}}`
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(body))
+
+			require.NoError(t, step.Run(ctx))
+
+			if tt.want == tt.timestamp {
+				assert.Equal(t, body, string(ctx.Body), "a conforming body is left untouched")
+				return
+			}
+			assert.Equal(t, `{"context":{"action":"search","timestamp":"`+tt.want+`"},"message":{}}`, string(ctx.Body))
+		})
+	}
+}
+
+func TestNormalizeTimestampStepErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     NormalizeTimestampConfig
+		body    string
+		wantErr string
+	}{
+		{
+			name:    "malformed",
+			body:    `{"context":{"timestamp":"yesterday at noon"}}`,
+			wantErr: `context.timestamp "yesterday at noon" is not a recognised time`,
+		},
+		{
+			name:    "malformed is rejected in reject mode too",
+			cfg:     NormalizeTimestampConfig{NonConforming: TimestampReject},
+			body:    `{"context":{"timestamp":"2024-13-01T10:00:00Z"}}`,
+			wantErr: "is not a recognised time",
+		},
+		{
+			name:    "non-conforming in reject mode",
+			cfg:     NormalizeTimestampConfig{NonConforming: TimestampReject},
+			body:    `{"context":{"timestamp":"2024-05-01T15:30:00+05:30"}}`,
+			wantErr: `context.timestamp "2024-05-01T15:30:00+05:30" is not an RFC 3339 UTC time such as 2024-05-01T10:00:00Z`,
+		},
+		{
+			name:    "missing",
+			body:    `{"context":{"action":"search"}}`,
+			wantErr: "context.timestamp is missing",
+		},
+		{
+			name:    "not a string",
+			body:    `{"context":{"timestamp":1714557600}}`,
+			wantErr: "context.timestamp is not a string",
+		},
+		{
+			name:    "missing context",
+			body:    `{"message":{}}`,
+			wantErr: "context is missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newNormalizeTimestampStep(tt.cfg)
+			require.NoError(t, err)
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(tt.body))
+
+			err = step.Run(ctx)
+
+			var badReq *model.BadReqErr
+			assert.ErrorAs(t, err, &badReq)
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Equal(t, tt.body, string(ctx.Body))
+		})
+	}
+}
+
+func TestNormalizeTimestampStepRejectAcceptsConforming(t *testing.T) {
+	step, err := newNormalizeTimestampStep(NormalizeTimestampConfig{NonConforming: TimestampReject})
+	require.NoError(t, err)
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(`{"context":{"timestamp":"2024-05-01T10:00:00.000Z"}}`))
+
+	assert.NoError(t, step.Run(ctx))
+}
+
+func TestNewNormalizeTimestampStepInvalidAction(t *testing.T) {
+	_, err := newNormalizeTimestampStep(NormalizeTimestampConfig{NonConforming: "drop"})
+	assert.ErrorContains(t, err, `unknown normalizeTimestamp nonConforming "drop"`)
+}
+
+func TestServeHTTPNormalizeTimestampVisibleToLaterSteps(t *testing.T) {
+	normalize, err := newNormalizeTimestampStep(NormalizeTimestampConfig{})
+	require.NoError(t, err)
+	var body string
+	var bCtx model.BecknContext
+	h := &stdHandler{
+		steps: []definition.Step{normalize, stepFunc(func(ctx *model.StepContext) error {
+			body = string(ctx.Body)
+			require.NotNil(t, ctx.BecknContext)
+			bCtx = *ctx.BecknContext
+			return nil
+		})},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search",
+		strings.NewReader(`{"context":{"action":"search","timestamp":"2024-05-01T15:30:00+05:30"},"message":{}}`))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, `{"context":{"action":"search","timestamp":"2024-05-01T10:00:00Z"},"message":{}}`, body)
+	assert.Equal(t, "2024-05-01T10:00:00Z", bCtx.Timestamp)
+}
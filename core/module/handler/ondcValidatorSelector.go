@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// domainOndcValidator dispatches ONDC validation to a validator selected by context.domain.
+type domainOndcValidator struct {
+	byDomain map[string]definition.OndcValidator
+	fallback definition.OndcValidator
+}
+
+// newDomainOndcValidator creates a domainOndcValidator. fallback may be nil, in which case
+// payloads for unconfigured domains are rejected.
+func newDomainOndcValidator(byDomain map[string]definition.OndcValidator, fallback definition.OndcValidator) *domainOndcValidator {
+	return &domainOndcValidator{byDomain: byDomain, fallback: fallback}
+}
+
+// ValidatePayload validates the body with the validator configured for its domain.
+func (v *domainOndcValidator) ValidatePayload(ctx context.Context, u *url.URL, body []byte) error {
+	validator, err := v.validator(ctx, body)
+	if err != nil {
+		return err
+	}
+	return validator.ValidatePayload(ctx, u, body)
+}
+
+// SaveValidationData saves validation data with the validator configured for the body's domain.
+func (v *domainOndcValidator) SaveValidationData(ctx context.Context, u *url.URL, body []byte) error {
+	validator, err := v.validator(ctx, body)
+	if err != nil {
+		return err
+	}
+	return validator.SaveValidationData(ctx, u, body)
+}
+
+// SelfTest runs the self-test of every configured validator that supports one.
+func (v *domainOndcValidator) SelfTest(ctx context.Context) error {
+	domains := make([]string, 0, len(v.byDomain))
+	for domain := range v.byDomain {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		if st, ok := v.byDomain[domain].(definition.SelfTester); ok {
+			if err := st.SelfTest(ctx); err != nil {
+				return fmt.Errorf("domain %s: %w", domain, err)
+			}
+		}
+	}
+	if st, ok := v.fallback.(definition.SelfTester); ok {
+		return st.SelfTest(ctx)
+	}
+	return nil
+}
+
+// validator returns the validator for the domain of the request, or the fallback if none
+// matches. The domain is taken from the context parsed from the request, and only parsed
+// from body for callers outside a step that have none.
+func (v *domainOndcValidator) validator(ctx context.Context, body []byte) (definition.OndcValidator, error) {
+	domain := ""
+	bCtx := becknContextFrom(ctx)
+	if bCtx == nil {
+		var err error
+		if bCtx, err = model.ParseBecknContext(body); err != nil {
+			log.Debugf(ctx, "Using fallback OndcValidator: %v", err)
+		}
+	}
+	if bCtx != nil {
+		domain = bCtx.Domain
+	}
+	if validator, ok := v.byDomain[domain]; ok {
+		return validator, nil
+	}
+	if v.fallback == nil {
+		return nil, model.NewBadReqErr(fmt.Errorf("no OndcValidator configured for domain %q", domain))
+	}
+	return v.fallback, nil
+}
+
+// loadOndcValidators loads the default OndcValidator and any per-domain validators. With no
+// per-domain validators configured it returns the default validator unchanged.
+func loadOndcValidators(ctx context.Context, mgr PluginManager, cache definition.Cache, cfg *plugin.Config, byDomain map[string]*plugin.Config) (definition.OndcValidator, error) {
+	fallback, err := loadOndcValidator(ctx, mgr, cache, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(byDomain) == 0 {
+		return fallback, nil
+	}
+	validators := make(map[string]definition.OndcValidator, len(byDomain))
+	for domain, dCfg := range byDomain {
+		if dCfg == nil {
+			return nil, fmt.Errorf("ondcValidators: missing plugin config for domain %q", domain)
+		}
+		if validators[domain], err = loadOndcValidator(ctx, mgr, cache, dCfg); err != nil {
+			return nil, fmt.Errorf("ondcValidators[%s]: %w", domain, err)
+		}
+	}
+	return newDomainOndcValidator(validators, fallback), nil
+}
+
+// becknContextKey is the context key of a *model.BecknContext attached by withBecknContext.
+type becknContextKey struct{}
+
+// withBecknContext attaches bCtx to ctx, for calls that outlive the step context, such as
+// saves run after the response. It returns ctx unchanged when bCtx is nil.
+func withBecknContext(ctx context.Context, bCtx *model.BecknContext) context.Context {
+	if bCtx == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, becknContextKey{}, bCtx)
+}
+
+// becknContextFrom returns the context parsed from the request ctx is for: that of a
+// *model.StepContext, or one attached by withBecknContext. It returns nil when there is none.
+func becknContextFrom(ctx context.Context) *model.BecknContext {
+	if sc, ok := ctx.(*model.StepContext); ok && sc.BecknContext != nil {
+		return sc.BecknContext
+	}
+	bCtx, _ := ctx.Value(becknContextKey{}).(*model.BecknContext)
+	return bCtx
+}
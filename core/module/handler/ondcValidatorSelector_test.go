@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+func TestDomainOndcValidatorRoutesByDomain(t *testing.T) {
+	retail, logistics, fallback := &mockOndcValidator{}, &mockOndcValidator{}, &mockOndcValidator{}
+	v := newDomainOndcValidator(map[string]definition.OndcValidator{
+		"ONDC:RET10": retail,
+		"ONDC:LOG10": logistics,
+	}, fallback)
+	step, err := newValidateOndcStep(v, DefaultProtocolValidationCookie)
+	require.NoError(t, err)
+
+	for _, domain := range []string{"ONDC:RET10", "ONDC:LOG10", "ONDC:LOG10", "ONDC:TRV10"} {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		require.NoError(t, step.Run(newTestStepCtx(r, testBody("search", domain))))
+	}
+
+	assert.Len(t, retail.calls, 1)
+	assert.Len(t, logistics.calls, 2)
+	assert.Len(t, fallback.calls, 1)
+}
+
+func TestDomainOndcValidatorSave(t *testing.T) {
+	retail, fallback := &mockOndcValidator{}, &mockOndcValidator{}
+	v := newDomainOndcValidator(map[string]definition.OndcValidator{"ONDC:RET10": retail}, fallback)
+
+	require.NoError(t, v.SaveValidationData(context.Background(), nil, testBody("search", "ONDC:RET10")))
+
+	assert.Equal(t, []string{"save"}, retail.calls)
+	assert.Empty(t, fallback.calls)
+}
+
+func TestDomainOndcValidatorUnparsableBodyUsesFallback(t *testing.T) {
+	retail, fallback := &mockOndcValidator{}, &mockOndcValidator{}
+	v := newDomainOndcValidator(map[string]definition.OndcValidator{"ONDC:RET10": retail}, fallback)
+
+	require.NoError(t, v.ValidatePayload(context.Background(), nil, []byte("not json")))
+
+	assert.Empty(t, retail.calls)
+	assert.Equal(t, []string{"validate"}, fallback.calls)
+}
+
+func TestDomainOndcValidatorWithoutFallback(t *testing.T) {
+	v := newDomainOndcValidator(map[string]definition.OndcValidator{"ONDC:RET10": &mockOndcValidator{}}, nil)
+
+	err := v.ValidatePayload(context.Background(), nil, testBody("search", "ONDC:TRV10"))
+
+	var badReq *model.BadReqErr
+	require.True(t, errors.As(err, &badReq))
+	assert.Contains(t, err.Error(), "ONDC:TRV10")
+}
+
+func TestDomainOndcValidatorUsesParsedContext(t *testing.T) {
+	retail, logistics := &mockOndcValidator{}, &mockOndcValidator{}
+	v := newDomainOndcValidator(map[string]definition.OndcValidator{
+		"ONDC:RET10": retail,
+		"ONDC:LOG10": logistics,
+	}, nil)
+	body := testBody("search", "ONDC:RET10")
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), body)
+	ctx.BecknContext = &model.BecknContext{Action: "search", Domain: "ONDC:LOG10"}
+
+	require.NoError(t, v.ValidatePayload(ctx, nil, body))
+	require.NoError(t, v.SaveValidationData(withBecknContext(context.Background(), ctx.BecknContext), nil, body))
+
+	assert.Empty(t, retail.calls, "the body is not parsed again")
+	assert.Equal(t, []string{"validate", "save"}, logistics.calls)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// pathRoles selects the role and subscriber of requests by their path prefix.
+type pathRoles struct {
+	// rules are sorted longest prefix first, so that the most specific prefix wins.
+	rules []PathRoleConfig
+}
+
+// newPathRoles creates pathRoles from cfgs, validating them. It returns nil when no
+// prefix is configured.
+func newPathRoles(cfgs []PathRoleConfig) (*pathRoles, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(cfgs))
+	p := &pathRoles{}
+	for _, cfg := range cfgs {
+		if !strings.HasPrefix(cfg.Prefix, "/") {
+			return nil, fmt.Errorf("invalid config: pathRoles prefix %q must start with /", cfg.Prefix)
+		}
+		cfg.Prefix = strings.TrimSuffix(cfg.Prefix, "/")
+		if seen[cfg.Prefix] {
+			return nil, fmt.Errorf("invalid config: pathRoles prefix %q is listed more than once", cfg.Prefix)
+		}
+		seen[cfg.Prefix] = true
+		if !cfg.Role.Valid() {
+			return nil, fmt.Errorf("invalid config: pathRoles prefix %q: invalid role %q", cfg.Prefix, cfg.Role)
+		}
+		p.rules = append(p.rules, cfg)
+	}
+	sort.SliceStable(p.rules, func(i, j int) bool {
+		return len(p.rules[i].Prefix) > len(p.rules[j].Prefix)
+	})
+	return p, nil
+}
+
+// match returns the rule of the longest prefix containing path.
+func (p *pathRoles) match(path string) (PathRoleConfig, bool) {
+	for _, rule := range p.rules {
+		if path == rule.Prefix || strings.HasPrefix(path, rule.Prefix+"/") {
+			return rule, true
+		}
+	}
+	return PathRoleConfig{}, false
+}
+
+// apply returns r with the role and subscriber ID of its path stored in its context, in
+// the same way as trustedHeaders. Requests to paths under no prefix are left to the
+// handler's role and subscriber.
+func (p *pathRoles) apply(r *http.Request) *http.Request {
+	if p == nil {
+		return r
+	}
+	rule, ok := p.match(r.URL.Path)
+	if !ok {
+		return r
+	}
+	ctx := context.WithValue(r.Context(), roleKey{}, rule.Role)
+	if rule.SubscriberID != "" {
+		ctx = context.WithValue(ctx, model.ContextKeySubscriberID, rule.SubscriberID)
+	}
+	return r.WithContext(ctx)
+}
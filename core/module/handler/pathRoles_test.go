@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signer"
+)
+
+// subscriberKeyManager returns the keyset of each subscriber.
+type subscriberKeyManager struct {
+	definition.KeyManager
+	keysets map[string]*model.Keyset
+}
+
+func (m *subscriberKeyManager) Keyset(_ context.Context, subscriberID string) (*model.Keyset, error) {
+	ks, ok := m.keysets[subscriberID]
+	if !ok {
+		return nil, errors.New("subscriber not found")
+	}
+	return ks, nil
+}
+
+func TestPathRolesMatch(t *testing.T) {
+	p, err := newPathRoles([]PathRoleConfig{
+		{Prefix: "/bap", Role: model.RoleBAP},
+		{Prefix: "/bap/gateway/", Role: model.RoleGateway, SubscriberID: "gw.example.com"},
+		{Prefix: "/bpp", Role: model.RoleBPP, SubscriberID: "bpp.example.com"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path     string
+		wantRole model.Role
+		wantSub  string
+	}{
+		{path: "/bap/caller/search", wantRole: model.RoleBAP, wantSub: "default.example.com"},
+		{path: "/bap/gateway/search", wantRole: model.RoleGateway, wantSub: "gw.example.com"},
+		{path: "/bpp", wantRole: model.RoleBPP, wantSub: "bpp.example.com"},
+		{path: "/bppx/search", wantRole: model.RoleBAP, wantSub: "default.example.com"},
+		{path: "/other/search", wantRole: model.RoleBAP, wantSub: "default.example.com"},
+	}
+	h := &stdHandler{role: model.RoleBAP, SubscriberID: "default.example.com", pathRoles: p}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			r := p.apply(httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			assert.Equal(t, tt.wantRole, h.requestRole(r.Context()))
+			assert.Equal(t, tt.wantSub, h.subID(r.Context()))
+		})
+	}
+}
+
+func TestNewPathRoles(t *testing.T) {
+	p, err := newPathRoles(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	tests := []struct {
+		name    string
+		cfgs    []PathRoleConfig
+		wantErr string
+	}{
+		{name: "relative prefix", cfgs: []PathRoleConfig{{Prefix: "bap", Role: model.RoleBAP}}, wantErr: "must start with /"},
+		{name: "invalid role", cfgs: []PathRoleConfig{{Prefix: "/bap", Role: "buyer"}}, wantErr: `invalid role "buyer"`},
+		{name: "duplicate prefix", cfgs: []PathRoleConfig{{Prefix: "/bap", Role: model.RoleBAP}, {Prefix: "/bap/", Role: model.RoleBPP}}, wantErr: "listed more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPathRoles(tt.cfgs)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestServeHTTPPathRoles(t *testing.T) {
+	sgn, _, err := signer.New(context.Background(), &signer.Config{})
+	require.NoError(t, err)
+	km := &subscriberKeyManager{keysets: map[string]*model.Keyset{
+		"bap.example.com": newEd25519KeyManager(t).keyset,
+		"bpp.example.com": newEd25519KeyManager(t).keyset,
+		"gw.example.com":  newEd25519KeyManager(t).keyset,
+	}}
+	sign, err := newSignStep(sgn, km, SignatureConfig{}, nil)
+	require.NoError(t, err)
+	p, err := newPathRoles([]PathRoleConfig{
+		{Prefix: "/bpp/caller", Role: model.RoleBPP, SubscriberID: "bpp.example.com"},
+		{Prefix: "/gateway/caller", Role: model.RoleGateway, SubscriberID: "gw.example.com"},
+	})
+	require.NoError(t, err)
+
+	var signed http.Header
+	var role model.Role
+	h := &stdHandler{
+		role:         model.RoleBAP,
+		SubscriberID: "bap.example.com",
+		pathRoles:    p,
+		steps: []definition.Step{sign, stepFunc(func(ctx *model.StepContext) error {
+			signed, role = ctx.Request.Header.Clone(), ctx.Role
+			return nil
+		})},
+	}
+
+	tests := []struct {
+		path       string
+		wantRole   model.Role
+		wantHeader string
+		otherHdr   string
+		wantSub    string
+	}{
+		{path: "/bap/caller/search", wantRole: model.RoleBAP, wantHeader: model.AuthHeaderSubscriber, otherHdr: model.AuthHeaderGateway, wantSub: "bap.example.com"},
+		{path: "/bpp/caller/on_search", wantRole: model.RoleBPP, wantHeader: model.AuthHeaderSubscriber, otherHdr: model.AuthHeaderGateway, wantSub: "bpp.example.com"},
+		{path: "/gateway/caller/search", wantRole: model.RoleGateway, wantHeader: model.AuthHeaderGateway, otherHdr: model.AuthHeaderSubscriber, wantSub: "gw.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"context":{"action":"search"}}`)))
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, tt.wantRole, role)
+			assert.Contains(t, signed.Get(tt.wantHeader), `keyId="`+tt.wantSub+`|k1|ed25519"`)
+			assert.Empty(t, signed.Get(tt.otherHdr))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+)
+
+// PluginErrorCategory classifies why a plugin failed to load.
+type PluginErrorCategory string
+
+const (
+	// PluginErrConfigInvalid means the plugin rejected its configuration.
+	PluginErrConfigInvalid PluginErrorCategory = "config-invalid"
+	// PluginErrDependencyMissing means the plugin, or a plugin it needs, is not available.
+	PluginErrDependencyMissing PluginErrorCategory = "dependency-missing"
+	// PluginErrIO means the plugin could not reach a file or service it needs.
+	PluginErrIO PluginErrorCategory = "io-error"
+)
+
+// errMissingDependency marks plugin load failures caused by another plugin not being configured.
+var errMissingDependency = errors.New("missing dependency")
+
+// sensitiveConfigKeys are substrings of plugin config keys whose values are redacted
+// from PluginLoadError.
+var sensitiveConfigKeys = []string{"password", "secret", "token", "key", "credential", "auth"}
+
+// PluginLoadError describes a plugin that failed to load, with what an operator needs
+// to diagnose it.
+type PluginLoadError struct {
+	// Plugin is the role of the plugin, e.g. "Cache".
+	Plugin string
+	// ID is the configured plugin ID.
+	ID string
+	// Category classifies the failure.
+	Category PluginErrorCategory
+	// Config is the plugin config with sensitive values redacted.
+	Config map[string]string
+	// Err is the underlying error.
+	Err error
+}
+
+// newPluginLoadError creates a PluginLoadError for the plugin name configured by cfg.
+func newPluginLoadError(name string, cfg *plugin.Config, err error) *PluginLoadError {
+	return &PluginLoadError{
+		Plugin:   name,
+		ID:       cfg.ID,
+		Category: pluginErrorCategory(err),
+		Config:   redactPluginConfig(cfg.Config),
+		Err:      err,
+	}
+}
+
+// Error returns the failure with its category and redacted config.
+func (e *PluginLoadError) Error() string {
+	keys := make([]string, 0, len(e.Config))
+	for k := range e.Config {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + e.Config[k]
+	}
+	return fmt.Sprintf("failed to load %s plugin (%s): %v [category: %s, config: {%s}]",
+		e.Plugin, e.ID, e.Err, e.Category, strings.Join(pairs, ", "))
+}
+
+// Unwrap returns the underlying error.
+func (e *PluginLoadError) Unwrap() error {
+	return e.Err
+}
+
+// pluginErrorCategory classifies a plugin load error. Errors that are neither missing
+// dependencies nor I/O failures are taken to be configuration problems.
+func pluginErrorCategory(err error) PluginErrorCategory {
+	var pathErr *fs.PathError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errMissingDependency), errors.Is(err, plugin.ErrNotFound):
+		return PluginErrDependencyMissing
+	case errors.Is(err, fs.ErrNotExist), errors.Is(err, fs.ErrPermission),
+		errors.As(err, &pathErr), errors.As(err, &netErr):
+		return PluginErrIO
+	default:
+		return PluginErrConfigInvalid
+	}
+}
+
+// redactPluginConfig returns a copy of cfg with the values of sensitive keys redacted.
+func redactPluginConfig(cfg map[string]string) map[string]string {
+	out := make(map[string]string, len(cfg))
+	for k, v := range cfg {
+		lower := strings.ToLower(k)
+		if slices.ContainsFunc(sensitiveConfigKeys, func(s string) bool { return strings.Contains(lower, s) }) {
+			v = redactedValue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+func TestLoadPluginErrorCategories(t *testing.T) {
+	_, statErr := os.Stat("/nonexistent/schemas")
+	tests := []struct {
+		name         string
+		err          error
+		wantCategory PluginErrorCategory
+	}{
+		{
+			name:         "invalid config",
+			err:          errors.New("config must contain 'schemaDir'"),
+			wantCategory: PluginErrConfigInvalid,
+		},
+		{
+			name:         "plugin not found",
+			err:          fmt.Errorf("failed to load provider for schemavalidator: plugin schemavalidator %w", plugin.ErrNotFound),
+			wantCategory: PluginErrDependencyMissing,
+		},
+		{
+			name:         "missing file",
+			err:          fmt.Errorf("failed to read schema directory: %w", statErr),
+			wantCategory: PluginErrIO,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &plugin.Config{ID: "schemavalidator", Config: map[string]string{"schemaDir": "/nonexistent/schemas"}}
+			_, err := loadPlugin(context.Background(), "SchemaValidator", cfg,
+				func(context.Context, *plugin.Config) (definition.SchemaValidator, error) { return nil, tt.err })
+
+			var loadErr *PluginLoadError
+			require.ErrorAs(t, err, &loadErr)
+			assert.Equal(t, "SchemaValidator", loadErr.Plugin)
+			assert.Equal(t, "schemavalidator", loadErr.ID)
+			assert.Equal(t, tt.wantCategory, loadErr.Category)
+			assert.Equal(t, map[string]string{"schemaDir": "/nonexistent/schemas"}, loadErr.Config)
+			assert.ErrorIs(t, err, tt.err)
+			assert.ErrorContains(t, err, "failed to load SchemaValidator plugin (schemavalidator): ")
+			assert.ErrorContains(t, err, fmt.Sprintf("[category: %s, config: {schemaDir=/nonexistent/schemas}]", tt.wantCategory))
+		})
+	}
+}
+
+func TestLoadKeyManagerMissingCache(t *testing.T) {
+	cfg := &plugin.Config{ID: "secretskeymanager", Config: map[string]string{"projectID": "p1", "privateKey": "c2VjcmV0"}}
+	_, err := loadKeyManager(context.Background(), &stubPluginManager{}, nil, nil, cfg)
+
+	var loadErr *PluginLoadError
+	require.ErrorAs(t, err, &loadErr)
+	assert.Equal(t, PluginErrDependencyMissing, loadErr.Category)
+	assert.Equal(t, "failed to load KeyManager plugin (secretskeymanager): missing dependency: Cache plugin not configured "+
+		"[category: dependency-missing, config: {privateKey=[REDACTED], projectID=p1}]", err.Error())
+}
+
+func TestRedactPluginConfig(t *testing.T) {
+	got := redactPluginConfig(map[string]string{
+		"addr":       "redis:6379",
+		"password":   "p",
+		"apiToken":   "t",
+		"clientKey":  "k",
+		"AuthHeader": "a",
+	})
+	assert.Equal(t, map[string]string{
+		"addr":       "redis:6379",
+		"password":   redactedValue,
+		"apiToken":   redactedValue,
+		"clientKey":  redactedValue,
+		"AuthHeader": redactedValue,
+	}, got)
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
 )
 
 type PostResponseHook func()
@@ -10,11 +13,60 @@ type PostResponseKey struct{}
 
 // RegisterPostResponseHook registers a function to be executed
 // after the response is written and all middleware has completed.
-func RegisterPostResponseHook(r *http.Request, fn PostResponseHook) {
+// It reports whether the hook was registered.
+func RegisterPostResponseHook(r *http.Request, fn PostResponseHook) bool {
 	hooks, ok := r.Context().Value(PostResponseKey{}).(*[]PostResponseHook)
 	if !ok || hooks == nil {
 		// PostResponseMiddleware not installed or already executed
-		return
+		return false
 	}
 	*hooks = append(*hooks, fn)
+	return true
+}
+
+// PostResponseMiddleware installs the post-response hook list on the request context and,
+// once the wrapped handler has returned, runs the registered hooks in the background so the
+// response is completed without waiting for them.
+//
+// The request context is cancelled if the client goes away while the wrapped handler runs,
+// and is detached from the client once the handler returns, so hooks that captured it keep
+// working after the server has finished the request.
+func PostResponseMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var hooks []PostResponseHook
+
+			ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+			stop := context.AfterFunc(r.Context(), cancel)
+			// store hook list pointer in request context
+			ctx = context.WithValue(ctx, PostResponseKey{}, &hooks)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			stop()
+			if len(hooks) == 0 {
+				cancel()
+				return
+			}
+			go func() {
+				defer cancel()
+				runPostResponseHooks(ctx, hooks)
+			}()
+		})
+	}
+}
+
+// runPostResponseHooks runs hooks in registration order, recovering from panics
+// so that one failing hook does not prevent the rest from running.
+func runPostResponseHooks(ctx context.Context, hooks []PostResponseHook) {
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Errorf(ctx, nil, "post-response hook panic: %v", r)
+				}
+			}()
+			hook()
+		}()
+	}
 }
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostResponseMiddlewareRunsHooksAfterResponse(t *testing.T) {
+	release := make(chan struct{})
+	ran := make(chan error, 1)
+	h := PostResponseMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registered := RegisterPostResponseHook(r, func() {
+			<-release
+			ran <- r.Context().Err()
+		})
+		assert.True(t, registered)
+		_, _ = io.WriteString(w, "ack")
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	// The response arrives while the hook is still blocked.
+	resp, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "ack", string(body))
+
+	select {
+	case <-ran:
+		t.Fatal("hook ran before it was released")
+	default:
+	}
+	close(release)
+
+	select {
+	case err := <-ran:
+		assert.NoError(t, err, "hook context must be detached from the finished request")
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook did not run")
+	}
+}
+
+func TestPostResponseMiddlewareRunsHooksInOrder(t *testing.T) {
+	done := make(chan struct{})
+	var order []int
+	h := PostResponseMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RegisterPostResponseHook(r, func() { order = append(order, 1) })
+		RegisterPostResponseHook(r, func() { panic("boom") })
+		RegisterPostResponseHook(r, func() { order = append(order, 3) })
+		RegisterPostResponseHook(r, func() { close(done) })
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hooks did not run")
+	}
+	assert.Equal(t, []int{1, 3}, order, "a panicking hook must not stop the others")
+}
+
+func TestRegisterPostResponseHookWithoutMiddleware(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	assert.False(t, RegisterPostResponseHook(req, func() {}))
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// modifyProxyResponse returns the httputil.ReverseProxy.ModifyResponse hook applying the
+// response headers and status rewrites of route to responses proxied back from its URL,
+// or nil if route configures none.
+func modifyProxyResponse(route *model.Route) func(*http.Response) error {
+	if len(route.ResponseHeaders) == 0 && len(route.ResponseStatus) == 0 {
+		return nil
+	}
+	return func(resp *http.Response) error {
+		for name, value := range route.ResponseHeaders {
+			if value == "" {
+				resp.Header.Del(name)
+				continue
+			}
+			resp.Header.Set(name, value)
+		}
+		if code, ok := route.ResponseStatus[resp.StatusCode]; ok {
+			resp.StatusCode = code
+			resp.Status = fmt.Sprintf("%d %s", code, http.StatusText(code))
+		}
+		return nil
+	}
+}
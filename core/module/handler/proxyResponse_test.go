@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+func TestModifyProxyResponse(t *testing.T) {
+	assert.Nil(t, modifyProxyResponse(&model.Route{}), "routes without rewrites need no hook")
+
+	modify := modifyProxyResponse(&model.Route{
+		ResponseHeaders: map[string]string{"cache-control": "no-store", "X-Powered-By": ""},
+		ResponseStatus:  map[int]int{http.StatusAccepted: http.StatusOK},
+	})
+	resp := &http.Response{StatusCode: http.StatusAccepted, Status: "202 Accepted", Header: http.Header{
+		"Cache-Control": {"max-age=60"},
+		"X-Powered-By":  {"backend"},
+		"X-Request-Id":  {"r1"},
+	}}
+
+	require.NoError(t, modify(resp))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "200 OK", resp.Status)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+	assert.Empty(t, resp.Header.Values("X-Powered-By"), "an empty value removes the header")
+	assert.Equal(t, "r1", resp.Header.Get("X-Request-Id"), "other headers are kept")
+}
+
+func TestServeHTTPProxyModifiesResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		upstreamCode int
+		wantCode     int
+	}{
+		{name: "rewritten status", upstreamCode: http.StatusAccepted, wantCode: http.StatusOK},
+		{name: "other status kept", upstreamCode: http.StatusBadRequest, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=60")
+				w.Header().Set("Server-Timing", "db;dur=12")
+				w.WriteHeader(tt.upstreamCode)
+				w.Write([]byte(`{"message":{"ack":{"status":"ACK"}}}`))
+			}))
+			defer upstream.Close()
+			target, err := url.Parse(upstream.URL)
+			require.NoError(t, err)
+			h := &stdHandler{
+				httpClient: upstream.Client(),
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.Route = &model.Route{
+						TargetType: "url",
+						URL:        target,
+						ActAsProxy: true,
+						ResponseHeaders: map[string]string{
+							"Access-Control-Allow-Origin": "*",
+							"Cache-Control":               "no-store",
+							"Server-Timing":               "",
+						},
+						ResponseStatus: map[int]int{http.StatusAccepted: http.StatusOK},
+					}
+					return nil
+				})},
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			assert.Empty(t, rec.Header().Values("Server-Timing"))
+			assert.JSONEq(t, `{"message":{"ack":{"status":"ACK"}}}`, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+const (
+	defaultProxyInitialBackoff = 100 * time.Millisecond
+	defaultProxyMaxBackoff     = 2 * time.Second
+)
+
+// defaultRetryableStatuses are retried when ProxyRetryConfig.RetryableStatuses is unset.
+// The reverse proxy answers transport errors with 502, so they are retried too.
+var defaultRetryableStatuses = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// maxHeldRetryResponse bounds the retryable response kept to be returned if no retry
+// can be made.
+const maxHeldRetryResponse = 64 << 10
+
+// proxyRetrier forwards idempotent requests in proxy mode again when the downstream
+// responds with a retryable status.
+type proxyRetrier struct {
+	maxAttempts    int
+	retryable      map[int]bool
+	actions        map[string]bool
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ProxyRetryConfig) withDefaults() ProxyRetryConfig {
+	if len(cfg.RetryableStatuses) == 0 {
+		cfg.RetryableStatuses = defaultRetryableStatuses
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultProxyInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultProxyMaxBackoff
+	}
+	return cfg
+}
+
+// newProxyRetrier creates a proxyRetrier from cfg, applying defaults for unset values. It
+// returns nil when retries are off.
+func newProxyRetrier(cfg ProxyRetryConfig) (*proxyRetrier, error) {
+	if cfg.MaxAttempts < 2 {
+		return nil, nil
+	}
+	cfg = cfg.withDefaults()
+	statuses := cfg.RetryableStatuses
+	p := &proxyRetrier{
+		maxAttempts:    cfg.MaxAttempts,
+		retryable:      make(map[int]bool, len(statuses)),
+		actions:        make(map[string]bool, len(cfg.Actions)),
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+	}
+	for _, status := range statuses {
+		if status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid config: proxyRetry retryable status %d is not an HTTP status", status)
+		}
+		if status < 400 {
+			return nil, fmt.Errorf("invalid config: proxyRetry retryable status %d is not an error status", status)
+		}
+		p.retryable[status] = true
+	}
+	for _, action := range cfg.Actions {
+		p.actions[action] = true
+	}
+	return p, nil
+}
+
+// idempotent reports whether r may be sent more than once: its method is idempotent, it
+// carries an Idempotency-Key header, or its Beckn action is listed as safe to retry.
+func (p *proxyRetrier) idempotent(ctx *model.StepContext, r *http.Request) bool {
+	if slices.Contains([]string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace}, r.Method) {
+		return true
+	}
+	if r.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	return ctx.BecknContext != nil && p.actions[ctx.BecknContext.Action]
+}
+
+// forward runs proxy, running it again while the downstream responds with a retryable
+// status and attempts are left. The request body is rewound from the buffered body
+// before each retry. Responses that are not retried are streamed to w unchanged, and the
+// last attempt is returned whatever its status.
+func (p *proxyRetrier) forward(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, proxy func(http.ResponseWriter)) {
+	if !p.idempotent(ctx, r) {
+		proxy(w)
+		return
+	}
+	backoff := p.initialBackoff
+	for attempt := 1; attempt < p.maxAttempts; attempt++ {
+		rw := &retryWriter{w: w, header: http.Header{}, retryable: p.retryable}
+		proxy(rw)
+		if rw.held == nil {
+			return
+		}
+		log.Warnf(ctx, "Downstream responded with status %d (attempt %d/%d), retrying", rw.held.statusCode(), attempt, p.maxAttempts)
+		if err := wait(ctx, jitter(backoff)); err != nil {
+			log.Warnf(ctx, "Retry aborted: %v", err)
+			rw.held.writeTo(ctx, w)
+			return
+		}
+		body, err := forwardBody(ctx)
+		if err != nil {
+			log.Errorf(ctx, err, "Failed to restore request body for retry")
+			rw.held.writeTo(ctx, w)
+			return
+		}
+		r.Body = io.NopCloser(body)
+		backoff = min(backoff*2, p.maxBackoff)
+	}
+	proxy(w)
+}
+
+// retryWriter is an http.ResponseWriter that holds back responses with a retryable
+// status and passes all others through to w.
+type retryWriter struct {
+	w         http.ResponseWriter
+	header    http.Header
+	retryable map[int]bool
+	// held is the retryable response; nil when the response was passed through.
+	held        *bufferedResponse
+	wroteHeader bool
+}
+
+// Header returns the response headers, copied to w if the response is passed through.
+func (rw *retryWriter) Header() http.Header {
+	return rw.header
+}
+
+// WriteHeader holds the response if status is retryable and otherwise starts passing it
+// through; only the first call takes effect.
+func (rw *retryWriter) WriteHeader(status int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	if rw.retryable[status] {
+		rw.held = &bufferedResponse{header: rw.header, max: maxHeldRetryResponse}
+		rw.held.WriteHeader(status)
+		return
+	}
+	for k, vs := range rw.header {
+		rw.w.Header()[k] = vs
+	}
+	rw.w.WriteHeader(status)
+}
+
+// Write writes p to the held response or through to w.
+func (rw *retryWriter) Write(p []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	if rw.held != nil {
+		return rw.held.Write(p)
+	}
+	return rw.w.Write(p)
+}
+
+// FlushError flushes w when the response is passed through, so that streamed responses
+// reach the client as they arrive. Held responses are not flushed.
+func (rw *retryWriter) FlushError() error {
+	if !rw.wroteHeader || rw.held != nil {
+		return nil
+	}
+	return http.NewResponseController(rw.w).Flush()
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// flakyDownstream answers with statuses in turn, then 200, recording the bodies received.
+type flakyDownstream struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func (d *flakyDownstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := http.StatusOK
+	if len(d.bodies) < len(d.statuses) {
+		status = d.statuses[len(d.bodies)]
+	}
+	d.bodies = append(d.bodies, string(body))
+	w.Header().Set("X-Attempt", strconv.Itoa(len(d.bodies)))
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, http.StatusText(status))
+}
+
+// newProxyRetryHandler returns a handler proxying to target with the given retry config.
+func newProxyRetryHandler(t *testing.T, target *httptest.Server, cfg ProxyRetryConfig) *stdHandler {
+	t.Helper()
+	retrier, err := newProxyRetrier(cfg)
+	require.NoError(t, err)
+	u, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	return &stdHandler{
+		httpClient: target.Client(),
+		proxyRetry: retrier,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", ActAsProxy: true, URL: u}
+			return nil
+		})},
+	}
+}
+
+func TestServeHTTPProxyRetry(t *testing.T) {
+	search := `{"context":{"action":"search","message_id":"m1"}}`
+	confirm := `{"context":{"action":"confirm","message_id":"m1"}}`
+	tests := []struct {
+		name         string
+		statuses     []int
+		body         string
+		header       string
+		wantCode     int
+		wantAttempts int
+	}{
+		{name: "retries on 503", statuses: []int{503}, body: search, wantCode: http.StatusOK, wantAttempts: 2},
+		{name: "does not retry on 400", statuses: []int{400}, body: search, wantCode: http.StatusBadRequest, wantAttempts: 1},
+		{name: "returns the last response when attempts run out", statuses: []int{503, 502, 504}, body: search, wantCode: http.StatusGatewayTimeout, wantAttempts: 3},
+		{name: "does not retry actions not listed", statuses: []int{503}, body: confirm, wantCode: http.StatusServiceUnavailable, wantAttempts: 1},
+		{name: "retries requests with an idempotency key", statuses: []int{503}, body: confirm, header: "k1", wantCode: http.StatusOK, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downstream := &flakyDownstream{statuses: tt.statuses}
+			srv := httptest.NewServer(downstream)
+			defer srv.Close()
+			h := newProxyRetryHandler(t, srv, ProxyRetryConfig{MaxAttempts: 3, Actions: []string{"search"}, InitialBackoff: time.Millisecond})
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set("Idempotency-Key", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, http.StatusText(tt.wantCode), rec.Body.String())
+			assert.Equal(t, strconv.Itoa(tt.wantAttempts), rec.Header().Get("X-Attempt"), "only the returned attempt's headers are sent")
+			require.Len(t, downstream.bodies, tt.wantAttempts)
+			for i, body := range downstream.bodies {
+				assert.Equal(t, tt.body, body, "attempt %d must forward the whole body", i+1)
+			}
+		})
+	}
+}
+
+func TestProxyRetryStopsWithContext(t *testing.T) {
+	downstream := &flakyDownstream{statuses: []int{503, 503}}
+	srv := httptest.NewServer(downstream)
+	defer srv.Close()
+	h := newProxyRetryHandler(t, srv, ProxyRetryConfig{MaxAttempts: 3, Actions: []string{"search"}, InitialBackoff: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{"context":{"action":"search"}}`)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "the held response is returned when the retry is aborted")
+	assert.Len(t, downstream.bodies, 1)
+}
+
+func TestNewProxyRetrier(t *testing.T) {
+	p, err := newProxyRetrier(ProxyRetryConfig{MaxAttempts: 1})
+	assert.NoError(t, err)
+	assert.Nil(t, p, "a single attempt disables retries")
+
+	p, err = newProxyRetrier(ProxyRetryConfig{MaxAttempts: 2})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{502: true, 503: true, 504: true}, p.retryable)
+	assert.Equal(t, defaultProxyInitialBackoff, p.initialBackoff)
+
+	_, err = newProxyRetrier(ProxyRetryConfig{MaxAttempts: 2, RetryableStatuses: []int{200}})
+	assert.ErrorContains(t, err, "status 200 is not an error status")
+
+	_, err = newProxyRetrier(ProxyRetryConfig{MaxAttempts: 2, RetryableStatuses: []int{1000}})
+	assert.ErrorContains(t, err, "status 1000 is not an HTTP status")
+}
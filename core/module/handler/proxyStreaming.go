@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httputil"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStreamBufferSize    = 32 << 10
+	defaultStreamFlushInterval = 100 * time.Millisecond
+)
+
+// proxyStreamer copies the responses of proxied requests to the client through a
+// pooled buffer of fixed size, flushing as it goes, so that large responses are never
+// held in memory whole.
+type proxyStreamer struct {
+	buffers       *bufferPool
+	flushInterval time.Duration
+}
+
+// newProxyStreamer creates a proxyStreamer from cfg, applying defaults for unset values.
+// It returns nil when streaming is off. Streaming cannot be combined with response
+// validation or signing, which buffer the whole response.
+func newProxyStreamer(cfg ProxyStreamingConfig, validation ResponseValidationConfig, signing ResponseSigningConfig) (*proxyStreamer, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if validation.Enabled {
+		return nil, errors.New("invalid config: proxyStreaming cannot be combined with responseValidation, which buffers the response")
+	}
+	if signing.Enabled {
+		return nil, errors.New("invalid config: proxyStreaming cannot be combined with responseSigning, which buffers the response")
+	}
+	if cfg.BufferSize < 0 {
+		return nil, fmt.Errorf("invalid config: proxyStreaming bufferSize %d is negative", cfg.BufferSize)
+	}
+	cfg = cfg.withDefaults()
+	return &proxyStreamer{buffers: newBufferPool(cfg.BufferSize), flushInterval: cfg.FlushInterval}, nil
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ProxyStreamingConfig) withDefaults() ProxyStreamingConfig {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultStreamBufferSize
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = defaultStreamFlushInterval
+	}
+	return cfg
+}
+
+// configure sets p up to copy responses through the streamer's buffers; a nil streamer
+// leaves p unchanged.
+func (s *proxyStreamer) configure(p *httputil.ReverseProxy) {
+	if s == nil {
+		return
+	}
+	p.BufferPool = s.buffers
+	p.FlushInterval = s.flushInterval
+}
+
+// bufferPool is an httputil.BufferPool handing out buffers of one size.
+type bufferPool struct {
+	pool sync.Pool
+}
+
+// newBufferPool returns a bufferPool of buffers of size bytes.
+func newBufferPool(size int) *bufferPool {
+	return &bufferPool{pool: sync.Pool{New: func() any {
+		b := make([]byte, size)
+		return &b
+	}}}
+}
+
+// Get returns a buffer from the pool, allocating one if it is empty.
+func (p *bufferPool) Get() []byte {
+	return *p.pool.Get().(*[]byte)
+}
+
+// Put returns b to the pool.
+func (p *bufferPool) Put(b []byte) {
+	p.pool.Put(&b)
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// streamingServer serves a handler proxying every request to upstream through streamer.
+func streamingServer(t *testing.T, upstream *httptest.Server, streamer *proxyStreamer, retry *proxyRetrier) *httptest.Server {
+	t.Helper()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	h := &stdHandler{
+		httpClient: upstream.Client(),
+		streamer:   streamer,
+		proxyRetry: retry,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+			return nil
+		})},
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewProxyStreamer(t *testing.T) {
+	s, err := newProxyStreamer(ProxyStreamingConfig{}, ResponseValidationConfig{}, ResponseSigningConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, s, "streaming is off unless enabled")
+
+	s, err = newProxyStreamer(ProxyStreamingConfig{Enabled: true}, ResponseValidationConfig{}, ResponseSigningConfig{})
+	require.NoError(t, err)
+	assert.Len(t, s.buffers.Get(), defaultStreamBufferSize)
+	assert.Equal(t, defaultStreamFlushInterval, s.flushInterval)
+
+	s, err = newProxyStreamer(ProxyStreamingConfig{Enabled: true, BufferSize: 1024, FlushInterval: -1}, ResponseValidationConfig{}, ResponseSigningConfig{})
+	require.NoError(t, err)
+	assert.Len(t, s.buffers.Get(), 1024)
+	assert.Equal(t, time.Duration(-1), s.flushInterval)
+}
+
+func TestNewProxyStreamerInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        ProxyStreamingConfig
+		validation ResponseValidationConfig
+		signing    ResponseSigningConfig
+		wantErr    string
+	}{
+		{
+			name:    "negative buffer size",
+			cfg:     ProxyStreamingConfig{Enabled: true, BufferSize: -1},
+			wantErr: "bufferSize -1 is negative",
+		},
+		{
+			name:       "with response validation",
+			cfg:        ProxyStreamingConfig{Enabled: true},
+			validation: ResponseValidationConfig{Enabled: true},
+			wantErr:    "cannot be combined with responseValidation",
+		},
+		{
+			name:    "with response signing",
+			cfg:     ProxyStreamingConfig{Enabled: true},
+			signing: ResponseSigningConfig{Enabled: true},
+			wantErr: "cannot be combined with responseSigning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newProxyStreamer(tt.cfg, tt.validation, tt.signing)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestServeHTTPProxyStreamsLargeResponse(t *testing.T) {
+	const size = 64 << 20
+	chunk := bytes.Repeat([]byte("x"), 64<<10)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusPartialContent)
+		for written := 0; written < size; written += len(chunk) {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer upstream.Close()
+	streamer, err := newProxyStreamer(ProxyStreamingConfig{Enabled: true}, ResponseValidationConfig{}, ResponseSigningConfig{})
+	require.NoError(t, err)
+	srv := streamingServer(t, upstream, streamer, nil)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	resp, err := srv.Client().Post(srv.URL+"/bap/caller/search", "application/json", strings.NewReader(`{"context":{"action":"search"}}`))
+	require.NoError(t, err)
+	n, err := io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	runtime.ReadMemStats(&after)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(size), n)
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(size/8), "the response must not be buffered whole")
+}
+
+func TestServeHTTPProxyStreamFlushesAsResponseArrives(t *testing.T) {
+	tests := []struct {
+		name  string
+		retry ProxyRetryConfig
+	}{
+		{name: "without retries"},
+		{name: "with retries", retry: ProxyRetryConfig{MaxAttempts: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The reverse proxy flushes responses of unknown length by itself.
+				w.Header().Set("Content-Length", "12")
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"items":[`))
+				w.(http.Flusher).Flush()
+				<-release
+				w.Write([]byte(`]}`))
+			}))
+			defer upstream.Close()
+			defer close(release)
+			streamer, err := newProxyStreamer(ProxyStreamingConfig{Enabled: true, FlushInterval: -1}, ResponseValidationConfig{}, ResponseSigningConfig{})
+			require.NoError(t, err)
+			retry, err := newProxyRetrier(tt.retry)
+			require.NoError(t, err)
+			srv := streamingServer(t, upstream, streamer, retry)
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/bap/caller/search", strings.NewReader(`{"context":{"action":"search"}}`))
+			require.NoError(t, err)
+			req.Header.Set("Idempotency-Key", "k1")
+
+			got := make(chan string, 1)
+			go func() {
+				resp, err := srv.Client().Do(req)
+				if err != nil {
+					got <- err.Error()
+					return
+				}
+				defer resp.Body.Close()
+				head, _ := bufio.NewReader(resp.Body).Peek(len(`{"items":[`))
+				got <- string(head)
+			}()
+
+			select {
+			case head := <-got:
+				assert.Equal(t, `{"items":[`, head)
+			case <-time.After(5 * time.Second):
+				t.Fatal("the start of the response did not reach the client before the downstream finished")
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+const (
+	// minPublishPollInterval and maxPublishPollInterval bound the wait between attempts
+	// to publish to a full queue.
+	minPublishPollInterval = 5 * time.Millisecond
+	maxPublishPollInterval = 100 * time.Millisecond
+)
+
+// errPublishQueueFull is returned when the publisher queue stays full for the whole wait.
+var errPublishQueueFull = errors.New("publisher queue is full, retry later")
+
+// boundedPublisher publishes without blocking on a full publisher queue for longer than
+// a timeout.
+type boundedPublisher struct {
+	publisher definition.BackpressurePublisher
+	timeout   time.Duration
+}
+
+// newBoundedPublisher creates a boundedPublisher from cfg. It returns nil when
+// backpressure handling is off.
+func newBoundedPublisher(pb definition.Publisher, cfg PublishBackpressureConfig) (*boundedPublisher, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if pb == nil {
+		return nil, fmt.Errorf("invalid config: publishBackpressure requires the Publisher plugin")
+	}
+	bp, ok := pb.(definition.BackpressurePublisher)
+	if !ok {
+		return nil, fmt.Errorf("invalid config: publishBackpressure requires a Publisher plugin implementing BackpressurePublisher")
+	}
+	return &boundedPublisher{publisher: bp, timeout: max(cfg.Timeout, 0)}, nil
+}
+
+// publish publishes body to pubID, retrying while the queue is full until the timeout
+// elapses. It returns errPublishQueueFull if the queue never took the message.
+func (p *boundedPublisher) publish(ctx context.Context, pubID string, body []byte) error {
+	deadline := time.Now().Add(p.timeout)
+	interval := minPublishPollInterval
+	for {
+		err := p.publisher.TryPublish(ctx, pubID, body)
+		if !errors.Is(err, definition.ErrPublisherFull) {
+			return err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return errPublishQueueFull
+		}
+		if err := wait(ctx, min(interval, remaining)); err != nil {
+			return fmt.Errorf("%w (wait aborted: %w)", errPublishQueueFull, err)
+		}
+		interval = min(interval*2, maxPublishPollInterval)
+	}
+}
+
+// rejectPublishQueueFull NACKs a request whose message a full queue did not take, with
+// a retryable 503 status.
+func rejectPublishQueueFull(ctx context.Context, w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "1")
+	nackErr := &model.Error{Code: strconv.Itoa(http.StatusServiceUnavailable), Message: errPublishQueueFull.Error()}
+	response.SendNackStatus(ctx, w, nackErr, http.StatusServiceUnavailable)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// boundedQueuePublisher is a BackpressurePublisher whose queue is full for the first
+// fullFor attempts. Plain Publish calls fail, as they would block.
+type boundedQueuePublisher struct {
+	mu        sync.Mutex
+	fullFor   int
+	err       error
+	attempts  int
+	published []string
+}
+
+func (p *boundedQueuePublisher) Publish(context.Context, string, []byte) error {
+	return errors.New("blocking publish")
+}
+
+func (p *boundedQueuePublisher) TryPublish(_ context.Context, topic string, _ []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts++
+	if p.fullFor < 0 || p.attempts <= p.fullFor {
+		return definition.ErrPublisherFull
+	}
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, topic)
+	return nil
+}
+
+func TestBoundedPublisher(t *testing.T) {
+	publishErr := errors.New("broker unavailable")
+	tests := []struct {
+		name          string
+		fullFor       int
+		err           error
+		timeout       time.Duration
+		wantErr       error
+		wantAttempts  int
+		wantPublished []string
+	}{
+		{name: "accepted", timeout: time.Second, wantAttempts: 1, wantPublished: []string{"orders"}},
+		{name: "full then accepted", fullFor: 2, timeout: time.Second, wantAttempts: 3, wantPublished: []string{"orders"}},
+		{name: "full until timeout", fullFor: -1, timeout: 30 * time.Millisecond, wantErr: errPublishQueueFull},
+		{name: "zero timeout does not wait", fullFor: -1, wantErr: errPublishQueueFull, wantAttempts: 1},
+		{name: "publish error", err: publishErr, timeout: time.Second, wantErr: publishErr, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb := &boundedQueuePublisher{fullFor: tt.fullFor, err: tt.err}
+			p, err := newBoundedPublisher(pb, PublishBackpressureConfig{Enabled: true, Timeout: tt.timeout})
+			require.NoError(t, err)
+
+			start := time.Now()
+			err = p.publish(context.Background(), "orders", []byte(`{}`))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantAttempts > 0 {
+				assert.Equal(t, tt.wantAttempts, pb.attempts)
+			}
+			assert.Equal(t, tt.wantPublished, pb.published)
+			assert.Less(t, time.Since(start), tt.timeout+time.Second, "publish must not block past the timeout")
+		})
+	}
+}
+
+func TestBoundedPublisherStopsWithContext(t *testing.T) {
+	p, err := newBoundedPublisher(&boundedQueuePublisher{fullFor: -1}, PublishBackpressureConfig{Enabled: true, Timeout: time.Minute})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = p.publish(ctx, "orders", []byte(`{}`))
+
+	assert.ErrorIs(t, err, errPublishQueueFull)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewBoundedPublisher(t *testing.T) {
+	p, err := newBoundedPublisher(nil, PublishBackpressureConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = newBoundedPublisher(nil, PublishBackpressureConfig{Enabled: true})
+	assert.ErrorContains(t, err, "requires the Publisher plugin")
+
+	_, err = newBoundedPublisher(&mockPublisher{}, PublishBackpressureConfig{Enabled: true})
+	assert.ErrorContains(t, err, "implementing BackpressurePublisher")
+}
+
+func TestServeHTTPPublishBackpressure(t *testing.T) {
+	tests := []struct {
+		name       string
+		fullFor    int
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "accepted", wantCode: http.StatusOK, wantStatus: `"ACK"`},
+		{name: "full then accepted", fullFor: 2, wantCode: http.StatusOK, wantStatus: `"ACK"`},
+		{name: "full until timeout", fullFor: -1, wantCode: http.StatusServiceUnavailable, wantStatus: `"NACK"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb := &boundedQueuePublisher{fullFor: tt.fullFor}
+			bounded, err := newBoundedPublisher(pb, PublishBackpressureConfig{Enabled: true, Timeout: 50 * time.Millisecond})
+			require.NoError(t, err)
+			h := &stdHandler{
+				publisher:  pb,
+				boundedPub: bounded,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.Route = &model.Route{TargetType: "publisher", PublisherID: "orders", ActAsProxy: true}
+					return nil
+				})},
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantStatus)
+			if tt.wantCode == http.StatusServiceUnavailable {
+				assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+				assert.Contains(t, rec.Body.String(), `"code":"503"`)
+				assert.Empty(t, pb.published)
+			} else {
+				assert.Equal(t, []string{"orders"}, pb.published)
+			}
+		})
+	}
+}
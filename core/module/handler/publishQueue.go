@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+)
+
+// defaultPublishQueueDepthPerWorker is the queue depth per worker when
+// PublishQueueConfig.Depth is unset.
+const defaultPublishQueueDepthPerWorker = 100
+
+// publishJob is a message waiting in the publish queue.
+type publishJob struct {
+	ctx   context.Context
+	pubID string
+	body  []byte
+}
+
+// publishQueue decouples accepting requests from publishing their messages: requests
+// put their message on a bounded queue, which a pool of workers publishes from.
+type publishQueue struct {
+	jobs    chan publishJob
+	publish func(ctx context.Context, pubID string, body []byte) error
+	workers sync.WaitGroup
+	closed  sync.Once
+}
+
+// newPublishQueue creates a publishQueue from cfg and starts its workers, which publish
+// through pr. It returns nil when the queue is off.
+func newPublishQueue(pr *publishRetrier, cfg PublishQueueConfig, backpressure PublishBackpressureConfig) (*publishQueue, error) {
+	if cfg.Workers == 0 {
+		return nil, nil
+	}
+	if cfg.Workers < 0 {
+		return nil, fmt.Errorf("invalid config: publishQueue workers %d is negative", cfg.Workers)
+	}
+	if cfg.Depth < 0 {
+		return nil, fmt.Errorf("invalid config: publishQueue depth %d is negative", cfg.Depth)
+	}
+	if pr == nil {
+		return nil, fmt.Errorf("invalid config: publishQueue requires the Publisher plugin")
+	}
+	if backpressure.Enabled {
+		return nil, fmt.Errorf("invalid config: publishQueue and publishBackpressure cannot both be enabled")
+	}
+	depth := cfg.Depth
+	if depth == 0 {
+		depth = cfg.Workers * defaultPublishQueueDepthPerWorker
+	}
+	q := &publishQueue{jobs: make(chan publishJob, depth), publish: pr.publish}
+	q.workers.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go q.work()
+	}
+	return q, nil
+}
+
+// enqueue puts a message for pubID on the queue, reporting false if the queue is full.
+// The message is published outside the request, so ctx is detached from its cancellation.
+func (q *publishQueue) enqueue(ctx context.Context, pubID string, body []byte) bool {
+	select {
+	case q.jobs <- publishJob{ctx: context.WithoutCancel(ctx), pubID: pubID, body: body}:
+		return true
+	default:
+		return false
+	}
+}
+
+// work publishes queued messages until the queue is closed and empty.
+func (q *publishQueue) work() {
+	defer q.workers.Done()
+	for job := range q.jobs {
+		if err := q.publish(job.ctx, job.pubID, job.body); err != nil {
+			log.Errorf(job.ctx, err, "Failed to publish queued message")
+		}
+	}
+}
+
+// drain closes the queue and waits for the workers to publish the messages left in it.
+// No message may be enqueued once drain is called. If ctx ends first, the remaining
+// messages are abandoned and the context error is returned.
+func (q *publishQueue) drain(ctx context.Context) error {
+	q.closed.Do(func() { close(q.jobs) })
+	done := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// gatedPublisher is a publisher whose Publish calls wait for release, announcing each
+// call on started.
+type gatedPublisher struct {
+	started chan string
+	release chan struct{}
+
+	mu     sync.Mutex
+	topics []string
+}
+
+func newGatedPublisher() *gatedPublisher {
+	return &gatedPublisher{started: make(chan string, 10), release: make(chan struct{})}
+}
+
+func (p *gatedPublisher) Publish(_ context.Context, topic string, _ []byte) error {
+	p.started <- topic
+	<-p.release
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.topics = append(p.topics, topic)
+	return nil
+}
+
+func (p *gatedPublisher) published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.topics...)
+}
+
+func newTestPublishQueue(t *testing.T, pb definition.Publisher, cfg PublishQueueConfig) *publishQueue {
+	t.Helper()
+	q, err := newPublishQueue(newPublishRetrier(pb, &PublishRetryConfig{}), cfg, PublishBackpressureConfig{})
+	require.NoError(t, err)
+	return q
+}
+
+func TestPublishQueuePublishes(t *testing.T) {
+	pb := newGatedPublisher()
+	close(pb.release)
+	q := newTestPublishQueue(t, pb, PublishQueueConfig{Workers: 2})
+
+	for _, topic := range []string{"orders", "catalog", "orders"} {
+		require.True(t, q.enqueue(context.Background(), topic, []byte(`{}`)))
+	}
+	require.NoError(t, q.drain(context.Background()))
+
+	assert.ElementsMatch(t, []string{"orders", "catalog", "orders"}, pb.published())
+}
+
+func TestPublishQueueRejectsWhenFull(t *testing.T) {
+	pb := newGatedPublisher()
+	q := newTestPublishQueue(t, pb, PublishQueueConfig{Workers: 1, Depth: 1})
+
+	require.True(t, q.enqueue(context.Background(), "first", nil))
+	assert.Equal(t, "first", <-pb.started, "the worker takes the first message")
+	assert.True(t, q.enqueue(context.Background(), "second", nil), "the queue holds one message")
+	assert.False(t, q.enqueue(context.Background(), "third", nil))
+
+	close(pb.release)
+	require.NoError(t, q.drain(context.Background()))
+	assert.Equal(t, []string{"first", "second"}, pb.published())
+}
+
+func TestPublishQueueDrain(t *testing.T) {
+	pb := newGatedPublisher()
+	q := newTestPublishQueue(t, pb, PublishQueueConfig{Workers: 1})
+	require.True(t, q.enqueue(context.Background(), "orders", nil))
+	require.True(t, q.enqueue(context.Background(), "catalog", nil))
+	<-pb.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.drain(ctx), context.DeadlineExceeded, "drain gives up on a stuck publisher")
+
+	close(pb.release)
+	require.NoError(t, q.drain(context.Background()), "drain can be retried")
+	assert.Equal(t, []string{"orders", "catalog"}, pb.published(), "messages queued before drain are published")
+}
+
+func TestNewPublishQueue(t *testing.T) {
+	pr := newPublishRetrier(&mockPublisher{}, &PublishRetryConfig{})
+	tests := []struct {
+		name         string
+		pr           *publishRetrier
+		cfg          PublishQueueConfig
+		backpressure PublishBackpressureConfig
+		wantErr      string
+	}{
+		{name: "negative workers", pr: pr, cfg: PublishQueueConfig{Workers: -1}, wantErr: "workers -1 is negative"},
+		{name: "negative depth", pr: pr, cfg: PublishQueueConfig{Workers: 1, Depth: -1}, wantErr: "depth -1 is negative"},
+		{name: "no publisher", cfg: PublishQueueConfig{Workers: 1}, wantErr: "requires the Publisher plugin"},
+		{
+			name:         "with backpressure",
+			pr:           pr,
+			cfg:          PublishQueueConfig{Workers: 1},
+			backpressure: PublishBackpressureConfig{Enabled: true},
+			wantErr:      "cannot both be enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPublishQueue(tt.pr, tt.cfg, tt.backpressure)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	q, err := newPublishQueue(nil, PublishQueueConfig{}, PublishBackpressureConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, q, "the queue is off without workers")
+
+	q, err = newPublishQueue(pr, PublishQueueConfig{Workers: 3}, PublishBackpressureConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, 300, cap(q.jobs), "depth defaults to 100 per worker")
+	require.NoError(t, q.drain(context.Background()))
+}
+
+func TestServeHTTPPublishQueue(t *testing.T) {
+	pb := newGatedPublisher()
+	h := &stdHandler{
+		publisher: pb,
+		pubQueue:  newTestPublishQueue(t, pb, PublishQueueConfig{Workers: 1, Depth: 1}),
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "publisher", PublisherID: "orders", ActAsProxy: true}
+			return nil
+		})},
+	}
+	serve := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`)))
+		return rec
+	}
+
+	rec := serve()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"ACK"`)
+	<-pb.started
+	assert.Equal(t, http.StatusOK, serve().Code, "the request is ACKed once queued")
+
+	rec = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"NACK"`)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	close(pb.release)
+	require.NoError(t, h.Shutdown(context.Background()))
+	assert.Equal(t, []string{"orders", "orders"}, pb.published(), "shutdown publishes the queued messages")
+}
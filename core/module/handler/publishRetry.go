@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+const (
+	defaultPublishInitialBackoff = 100 * time.Millisecond
+	defaultPublishMaxBackoff     = 5 * time.Second
+)
+
+// publishRetrier publishes messages with jittered exponential backoff and an optional dead letter queue.
+type publishRetrier struct {
+	publisher      definition.Publisher
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	dlqID          string
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg PublishRetryConfig) withDefaults() PublishRetryConfig {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultPublishInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultPublishMaxBackoff
+	}
+	return cfg
+}
+
+// newPublishRetrier creates a publishRetrier, applying defaults for unset values.
+func newPublishRetrier(pb definition.Publisher, cfg *PublishRetryConfig) *publishRetrier {
+	resolved := cfg.withDefaults()
+	return &publishRetrier{
+		publisher:      pb,
+		maxAttempts:    resolved.MaxAttempts,
+		initialBackoff: resolved.InitialBackoff,
+		maxBackoff:     resolved.MaxBackoff,
+		dlqID:          resolved.DLQPublisherID,
+	}
+}
+
+// publish attempts to publish the message until it succeeds or attempts are exhausted.
+// Exhausted messages are written to the DLQ when one is configured.
+func (r *publishRetrier) publish(ctx context.Context, pubID string, body []byte) error {
+	var err error
+	backoff := r.initialBackoff
+	for attempt := 1; attempt <= r.maxAttempts; attempt++ {
+		if err = r.publisher.Publish(ctx, pubID, body); err == nil {
+			return nil
+		}
+		if attempt == r.maxAttempts {
+			break
+		}
+		log.Warnf(ctx, "Publish to %s failed (attempt %d/%d): %v", pubID, attempt, r.maxAttempts, err)
+		if waitErr := wait(ctx, jitter(backoff)); waitErr != nil {
+			err = fmt.Errorf("%w (retry aborted: %w)", err, waitErr)
+			break
+		}
+		backoff = min(backoff*2, r.maxBackoff)
+	}
+	err = fmt.Errorf("publish to %s failed after %d attempt(s): %w", pubID, r.maxAttempts, err)
+	if r.dlqID == "" {
+		return err
+	}
+	if dlqErr := r.publisher.Publish(context.WithoutCancel(ctx), r.dlqID, body); dlqErr != nil {
+		return fmt.Errorf("%w; dead letter publish to %s failed: %v", err, r.dlqID, dlqErr)
+	}
+	log.Warnf(ctx, "Message written to dead letter queue %s: %v", r.dlqID, err)
+	return nil
+}
+
+// jitter returns a random duration in [d/2, d).
+func jitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + rand.N(half)
+}
+
+// wait blocks for d or until ctx is done.
+func wait(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
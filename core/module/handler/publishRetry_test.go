@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyPublisher fails the first failures publishes to non-DLQ topics.
+type flakyPublisher struct {
+	failures int
+	dlqID    string
+	calls    []string
+}
+
+func (f *flakyPublisher) Publish(_ context.Context, topic string, _ []byte) error {
+	f.calls = append(f.calls, topic)
+	if topic != f.dlqID && f.failures > 0 {
+		f.failures--
+		return errors.New("broker unavailable")
+	}
+	return nil
+}
+
+func TestPublishRetrierSucceedsOnRetry(t *testing.T) {
+	pub := &flakyPublisher{failures: 2, dlqID: "dlq"}
+	r := newPublishRetrier(pub, &PublishRetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		DLQPublisherID: "dlq",
+	})
+
+	err := r.publish(context.Background(), "topic", []byte(`{}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"topic", "topic", "topic"}, pub.calls)
+}
+
+func TestPublishRetrierExhaustsThenDLQ(t *testing.T) {
+	pub := &flakyPublisher{failures: 10, dlqID: "dlq"}
+	r := newPublishRetrier(pub, &PublishRetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		DLQPublisherID: "dlq",
+	})
+
+	err := r.publish(context.Background(), "topic", []byte(`{}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"topic", "topic", "topic", "dlq"}, pub.calls)
+}
+
+func TestPublishRetrierExhaustsWithoutDLQ(t *testing.T) {
+	pub := &flakyPublisher{failures: 10}
+	r := newPublishRetrier(pub, &PublishRetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	err := r.publish(context.Background(), "topic", []byte(`{}`))
+
+	assert.ErrorContains(t, err, "after 2 attempt(s)")
+	assert.Len(t, pub.calls, 2)
+}
+
+func TestPublishRetrierDefaultsToSingleAttempt(t *testing.T) {
+	pub := &flakyPublisher{failures: 1}
+	r := newPublishRetrier(pub, &PublishRetryConfig{})
+
+	err := r.publish(context.Background(), "topic", []byte(`{}`))
+
+	assert.Error(t, err)
+	assert.Len(t, pub.calls, 1)
+}
+
+func TestPublishRetrierStopsOnContextCancel(t *testing.T) {
+	pub := &flakyPublisher{failures: 10}
+	r := newPublishRetrier(pub, &PublishRetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.publish(ctx, "topic", []byte(`{}`))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, pub.calls, 1)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// publisherSelector picks a publisher ID from the request context using the configured rules.
+type publisherSelector struct {
+	rules []PublisherRule
+}
+
+// newPublisherSelector creates a publisherSelector, validating the configured rules.
+func newPublisherSelector(rules []PublisherRule) (*publisherSelector, error) {
+	for i, rule := range rules {
+		if rule.PublisherID == "" {
+			return nil, fmt.Errorf("publisher rule %d: publisherId is required", i)
+		}
+	}
+	return &publisherSelector{rules: rules}, nil
+}
+
+// resolve returns the publisher ID of the first rule matching the action and domain of
+// bCtx, the context parsed from the request. It returns "" when no rule matches, leaving
+// the choice to the publisher's default.
+func (s *publisherSelector) resolve(bCtx *model.BecknContext) string {
+	if s == nil || bCtx == nil {
+		return ""
+	}
+	for _, rule := range s.rules {
+		if rule.Action != "" && rule.Action != bCtx.Action {
+			continue
+		}
+		if rule.Domain != "" && rule.Domain != bCtx.Domain {
+			continue
+		}
+		return rule.PublisherID
+	}
+	return ""
+}
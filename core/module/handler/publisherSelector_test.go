@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/router"
+)
+
+// mockPublisher records the publisher IDs it was asked to publish to.
+type mockPublisher struct {
+	topics []string
+	err    error
+}
+
+func (m *mockPublisher) Publish(_ context.Context, topic string, _ []byte) error {
+	m.topics = append(m.topics, topic)
+	return m.err
+}
+
+func testBody(action, domain string) []byte {
+	return []byte(`{"context":{"action":"` + action + `","domain":"` + domain + `"},"message":{}}`)
+}
+
+func TestPublisherSelectorResolve(t *testing.T) {
+	selector, err := newPublisherSelector([]PublisherRule{
+		{Action: "search", PublisherID: "topic-A"},
+		{Action: "select", PublisherID: "topic-B"},
+		{Domain: "ONDC:TRV10", PublisherID: "topic-mobility"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		body []byte
+		want string
+	}{
+		{name: "search maps to topic-A", body: testBody("search", "ONDC:RET10"), want: "topic-A"},
+		{name: "select maps to topic-B", body: testBody("select", "ONDC:RET10"), want: "topic-B"},
+		{name: "domain rule matches any action", body: testBody("init", "ONDC:TRV10"), want: "topic-mobility"},
+		{name: "first matching rule wins", body: testBody("search", "ONDC:TRV10"), want: "topic-A"},
+		{name: "no matching rule", body: testBody("confirm", "ONDC:RET10"), want: ""},
+		{name: "invalid body", body: []byte(`not-json`), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bCtx, _ := model.ParseBecknContext(tt.body)
+			assert.Equal(t, tt.want, selector.resolve(bCtx))
+		})
+	}
+}
+
+func TestNewPublisherSelectorMissingID(t *testing.T) {
+	_, err := newPublisherSelector([]PublisherRule{{Action: "search"}})
+	assert.Error(t, err)
+}
+
+func TestRoutePublisherFromRules(t *testing.T) {
+	selector, err := newPublisherSelector([]PublisherRule{
+		{Action: "search", PublisherID: "topic-A"},
+		{Action: "select", PublisherID: "topic-B"},
+	})
+	require.NoError(t, err)
+
+	pub := &mockPublisher{}
+	h := &stdHandler{publisher: pub, pubSelector: selector}
+
+	for _, action := range []string{"search", "select"} {
+		body := testBody(action, "ONDC:RET10")
+		r := httptest.NewRequest(http.MethodPost, "/"+action, strings.NewReader(string(body)))
+		w := httptest.NewRecorder()
+		ctx := newTestStepCtx(r, body)
+		ctx.Route = &model.Route{TargetType: "publisher", ActAsProxy: true}
+		h.route(ctx, r, w)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, []string{"topic-A", "topic-B"}, pub.topics)
+}
+
+func TestRoutePublisherRulesWithRouter(t *testing.T) {
+	rules := `routingRules:
+  - domain: ONDC:RET10
+    version: 1.1.0
+    targetType: publisher
+    endpoints:
+      - search
+      - select
+      - confirm
+`
+	rulesPath := filepath.Join(t.TempDir(), "routing.yaml")
+	require.NoError(t, os.WriteFile(rulesPath, []byte(rules), 0644))
+	rtr, _, err := router.New(context.Background(), &router.Config{RoutingConfig: rulesPath})
+	require.NoError(t, err)
+	addRoute, err := newAddRouteStep(rtr)
+	require.NoError(t, err)
+
+	selector, err := newPublisherSelector([]PublisherRule{
+		{Action: "search", PublisherID: "topic-A"},
+		{Action: "select", PublisherID: "topic-B"},
+	})
+	require.NoError(t, err)
+	pub := &mockPublisher{}
+	h := &stdHandler{publisher: pub, pubSelector: selector}
+
+	for _, action := range []string{"search", "select", "confirm"} {
+		body := []byte(`{"context":{"action":"` + action + `","domain":"ONDC:RET10","version":"1.1.0"},"message":{}}`)
+		r := httptest.NewRequest(http.MethodPost, "/"+action, strings.NewReader(string(body)))
+		w := httptest.NewRecorder()
+		ctx := newTestStepCtx(r, body)
+		require.NoError(t, addRoute.Run(ctx))
+		ctx.Route.ActAsProxy = true
+		h.route(ctx, r, w)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, []string{"topic-A", "topic-B", ""}, pub.topics)
+}
+
+func TestRoutePublisherFallsBackToDefault(t *testing.T) {
+	selector, err := newPublisherSelector([]PublisherRule{{Action: "search", PublisherID: "topic-A"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		selector *publisherSelector
+	}{
+		{name: "no publisher rules", selector: nil},
+		{name: "no matching rule", selector: selector},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &mockPublisher{}
+			h := &stdHandler{publisher: pub, pubSelector: tt.selector}
+			body := testBody("confirm", "ONDC:RET10")
+			r := httptest.NewRequest(http.MethodPost, "/confirm", strings.NewReader(string(body)))
+			w := httptest.NewRecorder()
+			ctx := newTestStepCtx(r, body)
+			ctx.Route = &model.Route{TargetType: "publisher", ActAsProxy: true}
+			h.route(ctx, r, w)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, []string{""}, pub.topics)
+		})
+	}
+}
+
+func TestRoutePublisherIDFromRouterTakesPrecedence(t *testing.T) {
+	selector, err := newPublisherSelector([]PublisherRule{{PublisherID: "topic-A"}})
+	require.NoError(t, err)
+
+	pub := &mockPublisher{}
+	h := &stdHandler{publisher: pub, pubSelector: selector}
+	body := testBody("search", "ONDC:RET10")
+	r := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	ctx := &model.StepContext{
+		Context: context.Background(),
+		Request: r,
+		Body:    body,
+		Route:   &model.Route{TargetType: "publisher", PublisherID: "router-topic", ActAsProxy: true},
+	}
+	h.route(ctx, r, w)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"router-topic"}, pub.topics)
+}
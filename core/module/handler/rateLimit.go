@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// defaultRateLimitBucket is the bucket used when GlobalRateLimitConfig.Bucket is unset.
+const defaultRateLimitBucket = "global"
+
+// Outcomes of the global rate limit recorded in onix_rate_limit_decisions_total.
+const (
+	rateLimitAllowed   = "allowed"
+	rateLimitThrottled = "throttled"
+)
+
+// tokenBucket lets requests through at a sustained rate, allowing bursts of up to burst
+// requests. It is safe for concurrent use.
+type tokenBucket struct {
+	rate  float64
+	burst int
+	clock Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token from the bucket, reporting false if there is none.
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.burst), b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimitBuckets holds the token buckets of the process by name, so that modules
+// naming the same bucket share it.
+var rateLimitBuckets = struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}{buckets: map[string]*tokenBucket{}}
+
+// sharedTokenBucket returns the bucket registered as name, creating it full when it does
+// not exist yet. It fails if the bucket exists with a different rate or burst.
+func sharedTokenBucket(name string, rate float64, burst int, clock Clock) (*tokenBucket, error) {
+	rateLimitBuckets.mu.Lock()
+	defer rateLimitBuckets.mu.Unlock()
+	if b, ok := rateLimitBuckets.buckets[name]; ok {
+		if b.rate != rate || b.burst != burst {
+			return nil, fmt.Errorf("invalid config: globalRateLimit bucket %q is already configured with rate %g and burst %d", name, b.rate, b.burst)
+		}
+		return b, nil
+	}
+	b := &tokenBucket{rate: rate, burst: burst, clock: clock, tokens: float64(burst), last: clock.Now()}
+	rateLimitBuckets.buckets[name] = b
+	return b, nil
+}
+
+// globalRateLimitStep throttles requests once the process-wide rate is exceeded,
+// whoever sends them.
+type globalRateLimitStep struct {
+	bucket     *tokenBucket
+	name       string
+	retryAfter string
+	metrics    *HandlerMetrics
+}
+
+// newGlobalRateLimitStep creates and returns the globalRateLimit step. Its bucket starts
+// full and is refilled as measured by clock, which defaults to the system clock when nil.
+func newGlobalRateLimitStep(cfg GlobalRateLimitConfig, clock Clock) (definition.Step, error) {
+	if cfg.Rate <= 0 || math.IsInf(cfg.Rate, 0) || math.IsNaN(cfg.Rate) {
+		return nil, fmt.Errorf("invalid config: globalRateLimit rate must be a positive number of requests per second")
+	}
+	burst := cfg.Burst
+	if burst < 0 {
+		return nil, fmt.Errorf("invalid config: globalRateLimit burst %d is negative", burst)
+	}
+	if burst == 0 {
+		burst = int(math.Ceil(cfg.Rate))
+	}
+	name := cfg.Bucket
+	if name == "" {
+		name = defaultRateLimitBucket
+	}
+	bucket, err := sharedTokenBucket(name, cfg.Rate, burst, orSystemClock(clock))
+	if err != nil {
+		return nil, err
+	}
+	metrics, _ := GetHandlerMetrics(context.Background())
+	return &globalRateLimitStep{
+		bucket:     bucket,
+		name:       name,
+		retryAfter: strconv.Itoa(int(math.Ceil(1 / cfg.Rate))),
+		metrics:    metrics,
+	}, nil
+}
+
+// Run lets the request through if the bucket has a token, and otherwise fails it with a
+// TooManyRequestsErr, which is answered with HTTP 429.
+func (s *globalRateLimitStep) Run(ctx *model.StepContext) error {
+	if s.bucket.allow() {
+		s.count(ctx, rateLimitAllowed)
+		return nil
+	}
+	s.count(ctx, rateLimitThrottled)
+	ctx.RespHeader.Set("Retry-After", s.retryAfter)
+	return model.NewTooManyRequestsErr(fmt.Errorf("global request rate of %g/s exceeded", s.bucket.rate))
+}
+
+// count adds a decision of the given outcome to the rate limit metric.
+func (s *globalRateLimitStep) count(ctx context.Context, outcome string) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.RateLimitDecisionsTotal.Add(ctx, 1, metric.WithAttributes(
+		telemetry.AttrName.String(s.name),
+		telemetry.AttrOutcome.String(outcome),
+	))
+}
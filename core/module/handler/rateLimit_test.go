@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// runRateLimited runs step for a request signed by subscriberID, reporting whether it
+// was let through.
+func runRateLimited(t *testing.T, step definition.Step, subscriberID string) bool {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+	ctx := newTestStepCtx(r, []byte(`{}`))
+	ctx.SubID = subscriberID
+	ctx.Signer = subscriberID
+	err := step.Run(ctx)
+	if err == nil {
+		return true
+	}
+	var tooMany *model.TooManyRequestsErr
+	require.ErrorAs(t, err, &tooMany)
+	assert.Equal(t, "1", ctx.RespHeader.Get("Retry-After"))
+	return false
+}
+
+func TestGlobalRateLimitStep(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	step, err := newGlobalRateLimitStep(GlobalRateLimitConfig{Rate: 2, Burst: 3, Bucket: t.Name()}, clock)
+	require.NoError(t, err)
+	metrics, reader := newTestHandlerMetrics(t)
+	step.(*globalRateLimitStep).metrics = metrics
+
+	subscribers := []string{"bap1.example.com", "bap2.example.com", "bap3.example.com", "bap4.example.com"}
+	var allowed []bool
+	for _, sub := range subscribers {
+		allowed = append(allowed, runRateLimited(t, step, sub))
+	}
+	assert.Equal(t, []bool{true, true, true, false}, allowed, "the burst is shared by all subscribers")
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.True(t, runRateLimited(t, step, "bap4.example.com"), "a token is refilled after 1/rate")
+	assert.False(t, runRateLimited(t, step, "bap5.example.com"))
+
+	clock.now = clock.now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, runRateLimited(t, step, "bap1.example.com"), "refills stop at the burst")
+	}
+	assert.False(t, runRateLimited(t, step, "bap1.example.com"))
+
+	name := telemetry.AttrName.String(t.Name())
+	assert.Equal(t, int64(7), counterValue(t, reader, "onix_rate_limit_decisions_total", name, telemetry.AttrOutcome.String(rateLimitAllowed)))
+	assert.Equal(t, int64(3), counterValue(t, reader, "onix_rate_limit_decisions_total", name, telemetry.AttrOutcome.String(rateLimitThrottled)))
+}
+
+func TestGlobalRateLimitSharedAcrossModules(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	cfg := GlobalRateLimitConfig{Rate: 1, Bucket: t.Name()}
+	caller, err := newGlobalRateLimitStep(cfg, clock)
+	require.NoError(t, err)
+	receiver, err := newGlobalRateLimitStep(cfg, clock)
+	require.NoError(t, err)
+
+	assert.True(t, runRateLimited(t, caller, "bap.example.com"))
+	assert.False(t, runRateLimited(t, receiver, "bpp.example.com"), "modules naming the same bucket share it")
+
+	_, err = newGlobalRateLimitStep(GlobalRateLimitConfig{Rate: 5, Bucket: t.Name()}, clock)
+	assert.ErrorContains(t, err, "is already configured with rate 1 and burst 1")
+}
+
+func TestNewGlobalRateLimitStep(t *testing.T) {
+	_, err := newGlobalRateLimitStep(GlobalRateLimitConfig{Bucket: t.Name()}, nil)
+	assert.ErrorContains(t, err, "rate must be a positive number")
+
+	_, err = newGlobalRateLimitStep(GlobalRateLimitConfig{Rate: 1, Burst: -1, Bucket: t.Name()}, nil)
+	assert.ErrorContains(t, err, "burst -1 is negative")
+
+	step, err := newGlobalRateLimitStep(GlobalRateLimitConfig{Rate: 2.5, Bucket: t.Name()}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, step.(*globalRateLimitStep).bucket.burst, "burst defaults to the rate rounded up")
+}
+
+func TestServeHTTPGlobalRateLimit(t *testing.T) {
+	step, err := newGlobalRateLimitStep(GlobalRateLimitConfig{Rate: 0.5, Burst: 1, Bucket: t.Name()}, &fakeClock{now: time.Now()})
+	require.NoError(t, err)
+	h := &stdHandler{steps: []definition.Step{step}}
+
+	codes := []int{}
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`)))
+		codes = append(codes, rec.Code)
+		if rec.Code == http.StatusTooManyRequests {
+			assert.Contains(t, rec.Body.String(), `"NACK"`)
+			assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// ReadinessChecker is implemented by handlers that can report whether they are ready to serve traffic.
+type ReadinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+// Ready reports whether all critical plugins of the handler are healthy.
+// Plugins that do not implement definition.HealthChecker are assumed healthy.
+// A handler that is warming up or shutting down is never ready.
+func (h *stdHandler) Ready(ctx context.Context) error {
+	if h.warmingUp() {
+		return errWarmingUp
+	}
+	h.drainMu.RLock()
+	draining := h.draining
+	h.drainMu.RUnlock()
+	if draining {
+		return errShuttingDown
+	}
+	// Plugins are checked in name order, so that the same failure is reported every time.
+	critical := []struct {
+		name   string
+		plugin any
+	}{
+		{"Cache", h.cache},
+		{"Publisher", h.publisher},
+		{"Registry", h.registry},
+	}
+	for _, c := range critical {
+		hc, ok := c.plugin.(definition.HealthChecker)
+		if !ok {
+			continue
+		}
+		if err := hc.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("%s plugin unhealthy: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// readyResponse defines the structure of the readiness JSON response.
+type readyResponse struct {
+	Status string            `json:"status"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ReadyHandler returns an http.Handler for the /readyz endpoint that reports
+// 503 Service Unavailable if any of the given checkers is not ready.
+func ReadyHandler(checkers map[string]ReadinessChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp := readyResponse{Status: "ok"}
+		status := http.StatusOK
+		for name, c := range checkers {
+			if err := c.Ready(r.Context()); err != nil {
+				if resp.Errors == nil {
+					resp.Errors = make(map[string]string)
+				}
+				resp.Errors[name] = err.Error()
+				resp.Status = "unavailable"
+				status = http.StatusServiceUnavailable
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthCheckPublisher is a publisher that also reports its health.
+type healthCheckPublisher struct {
+	mockPublisher
+	healthErr error
+}
+
+func (p *healthCheckPublisher) HealthCheck(context.Context) error {
+	return p.healthErr
+}
+
+func TestStdHandlerReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler *stdHandler
+		wantErr bool
+	}{
+		{
+			name:    "healthy publisher",
+			handler: &stdHandler{publisher: &healthCheckPublisher{}},
+		},
+		{
+			name:    "unhealthy publisher",
+			handler: &stdHandler{publisher: &healthCheckPublisher{healthErr: errors.New("connection closed")}},
+			wantErr: true,
+		},
+		{
+			name:    "publisher without health check",
+			handler: &stdHandler{publisher: &mockPublisher{}},
+		},
+		{
+			name:    "no plugins configured",
+			handler: &stdHandler{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.handler.Ready(context.Background())
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "Publisher plugin unhealthy")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// healthCheckCache is a cache that also reports its health.
+type healthCheckCache struct {
+	*memCache
+	healthErr error
+}
+
+func (c *healthCheckCache) HealthCheck(context.Context) error {
+	return c.healthErr
+}
+
+func TestStdHandlerReadyReportsFirstPluginByName(t *testing.T) {
+	h := &stdHandler{
+		publisher: &healthCheckPublisher{healthErr: errors.New("connection closed")},
+		cache:     &healthCheckCache{memCache: newMemCache(), healthErr: errors.New("timeout")},
+	}
+
+	for range 20 {
+		assert.EqualError(t, h.Ready(context.Background()), "Cache plugin unhealthy: timeout")
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	healthy := &stdHandler{publisher: &healthCheckPublisher{}}
+	unhealthy := &stdHandler{publisher: &healthCheckPublisher{healthErr: errors.New("connection closed")}}
+
+	tests := []struct {
+		name       string
+		checkers   map[string]ReadinessChecker
+		wantStatus int
+	}{
+		{name: "all ready", checkers: map[string]ReadinessChecker{"bapTxnCaller": healthy}, wantStatus: http.StatusOK},
+		{name: "one not ready", checkers: map[string]ReadinessChecker{"bapTxnCaller": healthy, "bapTxnReceiver": unhealthy}, wantStatus: http.StatusServiceUnavailable},
+		{name: "no checkers", checkers: map[string]ReadinessChecker{}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			ReadyHandler(tt.checkers).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			require.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestReadyHandlerMethodNotAllowed(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ReadyHandler(nil).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/readyz", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
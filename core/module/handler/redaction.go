@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// redactedValue replaces the values of redacted fields in logged bodies.
+const redactedValue = "[REDACTED]"
+
+// wildcard matches every key of an object or element of an array in a field selector.
+const wildcard = "*"
+
+// bodyRedactor masks configured fields in copies of JSON bodies written to logs.
+type bodyRedactor struct {
+	selectors [][]string
+}
+
+// newBodyRedactor parses field selectors such as "message.order.billing.phone" or
+// "message.order.fulfillments[*].end.contact". Segments are object keys, "*" for any
+// key or element, or array indexes in brackets. It returns nil when there are no selectors.
+func newBodyRedactor(selectors []string) (*bodyRedactor, error) {
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+	r := &bodyRedactor{}
+	for _, s := range selectors {
+		path, err := parseSelector(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact selector %q: %w", s, err)
+		}
+		r.selectors = append(r.selectors, path)
+	}
+	return r, nil
+}
+
+// parseSelector splits a selector into path segments, turning "[n]" and "[*]" into
+// segments of their own. A leading "$." is accepted and ignored.
+func parseSelector(s string) ([]string, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "$"), ".")
+	if s == "" {
+		return nil, fmt.Errorf("empty selector")
+	}
+	var path []string
+	for _, part := range strings.Split(s, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			path = append(path, key)
+		} else if rest == "" {
+			return nil, fmt.Errorf("empty segment")
+		}
+		for rest != "" {
+			idx, after, ok := strings.Cut(rest, "]")
+			if !ok || (idx != wildcard && !isIndex(idx)) {
+				return nil, fmt.Errorf("invalid index in %q", part)
+			}
+			path = append(path, idx)
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("invalid segment %q", part)
+			}
+			rest = after[1:]
+		}
+	}
+	return path, nil
+}
+
+// isIndex reports whether s is a non-negative array index.
+func isIndex(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0
+}
+
+// redact returns a copy of body with the selected fields replaced by redactedValue.
+// Bodies that are not JSON are returned unchanged, as they have no fields to select.
+func (r *bodyRedactor) redact(body []byte) []byte {
+	if r == nil || len(body) == 0 {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return body
+	}
+	for _, path := range r.selectors {
+		doc = redactPath(doc, path)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// redactPath replaces the values at path within v, returning the updated value.
+func redactPath(v any, path []string) any {
+	if len(path) == 0 {
+		return redactedValue
+	}
+	seg, rest := path[0], path[1:]
+	switch node := v.(type) {
+	case map[string]any:
+		for k, child := range node {
+			if seg == wildcard || seg == k {
+				node[k] = redactPath(child, rest)
+			}
+		}
+	case []any:
+		for i, child := range node {
+			if seg == wildcard || seg == strconv.Itoa(i) {
+				node[i] = redactPath(child, rest)
+			}
+		}
+	}
+	return v
+}
+
+type redactorKey struct{}
+
+// withRedaction attaches r to ctx so that bodies logged for the request are redacted.
+func withRedaction(ctx context.Context, r *bodyRedactor) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, redactorKey{}, r)
+}
+
+// redactedBody returns body with the fields selected for the request's redactor masked.
+func redactedBody(ctx context.Context, body []byte) []byte {
+	r, _ := ctx.Value(redactorKey{}).(*bodyRedactor)
+	return r.redact(body)
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redactionTestBody = `{"context":{"action":"confirm"},"message":{"order":{"billing":{"name":"A","phone":"9999999999"},"payments":[{"params":{"bank_account":"123"},"amount":1250.50},{"params":{"bank_account":"456"}}]}}}`
+
+func TestBodyRedactorRedact(t *testing.T) {
+	tests := []struct {
+		name      string
+		selectors []string
+		body      string
+		want      string
+	}{
+		{
+			name:      "nested field",
+			selectors: []string{"message.order.billing.phone"},
+			body:      `{"message":{"order":{"billing":{"name":"A","phone":"9999999999"}}}}`,
+			want:      `{"message":{"order":{"billing":{"name":"A","phone":"[REDACTED]"}}}}`,
+		},
+		{
+			name:      "object value",
+			selectors: []string{"$.message.order.billing"},
+			body:      `{"message":{"order":{"billing":{"name":"A","phone":"9999999999"}}}}`,
+			want:      `{"message":{"order":{"billing":"[REDACTED]"}}}`,
+		},
+		{
+			name:      "every array element",
+			selectors: []string{"payments[*].params"},
+			body:      `{"payments":[{"params":{"a":1},"amount":1250.50},{"params":{"a":2}}]}`,
+			want:      `{"payments":[{"amount":1250.50,"params":"[REDACTED]"},{"params":"[REDACTED]"}]}`,
+		},
+		{
+			name:      "array index",
+			selectors: []string{"payments[1].params"},
+			body:      `{"payments":[{"params":1},{"params":2}]}`,
+			want:      `{"payments":[{"params":1},{"params":"[REDACTED]"}]}`,
+		},
+		{
+			name:      "any key",
+			selectors: []string{"contacts.*.email"},
+			body:      `{"contacts":{"a":{"email":"a@x"},"b":{"email":"b@x"}}}`,
+			want:      `{"contacts":{"a":{"email":"[REDACTED]"},"b":{"email":"[REDACTED]"}}}`,
+		},
+		{
+			name:      "missing field",
+			selectors: []string{"message.order.billing.email"},
+			body:      `{"message":{"order":{}}}`,
+			want:      `{"message":{"order":{}}}`,
+		},
+		{
+			name:      "not json",
+			selectors: []string{"message"},
+			body:      `not json`,
+			want:      `not json`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newBodyRedactor(tt.selectors)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(r.redact([]byte(tt.body))))
+		})
+	}
+}
+
+func TestNewBodyRedactor(t *testing.T) {
+	r, err := newBodyRedactor(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	for _, s := range []string{"", "$", "a..b", "a[x]", "a[-1]", "a[*]b", "a[1"} {
+		_, err := newBodyRedactor([]string{s})
+		assert.Error(t, err, "selector %q", s)
+	}
+}
+
+func TestServeRedactsLoggedBodies(t *testing.T) {
+	redactor, err := newBodyRedactor([]string{"message.order.billing.phone", "message.order.payments[*].params"})
+	require.NoError(t, err)
+
+	t.Run("forwarded body is untouched", func(t *testing.T) {
+		bodies, _ := captureRequestLogs(t)
+		var forwarded string
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			forwarded = string(b)
+		}))
+		defer upstream.Close()
+		target, err := url.Parse(upstream.URL)
+		require.NoError(t, err)
+		h := &stdHandler{
+			httpClient:  upstream.Client(),
+			bodySampler: newBodySampler(1),
+			redactor:    redactor,
+			steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+				ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+				return nil
+			})},
+		}
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", strings.NewReader(redactionTestBody)))
+
+		assert.Equal(t, redactionTestBody, forwarded)
+		require.Len(t, *bodies, 2, "incoming and forwarded requests should be logged")
+		for _, b := range *bodies {
+			assert.NotContains(t, string(b), "9999999999")
+			assert.NotContains(t, string(b), "bank_account")
+			assert.Contains(t, string(b), `"phone":"[REDACTED]"`)
+		}
+	})
+
+	t.Run("failed request body", func(t *testing.T) {
+		_, errBodies := captureRequestLogs(t)
+		h := &stdHandler{
+			redactor: redactor,
+			steps: []definition.Step{stepFunc(func(*model.StepContext) error {
+				return model.NewBadReqErr(errors.New("bad"))
+			})},
+		}
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", strings.NewReader(redactionTestBody)))
+
+		require.Len(t, *errBodies, 1)
+		assert.NotContains(t, string((*errBodies)[0]), "9999999999")
+		assert.Contains(t, string((*errBodies)[0]), `"name":"A"`)
+	})
+}
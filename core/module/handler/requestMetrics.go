@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// Request outcomes recorded with the request duration.
+const (
+	outcomeAck  = "ack"
+	outcomeNack = "nack"
+)
+
+// outcomeSniffLimit bounds how much of the response body is inspected for a NACK.
+const outcomeSniffLimit = 512
+
+// trackRequest marks a request as in flight and returns a function that records its
+// duration with the final outcome and marks it complete.
+// A sampled span carried by ctx is attached to the duration as an exemplar.
+func (h *stdHandler) trackRequest(ctx context.Context) func(outcome string) {
+	start := time.Now()
+	module := telemetry.AttrModule.String(h.moduleName)
+	role := telemetry.AttrRole.String(string(h.role))
+	h.metrics.RequestsInFlight.Add(ctx, 1, metric.WithAttributes(module, role))
+	return func(outcome string) {
+		h.metrics.RequestsInFlight.Add(ctx, -1, metric.WithAttributes(module, role))
+		h.metrics.RequestDuration.Record(ctx, time.Since(start).Seconds(),
+			metric.WithAttributes(module, role, telemetry.AttrOutcome.String(outcome)))
+	}
+}
+
+// recordBodySize records the request body size, tagged by module and bounded action.
+func (h *stdHandler) recordBodySize(ctx context.Context, size int, bCtx *model.BecknContext) {
+	if h.metrics == nil {
+		return
+	}
+	h.metrics.RequestBodySize.Record(ctx, int64(size), metric.WithAttributes(
+		telemetry.AttrModule.String(h.moduleName),
+		telemetry.AttrAction.String(boundedAction(bCtx)),
+	))
+}
+
+// outcomeWriter captures the status code and the start of the body so the request
+// outcome can be classified once the response has been written.
+type outcomeWriter struct {
+	http.ResponseWriter
+	status int
+	head   []byte
+}
+
+// WriteHeader records the status code before writing it.
+func (w *outcomeWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write keeps the first outcomeSniffLimit bytes of the body before writing it.
+func (w *outcomeWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if n := outcomeSniffLimit - len(w.head); n > 0 {
+		w.head = append(w.head, b[:min(n, len(b))]...)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *outcomeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// statusCode returns the status written to the client, defaulting to 200 like net/http.
+func (w *outcomeWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// outcome reports nack for error statuses and NACK bodies, and ack otherwise.
+func (w *outcomeWriter) outcome() string {
+	if w.status >= http.StatusBadRequest || response.ContainsNack(w.head) {
+		return outcomeNack
+	}
+	return outcomeAck
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// stepFunc adapts a function to definition.Step.
+type stepFunc func(ctx *model.StepContext) error
+
+func (f stepFunc) Run(ctx *model.StepContext) error {
+	return f(ctx)
+}
+
+func TestServeHTTPRequestMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		stepErr     error
+		wantOutcome string
+	}{
+		{name: "ack", wantOutcome: outcomeAck},
+		{name: "nack", stepErr: model.NewBadReqErr(errors.New("bad")), wantOutcome: outcomeNack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, reader := newTestHandlerMetrics(t)
+			module := telemetry.AttrModule.String("bapTxnReceiver")
+			role := telemetry.AttrRole.String(string(model.RoleBAP))
+
+			var inFlightDuring int64
+			h := &stdHandler{
+				moduleName: "bapTxnReceiver",
+				role:       model.RoleBAP,
+				metrics:    metrics,
+				steps: []definition.Step{stepFunc(func(*model.StepContext) error {
+					inFlightDuring = counterValue(t, reader, "onix_requests_in_flight", module, role)
+					return tt.stepErr
+				})},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/bap/receiver/on_search", strings.NewReader(`{"context":{"action":"on_search"}}`))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, int64(1), inFlightDuring)
+			assert.Equal(t, int64(0), counterValue(t, reader, "onix_requests_in_flight", module, role))
+			assert.Equal(t, uint64(1), histogramCount(t, reader, "onix_request_duration_seconds",
+				module, role, telemetry.AttrOutcome.String(tt.wantOutcome)))
+		})
+	}
+}
+
+func TestOutcomeWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{name: "ack body", body: `{"message":{"ack":{"status":"ACK"}}}`, want: outcomeAck},
+		{name: "nack body with 200", status: http.StatusOK, body: `{"message":{"ack":{"status":"NACK"}}}`, want: outcomeNack},
+		{name: "error status", status: http.StatusInternalServerError, body: `oops`, want: outcomeNack},
+		{name: "no body", status: http.StatusNoContent, want: outcomeAck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ow := &outcomeWriter{ResponseWriter: httptest.NewRecorder()}
+			if tt.status != 0 {
+				ow.WriteHeader(tt.status)
+			}
+			if tt.body != "" {
+				_, err := ow.Write([]byte(tt.body))
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, ow.outcome())
+		})
+	}
+}
+
+// int64HistogramPoint returns the count and sum of the int64 histogram data point with the given attributes.
+func int64HistogramPoint(t *testing.T, reader *sdkmetric.ManualReader, name string, attrs ...attribute.KeyValue) (uint64, int64) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	want := attribute.NewSet(attrs...)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			hist, ok := m.Data.(metricdata.Histogram[int64])
+			require.True(t, ok, "metric %s is not an int64 histogram", name)
+			for _, dp := range hist.DataPoints {
+				if dp.Attributes.Equals(&want) {
+					return dp.Count, dp.Sum
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+func TestStepCtxRecordsBodySize(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantAction string
+	}{
+		{name: "empty body", body: "", wantAction: "unknown"},
+		{name: "small body", body: `{"context":{"action":"search"}}`, wantAction: "search"},
+		{name: "large body", body: `{"context":{"action":"on_search"},"message":"` + strings.Repeat("x", 100000) + `"}`, wantAction: "on_search"},
+		{name: "unknown action", body: `{"context":{"action":"probe"}}`, wantAction: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, reader := newTestHandlerMetrics(t)
+			h := &stdHandler{moduleName: "bppTxnReceiver", metrics: metrics}
+
+			_, err := h.stepCtx(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)), http.Header{})
+			if tt.body == "" {
+				assert.ErrorContains(t, err, "empty request body", "empty bodies are rejected after being measured")
+			} else {
+				require.NoError(t, err)
+			}
+
+			count, sum := int64HistogramPoint(t, reader, "onix_request_body_size_bytes",
+				telemetry.AttrModule.String("bppTxnReceiver"),
+				telemetry.AttrAction.String(tt.wantAction))
+			assert.Equal(t, uint64(1), count)
+			assert.Equal(t, int64(len(tt.body)), sum)
+		})
+	}
+}
+
+func TestServeHTTPAccessLog(t *testing.T) {
+	tests := []struct {
+		name        string
+		stepErr     error
+		wantStatus  int
+		wantOutcome string
+	}{
+		{name: "ack", wantStatus: http.StatusOK, wantOutcome: outcomeAck},
+		{name: "nack", stepErr: model.NewBadReqErr(errors.New("bad")), wantStatus: http.StatusBadRequest, wantOutcome: outcomeNack},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entries []log.AccessEntry
+			orig := accessLogFunc
+			accessLogFunc = func(_ context.Context, e log.AccessEntry) { entries = append(entries, e) }
+			t.Cleanup(func() { accessLogFunc = orig })
+
+			h := &stdHandler{
+				moduleName: "bapTxnReceiver",
+				role:       model.RoleBAP,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.SubID = "bap.example.com"
+					return tt.stepErr
+				})},
+			}
+
+			req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/receiver/on_search", strings.NewReader(`{}`)))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Empty(t, entries, "access log should be deferred until the response is sent")
+			for _, hook := range *hooks {
+				hook()
+			}
+
+			require.Len(t, entries, 1)
+			e := entries[0]
+			assert.Equal(t, http.MethodPost, e.Method)
+			assert.Equal(t, "/bap/receiver/on_search", e.Path)
+			assert.Equal(t, tt.wantStatus, e.Status)
+			assert.Equal(t, "bap.example.com", e.SubscriberID)
+			assert.Equal(t, tt.wantOutcome, e.Outcome)
+			assert.Positive(t, e.Duration)
+		})
+	}
+}
+
+func TestServeHTTPAccessLogWithoutPostResponseMiddleware(t *testing.T) {
+	var entries []log.AccessEntry
+	orig := accessLogFunc
+	accessLogFunc = func(_ context.Context, e log.AccessEntry) { entries = append(entries, e) }
+	t.Cleanup(func() { accessLogFunc = orig })
+
+	h := &stdHandler{SubscriberID: "bpp.example.com"}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`)))
+
+	require.Len(t, entries, 1)
+	assert.Equal(t, "bpp.example.com", entries[0].SubscriberID)
+	assert.Equal(t, outcomeAck, entries[0].Outcome)
+}
+
+// exemplarTraceIDs returns the hex trace IDs of the exemplars on the float64 histogram name.
+func exemplarTraceIDs(t *testing.T, reader *sdkmetric.ManualReader, name string) []string {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var ids []string
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			require.True(t, ok, "metric %s is not a float64 histogram", name)
+			for _, dp := range hist.DataPoints {
+				for _, ex := range dp.Exemplars {
+					ids = append(ids, hex.EncodeToString(ex.TraceID))
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// testTracer returns a tracer that samples according to sampler.
+func testTracer(t *testing.T, sampler sdktrace.Sampler) trace.Tracer {
+	t.Helper()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tp.Tracer(stepTracerName)
+}
+
+func TestTrackRequestExemplar(t *testing.T) {
+	metrics, reader := newTestHandlerMetrics(t)
+	h := &stdHandler{moduleName: "bapTxnReceiver", role: model.RoleBAP, metrics: metrics}
+
+	ctx, span := testTracer(t, sdktrace.AlwaysSample()).Start(context.Background(), "request")
+	h.trackRequest(ctx)(outcomeAck)
+	span.End()
+
+	assert.Equal(t, []string{span.SpanContext().TraceID().String()},
+		exemplarTraceIDs(t, reader, "onix_request_duration_seconds"))
+}
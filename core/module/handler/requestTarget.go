@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// requestTargetHeader names the pseudo-header of signatures covering the method and
+// path of the request.
+const requestTargetHeader = "(request-target)"
+
+// signedHeaders returns the headers listed in the headers parameter of signature header
+// value, or nil if it has none.
+func signedHeaders(value string) []string {
+	const prefix = `headers="`
+	i := strings.Index(value, prefix)
+	if i == -1 {
+		return nil
+	}
+	v := value[i+len(prefix):]
+	if end := strings.Index(v, `"`); end != -1 {
+		v = v[:end]
+	}
+	return strings.Fields(strings.ToLower(v))
+}
+
+// requestTarget returns the request target of r as signed: the lower-cased method and
+// the path with its query, such as "post /bap/caller/search".
+func requestTarget(r *http.Request) string {
+	return strings.ToLower(r.Method) + " " + r.URL.RequestURI()
+}
+
+// withRequestTarget returns ctx carrying the request target of r under
+// model.ContextKeyRequestTarget when the signature in header value covers it.
+func withRequestTarget(ctx context.Context, r *http.Request, value string) context.Context {
+	if !slices.Contains(signedHeaders(value), requestTargetHeader) {
+		return ctx
+	}
+	return context.WithValue(ctx, model.ContextKeyRequestTarget, requestTarget(r))
+}
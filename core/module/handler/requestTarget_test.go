@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signvalidator"
+)
+
+// signedAuthHeader returns an Authorization header signing body with the key of km over
+// headers, in order, taking the (request-target) to be target. Without headers the
+// header has no headers parameter.
+func signedAuthHeader(t *testing.T, km *ed25519KeyManager, headers []string, target, body string) string {
+	t.Helper()
+	seed, err := base64.StdEncoding.DecodeString(km.keyset.SigningPrivate)
+	require.NoError(t, err)
+	created := time.Now().Unix()
+	expires := created + 300
+	digest := blake2b.Sum512([]byte(body))
+	signed := headers
+	if len(signed) == 0 {
+		signed = []string{"(created)", "(expires)", "digest"}
+	}
+	var lines []string
+	for _, h := range signed {
+		switch h {
+		case "(created)":
+			lines = append(lines, fmt.Sprintf("(created): %d", created))
+		case "(expires)":
+			lines = append(lines, fmt.Sprintf("(expires): %d", expires))
+		case "digest":
+			lines = append(lines, "digest: BLAKE-512="+base64.StdEncoding.EncodeToString(digest[:]))
+		case "(request-target)":
+			lines = append(lines, "(request-target): "+target)
+		}
+	}
+	signature := ed25519.Sign(ed25519.NewKeyFromSeed(seed), []byte(strings.Join(lines, "\n")))
+	header := fmt.Sprintf(`Signature keyId="bap.example.com|k1|ed25519",algorithm="ed25519",created="%d",expires="%d"`, created, expires)
+	if len(headers) > 0 {
+		header += fmt.Sprintf(`,headers="%s"`, strings.Join(headers, " "))
+	}
+	return header + fmt.Sprintf(`,signature="%s"`, base64.StdEncoding.EncodeToString(signature))
+}
+
+func TestValidateSignStepRequestTarget(t *testing.T) {
+	const body = `{"context":{"action":"search"}}`
+	withTarget := []string{"(created)", "(expires)", "(request-target)", "digest"}
+	tests := []struct {
+		name    string
+		headers []string
+		target  string
+		path    string
+		wantErr string
+	}{
+		{name: "request target included", headers: withTarget, target: "post /bap/caller/search", path: "/bap/caller/search"},
+		{name: "request target with query", headers: withTarget, target: "post /bap/caller/search?mode=full", path: "/bap/caller/search?mode=full"},
+		{name: "request target of another path", headers: withTarget, target: "post /bap/caller/confirm", path: "/bap/caller/search", wantErr: "signature verification failed"},
+		{name: "default digest only", path: "/bap/caller/search"},
+		{name: "digest only listed", headers: []string{"(created)", "(expires)", "digest"}, path: "/bap/caller/search"},
+		{name: "digest not covered", headers: []string{"(created)", "(expires)", "(request-target)"}, target: "post /bap/caller/search", path: "/bap/caller/search", wantErr: "signature does not cover digest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, _, err := signvalidator.New(context.Background(), &signvalidator.Config{})
+			require.NoError(t, err)
+			km := newEd25519KeyManager(t)
+			step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{}, nil, nil)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set(model.AuthHeaderSubscriber, signedAuthHeader(t, km, tt.headers, tt.target, body))
+
+			err = step.Run(newTestStepCtx(req, []byte(body)))
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWithRequestTarget(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/bap/caller/search?mode=full", nil)
+
+	ctx := withRequestTarget(context.Background(), r, `Signature keyId="a|k1|ed25519",headers="(created) (expires) (request-target) digest",signature="s"`)
+	assert.Equal(t, "post /bap/caller/search?mode=full", ctx.Value(model.ContextKeyRequestTarget))
+
+	ctx = withRequestTarget(context.Background(), r, `Signature keyId="a|k1|ed25519",headers="(created) (expires) digest",signature="s"`)
+	assert.Nil(t, ctx.Value(model.ContextKeyRequestTarget), "signatures not covering the request target get none")
+
+	ctx = withRequestTarget(context.Background(), r, `Signature keyId="a|k1|ed25519",signature="s"`)
+	assert.Nil(t, ctx.Value(model.ContextKeyRequestTarget))
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+const (
+	// defaultResponseCacheTTL is used when ResponseCacheConfig.TTL is unset.
+	defaultResponseCacheTTL = 30 * time.Second
+
+	// maxCachedResponseSize bounds the body of a cached response; larger responses are not cached.
+	maxCachedResponseSize = 1 << 20
+)
+
+// defaultResponseCacheKeyFields are the context fields keyed on when none are configured.
+var defaultResponseCacheKeyFields = []string{"domain", "action"}
+
+// errResponseCacheNoCache is returned when response caching is configured without a Cache plugin.
+var errResponseCacheNoCache = errors.New("responseCache requires the cache plugin")
+
+// cacheGateSteps authenticate or authorise the caller. The cache is only looked up once
+// every one of them configured has run, so that it never answers callers they reject.
+var cacheGateSteps = map[string]bool{"validateSign": true, "checkSubscriberAccess": true}
+
+// uncachedHeaders are never stored with a cached response.
+var uncachedHeaders = []string{"Date", "Set-Cookie"}
+
+// responseCache stores full responses of cacheable actions in the Cache plugin.
+type responseCache struct {
+	cache     definition.Cache
+	actions   map[string]bool
+	keyFields []string
+	ttl       time.Duration
+	prefix    string
+	// gated is the number of leading steps, through the last of cacheGateSteps, run
+	// before the cache is looked up.
+	gated int
+}
+
+// cachedResponse is the form in which a response is kept in the cache.
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// newResponseCache creates a responseCache from cfg for a module running steps. It returns
+// nil when no action is marked cacheable.
+func newResponseCache(cfg ResponseCacheConfig, cache definition.Cache, moduleName string, steps []string) (*responseCache, error) {
+	if len(cfg.Actions) == 0 {
+		return nil, nil
+	}
+	if cache == nil {
+		return nil, errResponseCacheNoCache
+	}
+	actions := make(map[string]bool, len(cfg.Actions))
+	for _, a := range cfg.Actions {
+		if a == "" {
+			return nil, fmt.Errorf("invalid responseCache: empty action")
+		}
+		actions[a] = true
+	}
+	gated := 0
+	for i, s := range steps {
+		if cacheGateSteps[s] {
+			gated = i + 1
+		}
+	}
+	cfg = cfg.withDefaults()
+	return &responseCache{
+		cache:     cache,
+		actions:   actions,
+		keyFields: cfg.KeyFields,
+		ttl:       cfg.TTL,
+		prefix:    "onix:response:" + moduleName + ":",
+		gated:     gated,
+	}, nil
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ResponseCacheConfig) withDefaults() ResponseCacheConfig {
+	if len(cfg.KeyFields) == 0 {
+		cfg.KeyFields = defaultResponseCacheKeyFields
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultResponseCacheTTL
+	}
+	return cfg
+}
+
+// gatedSteps returns the number of leading steps to run before the cache is looked up.
+func (c *responseCache) gatedSteps() int {
+	if c == nil {
+		return 0
+	}
+	return c.gated
+}
+
+// key returns the cache key of the request and whether its action is cacheable. Besides
+// the configured context fields, the key covers the subscriber, the signer validateSign
+// authenticated and the message, so that responses are never shared between callers or
+// between different requests.
+func (c *responseCache) key(ctx *model.StepContext) (string, bool) {
+	if c == nil || ctx.BecknContext == nil || !c.actions[ctx.BecknContext.Action] {
+		return "", false
+	}
+	body, err := ctx.BodyBytes()
+	if err != nil {
+		return "", false
+	}
+	var payload struct {
+		Context map[string]any  `json:"context"`
+		Message json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", false
+	}
+	values := make([]any, 0, len(c.keyFields)+3)
+	for _, f := range c.keyFields {
+		values = append(values, payload.Context[f])
+	}
+	message := sha256.Sum256(payload.Message)
+	values = append(values, ctx.SubID, ctx.Signer, hex.EncodeToString(message[:]))
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(b)
+	return c.prefix + hex.EncodeToString(sum[:]), true
+}
+
+// serve writes the cached response for key to w. It reports false on a miss, including
+// when the cache cannot be read.
+func (c *responseCache) serve(ctx context.Context, w http.ResponseWriter, key string) bool {
+	v, err := c.cache.Get(ctx, key)
+	if err != nil || v == "" {
+		return false
+	}
+	var resp cachedResponse
+	if err := json.Unmarshal([]byte(v), &resp); err != nil {
+		log.Warnf(ctx, "Discarding unreadable cached response: %v", err)
+		return false
+	}
+	for k, vs := range resp.Header {
+		w.Header()[k] = vs
+	}
+	w.WriteHeader(resp.Status)
+	if _, err := w.Write(resp.Body); err != nil {
+		log.Errorf(ctx, err, "Failed to write cached response")
+	}
+	return true
+}
+
+// store caches the response recorded by rec once it has been sent. Only complete
+// successful ACK responses are cached.
+func (c *responseCache) store(ctx context.Context, r *http.Request, key string, rec *responseRecorder) {
+	if !rec.cacheable() {
+		return
+	}
+	resp := cachedResponse{Status: rec.status, Header: rec.Header().Clone(), Body: rec.body.Bytes()}
+	for _, h := range uncachedHeaders {
+		resp.Header.Del(h)
+	}
+	save := func() {
+		b, err := json.Marshal(resp)
+		if err != nil {
+			log.Errorf(ctx, err, "Failed to encode response for caching")
+			return
+		}
+		if err := c.cache.Set(context.WithoutCancel(ctx), key, string(b), c.ttl); err != nil {
+			log.Warnf(ctx, "Failed to cache response: %v", err)
+		}
+	}
+	if !RegisterPostResponseHook(r, save) {
+		save()
+	}
+}
+
+// responseRecorder keeps a copy of the response written through it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+// newResponseRecorder returns a responseRecorder writing through to w.
+func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
+	return &responseRecorder{ResponseWriter: w}
+}
+
+// WriteHeader records the status code before writing it.
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write copies b up to maxCachedResponseSize before writing it.
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.overflow {
+		if w.body.Len()+len(b) > maxCachedResponseSize {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// cacheable reports whether the recorded response is a complete 2xx ACK that the
+// upstream did not mark no-store.
+func (w *responseRecorder) cacheable() bool {
+	return w.status >= http.StatusOK && w.status < http.StatusMultipleChoices &&
+		!w.overflow && !response.ContainsNack(w.body.Bytes()) &&
+		!slices.Contains(w.Header().Values("Cache-Control"), "no-store")
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCache is an in-memory Cache whose entries expire against a settable clock.
+type memCache struct {
+	definition.Cache
+	now     time.Time
+	entries map[string]memCacheEntry
+}
+
+type memCacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+func newMemCache() *memCache {
+	return &memCache{now: time.Now(), entries: map[string]memCacheEntry{}}
+}
+
+func (c *memCache) Get(_ context.Context, key string) (string, error) {
+	e, ok := c.entries[key]
+	if !ok || !c.now.Before(e.expires) {
+		return "", errors.New("cache miss")
+	}
+	return e.value, nil
+}
+
+func (c *memCache) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	c.entries[key] = memCacheEntry{value: value, expires: c.now.Add(ttl)}
+	return nil
+}
+
+func TestServeHTTPResponseCache(t *testing.T) {
+	var upstreamCalls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := upstreamCalls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		fmt.Fprintf(w, `{"n":%d,"message":{"ack":{"status":"ACK"}}}`, n)
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+
+	body := func(action, txnID string) string {
+		return fmt.Sprintf(`{"context":{"domain":"retail","action":%q,"transaction_id":%q},"message":{}}`, action, txnID)
+	}
+	withMessage := func(message string) string {
+		return fmt.Sprintf(`{"context":{"domain":"retail","action":"search","transaction_id":"t1"},"message":%s}`, message)
+	}
+	type call struct {
+		body       string
+		subscriber string
+		advance    time.Duration
+		want       string
+	}
+	tests := []struct {
+		name      string
+		calls     []call
+		wantSteps int
+	}{
+		{
+			name: "miss then hit",
+			calls: []call{
+				{body: body("search", "t1"), want: `"n":1`},
+				{body: body("search", "t1"), want: `"n":1`},
+			},
+			wantSteps: 1,
+		},
+		{
+			name: "different key fields miss",
+			calls: []call{
+				{body: body("search", "t1"), want: `"n":1`},
+				{body: body("search", "t2"), want: `"n":2`},
+			},
+			wantSteps: 2,
+		},
+		{
+			name: "different subscribers miss",
+			calls: []call{
+				{body: body("search", "t1"), subscriber: "bap1.example.com", want: `"n":1`},
+				{body: body("search", "t1"), subscriber: "bap2.example.com", want: `"n":2`},
+				{body: body("search", "t1"), subscriber: "bap1.example.com", want: `"n":1`},
+			},
+			wantSteps: 2,
+		},
+		{
+			name: "different messages miss",
+			calls: []call{
+				{body: withMessage(`{"intent":{"item":{"descriptor":{"name":"tea"}}}}`), want: `"n":1`},
+				{body: withMessage(`{"intent":{"item":{"descriptor":{"name":"coffee"}}}}`), want: `"n":2`},
+				{body: withMessage(`{"intent":{"item":{"descriptor":{"name":"tea"}}}}`), want: `"n":1`},
+			},
+			wantSteps: 2,
+		},
+		{
+			name: "uncacheable action is never cached",
+			calls: []call{
+				{body: body("select", "t1"), want: `"n":1`},
+				{body: body("select", "t1"), want: `"n":2`},
+			},
+			wantSteps: 2,
+		},
+		{
+			name: "expired entry misses",
+			calls: []call{
+				{body: body("search", "t1"), want: `"n":1`},
+				{body: body("search", "t1"), advance: 30 * time.Second, want: `"n":1`},
+				{body: body("search", "t1"), advance: 31 * time.Second, want: `"n":2`},
+			},
+			wantSteps: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstreamCalls.Store(0)
+			cache := newMemCache()
+			rc, err := newResponseCache(ResponseCacheConfig{
+				Actions:   []string{"search"},
+				KeyFields: []string{"domain", "action", "transaction_id"},
+				TTL:       time.Minute,
+			}, cache, "bapTxnCaller", nil)
+			require.NoError(t, err)
+			steps := 0
+			h := &stdHandler{
+				httpClient: upstream.Client(),
+				respCache:  rc,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					steps++
+					ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+					return nil
+				})},
+			}
+
+			for i, c := range tt.calls {
+				cache.now = cache.now.Add(c.advance)
+				req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(c.body))
+				if c.subscriber != "" {
+					req = req.WithContext(context.WithValue(req.Context(), model.ContextKeySubscriberID, c.subscriber))
+				}
+				req, hooks := withPostResponseHooks(req)
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+				for _, hook := range *hooks {
+					hook()
+				}
+
+				assert.Equal(t, http.StatusOK, rec.Code, "call %d", i)
+				assert.Contains(t, rec.Body.String(), c.want, "call %d", i)
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), "call %d", i)
+			}
+			assert.Equal(t, tt.wantSteps, steps)
+		})
+	}
+}
+
+func TestServeHTTPResponseCacheSkipsNacks(t *testing.T) {
+	cache := newMemCache()
+	rc, err := newResponseCache(ResponseCacheConfig{Actions: []string{"search"}}, cache, "bapTxnCaller", nil)
+	require.NoError(t, err)
+	h := &stdHandler{
+		respCache: rc,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			return model.NewBadReqErr(errors.New("invalid message"))
+		})},
+	}
+	req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/caller/search",
+		strings.NewReader(`{"context":{"domain":"retail","action":"search"},"message":{}}`)))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	for _, hook := range *hooks {
+		hook()
+	}
+
+	assert.Empty(t, cache.entries)
+}
+
+func TestServeHTTPResponseCacheAfterAuthentication(t *testing.T) {
+	var upstreamCalls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"n":%d,"message":{"ack":{"status":"ACK"}}}`, upstreamCalls.Add(1))
+	}))
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	rc, err := newResponseCache(ResponseCacheConfig{Actions: []string{"search"}}, newMemCache(), "bppTxnReceiver", []string{"validateSign", "addRoute"})
+	require.NoError(t, err)
+	h := &stdHandler{
+		httpClient: upstream.Client(),
+		respCache:  rc,
+		steps: []definition.Step{
+			stepFunc(func(ctx *model.StepContext) error {
+				if ctx.Signer = ctx.Request.Header.Get("Authorization"); ctx.Signer == "" {
+					return model.NewSignValidationErr(errors.New("missing signature"))
+				}
+				return nil
+			}),
+			stepFunc(func(ctx *model.StepContext) error {
+				ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+				return nil
+			}),
+		},
+	}
+	send := func(signer string) *httptest.ResponseRecorder {
+		req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bpp/receiver/search",
+			strings.NewReader(`{"context":{"domain":"retail","action":"search"},"message":{}}`)))
+		if signer != "" {
+			req.Header.Set("Authorization", signer)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		for _, hook := range *hooks {
+			hook()
+		}
+		return rec
+	}
+
+	assert.Contains(t, send("bap1.example.com").Body.String(), `"n":1`)
+	assert.Contains(t, send("bap1.example.com").Body.String(), `"n":1`, "the same signer is served from the cache")
+
+	rec := send("")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "unsigned requests are rejected before the cache is looked up")
+	assert.NotContains(t, rec.Body.String(), `"n":`)
+
+	assert.Contains(t, send("bap2.example.com").Body.String(), `"n":2`, "another signer gets its own response")
+	assert.Equal(t, int32(2), upstreamCalls.Load())
+}
+
+func TestNewResponseCache(t *testing.T) {
+	rc, err := newResponseCache(ResponseCacheConfig{}, nil, "m", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, rc)
+
+	_, err = newResponseCache(ResponseCacheConfig{Actions: []string{"search"}}, nil, "m", nil)
+	assert.ErrorIs(t, err, errResponseCacheNoCache)
+
+	_, err = newResponseCache(ResponseCacheConfig{Actions: []string{""}}, newMemCache(), "m", nil)
+	assert.ErrorContains(t, err, "empty action")
+
+	rc, err = newResponseCache(ResponseCacheConfig{Actions: []string{"search"}}, newMemCache(), "m", nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultResponseCacheTTL, rc.ttl)
+	assert.Equal(t, defaultResponseCacheKeyFields, rc.keyFields)
+	assert.Zero(t, rc.gatedSteps())
+
+	rc, err = newResponseCache(ResponseCacheConfig{Actions: []string{"search"}}, newMemCache(), "m",
+		[]string{"validateSign", "validateSchema", "checkSubscriberAccess", "addRoute"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, rc.gatedSteps())
+}
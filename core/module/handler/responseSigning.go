@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+// defaultMaxSignedResponseSize is used when ResponseSigningConfig.MaxBodySize is unset.
+const defaultMaxSignedResponseSize = 1 << 20
+
+// responseSigner signs the responses of requests forwarded in proxy mode before they
+// are returned to the client, with the keys of the subscriber the module acts for.
+type responseSigner struct {
+	km         definition.KeyManager
+	algorithms *signAlgorithms
+	clock      Clock
+	maxSize    int64
+}
+
+// newResponseSigner creates a responseSigner from cfg. Signature validity is measured
+// from clock, which defaults to the system clock when nil. It returns nil when response
+// signing is off.
+func newResponseSigner(signer definition.Signer, km definition.KeyManager, cfg ResponseSigningConfig, clock Clock) (*responseSigner, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if signer == nil {
+		return nil, fmt.Errorf("invalid config: responseSigning requires the Signer plugin")
+	}
+	if km == nil {
+		return nil, fmt.Errorf("invalid config: responseSigning requires the KeyManager plugin")
+	}
+	cfg = cfg.withDefaults()
+	algorithms, err := newSignAlgorithms(signer, cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &responseSigner{km: km, algorithms: algorithms, clock: orSystemClock(clock), maxSize: cfg.MaxBodySize}, nil
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ResponseSigningConfig) withDefaults() ResponseSigningConfig {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = defaultSignAlgorithm
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultMaxSignedResponseSize
+	}
+	return cfg
+}
+
+// forward runs proxy with the downstream response buffered and writes it to w with an
+// Authorization header signing its body. Responses too large to buffer are replaced by
+// a NACK with status 502, and responses that cannot be signed by a NACK.
+func (s *responseSigner) forward(ctx *model.StepContext, w http.ResponseWriter, proxy func(http.ResponseWriter)) {
+	buf := &bufferedResponse{header: http.Header{}, max: s.maxSize}
+	proxy(buf)
+
+	if buf.overflow {
+		err := fmt.Errorf("downstream response exceeds %d bytes and cannot be signed", s.maxSize)
+		log.Errorf(ctx, err, "Rejecting downstream response")
+		response.SendNackStatus(ctx, w, &model.Error{Code: strconv.Itoa(http.StatusBadGateway), Message: err.Error()}, http.StatusBadGateway)
+		return
+	}
+	authHeader, err := s.sign(ctx, buf.body.Bytes())
+	if err != nil {
+		log.Errorf(ctx, err, "Failed to sign downstream response")
+		response.SendNack(ctx, w, err)
+		return
+	}
+	buf.header.Set(model.AuthHeaderSubscriber, authHeader)
+	buf.writeTo(ctx, w)
+}
+
+// sign returns the Authorization header signing body with the keys of the request's
+// subscriber, using the first algorithm in the request's Accept-Signature header that
+// the signer supports.
+func (s *responseSigner) sign(ctx *model.StepContext, body []byte) (string, error) {
+	if ctx.SubID == "" {
+		return "", fmt.Errorf("subscriberID not set")
+	}
+	keySet, err := s.km.Keyset(ctx, ctx.SubID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get signing key: %w", err)
+	}
+	now := s.clock.Now()
+	createdAt := now.Unix()
+	validTill := now.Add(signatureValidity).Unix()
+	algorithm := s.algorithms.negotiate(ctx.Request.Header.Get(acceptSignatureHeader))
+	signature, err := s.algorithms.sign(ctx, algorithm, body, keySet, createdAt, validTill)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign response: %w", err)
+	}
+	return generateAuthHeader(ctx.SubID, keySet.UniqueKeyID, algorithm, createdAt, validTill, signature), nil
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signer"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signvalidator"
+)
+
+// proxyingHandler returns a handler forwarding requests in proxy mode to upstream and
+// signing the responses with respSigner.
+func proxyingHandler(t *testing.T, upstream *httptest.Server, respSigner *responseSigner) *stdHandler {
+	t.Helper()
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	return &stdHandler{
+		SubscriberID: "bpp.example.com",
+		httpClient:   upstream.Client(),
+		respSigner:   respSigner,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+			return nil
+		})},
+	}
+}
+
+func TestServeHTTPSignsProxiedResponse(t *testing.T) {
+	const body = `{"message":{"catalog":{"descriptor":{"name":"coffee"}}}}`
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer upstream.Close()
+	sgn, _, err := signer.New(context.Background(), &signer.Config{})
+	require.NoError(t, err)
+	sv, _, err := signvalidator.New(context.Background(), &signvalidator.Config{})
+	require.NoError(t, err)
+	km := newEd25519KeyManager(t)
+	respSigner, err := newResponseSigner(sgn, km, ResponseSigningConfig{Enabled: true}, nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+
+	proxyingHandler(t, upstream, respSigner).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`)))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, body, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	auth := rec.Header().Get(model.AuthHeaderSubscriber)
+	assert.Contains(t, auth, `keyId="bpp.example.com|k1|ed25519"`)
+
+	validate := func(body string) error {
+		step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{}, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/bap/receiver/on_search", nil)
+		req.Header.Set(model.AuthHeaderSubscriber, auth)
+		return step.Run(newTestStepCtx(req, []byte(body)))
+	}
+	assert.NoError(t, validate(rec.Body.String()), "the response signature should verify")
+	assert.Error(t, validate(strings.Replace(body, "coffee", "tea", 1)), "the signature should cover the body")
+}
+
+func TestServeHTTPResponseSigningFailures(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"ack":{"status":"ACK"}}}`))
+	}))
+	defer upstream.Close()
+	sgn, _, err := signer.New(context.Background(), &signer.Config{})
+	require.NoError(t, err)
+
+	t.Run("oversized response", func(t *testing.T) {
+		respSigner, err := newResponseSigner(sgn, newEd25519KeyManager(t), ResponseSigningConfig{Enabled: true, MaxBodySize: 8}, nil)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+
+		proxyingHandler(t, upstream, respSigner).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`)))
+
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		assert.Contains(t, rec.Body.String(), "exceeds 8 bytes and cannot be signed")
+		assert.Empty(t, rec.Header().Get(model.AuthHeaderSubscriber))
+	})
+
+	t.Run("accepted algorithm unsupported by the signer", func(t *testing.T) {
+		respSigner, err := newResponseSigner(sgn, newEd25519KeyManager(t), ResponseSigningConfig{Enabled: true}, nil)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(`{}`))
+		req.Header.Set(acceptSignatureHeader, "rsa-sha256")
+
+		proxyingHandler(t, upstream, respSigner).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "the signer falls back to its default algorithm")
+		assert.Contains(t, rec.Header().Get(model.AuthHeaderSubscriber), `algorithm="ed25519"`)
+	})
+}
+
+func TestNewResponseSigner(t *testing.T) {
+	s, err := newResponseSigner(nil, nil, ResponseSigningConfig{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	_, err = newResponseSigner(nil, newEd25519KeyManager(t), ResponseSigningConfig{Enabled: true}, nil)
+	assert.ErrorContains(t, err, "requires the Signer plugin")
+
+	sgn, _, err := signer.New(context.Background(), &signer.Config{})
+	require.NoError(t, err)
+	_, err = newResponseSigner(sgn, nil, ResponseSigningConfig{Enabled: true}, nil)
+	assert.ErrorContains(t, err, "requires the KeyManager plugin")
+
+	_, err = newResponseSigner(sgn, newEd25519KeyManager(t), ResponseSigningConfig{Enabled: true, Algorithm: "rsa-sha256"}, nil)
+	assert.Error(t, err, "the signer must support the configured algorithm")
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+)
+
+// defaultMaxValidatedResponseSize is used when ResponseValidationConfig.MaxBodySize is unset.
+const defaultMaxValidatedResponseSize = 1 << 20
+
+// errResponseValidationNoValidator is returned when response validation is enabled
+// without a schema validator to validate with.
+var errResponseValidationNoValidator = errors.New("responseValidation requires the responseSchemaValidator or schemaValidator plugin")
+
+// responseValidator validates the responses of requests forwarded in proxy mode before
+// they are returned to the client.
+type responseValidator struct {
+	validator     definition.SchemaValidator
+	endpoint      string
+	rewriteErrors bool
+	maxSize       int64
+}
+
+// newResponseValidator creates a responseValidator from cfg. Responses are validated with
+// the ResponseSchemaValidator plugin when configured, falling back to fallback. It
+// returns nil when response validation is off.
+func newResponseValidator(ctx context.Context, mgr PluginManager, cfg ResponseValidationConfig, pluginCfg *PluginCfg, fallback definition.SchemaValidator) (*responseValidator, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	validator, err := loadPlugin(ctx, "ResponseSchemaValidator", pluginCfg.ResponseSchemaValidator, mgr.SchemaValidator)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		validator = fallback
+	}
+	if validator == nil {
+		return nil, errResponseValidationNoValidator
+	}
+	return &responseValidator{
+		validator:     validator,
+		endpoint:      cfg.Endpoint,
+		rewriteErrors: cfg.RewriteErrors,
+		maxSize:       cfg.withDefaults().MaxBodySize,
+	}, nil
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ResponseValidationConfig) withDefaults() ResponseValidationConfig {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultMaxValidatedResponseSize
+	}
+	return cfg
+}
+
+// forward runs proxy with the downstream response buffered and writes the response to w
+// once it has been checked. Successful responses that fail validation are replaced by a
+// NACK with status 502. With rewriteErrors, error responses that are not NACKs are
+// replaced by a NACK with the same status.
+func (v *responseValidator) forward(ctx *model.StepContext, w http.ResponseWriter, proxy func(http.ResponseWriter)) {
+	buf := &bufferedResponse{header: http.Header{}, max: v.maxSize}
+	proxy(buf)
+
+	status := buf.statusCode()
+	switch {
+	case buf.overflow:
+		err := fmt.Errorf("downstream response exceeds %d bytes and cannot be validated", v.maxSize)
+		log.Errorf(ctx, err, "Rejecting downstream response")
+		response.SendNackStatus(ctx, w, &model.Error{Code: strconv.Itoa(http.StatusBadGateway), Message: err.Error()}, http.StatusBadGateway)
+		return
+	case status >= 200 && status < 300:
+		if nackErr := v.validate(ctx, buf.body.Bytes()); nackErr != nil {
+			log.Errorf(ctx, nackErr, "Downstream response failed schema validation")
+			response.SendNackStatus(ctx, w, nackErr, http.StatusBadGateway)
+			return
+		}
+	case v.rewriteErrors && status >= 400 && !isNack(buf.body.Bytes()):
+		log.Warnf(ctx, "Rewriting downstream error response with status %d into a NACK", status)
+		nackErr := &model.Error{Code: strconv.Itoa(status), Message: fmt.Sprintf("downstream responded with status %d", status)}
+		response.SendNackStatus(ctx, w, nackErr, status)
+		return
+	}
+	buf.writeTo(ctx, w)
+}
+
+// validate checks body against the schema of the response endpoint, returning the error
+// of the NACK to send when it does not conform.
+func (v *responseValidator) validate(ctx *model.StepContext, body []byte) *model.Error {
+	endpoint := v.endpoint
+	if endpoint == "" {
+		action := ""
+		if ctx.BecknContext != nil {
+			action = ctx.BecknContext.Action
+		}
+		if action == "" || strings.HasPrefix(action, "on_") {
+			// Callbacks are answered with a bare ACK, which has no schema of its own.
+			return nil
+		}
+		endpoint = "on_" + action
+	}
+	err := v.validator.Validate(ctx, &url.URL{Path: "/" + endpoint}, body)
+	if err == nil {
+		return nil
+	}
+	nackErr := &model.Error{
+		Code:    strconv.Itoa(http.StatusBadGateway),
+		Message: fmt.Sprintf("invalid downstream response: %v", err),
+	}
+	var schemaErr *model.SchemaValidationErr
+	if errors.As(err, &schemaErr) {
+		nackErr.Paths = schemaErr.BecknError().Paths
+	}
+	return nackErr
+}
+
+// isNack reports whether body is a Beckn NACK response.
+func isNack(body []byte) bool {
+	var resp model.Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false
+	}
+	return resp.Message.Ack.Status == response.NackStatus()
+}
+
+// bufferedResponse is an http.ResponseWriter that holds the response in memory. Bytes
+// beyond max are discarded and mark the response as overflowed.
+type bufferedResponse struct {
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	max      int64
+	overflow bool
+}
+
+// Header returns the response headers.
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the response status; only the first call takes effect.
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write buffers p, up to max bytes in all.
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	if room := b.max - int64(b.body.Len()); int64(len(p)) > room {
+		b.overflow = true
+		if room > 0 {
+			b.body.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	b.body.Write(p)
+	return len(p), nil
+}
+
+// statusCode returns the recorded status, defaulting to 200 like net/http.
+func (b *bufferedResponse) statusCode() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// writeTo sends the buffered response to w.
+func (b *bufferedResponse) writeTo(ctx context.Context, w http.ResponseWriter) {
+	for k, vs := range b.header {
+		w.Header()[k] = vs
+	}
+	w.WriteHeader(b.statusCode())
+	if _, err := w.Write(b.body.Bytes()); err != nil {
+		log.Errorf(ctx, err, "Failed to write downstream response")
+	}
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/schemavalidator"
+)
+
+// recordingSchemaValidator records the endpoints it validates against.
+type recordingSchemaValidator struct {
+	endpoints []string
+}
+
+func (v *recordingSchemaValidator) Validate(_ context.Context, u *url.URL, _ []byte) error {
+	v.endpoints = append(v.endpoints, u.Path)
+	return nil
+}
+
+// newOnSearchSchemaValidator returns a schema validator holding an on_search schema that
+// requires message.catalog.
+func newOnSearchSchemaValidator(t *testing.T) definition.SchemaValidator {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, dir, "schemas/retail/v1.1.0/on_search.json", `{
+		"type": "object",
+		"properties": {"message": {"type": "object", "required": ["catalog"]}},
+		"required": ["context", "message"]
+	}`)
+	sv, _, err := schemavalidator.New(context.Background(), &schemavalidator.Config{SchemaDir: filepath.Join(dir, "schemas")})
+	require.NoError(t, err)
+	return sv
+}
+
+// proxyResponding makes proxyFunc answer with status and body for the duration of t.
+func proxyResponding(t *testing.T, status int, body string) {
+	t.Helper()
+	orig := proxyFunc
+	proxyFunc = func(_ *model.StepContext, _ *http.Request, w http.ResponseWriter, _ *http.Client, _ *proxyStreamer) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Downstream", "bpp")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+	t.Cleanup(func() { proxyFunc = orig })
+}
+
+func TestServeHTTPValidatesProxiedResponse(t *testing.T) {
+	validResp := `{"context": {"domain": "retail", "version": "1.1.0", "action": "on_search"}, "message": {"catalog": {}}}`
+	invalidResp := `{"context": {"domain": "retail", "version": "1.1.0", "action": "on_search"}, "message": {}}`
+	downstreamNack := `{"message": {"ack": {"status": "NACK"}}, "error": {"code": "40000", "message": "bad item"}}`
+
+	tests := []struct {
+		name          string
+		cfg           ResponseValidationConfig
+		status        int
+		body          string
+		wantCode      int
+		wantBody      string
+		wantNack      string
+		wantForwarded bool
+	}{
+		{
+			name:          "schema-valid response is returned",
+			cfg:           ResponseValidationConfig{Enabled: true},
+			status:        http.StatusOK,
+			body:          validResp,
+			wantCode:      http.StatusOK,
+			wantBody:      validResp,
+			wantForwarded: true,
+		},
+		{
+			name:     "schema-invalid response is replaced by a NACK",
+			cfg:      ResponseValidationConfig{Enabled: true},
+			status:   http.StatusOK,
+			body:     invalidResp,
+			wantCode: http.StatusBadGateway,
+			wantNack: "invalid downstream response",
+		},
+		{
+			name:     "oversized response is rejected",
+			cfg:      ResponseValidationConfig{Enabled: true, MaxBodySize: 16},
+			status:   http.StatusOK,
+			body:     validResp,
+			wantCode: http.StatusBadGateway,
+			wantNack: "exceeds 16 bytes",
+		},
+		{
+			name:          "downstream error is returned unchanged",
+			cfg:           ResponseValidationConfig{Enabled: true},
+			status:        http.StatusServiceUnavailable,
+			body:          "upstream down",
+			wantCode:      http.StatusServiceUnavailable,
+			wantBody:      "upstream down",
+			wantForwarded: true,
+		},
+		{
+			name:     "downstream error is rewritten into a NACK",
+			cfg:      ResponseValidationConfig{Enabled: true, RewriteErrors: true},
+			status:   http.StatusServiceUnavailable,
+			body:     "upstream down",
+			wantCode: http.StatusServiceUnavailable,
+			wantNack: "downstream responded with status 503",
+		},
+		{
+			name:          "downstream NACK is not rewritten",
+			cfg:           ResponseValidationConfig{Enabled: true, RewriteErrors: true},
+			status:        http.StatusBadRequest,
+			body:          downstreamNack,
+			wantCode:      http.StatusBadRequest,
+			wantBody:      downstreamNack,
+			wantForwarded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxyResponding(t, tt.status, tt.body)
+			rv, err := newResponseValidator(context.Background(), &stubPluginManager{}, tt.cfg, &PluginCfg{}, newOnSearchSchemaValidator(t))
+			require.NoError(t, err)
+			h := &stdHandler{
+				respValidator: rv,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.Route = &model.Route{TargetType: "url", ActAsProxy: true, URL: &url.URL{Scheme: "http", Host: "bpp.example.com"}}
+					return nil
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{"context": {"domain": "retail", "version": "1.1.0", "action": "search"}}`))
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantNack != "" {
+				assert.Contains(t, rec.Body.String(), `"NACK"`)
+				assert.Contains(t, rec.Body.String(), tt.wantNack)
+			} else {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+			assert.Equal(t, tt.wantForwarded, rec.Header().Get("X-Downstream") == "bpp")
+		})
+	}
+}
+
+func TestResponseValidatorEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		action   string
+		want     []string
+	}{
+		{name: "defaults to the callback of the action", action: "select", want: []string{"/on_select"}},
+		{name: "configured endpoint", endpoint: "search_response", action: "search", want: []string{"/search_response"}},
+		{name: "callbacks are not validated by default", action: "on_select"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := &recordingSchemaValidator{}
+			rv, err := newResponseValidator(context.Background(), &stubPluginManager{}, ResponseValidationConfig{Enabled: true, Endpoint: tt.endpoint}, &PluginCfg{}, sv)
+			require.NoError(t, err)
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/"+tt.action, nil), []byte(`{}`))
+			ctx.BecknContext = &model.BecknContext{Action: tt.action}
+
+			assert.Nil(t, rv.validate(ctx, []byte(`{}`)))
+			assert.Equal(t, tt.want, sv.endpoints)
+		})
+	}
+}
+
+func TestNewResponseValidator(t *testing.T) {
+	fallback := &recordingSchemaValidator{}
+	dedicated := &recordingSchemaValidator{}
+
+	rv, err := newResponseValidator(context.Background(), &stubPluginManager{}, ResponseValidationConfig{}, &PluginCfg{}, fallback)
+	assert.NoError(t, err)
+	assert.Nil(t, rv, "disabled")
+
+	rv, err = newResponseValidator(context.Background(), &stubPluginManager{}, ResponseValidationConfig{Enabled: true}, &PluginCfg{}, fallback)
+	require.NoError(t, err)
+	assert.Same(t, fallback, rv.validator)
+	assert.EqualValues(t, defaultMaxValidatedResponseSize, rv.maxSize)
+
+	pluginCfg := &PluginCfg{ResponseSchemaValidator: &plugin.Config{ID: "responseschemavalidator"}}
+	rv, err = newResponseValidator(context.Background(), &stubPluginManager{schemaValidator: dedicated}, ResponseValidationConfig{Enabled: true}, pluginCfg, fallback)
+	require.NoError(t, err)
+	assert.Same(t, dedicated, rv.validator)
+
+	_, err = newResponseValidator(context.Background(), &stubPluginManager{}, ResponseValidationConfig{Enabled: true}, &PluginCfg{}, nil)
+	assert.True(t, errors.Is(err, errResponseValidationNoValidator))
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// validateActionForRoleStep rejects requests whose action the role they are processed
+// for must not receive, such as a search sent to a BAP.
+type validateActionForRoleStep struct {
+	// allowed holds the allowed actions of each role. Entries ending in * match the
+	// actions starting with the rest of the entry.
+	allowed map[model.Role][]string
+}
+
+// newValidateActionForRoleStep creates and returns the validateActionForRole step.
+func newValidateActionForRoleStep(cfg RoleActionsConfig) (definition.Step, error) {
+	if len(cfg.Allowed) == 0 {
+		return nil, errors.New("invalid config: validateActionForRole requires roleActions.allowed")
+	}
+	for role, actions := range cfg.Allowed {
+		for _, action := range actions {
+			if action == "" || strings.Contains(strings.TrimSuffix(action, "*"), "*") {
+				return nil, fmt.Errorf("invalid config: roleActions for %s: invalid action %q", role, action)
+			}
+		}
+	}
+	return &validateActionForRoleStep{allowed: cfg.Allowed}, nil
+}
+
+// Run checks context.action against the actions allowed for the role of the request.
+// Roles without an allow-list accept no actions.
+func (s *validateActionForRoleStep) Run(ctx *model.StepContext) error {
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	if bCtx.Action == "" {
+		return model.NewBadReqErr(errors.New("missing field action in context"))
+	}
+	for _, allowed := range s.allowed[ctx.Role] {
+		if allowed == bCtx.Action {
+			return nil
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok && strings.HasPrefix(bCtx.Action, prefix) {
+			return nil
+		}
+	}
+	return model.NewBadReqErr(fmt.Errorf("action %s is not allowed for role %s", bCtx.Action, ctx.Role))
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+func TestValidateActionForRoleStep(t *testing.T) {
+	step, err := newValidateActionForRoleStep(RoleActionsConfig{Allowed: map[model.Role][]string{
+		model.RoleBPP: {"search", "select", "init", "confirm", "status"},
+		model.RoleBAP: {"on_*"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		role    model.Role
+		action  string
+		allowed bool
+	}{
+		{role: model.RoleBPP, action: "search", allowed: true},
+		{role: model.RoleBPP, action: "confirm", allowed: true},
+		{role: model.RoleBPP, action: "on_search"},
+		{role: model.RoleBPP, action: "cancel"},
+		{role: model.RoleBAP, action: "on_search", allowed: true},
+		{role: model.RoleBAP, action: "on_confirm", allowed: true},
+		{role: model.RoleBAP, action: "search"},
+		{role: model.RoleGateway, action: "search"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+tt.action, func(t *testing.T) {
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{"context":{"action":"`+tt.action+`"}}`))
+			ctx.Role = tt.role
+
+			err := step.Run(ctx)
+
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			var badReq *model.BadReqErr
+			require.True(t, errors.As(err, &badReq), "got %v", err)
+			assert.ErrorContains(t, err, "action "+tt.action+" is not allowed for role "+string(tt.role))
+		})
+	}
+}
+
+func TestValidateActionForRoleStepMissingAction(t *testing.T) {
+	step, err := newValidateActionForRoleStep(RoleActionsConfig{Allowed: map[model.Role][]string{model.RoleBPP: {"*"}}})
+	require.NoError(t, err)
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{"context":{}}`))
+	ctx.Role = model.RoleBPP
+
+	assert.ErrorContains(t, step.Run(ctx), "missing field action")
+}
+
+func TestNewValidateActionForRoleStep(t *testing.T) {
+	_, err := newValidateActionForRoleStep(RoleActionsConfig{})
+	assert.ErrorContains(t, err, "requires roleActions.allowed")
+
+	_, err = newValidateActionForRoleStep(RoleActionsConfig{Allowed: map[model.Role][]string{model.RoleBAP: {"on_*_x*"}}})
+	assert.ErrorContains(t, err, `invalid action "on_*_x*"`)
+}
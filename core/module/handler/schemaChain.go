@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// schemaValidatorChain validates payloads with the first of its validators that has a
+// schema for them, such as a network's schemas backed by the generic Beckn core schemas.
+type schemaValidatorChain []definition.SchemaValidator
+
+// loadSchemaValidators loads the SchemaValidator plugin and its fallbacks, chaining them
+// when fallbacks are configured.
+func loadSchemaValidators(ctx context.Context, mgr PluginManager, cfg *plugin.Config, fallbacks []plugin.Config) (definition.SchemaValidator, error) {
+	primary, err := loadPlugin(ctx, "SchemaValidator", cfg, mgr.SchemaValidator)
+	if err != nil || len(fallbacks) == 0 {
+		return primary, err
+	}
+	if primary == nil {
+		return nil, fmt.Errorf("fallbackSchemaValidators require the SchemaValidator plugin")
+	}
+	chain := schemaValidatorChain{primary}
+	for i := range fallbacks {
+		v, err := loadPlugin(ctx, "SchemaValidator", &fallbacks[i], mgr.SchemaValidator)
+		if err != nil {
+			return nil, fmt.Errorf("fallback schema validator %d: %w", i, err)
+		}
+		chain = append(chain, v)
+	}
+	return chain, nil
+}
+
+// Validate validates payload with each validator in turn until one has a schema for it,
+// returning that validator's outcome. If none has, the not-found errors of all of them are
+// aggregated into a BadReqErr wrapping definition.ErrSchemaNotFound.
+func (c schemaValidatorChain) Validate(ctx context.Context, url *url.URL, payload []byte) error {
+	notFound := make([]string, 0, len(c))
+	for i, v := range c {
+		err := v.Validate(ctx, url, payload)
+		if !errors.Is(err, definition.ErrSchemaNotFound) {
+			if i > 0 && err == nil {
+				log.Debugf(ctx, "Validated payload with fallback schema validator %d", i)
+			}
+			return err
+		}
+		notFound = append(notFound, err.Error())
+	}
+	return model.NewBadReqErr(fmt.Errorf("%w by any of %d schema validators: %s", definition.ErrSchemaNotFound, len(c), strings.Join(notFound, "; ")))
+}
+
+// WarmUp warms up the validators of the chain that support it.
+func (c schemaValidatorChain) WarmUp(ctx context.Context, targets []string) error {
+	var errs []error
+	for _, v := range c {
+		if w, ok := v.(definition.Warmer); ok {
+			errs = append(errs, w.WarmUp(ctx, targets))
+		}
+	}
+	return errors.Join(errs...)
+}
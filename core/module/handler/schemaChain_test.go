@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// schemaSet is a SchemaValidator holding schemas for the endpoints it maps to the
+// outcome of validating against them.
+type schemaSet struct {
+	name    string
+	schemas map[string]error
+	calls   int
+}
+
+func (s *schemaSet) Validate(_ context.Context, u *url.URL, _ []byte) error {
+	s.calls++
+	err, ok := s.schemas[u.Path]
+	if !ok {
+		return model.NewBadReqErr(fmt.Errorf("%w in %s for %s", definition.ErrSchemaNotFound, s.name, u.Path))
+	}
+	return err
+}
+
+func TestSchemaValidatorChain(t *testing.T) {
+	errInvalid := &model.SchemaValidationErr{Errors: []model.Error{{Paths: "context.city", Message: "invalid city"}}}
+	tests := []struct {
+		name         string
+		path         string
+		wantErr      error
+		wantNotFound string
+		wantCalls    []int
+	}{
+		{name: "primary hit", path: "/search", wantCalls: []int{1, 0}},
+		{name: "primary hit failing validation", path: "/select", wantErr: errInvalid, wantCalls: []int{1, 0}},
+		{name: "fallback hit", path: "/init", wantCalls: []int{1, 1}},
+		{
+			name:         "both miss",
+			path:         "/confirm",
+			wantNotFound: "schema not found by any of 2 schema validators: schema not found in ondc for /confirm; schema not found in core for /confirm",
+			wantCalls:    []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &schemaSet{name: "ondc", schemas: map[string]error{"/search": nil, "/select": errInvalid}}
+			fallback := &schemaSet{name: "core", schemas: map[string]error{"/search": nil, "/init": nil}}
+			chain := schemaValidatorChain{primary, fallback}
+
+			err := chain.Validate(context.Background(), &url.URL{Path: tt.path}, []byte(`{}`))
+
+			switch {
+			case tt.wantNotFound != "":
+				var badReq *model.BadReqErr
+				require.True(t, errors.As(err, &badReq), "got %v", err)
+				assert.ErrorIs(t, err, definition.ErrSchemaNotFound)
+				assert.EqualError(t, err, tt.wantNotFound)
+			case tt.wantErr != nil:
+				assert.Equal(t, tt.wantErr, err)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, []int{primary.calls, fallback.calls})
+		})
+	}
+}
+
+func TestLoadSchemaValidators(t *testing.T) {
+	v := &schemaSet{name: "ondc"}
+	mgr := &stubPluginManager{schemaValidator: v}
+	fallbacks := []plugin.Config{{ID: "schemavalidator"}}
+
+	got, err := loadSchemaValidators(context.Background(), mgr, &plugin.Config{ID: "schemavalidator"}, nil)
+	require.NoError(t, err)
+	assert.Same(t, v, got, "without fallbacks the plugin is not chained")
+
+	got, err = loadSchemaValidators(context.Background(), mgr, &plugin.Config{ID: "schemavalidator"}, fallbacks)
+	require.NoError(t, err)
+	assert.Equal(t, schemaValidatorChain{v, v}, got)
+
+	_, err = loadSchemaValidators(context.Background(), mgr, nil, fallbacks)
+	assert.ErrorContains(t, err, "fallbackSchemaValidators require the SchemaValidator plugin")
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// anyCity is the city entry of a ServedRegion that serves every city of its country.
+const anyCity = "*"
+
+// validateServiceabilityStep rejects requests whose context names a region that is not
+// served.
+type validateServiceabilityStep struct {
+	// cities holds the served cities of each served country; a nil set serves every
+	// city of the country.
+	cities map[string]map[string]bool
+}
+
+// newValidateServiceabilityStep creates and returns the validateServiceability step.
+func newValidateServiceabilityStep(cfg ServiceabilityConfig) (definition.Step, error) {
+	if len(cfg.Regions) == 0 {
+		return nil, errors.New("invalid config: validateServiceability requires serviceability.regions")
+	}
+	s := &validateServiceabilityStep{cities: make(map[string]map[string]bool, len(cfg.Regions))}
+	for _, region := range cfg.Regions {
+		if region.Country == "" {
+			return nil, errors.New("invalid config: serviceability region without a country")
+		}
+		if _, ok := s.cities[region.Country]; ok {
+			return nil, fmt.Errorf("invalid config: serviceability country %s is listed more than once", region.Country)
+		}
+		var cities map[string]bool
+		for _, city := range region.Cities {
+			if city == "" {
+				return nil, fmt.Errorf("invalid config: serviceability country %s: empty city code", region.Country)
+			}
+			if city == anyCity {
+				cities = nil
+				break
+			}
+			if cities == nil {
+				cities = make(map[string]bool, len(region.Cities))
+			}
+			cities[city] = true
+		}
+		s.cities[region.Country] = cities
+	}
+	return s, nil
+}
+
+// serviceabilityContext holds the location fields of a Beckn context: location.city.code
+// and location.country.code, or the city and country codes of older ONDC versions.
+type serviceabilityContext struct {
+	Context struct {
+		City     string `json:"city"`
+		Country  string `json:"country"`
+		Location struct {
+			City struct {
+				Code string `json:"code"`
+			} `json:"city"`
+			Country struct {
+				Code string `json:"code"`
+			} `json:"country"`
+		} `json:"location"`
+	} `json:"context"`
+}
+
+// Run checks the country and city codes of the context against the served regions.
+func (s *validateServiceabilityStep) Run(ctx *model.StepContext) error {
+	var payload serviceabilityContext
+	if err := json.Unmarshal(ctx.Body, &payload); err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to parse context: %w", err))
+	}
+	loc := payload.Context.Location
+	country, city := loc.Country.Code, loc.City.Code
+	if country == "" {
+		country = payload.Context.Country
+	}
+	if city == "" {
+		city = payload.Context.City
+	}
+	if country == "" {
+		return model.NewBadReqErr(errors.New("missing field location.country.code in context"))
+	}
+	if city == "" {
+		return model.NewBadReqErr(errors.New("missing field location.city.code in context"))
+	}
+	cities, ok := s.cities[country]
+	if !ok {
+		return model.NewBadReqErr(fmt.Errorf("country %s is not served", country))
+	}
+	if cities != nil && !cities[city] {
+		return model.NewBadReqErr(fmt.Errorf("city %s is not served in country %s", city, country))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+func TestValidateServiceabilityStep(t *testing.T) {
+	step, err := newValidateServiceabilityStep(ServiceabilityConfig{Regions: []ServedRegion{
+		{Country: "IND", Cities: []string{"std:080", "std:011"}},
+		{Country: "LKA"},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		context string
+		wantErr string
+	}{
+		{name: "served city", context: `{"location":{"city":{"code":"std:080"},"country":{"code":"IND"}}}`},
+		{name: "whole country served", context: `{"location":{"city":{"code":"std:011"},"country":{"code":"LKA"}}}`},
+		{name: "older context fields", context: `{"city":"std:011","country":"IND"}`},
+		{name: "unserved city", context: `{"location":{"city":{"code":"std:044"},"country":{"code":"IND"}}}`, wantErr: "city std:044 is not served in country IND"},
+		{name: "unserved country", context: `{"location":{"city":{"code":"*"},"country":{"code":"USA"}}}`, wantErr: "country USA is not served"},
+		{name: "missing country", context: `{"location":{"city":{"code":"std:080"}}}`, wantErr: "missing field location.country.code"},
+		{name: "missing city", context: `{"location":{"country":{"code":"IND"}}}`, wantErr: "missing field location.city.code"},
+		{name: "missing location", context: `{"action":"search"}`, wantErr: "missing field location.country.code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{"context":`+tt.context+`}`))
+
+			err := step.Run(ctx)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var badReq *model.BadReqErr
+			require.ErrorAs(t, err, &badReq)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewValidateServiceabilityStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ServiceabilityConfig
+		wantErr string
+	}{
+		{name: "no regions", wantErr: "requires serviceability.regions"},
+		{name: "no country", cfg: ServiceabilityConfig{Regions: []ServedRegion{{Cities: []string{"std:080"}}}}, wantErr: "region without a country"},
+		{name: "duplicate country", cfg: ServiceabilityConfig{Regions: []ServedRegion{{Country: "IND"}, {Country: "IND"}}}, wantErr: "IND is listed more than once"},
+		{name: "empty city", cfg: ServiceabilityConfig{Regions: []ServedRegion{{Country: "IND", Cities: []string{""}}}}, wantErr: "empty city code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newValidateServiceabilityStep(tt.cfg)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	step, err := newValidateServiceabilityStep(ServiceabilityConfig{Regions: []ServedRegion{{Country: "IND", Cities: []string{"std:080", "*"}}}})
+	require.NoError(t, err)
+	assert.Nil(t, step.(*validateServiceabilityStep).cities["IND"], "* serves every city")
+}
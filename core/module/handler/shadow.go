@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// defaultShadowTimeout bounds a mirrored request when ShadowConfig.Timeout is unset.
+const defaultShadowTimeout = 30 * time.Second
+
+// shadowTarget receives a copy of every routed request.
+type shadowTarget struct {
+	url     *url.URL
+	timeout time.Duration
+}
+
+// newShadowTarget creates a shadowTarget from cfg. It returns nil when no shadow URL is configured.
+func newShadowTarget(cfg ShadowConfig) (*shadowTarget, error) {
+	if cfg.URL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid shadow url %q: scheme must be http or https", cfg.URL)
+	}
+	return &shadowTarget{url: u, timeout: cfg.withDefaults().Timeout}, nil
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg ShadowConfig) withDefaults() ShadowConfig {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultShadowTimeout
+	}
+	return cfg
+}
+
+// mirror sends a copy of the routed request to the shadow target once the response has
+// been sent. The shadow response is discarded and failures are only logged, so the
+// primary path is unaffected.
+func (h *stdHandler) mirror(ctx *model.StepContext, r *http.Request) {
+	if h.shadow == nil {
+		return
+	}
+	header := r.Header.Clone()
+	send := func() {
+		sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx.Context), h.shadow.timeout)
+		defer cancel()
+		if err := h.sendShadow(sctx, ctx, header); err != nil {
+			log.Warnf(ctx, "Shadow request to %s failed: %v", h.shadow.url, err)
+		}
+	}
+	if !RegisterPostResponseHook(r, send) {
+		send()
+	}
+}
+
+// sendShadow posts the forwarded body with header to the shadow target.
+func (h *stdHandler) sendShadow(ctx context.Context, stepCtx *model.StepContext, header http.Header) error {
+	body, err := forwardBody(stepCtx)
+	if err != nil {
+		return fmt.Errorf("failed to restore request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.shadow.url.String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = header
+	removeHopByHopHeaders(req.Header)
+	if err := setBodyFraming(req, body); err != nil {
+		return err
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	log.Debugf(ctx, "Shadow request completed with status %d", resp.StatusCode)
+	return nil
+}
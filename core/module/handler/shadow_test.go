@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shadowRequest is a request received by a test shadow target.
+type shadowRequest struct {
+	body string
+	auth string
+}
+
+func newShadowServer(t *testing.T, status int) (*httptest.Server, <-chan shadowRequest) {
+	t.Helper()
+	got := make(chan shadowRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got <- shadowRequest{body: string(b), auth: r.Header.Get("Authorization")}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func TestServeHTTPMirrorsToShadow(t *testing.T) {
+	const transformed = `{"context":{"action":"search"},"message":{}}`
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"message":{"ack":{"status":"ACK"}}}`)
+	}))
+	defer primary.Close()
+	target, err := url.Parse(primary.URL)
+	require.NoError(t, err)
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	tests := []struct {
+		name       string
+		shadowURL  func(t *testing.T) (string, <-chan shadowRequest)
+		wantShadow bool
+	}{
+		{
+			name: "shadow receives a copy",
+			shadowURL: func(t *testing.T) (string, <-chan shadowRequest) {
+				srv, got := newShadowServer(t, http.StatusOK)
+				return srv.URL, got
+			},
+			wantShadow: true,
+		},
+		{
+			name: "shadow error response",
+			shadowURL: func(t *testing.T) (string, <-chan shadowRequest) {
+				srv, got := newShadowServer(t, http.StatusInternalServerError)
+				return srv.URL, got
+			},
+			wantShadow: true,
+		},
+		{
+			name: "shadow unreachable",
+			shadowURL: func(t *testing.T) (string, <-chan shadowRequest) {
+				return unreachable.URL, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shadowURL, got := tt.shadowURL(t)
+			shadow, err := newShadowTarget(ShadowConfig{URL: shadowURL})
+			require.NoError(t, err)
+			h := &stdHandler{
+				httpClient: primary.Client(),
+				shadow:     shadow,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.Body = []byte(transformed)
+					ctx.Route = &model.Route{TargetType: "url", URL: target, ActAsProxy: true}
+					return nil
+				})},
+			}
+			req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bpp/caller/on_search", strings.NewReader(`{}`)))
+			req.Header.Set("Authorization", `Signature keyId="bpp|k1|ed25519"`)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"ACK"`)
+
+			require.NotPanics(t, func() {
+				for _, hook := range *hooks {
+					hook()
+				}
+			})
+			if !tt.wantShadow {
+				return
+			}
+			select {
+			case r := <-got:
+				assert.Equal(t, transformed, r.body)
+				assert.Equal(t, `Signature keyId="bpp|k1|ed25519"`, r.auth)
+			default:
+				t.Fatal("shadow target did not receive the request")
+			}
+		})
+	}
+}
+
+func TestServeHTTPSkipsShadowForUnroutedRequests(t *testing.T) {
+	srv, got := newShadowServer(t, http.StatusOK)
+	shadow, err := newShadowTarget(ShadowConfig{URL: srv.URL})
+	require.NoError(t, err)
+	h := &stdHandler{httpClient: srv.Client(), shadow: shadow}
+	req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/receiver/on_search", strings.NewReader(`{}`)))
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	for _, hook := range *hooks {
+		hook()
+	}
+
+	assert.Empty(t, got)
+}
+
+func TestNewShadowTarget(t *testing.T) {
+	shadow, err := newShadowTarget(ShadowConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, shadow)
+
+	shadow, err = newShadowTarget(ShadowConfig{URL: "http://shadow.example.com/bpp"})
+	require.NoError(t, err)
+	assert.Equal(t, defaultShadowTimeout, shadow.timeout)
+
+	_, err = newShadowTarget(ShadowConfig{URL: "shadow.example.com/bpp"})
+	assert.ErrorContains(t, err, "scheme must be http or https")
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// SharedStepLookup is an optional interface implemented by PluginManagers that hold step
+// plugin instances shared by the handlers of several modules. Modules reference a shared
+// step by its ID in their steps list; a step plugin of the same ID configured in the
+// module itself takes precedence.
+type SharedStepLookup interface {
+	// SharedStep returns the shared step registered as id.
+	SharedStep(id string) (definition.Step, bool)
+}
+
+// StepRegistry holds step plugin instances that are created once and shared by the
+// handlers of several modules. It is safe for concurrent use. Shared steps are run by
+// every module that references them, concurrently, so they must be safe for concurrent
+// use too.
+type StepRegistry struct {
+	mu    sync.RWMutex
+	steps map[string]definition.Step
+}
+
+// NewStepRegistry returns an empty StepRegistry.
+func NewStepRegistry() *StepRegistry {
+	return &StepRegistry{steps: map[string]definition.Step{}}
+}
+
+// Register adds step to the registry as id. Each id can be registered once.
+func (r *StepRegistry) Register(id string, step definition.Step) error {
+	if id == "" {
+		return fmt.Errorf("invalid shared step: empty id")
+	}
+	if step == nil {
+		return fmt.Errorf("invalid shared step %s: nil step", id)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.steps[id]; ok {
+		return fmt.Errorf("shared step %s is already registered", id)
+	}
+	r.steps[id] = step
+	return nil
+}
+
+// Load creates an instance of each configured step plugin through mgr and registers it
+// under the plugin ID.
+func (r *StepRegistry) Load(ctx context.Context, mgr PluginManager, cfgs []plugin.Config) error {
+	for i := range cfgs {
+		step, err := mgr.Step(ctx, &cfgs[i])
+		if err != nil {
+			return fmt.Errorf("failed to initialize shared step %s: %w", cfgs[i].ID, err)
+		}
+		if err := r.Register(cfgs[i].ID, step); err != nil {
+			return err
+		}
+		log.Infof(ctx, "Registered shared step %s", cfgs[i].ID)
+	}
+	return nil
+}
+
+// SharedStep returns the step registered as id.
+func (r *StepRegistry) SharedStep(id string) (definition.Step, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	step, ok := r.steps[id]
+	return step, ok
+}
+
+// sharedStep returns the shared step registered as id with mgr, if mgr holds shared steps.
+func sharedStep(mgr PluginManager, id string) (definition.Step, bool) {
+	l, ok := mgr.(SharedStepLookup)
+	if !ok {
+		return nil, false
+	}
+	return l.SharedStep(id)
+}
+
+// sharedStepManager is a PluginManager that also looks up shared steps.
+type sharedStepManager struct {
+	PluginManager
+	steps *StepRegistry
+}
+
+// WithSharedSteps returns mgr extended with the steps of r, so that handlers created
+// with it can reference them by ID.
+func WithSharedSteps(mgr PluginManager, r *StepRegistry) PluginManager {
+	return &sharedStepManager{PluginManager: mgr, steps: r}
+}
+
+// SharedStep returns the step registered as id in the registry.
+func (m *sharedStepManager) SharedStep(id string) (definition.Step, bool) {
+	return m.steps.SharedStep(id)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// atomicCountingStep counts its runs and is safe for concurrent use.
+type atomicCountingStep struct {
+	id   string
+	runs atomic.Int32
+}
+
+func (s *atomicCountingStep) Run(*model.StepContext) error {
+	s.runs.Add(1)
+	return nil
+}
+
+// stepPluginManager creates a new atomicCountingStep for every step plugin it is asked for.
+type stepPluginManager struct {
+	stubPluginManager
+	mu      sync.Mutex
+	created []*atomicCountingStep
+}
+
+func (m *stepPluginManager) Step(_ context.Context, cfg *plugin.Config) (definition.Step, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := &atomicCountingStep{id: cfg.ID}
+	m.created = append(m.created, s)
+	return s, nil
+}
+
+func TestSharedStepReusedAcrossHandlers(t *testing.T) {
+	mgr := &stepPluginManager{}
+	registry := NewStepRegistry()
+	require.NoError(t, registry.Load(context.Background(), mgr, []plugin.Config{{ID: "audit"}}))
+	shared := WithSharedSteps(mgr, registry)
+
+	var handlers []http.Handler
+	for _, module := range []string{"bapTxnReceiver", "bppTxnReceiver"} {
+		h, err := NewStdHandler(context.Background(), shared, &Config{Steps: []string{"audit"}}, module)
+		require.NoError(t, err)
+		handlers = append(handlers, h)
+	}
+
+	const perHandler = 20
+	var wg sync.WaitGroup
+	for _, h := range handlers {
+		for range perHandler {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(`{}`))
+				h.ServeHTTP(httptest.NewRecorder(), req)
+			}()
+		}
+	}
+	wg.Wait()
+
+	require.Len(t, mgr.created, 1, "the shared step is created once")
+	assert.Equal(t, int32(len(handlers)*perHandler), mgr.created[0].runs.Load())
+}
+
+func TestSharedStepModulePluginTakesPrecedence(t *testing.T) {
+	mgr := &stepPluginManager{}
+	registry := NewStepRegistry()
+	require.NoError(t, registry.Load(context.Background(), mgr, []plugin.Config{{ID: "audit"}}))
+	h, err := NewStdHandler(context.Background(), WithSharedSteps(mgr, registry), &Config{
+		Plugins: PluginCfg{Steps: []plugin.Config{{ID: "audit"}}},
+		Steps:   []string{"audit"},
+	}, "bapTxnReceiver")
+	require.NoError(t, err)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(`{}`)))
+
+	require.Len(t, mgr.created, 2)
+	assert.Equal(t, int32(0), mgr.created[0].runs.Load(), "shared step")
+	assert.Equal(t, int32(1), mgr.created[1].runs.Load(), "module step")
+}
+
+func TestSharedStepUnknownWithoutRegistry(t *testing.T) {
+	_, err := NewStdHandler(context.Background(), &stepPluginManager{}, &Config{Steps: []string{"audit"}}, "bapTxnReceiver")
+	assert.ErrorContains(t, err, "unrecognized step: audit")
+}
+
+func TestStepRegistryRegister(t *testing.T) {
+	r := NewStepRegistry()
+	step := &atomicCountingStep{}
+	require.NoError(t, r.Register("audit", step))
+
+	got, ok := r.SharedStep("audit")
+	assert.True(t, ok)
+	assert.Same(t, step, got)
+	_, ok = r.SharedStep("other")
+	assert.False(t, ok)
+
+	assert.ErrorContains(t, r.Register("audit", &atomicCountingStep{}), "already registered")
+	assert.ErrorContains(t, r.Register("", step), "empty id")
+	assert.ErrorContains(t, r.Register("nil", nil), "nil step")
+}
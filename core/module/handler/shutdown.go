@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// Drainer is implemented by handlers that can finish their in-flight work before shutdown.
+type Drainer interface {
+	Shutdown(ctx context.Context) error
+}
+
+// errShuttingDown is reported for requests and readiness checks once draining has started.
+var errShuttingDown = errors.New("handler is shutting down")
+
+// beginRequest marks a request as in flight. It returns false once the handler is draining.
+func (h *stdHandler) beginRequest() bool {
+	h.drainMu.RLock()
+	defer h.drainMu.RUnlock()
+	if h.draining {
+		return false
+	}
+	h.inflight.Add(1)
+	return true
+}
+
+// endRequest marks the request complete once its post-response hooks have run.
+// It must be called after the request has registered all of its hooks.
+func (h *stdHandler) endRequest(r *http.Request) {
+	if !RegisterPostResponseHook(r, h.inflight.Done) {
+		h.inflight.Done()
+	}
+}
+
+// Shutdown stops the handler from accepting new requests, waits for in-flight requests
+// and their post-response hooks (async forwards and publishes) to finish, publishes the
+// messages left in the publish queue, and then flushes a publisher implementing
+// definition.Flusher. If ctx ends first, the remaining work is abandoned and the context
+// error is returned.
+func (h *stdHandler) Shutdown(ctx context.Context) error {
+	h.drainMu.Lock()
+	h.draining = true
+	h.drainMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("abandoned in-flight requests: %w", ctx.Err())
+	}
+
+	if h.pubQueue != nil {
+		if err := h.pubQueue.drain(ctx); err != nil {
+			return fmt.Errorf("abandoned queued messages: %w", err)
+		}
+	}
+	if f, ok := h.publisher.(definition.Flusher); ok {
+		if err := f.Flush(ctx); err != nil {
+			return fmt.Errorf("failed to flush publisher: %w", err)
+		}
+	}
+	return nil
+}
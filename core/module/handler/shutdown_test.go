@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flushingPublisher is a publisher that records whether it was flushed.
+type flushingPublisher struct {
+	mockPublisher
+	flushed  bool
+	flushErr error
+}
+
+func (p *flushingPublisher) Flush(context.Context) error {
+	p.flushed = true
+	return p.flushErr
+}
+
+// asyncHandler returns a handler whose single step registers a post-response hook
+// that records its completion on done.
+func asyncHandler(pub definition.Publisher, done chan<- struct{}) *stdHandler {
+	return &stdHandler{
+		publisher: pub,
+		steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+			RegisterPostResponseHook(ctx.Request, func() { close(done) })
+			return nil
+		})},
+	}
+}
+
+// serveWithHooks serves a request through h and returns its pending post-response hooks.
+func serveWithHooks(t *testing.T, h http.Handler) []PostResponseHook {
+	t.Helper()
+	req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`)))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	return *hooks
+}
+
+func TestShutdownWaitsForPostResponseHooks(t *testing.T) {
+	done := make(chan struct{})
+	pub := &flushingPublisher{}
+	h := asyncHandler(pub, done)
+	hooks := serveWithHooks(t, h)
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- h.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("Shutdown returned before the async hook ran: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// New requests are rejected while draining.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.ErrorIs(t, h.Ready(context.Background()), errShuttingDown)
+
+	// Run the hooks as PostResponseMiddleware would once the response is sent.
+	for _, hook := range hooks {
+		hook()
+	}
+	require.NoError(t, <-shutdownErr)
+	assert.True(t, pub.flushed, "publisher should be flushed after draining")
+	select {
+	case <-done:
+	default:
+		t.Fatal("async hook did not complete")
+	}
+}
+
+func TestShutdownAbandonsAfterDeadline(t *testing.T) {
+	pub := &flushingPublisher{}
+	h := asyncHandler(pub, make(chan struct{}))
+	serveWithHooks(t, h) // hooks are never run
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := h.Shutdown(ctx)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, pub.flushed, "publisher should not be flushed when draining is abandoned")
+}
+
+func TestShutdownWithoutPostResponseMiddleware(t *testing.T) {
+	pub := &flushingPublisher{flushErr: errors.New("broker unavailable")}
+	h := &stdHandler{publisher: pub}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`)))
+
+	err := h.Shutdown(context.Background())
+
+	require.ErrorContains(t, err, "broker unavailable")
+	assert.True(t, pub.flushed)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// defaultSignAlgorithm is the signature algorithm used when SignatureConfig.Algorithm is unset.
+const defaultSignAlgorithm = "ed25519"
+
+// acceptSignatureHeader lets the sender of a request ask for the signature algorithms it accepts.
+const acceptSignatureHeader = "Accept-Signature"
+
+// errNoKeyRefSigner is returned when a signing key is held by reference and the Signer
+// plugin cannot sign with it.
+var errNoKeyRefSigner = errors.New("signing key is held in a KMS but the Signer plugin cannot sign by key reference")
+
+// signAlgorithms selects the algorithm a request is signed with.
+type signAlgorithms struct {
+	signer definition.Signer
+	// fallback is used when the request does not negotiate a supported algorithm.
+	fallback string
+	// supported holds the algorithms the signer supports, keyed by their lower-cased name.
+	supported map[string]string
+}
+
+// newSignAlgorithms creates signAlgorithms for signer, falling back to algorithm, which
+// callers take from a config with defaults filled in. Signers that do not implement
+// definition.AlgorithmSigner only support defaultSignAlgorithm.
+func newSignAlgorithms(signer definition.Signer, algorithm string) (*signAlgorithms, error) {
+	a := &signAlgorithms{signer: signer, fallback: algorithm, supported: map[string]string{}}
+	if as, ok := signer.(definition.AlgorithmSigner); ok {
+		for _, alg := range as.Algorithms() {
+			a.supported[strings.ToLower(alg)] = alg
+		}
+	} else {
+		a.supported[defaultSignAlgorithm] = defaultSignAlgorithm
+	}
+	alg, ok := a.supported[strings.ToLower(a.fallback)]
+	if !ok {
+		return nil, fmt.Errorf("invalid config: signature algorithm %q is not supported by the Signer plugin", a.fallback)
+	}
+	a.fallback = alg
+	return a, nil
+}
+
+// negotiate returns the first algorithm listed in acceptSignature that the signer
+// supports, or the fallback when there is none. Entries are either bare algorithm names,
+// such as "ed25519", or RFC 9421 signature requests carrying an alg parameter, such as
+// `sig1=("@method");alg="ed25519"`.
+func (a *signAlgorithms) negotiate(acceptSignature string) string {
+	for _, entry := range strings.Split(acceptSignature, ",") {
+		if alg, ok := a.supported[strings.ToLower(acceptedAlgorithm(entry))]; ok {
+			return alg
+		}
+	}
+	return a.fallback
+}
+
+// acceptedAlgorithm returns the algorithm named by an Accept-Signature entry, or "" if it
+// names none.
+func acceptedAlgorithm(entry string) string {
+	entry = strings.TrimSpace(entry)
+	if !strings.ContainsAny(entry, "=;") {
+		return strings.Trim(entry, `"`)
+	}
+	for _, param := range strings.Split(entry, ";")[1:] {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.EqualFold(name, "alg") {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// sign signs body with algorithm and the signing key of keySet, calling the plain Sign
+// method for the default algorithm of signers without algorithm support. Keys held by
+// reference are signed with remotely and require a definition.KeyRefSigner.
+func (a *signAlgorithms) sign(ctx context.Context, algorithm string, body []byte, keySet *model.Keyset, createdAt, expiresAt int64) (string, error) {
+	if keySet.SigningKeyRef != "" {
+		rs, ok := a.signer.(definition.KeyRefSigner)
+		if !ok {
+			return "", errNoKeyRefSigner
+		}
+		return rs.SignWithKeyRef(ctx, algorithm, body, keySet.SigningKeyRef, createdAt, expiresAt)
+	}
+	if as, ok := a.signer.(definition.AlgorithmSigner); ok {
+		return as.SignWithAlgorithm(ctx, algorithm, body, keySet.SigningPrivate, createdAt, expiresAt)
+	}
+	return a.signer.Sign(ctx, body, keySet.SigningPrivate, createdAt, expiresAt)
+}
+
+// validateAlgorithms returns the algorithms validator supports, keyed by their lower-cased
+// name. Validators that do not implement definition.AlgorithmValidator only support
+// defaultSignAlgorithm.
+func validateAlgorithms(validator definition.SignValidator) map[string]string {
+	av, ok := validator.(definition.AlgorithmValidator)
+	if !ok {
+		return map[string]string{defaultSignAlgorithm: defaultSignAlgorithm}
+	}
+	algorithms := map[string]string{}
+	for _, alg := range av.Algorithms() {
+		algorithms[strings.ToLower(alg)] = alg
+	}
+	return algorithms
+}
+
+// signatureAlgorithm returns the algorithm named in the keyId of a signature header,
+// defaulting to defaultSignAlgorithm when the keyId names none. It fails for algorithms
+// the validator does not support.
+func (s *validateSignStep) signatureAlgorithm(headerVals *authHeader) (string, error) {
+	if headerVals.Algorithm == "" {
+		return defaultSignAlgorithm, nil
+	}
+	alg, ok := s.algorithms[strings.ToLower(headerVals.Algorithm)]
+	if !ok {
+		return "", fmt.Errorf("signature algorithm %q is not supported by the SignValidator plugin", headerVals.Algorithm)
+	}
+	return alg, nil
+}
+
+// verify checks the signature in header over body with publicKey and algorithm, calling
+// the plain Validate method of validators without algorithm support.
+func (s *validateSignStep) verify(ctx context.Context, algorithm string, body []byte, header, publicKey string) error {
+	if av, ok := s.validator.(definition.AlgorithmValidator); ok {
+		return av.ValidateWithAlgorithm(ctx, algorithm, body, header, publicKey)
+	}
+	return s.validator.Validate(ctx, body, header, publicKey)
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signer"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signvalidator"
+)
+
+// multiAlgSigner is an AlgorithmSigner that records the algorithm it signed with.
+type multiAlgSigner struct {
+	algorithms []string
+	used       string
+}
+
+func (s *multiAlgSigner) Sign(ctx context.Context, body []byte, key string, createdAt, expiresAt int64) (string, error) {
+	return s.SignWithAlgorithm(ctx, "plain", body, key, createdAt, expiresAt)
+}
+
+func (s *multiAlgSigner) Algorithms() []string {
+	return s.algorithms
+}
+
+func (s *multiAlgSigner) SignWithAlgorithm(_ context.Context, algorithm string, _ []byte, _ string, _, _ int64) (string, error) {
+	s.used = algorithm
+	return "sig-" + algorithm, nil
+}
+
+func TestSignStepAlgorithmNegotiation(t *testing.T) {
+	tests := []struct {
+		name            string
+		algorithm       string
+		acceptSignature string
+		want            string
+	}{
+		{name: "no header uses default", want: "ed25519"},
+		{name: "no header uses configured default", algorithm: "ecdsa-p256-sha256", want: "ecdsa-p256-sha256"},
+		{name: "bare algorithm", acceptSignature: "ecdsa-p256-sha256", want: "ecdsa-p256-sha256"},
+		{name: "first supported wins", acceptSignature: "rsa-pss-sha512, ECDSA-P256-SHA256, ed25519", want: "ecdsa-p256-sha256"},
+		{name: "rfc 9421 alg parameter", acceptSignature: `sig1=("@method" "content-digest");keyid="k1";alg="ecdsa-p256-sha256"`, want: "ecdsa-p256-sha256"},
+		{name: "unsupported falls back", acceptSignature: "rsa-pss-sha512", want: "ed25519"},
+		{name: "entry without alg falls back", acceptSignature: `sig1=("@method");keyid="k1"`, want: "ed25519"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sgn := &multiAlgSigner{algorithms: []string{"ed25519", "ecdsa-p256-sha256"}}
+			step, err := newSignStep(sgn, newEd25519KeyManager(t), SignatureConfig{Algorithm: tt.algorithm}, nil)
+			require.NoError(t, err)
+			r := httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil)
+			if tt.acceptSignature != "" {
+				r.Header.Set(acceptSignatureHeader, tt.acceptSignature)
+			}
+			ctx := newTestStepCtx(r, []byte(`{}`))
+			ctx.SubID = "bap.example.com"
+
+			require.NoError(t, step.Run(ctx))
+
+			assert.Equal(t, tt.want, sgn.used)
+			auth := ctx.Request.Header.Get(model.AuthHeaderSubscriber)
+			assert.Contains(t, auth, `|`+tt.want+`",algorithm="`+tt.want+`"`)
+			assert.Contains(t, auth, `signature="sig-`+tt.want+`"`)
+		})
+	}
+}
+
+func TestSignStepWithoutAlgorithmSupport(t *testing.T) {
+	sgn := &windowSigner{}
+	step, err := newSignStep(sgn, newEd25519KeyManager(t), SignatureConfig{}, nil)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil)
+	r.Header.Set(acceptSignatureHeader, "ecdsa-p256-sha256")
+	ctx := newTestStepCtx(r, []byte(`{}`))
+	ctx.SubID = "bap.example.com"
+
+	require.NoError(t, step.Run(ctx))
+
+	assert.Contains(t, ctx.Request.Header.Get(model.AuthHeaderSubscriber), `|ed25519",algorithm="ed25519"`)
+
+	_, err = newSignStep(sgn, newEd25519KeyManager(t), SignatureConfig{Algorithm: "ecdsa-p256-sha256"}, nil)
+	assert.ErrorContains(t, err, `signature algorithm "ecdsa-p256-sha256" is not supported`)
+}
+
+// fakeKMSSigner is a KeyRefSigner whose private keys stay in its own key store, standing
+// in for an HSM or KMS. It refuses to sign with key material passed in.
+type fakeKMSSigner struct {
+	signer definition.Signer
+	keys   map[string]string
+	refs   []string
+}
+
+func (s *fakeKMSSigner) Sign(context.Context, []byte, string, int64, int64) (string, error) {
+	return "", errors.New("private key material is not accepted")
+}
+
+func (s *fakeKMSSigner) SignWithKeyRef(ctx context.Context, algorithm string, body []byte, keyRef string, createdAt, expiresAt int64) (string, error) {
+	s.refs = append(s.refs, algorithm+":"+keyRef)
+	key, ok := s.keys[keyRef]
+	if !ok {
+		return "", errors.New("unknown key reference")
+	}
+	return s.signer.Sign(ctx, body, key, createdAt, expiresAt)
+}
+
+func TestSignStepWithKeyReference(t *testing.T) {
+	ctx := context.Background()
+	inner, _, err := signer.New(ctx, &signer.Config{})
+	require.NoError(t, err)
+	sv, _, err := signvalidator.New(ctx, &signvalidator.Config{})
+	require.NoError(t, err)
+	km := newEd25519KeyManager(t)
+	kms := &fakeKMSSigner{signer: inner, keys: map[string]string{"projects/p/keys/k1": km.keyset.SigningPrivate}}
+	// The key manager only hands out the reference; the private key stays in the KMS.
+	km.keyset.SigningKeyRef = "projects/p/keys/k1"
+	km.keyset.SigningPrivate = ""
+	body := []byte(`{"context":{"action":"search"}}`)
+
+	step, err := newSignStep(kms, km, SignatureConfig{}, nil)
+	require.NoError(t, err)
+	sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), body)
+	sctx.SubID = "bap.example.com"
+	require.NoError(t, step.Run(sctx))
+
+	assert.Equal(t, []string{"ed25519:projects/p/keys/k1"}, kms.refs)
+	validate, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{}, nil, nil)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+	req.Header.Set(model.AuthHeaderSubscriber, sctx.Request.Header.Get(model.AuthHeaderSubscriber))
+	assert.NoError(t, validate.Run(newTestStepCtx(req, body)), "the remote signature verifies against the public key")
+}
+
+func TestSignStepKeyReferenceWithoutKMSSigner(t *testing.T) {
+	km := newEd25519KeyManager(t)
+	km.keyset.SigningKeyRef = "projects/p/keys/k1"
+	km.keyset.SigningPrivate = ""
+	step, err := newSignStep(&windowSigner{}, km, SignatureConfig{}, nil)
+	require.NoError(t, err)
+	sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(`{}`))
+	sctx.SubID = "bap.example.com"
+
+	err = step.Run(sctx)
+
+	assert.ErrorIs(t, err, errNoKeyRefSigner)
+	assert.Empty(t, sctx.Request.Header.Get(model.AuthHeaderSubscriber))
+}
+
+// keyTypeValidator is an AlgorithmValidator accepting signatures checked against a key of
+// the form "<algorithm>-key", failing for keys of another algorithm.
+type keyTypeValidator struct {
+	used string
+}
+
+func (v *keyTypeValidator) Validate(ctx context.Context, body []byte, header, key string) error {
+	return v.ValidateWithAlgorithm(ctx, "ed25519", body, header, key)
+}
+
+func (v *keyTypeValidator) Algorithms() []string {
+	return []string{"ed25519", "ecdsa-p256-sha256"}
+}
+
+func (v *keyTypeValidator) ValidateWithAlgorithm(_ context.Context, algorithm string, _ []byte, _, key string) error {
+	v.used = algorithm
+	if key != algorithm+"-key" {
+		return fmt.Errorf("public key is not an %s key", algorithm)
+	}
+	return nil
+}
+
+func TestValidateSignStepAlgorithm(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator definition.SignValidator
+		algorithm string
+		key       string
+		wantUsed  string
+		wantErr   string
+	}{
+		{name: "ed25519", validator: &keyTypeValidator{}, algorithm: "ed25519", key: "ed25519-key", wantUsed: "ed25519"},
+		{name: "ecdsa", validator: &keyTypeValidator{}, algorithm: "ECDSA-P256-SHA256", key: "ecdsa-p256-sha256-key", wantUsed: "ecdsa-p256-sha256"},
+		{name: "no algorithm in keyId", validator: &keyTypeValidator{}, key: "ed25519-key", wantUsed: "ed25519"},
+		{
+			name:      "ed25519 signature with ecdsa key",
+			validator: &keyTypeValidator{},
+			algorithm: "ed25519",
+			key:       "ecdsa-p256-sha256-key",
+			wantUsed:  "ed25519",
+			wantErr:   "sign validation failed: public key is not an ed25519 key",
+		},
+		{
+			name:      "ecdsa signature with ed25519 key",
+			validator: &keyTypeValidator{},
+			algorithm: "ecdsa-p256-sha256",
+			key:       "ed25519-key",
+			wantUsed:  "ecdsa-p256-sha256",
+			wantErr:   "sign validation failed: public key is not an ecdsa-p256-sha256 key",
+		},
+		{
+			name:      "unsupported algorithm",
+			validator: &keyTypeValidator{},
+			algorithm: "rsa-pss-sha512",
+			key:       "rsa-pss-sha512-key",
+			wantErr:   `signature algorithm "rsa-pss-sha512" is not supported by the SignValidator plugin`,
+		},
+		{
+			name:      "validator without algorithm support",
+			validator: &keySignValidator{valid: "good"},
+			algorithm: "ecdsa-p256-sha256",
+			key:       "good",
+			wantErr:   `signature algorithm "ecdsa-p256-sha256" is not supported by the SignValidator plugin`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km := &mapKeyManager{keys: map[string]string{"bpp.example.com": tt.key}}
+			step, err := newValidateSignStep(tt.validator, km, DefaultHeaderValidationCookie, SignatureConfig{}, nil, nil)
+			require.NoError(t, err)
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Header.Set(model.AuthHeaderSubscriber, `Signature keyId="bpp.example.com|k1|`+tt.algorithm+`",signature="sig"`)
+
+			err = step.Run(newTestStepCtx(r, []byte(`{}`)))
+
+			if v, ok := tt.validator.(*keyTypeValidator); ok {
+				assert.Equal(t, tt.wantUsed, v.used)
+			}
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var signErr *model.SignValidationErr
+			require.ErrorAs(t, err, &signErr)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+const (
+	// defaultSignFailureWindow is used when SignFailureConfig.Window is unset.
+	defaultSignFailureWindow = time.Minute
+	// defaultSignFailureBlockFor is used when SignFailureConfig.BlockFor is unset.
+	defaultSignFailureBlockFor = 5 * time.Minute
+	// maxTrackedSigners bounds the signers tracked before idle ones are pruned, as
+	// subscriber IDs in failing requests are chosen by the caller.
+	maxTrackedSigners = 10000
+)
+
+// signFailureTracker counts signature validation failures per subscriber and source
+// address over a window and blocks the pairs whose failures reach a threshold. The
+// subscriber ID of a failing signature is unauthenticated, so failures are not counted
+// per subscriber alone: that would let anyone block a subscriber by sending forged
+// signatures under its ID.
+type signFailureTracker struct {
+	window    time.Duration
+	threshold int
+	blockFor  time.Duration
+	clock     Clock
+	metrics   *HandlerMetrics
+
+	mu      sync.Mutex
+	signers map[signerSource]*signFailures
+}
+
+// signerSource identifies a subscriber signing from a source address.
+type signerSource struct {
+	subscriberID string
+	source       string
+}
+
+// newSignerSource returns the signerSource of subscriberID signing from remoteAddr, the
+// address of the request's peer.
+func newSignerSource(subscriberID, remoteAddr string) signerSource {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return signerSource{subscriberID: subscriberID, source: host}
+}
+
+// signFailures is the failure record of one subscriber and source.
+type signFailures struct {
+	start        time.Time
+	count        int
+	blockedUntil time.Time
+}
+
+// newSignFailureTracker creates a signFailureTracker from cfg. It returns nil when
+// tracking is disabled.
+func newSignFailureTracker(cfg SignFailureConfig, clock Clock, metrics *HandlerMetrics) (*signFailureTracker, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.Window < 0 || cfg.BlockFor < 0 || cfg.Threshold < 0 {
+		return nil, fmt.Errorf("invalid config: signature failureTracking window, threshold and blockFor must not be negative")
+	}
+	t := &signFailureTracker{
+		window:    cfg.Window,
+		threshold: cfg.Threshold,
+		blockFor:  cfg.BlockFor,
+		clock:     orSystemClock(clock),
+		metrics:   metrics,
+		signers:   make(map[signerSource]*signFailures),
+	}
+	if t.window == 0 {
+		t.window = defaultSignFailureWindow
+	}
+	if t.blockFor == 0 {
+		t.blockFor = defaultSignFailureBlockFor
+	}
+	return t, nil
+}
+
+// blocked reports whether requests of signer are to be rejected without validation.
+func (t *signFailureTracker) blocked(ctx context.Context, signer signerSource) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	f, ok := t.signers[signer]
+	blocked := ok && t.clock.Now().Before(f.blockedUntil)
+	t.mu.Unlock()
+	if blocked {
+		t.count(ctx, signer.subscriberID, "blocked")
+	}
+	return blocked
+}
+
+// record counts a failure of signer, blocking it when the failures in the current
+// window reach the threshold.
+func (t *signFailureTracker) record(ctx context.Context, signer signerSource) {
+	if t == nil {
+		return
+	}
+	t.count(ctx, signer.subscriberID, "failed")
+	now := t.clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f, ok := t.signers[signer]
+	if !ok {
+		if len(t.signers) >= maxTrackedSigners {
+			t.prune(now)
+		}
+		f = &signFailures{}
+		t.signers[signer] = f
+	}
+	if f.count == 0 || now.Sub(f.start) >= t.window {
+		f.start, f.count = now, 0
+	}
+	f.count++
+	if t.threshold > 0 && f.count >= t.threshold {
+		f.blockedUntil = now.Add(t.blockFor)
+		f.count = 0
+		log.Warnf(ctx, "Blocking subscriber %s from %s for %s after %d signature validation failures within %s",
+			signer.subscriberID, signer.source, t.blockFor, t.threshold, t.window)
+	}
+}
+
+// prune drops signers that are neither blocked nor within a failure window.
+func (t *signFailureTracker) prune(now time.Time) {
+	for id, f := range t.signers {
+		if !now.Before(f.blockedUntil) && now.Sub(f.start) >= t.window {
+			delete(t.signers, id)
+		}
+	}
+}
+
+// count adds a failure of the given outcome to the per-subscriber metric.
+func (t *signFailureTracker) count(ctx context.Context, subscriberID, outcome string) {
+	if t.metrics == nil {
+		return
+	}
+	t.metrics.SignatureFailuresTotal.Add(ctx, 1, metric.WithAttributes(
+		telemetry.AttrSubscriberID.String(subscriberID),
+		telemetry.AttrOutcome.String(outcome),
+	))
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+func TestValidateSignStepFailureTracking(t *testing.T) {
+	type call struct {
+		advance   time.Duration
+		valid     bool
+		wantErr   string
+		wantBlock bool
+	}
+	tests := []struct {
+		name  string
+		cfg   SignFailureConfig
+		calls []call
+	}{
+		{
+			name: "threshold trips block until it expires",
+			cfg:  SignFailureConfig{Enabled: true, Threshold: 3},
+			calls: []call{
+				{wantErr: "sign validation failed"},
+				{wantErr: "sign validation failed"},
+				{wantErr: "sign validation failed"},
+				{valid: true, wantBlock: true},
+				{advance: 4 * time.Minute, valid: true, wantBlock: true},
+				{advance: time.Minute, valid: true},
+			},
+		},
+		{
+			name: "window reset",
+			cfg:  SignFailureConfig{Enabled: true, Threshold: 3, Window: 30 * time.Second},
+			calls: []call{
+				{wantErr: "sign validation failed"},
+				{advance: 20 * time.Second, wantErr: "sign validation failed"},
+				{advance: 10 * time.Second, wantErr: "sign validation failed"},
+				{advance: 10 * time.Second, wantErr: "sign validation failed"},
+				{valid: true},
+				{wantErr: "sign validation failed"},
+				{valid: true, wantBlock: true},
+			},
+		},
+		{
+			name: "zero threshold only counts",
+			cfg:  SignFailureConfig{Enabled: true},
+			calls: []call{
+				{wantErr: "sign validation failed"},
+				{wantErr: "sign validation failed"},
+				{wantErr: "sign validation failed"},
+				{valid: true},
+			},
+		},
+		{
+			name: "disabled",
+			cfg:  SignFailureConfig{Threshold: 1},
+			calls: []call{
+				{wantErr: "sign validation failed"},
+				{valid: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+			sv := &keySignValidator{}
+			km := &mapKeyManager{keys: map[string]string{"bpp.example.com": "good"}}
+			step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{FailureTracking: tt.cfg}, clock, nil)
+			require.NoError(t, err)
+			metrics, reader := newTestHandlerMetrics(t)
+			step.(*validateSignStep).metrics = metrics
+			if f := step.(*validateSignStep).failures; f != nil {
+				f.metrics = metrics
+			}
+
+			failed, blocked := 0, 0
+			for i, c := range tt.calls {
+				clock.now = clock.now.Add(c.advance)
+				sv.valid = "bad"
+				if c.valid {
+					sv.valid = "good"
+				}
+				r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+				r.Header.Set(model.AuthHeaderSubscriber, `Signature keyId="bpp.example.com|k1|ed25519",signature="sig"`)
+				err := step.Run(newTestStepCtx(r, []byte(`{}`)))
+
+				switch {
+				case c.wantBlock:
+					blocked++
+					var signErr *model.SignValidationErr
+					require.ErrorAs(t, err, &signErr, "call %d", i)
+					assert.ErrorContains(t, err, "subscriber bpp.example.com is temporarily blocked from 192.0.2.1", "call %d", i)
+				case c.wantErr != "":
+					failed++
+					assert.ErrorContains(t, err, c.wantErr, "call %d", i)
+				default:
+					assert.NoError(t, err, "call %d", i)
+				}
+			}
+			if !tt.cfg.Enabled {
+				return
+			}
+			assert.Equal(t, int64(failed), counterValue(t, reader, "onix_signature_failures_total",
+				telemetry.AttrSubscriberID.String("bpp.example.com"), telemetry.AttrOutcome.String("failed")))
+			if blocked > 0 {
+				assert.Equal(t, int64(blocked), counterValue(t, reader, "onix_signature_failures_total",
+					telemetry.AttrSubscriberID.String("bpp.example.com"), telemetry.AttrOutcome.String("blocked")))
+			}
+		})
+	}
+}
+
+func TestValidateSignStepForgedFailuresDoNotBlockOtherSources(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	sv := &keySignValidator{}
+	km := &mapKeyManager{keys: map[string]string{"bpp.example.com": "good"}}
+	step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie,
+		SignatureConfig{FailureTracking: SignFailureConfig{Enabled: true, Threshold: 2}}, clock, nil)
+	require.NoError(t, err)
+
+	run := func(remoteAddr, signature string) error {
+		sv.valid = signature
+		r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set(model.AuthHeaderSubscriber, `Signature keyId="bpp.example.com|k1|ed25519",signature="sig"`)
+		return step.Run(newTestStepCtx(r, []byte(`{}`)))
+	}
+
+	// An attacker forges signatures under the subscriber's ID until it is blocked.
+	for i := 0; i < 2; i++ {
+		assert.ErrorContains(t, run("203.0.113.9:4000", "bad"), "sign validation failed")
+	}
+	assert.ErrorContains(t, run("203.0.113.9:4001", "good"), "temporarily blocked from 203.0.113.9")
+
+	// The real subscriber, signing from its own address, is still validated.
+	assert.NoError(t, run("198.51.100.7:5000", "good"))
+}
+
+func TestNewSignFailureTracker(t *testing.T) {
+	tracker, err := newSignFailureTracker(SignFailureConfig{}, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tracker)
+
+	_, err = newSignFailureTracker(SignFailureConfig{Enabled: true, Threshold: -1}, nil, nil)
+	assert.ErrorContains(t, err, "must not be negative")
+
+	tracker, err = newSignFailureTracker(SignFailureConfig{Enabled: true}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultSignFailureWindow, tracker.window)
+	assert.Equal(t, defaultSignFailureBlockFor, tracker.blockFor)
+}
+
+func TestSignFailureTrackerPrunesIdleSigners(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	tracker, err := newSignFailureTracker(SignFailureConfig{Enabled: true, Threshold: 1}, clock, nil)
+	require.NoError(t, err)
+	ctx := t.Context()
+	blocked := newSignerSource("blocked.example.com", "192.0.2.1:1234")
+	tracker.record(ctx, blocked)
+	for i := 0; i < maxTrackedSigners-1; i++ {
+		tracker.signers[signerSource{subscriberID: string(rune(i))}] = &signFailures{start: clock.now, count: 1}
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	tracker.record(ctx, newSignerSource("new.example.com", "192.0.2.1:1234"))
+
+	assert.Len(t, tracker.signers, 2, "idle subscribers are pruned")
+	assert.True(t, tracker.blocked(ctx, blocked), "blocked subscribers are kept")
+}
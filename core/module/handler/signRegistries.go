@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// defaultRegistryName identifies the module's own Registry in logs and metrics.
+const defaultRegistryName = "default"
+
+// registryKeys is a KeyManager looking up keys in a named registry.
+type registryKeys struct {
+	registry string
+	km       definition.KeyManager
+}
+
+// loadSignRegistries loads the registries tried by validateSign after the module's own,
+// each with a KeyManager created from kmCfg.
+func loadSignRegistries(ctx context.Context, mgr PluginManager, cache definition.Cache, kmCfg *plugin.Config, cfgs []SignRegistryConfig) ([]registryKeys, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+	if kmCfg == nil {
+		return nil, fmt.Errorf("signValidationRegistries require the KeyManager plugin")
+	}
+	seen := map[string]bool{defaultRegistryName: true}
+	keys := make([]registryKeys, 0, len(cfgs))
+	for i, c := range cfgs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("sign validation registry %d: name is required", i)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("sign validation registry %d: duplicate name %q", i, c.Name)
+		}
+		seen[c.Name] = true
+		registry, err := loadPlugin(ctx, "Registry", &c.Registry, mgr.Registry)
+		if err != nil {
+			return nil, fmt.Errorf("sign validation registry %s: %w", c.Name, err)
+		}
+		km, err := loadKeyManager(ctx, mgr, cache, registry, kmCfg)
+		if err != nil {
+			return nil, fmt.Errorf("sign validation registry %s: %w", c.Name, err)
+		}
+		keys = append(keys, registryKeys{registry: c.Name, km: km})
+	}
+	return keys, nil
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// mapKeyManager is a KeyManager serving signing keys from a map keyed by subscriber ID.
+type mapKeyManager struct {
+	definition.KeyManager
+	keys map[string]string
+}
+
+func (m *mapKeyManager) LookupNPKeys(_ context.Context, subscriberID, _ string) (string, string, error) {
+	key, ok := m.keys[subscriberID]
+	if !ok {
+		return "", "", errors.New("subscriber not found")
+	}
+	return key, "", nil
+}
+
+// keySignValidator accepts signatures checked against its valid key.
+type keySignValidator struct {
+	valid string
+}
+
+func (v *keySignValidator) Validate(_ context.Context, _ []byte, _ string, key string) error {
+	if key != v.valid {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func TestValidateSignStepTriesRegistriesInOrder(t *testing.T) {
+	const header = `Signature keyId="bpp.example.com|k1|ed25519",signature="sig"`
+	tests := []struct {
+		name         string
+		primary      map[string]string
+		fallbacks    []map[string]string
+		wantRegistry string
+		wantErr      []string
+	}{
+		{
+			name:         "key in default registry",
+			primary:      map[string]string{"bpp.example.com": "good"},
+			fallbacks:    []map[string]string{{"bpp.example.com": "good"}},
+			wantRegistry: defaultRegistryName,
+		},
+		{
+			name:         "key only in second registry",
+			primary:      map[string]string{},
+			fallbacks:    []map[string]string{{"bpp.example.com": "good"}},
+			wantRegistry: "registry-1",
+		},
+		{
+			name:         "stale key in default registry",
+			primary:      map[string]string{"bpp.example.com": "rotated"},
+			fallbacks:    []map[string]string{{}, {"bpp.example.com": "good"}},
+			wantRegistry: "registry-2",
+		},
+		{
+			name:      "key in no registry",
+			primary:   map[string]string{"bpp.example.com": "rotated"},
+			fallbacks: []map[string]string{{}},
+			wantErr:   []string{"registry default: sign validation failed", "registry registry-1: failed to get validation key"},
+		},
+		{
+			name:    "single registry",
+			primary: map[string]string{},
+			wantErr: []string{"failed to validate Authorization: failed to get validation key: subscriber not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fallbacks []registryKeys
+			for i, keys := range tt.fallbacks {
+				fallbacks = append(fallbacks, registryKeys{
+					registry: "registry-" + string(rune('1'+i)),
+					km:       &mapKeyManager{keys: keys},
+				})
+			}
+			step, err := newValidateSignStep(&keySignValidator{valid: "good"}, &mapKeyManager{keys: tt.primary}, DefaultHeaderValidationCookie, SignatureConfig{}, nil, nil, fallbacks...)
+			require.NoError(t, err)
+			metrics, reader := newTestHandlerMetrics(t)
+			step.(*validateSignStep).metrics = metrics
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Header.Set(model.AuthHeaderSubscriber, header)
+			err = step.Run(newTestStepCtx(r, []byte(`{}`)))
+
+			if len(tt.wantErr) > 0 {
+				var signErr *model.SignValidationErr
+				require.ErrorAs(t, err, &signErr)
+				for _, want := range tt.wantErr {
+					assert.ErrorContains(t, err, want)
+				}
+				assert.Equal(t, int64(1), counterValue(t, reader, "beckn_signature_validations_total",
+					telemetry.AttrStatus.String("failed")))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), counterValue(t, reader, "beckn_signature_validations_total",
+				telemetry.AttrStatus.String("success"), telemetry.AttrRegistry.String(tt.wantRegistry)))
+		})
+	}
+}
+
+// emptyCache is a Cache that is never used, satisfying loadKeyManager's requirement.
+type emptyCache struct {
+	definition.Cache
+}
+
+func TestLoadSignRegistries(t *testing.T) {
+	kmCfg := &plugin.Config{ID: "keymanager"}
+	tests := []struct {
+		name    string
+		kmCfg   *plugin.Config
+		cfgs    []SignRegistryConfig
+		want    []string
+		wantErr string
+	}{
+		{name: "none configured", kmCfg: kmCfg},
+		{
+			name:  "named registries",
+			kmCfg: kmCfg,
+			cfgs:  []SignRegistryConfig{{Name: "staging"}, {Name: "preprod"}},
+			want:  []string{"staging", "preprod"},
+		},
+		{
+			name:    "without key manager",
+			cfgs:    []SignRegistryConfig{{Name: "staging"}},
+			wantErr: "require the KeyManager plugin",
+		},
+		{
+			name:    "missing name",
+			kmCfg:   kmCfg,
+			cfgs:    []SignRegistryConfig{{}},
+			wantErr: "name is required",
+		},
+		{
+			name:    "duplicate name",
+			kmCfg:   kmCfg,
+			cfgs:    []SignRegistryConfig{{Name: "staging"}, {Name: "staging"}},
+			wantErr: `duplicate name "staging"`,
+		},
+		{
+			name:    "reserved name",
+			kmCfg:   kmCfg,
+			cfgs:    []SignRegistryConfig{{Name: defaultRegistryName}},
+			wantErr: "duplicate name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := loadSignRegistries(context.Background(), &stubPluginManager{}, &emptyCache{}, tt.kmCfg, tt.cfgs)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, k := range keys {
+				names = append(names, k.registry)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// signedPart selects the sub-document of a JSON body that is signed. A nil signedPart
+// selects the whole body.
+type signedPart []string
+
+// newSignedPart parses a selector such as "message" or "message.order" into a
+// signedPart. Selectors use the syntax of redaction selectors without wildcards. It
+// returns nil for an empty selector.
+func newSignedPart(selector string) (signedPart, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	path, err := parseSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature path %q: %w", selector, err)
+	}
+	for _, seg := range path {
+		if seg == wildcard {
+			return nil, fmt.Errorf("invalid signature path %q: wildcards are not allowed", selector)
+		}
+	}
+	return signedPart(path), nil
+}
+
+// extract returns the selected sub-document exactly as it appears in body, so that a
+// signature over it does not depend on how the rest of the body is serialized.
+func (p signedPart) extract(body []byte) ([]byte, error) {
+	raw := json.RawMessage(body)
+	for i, seg := range p {
+		if idx, err := strconv.Atoi(seg); err == nil && bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			var arr []json.RawMessage
+			if err := json.Unmarshal(raw, &arr); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", p.name(i), err)
+			}
+			if idx >= len(arr) {
+				return nil, fmt.Errorf("%s not found in body", p.name(i+1))
+			}
+			raw = arr[idx]
+			continue
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", p.name(i), err)
+		}
+		child, ok := obj[seg]
+		if !ok {
+			return nil, fmt.Errorf("%s not found in body", p.name(i+1))
+		}
+		raw = child
+	}
+	return raw, nil
+}
+
+// name returns the selector of the first n segments of p, for error messages.
+func (p signedPart) name(n int) string {
+	if n == 0 {
+		return "body"
+	}
+	var b strings.Builder
+	for i, seg := range p[:n] {
+		if _, err := strconv.Atoi(seg); err == nil {
+			b.WriteString("[" + seg + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(seg)
+	}
+	return b.String()
+}
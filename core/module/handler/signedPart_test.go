@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signer"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/signvalidator"
+)
+
+func TestSignedPartExtract(t *testing.T) {
+	const body = `{"context": {"action": "confirm"}, "message": {"order": {"id": "o1", "items": [{"id": "i1"}, {"id": "i2"}]}}}`
+	tests := []struct {
+		name     string
+		selector string
+		want     string
+		wantErr  string
+	}{
+		{name: "whole body", want: body},
+		{name: "object", selector: "message", want: `{"order": {"id": "o1", "items": [{"id": "i1"}, {"id": "i2"}]}}`},
+		{name: "nested object", selector: "$.message.order.items", want: `[{"id": "i1"}, {"id": "i2"}]`},
+		{name: "array element", selector: "message.order.items[1]", want: `{"id": "i2"}`},
+		{name: "missing key", selector: "message.catalog", wantErr: "message.catalog not found in body"},
+		{name: "index out of range", selector: "message.order.items[2]", wantErr: "message.order.items[2] not found in body"},
+		{name: "not an object", selector: "context.action.name", wantErr: "failed to parse context.action"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			part, err := newSignedPart(tt.selector)
+			require.NoError(t, err)
+			got, err := part.extract([]byte(body))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestNewSignedPartInvalid(t *testing.T) {
+	_, err := newSignedPart("message.order.items[*]")
+	assert.ErrorContains(t, err, "wildcards are not allowed")
+	_, err = newSignedPart("message..order")
+	assert.ErrorContains(t, err, `invalid signature path "message..order"`)
+}
+
+func TestSignatureOverSignedPart(t *testing.T) {
+	const (
+		body       = `{"context": {"action": "confirm", "ttl": "PT30S"}, "message": {"order": {"id": "o1"}}}`
+		newContext = `{"context": {"action": "confirm", "ttl": "PT10S"}, "message": {"order": {"id": "o1"}}}`
+		tampered   = `{"context": {"action": "confirm", "ttl": "PT30S"}, "message": {"order": {"id": "o2"}}}`
+		noMessage  = `{"context": {"action": "confirm"}}`
+	)
+	ctx := context.Background()
+	sgn, _, err := signer.New(ctx, &signer.Config{})
+	require.NoError(t, err)
+	sv, _, err := signvalidator.New(ctx, &signvalidator.Config{})
+	require.NoError(t, err)
+	km := newEd25519KeyManager(t)
+
+	sign := func(t *testing.T, path, body string) (string, string, error) {
+		t.Helper()
+		step, err := newSignStep(sgn, km, SignatureConfig{Path: path}, nil)
+		require.NoError(t, err)
+		sctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/confirm", nil), []byte(body))
+		sctx.SubID = "bap.example.com"
+		err = step.Run(sctx)
+		return string(sctx.Body), sctx.Request.Header.Get(model.AuthHeaderSubscriber), err
+	}
+	validate := func(t *testing.T, path, body, auth string) error {
+		t.Helper()
+		step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{Path: path}, nil, nil)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/bpp/receiver/confirm", nil)
+		req.Header.Set(model.AuthHeaderSubscriber, auth)
+		return step.Run(newTestStepCtx(req, []byte(body)))
+	}
+
+	t.Run("full body", func(t *testing.T) {
+		signed, auth, err := sign(t, "", body)
+		require.NoError(t, err)
+		assert.Equal(t, body, signed)
+		assert.NoError(t, validate(t, "", body, auth))
+		assert.Error(t, validate(t, "", newContext, auth), "the whole body is covered")
+		assert.Error(t, validate(t, "message", body, auth), "a full-body signature does not cover the sub-document alone")
+	})
+
+	t.Run("sub-document", func(t *testing.T) {
+		signed, auth, err := sign(t, "message", body)
+		require.NoError(t, err)
+		assert.Equal(t, body, signed, "the forwarded body is left intact")
+		assert.NoError(t, validate(t, "message", body, auth))
+		assert.NoError(t, validate(t, "message", newContext, auth), "fields outside the sub-document are not covered")
+		assert.Error(t, validate(t, "message", tampered, auth))
+		assert.Error(t, validate(t, "", body, auth), "a sub-document signature does not cover the full body")
+	})
+
+	t.Run("missing sub-document", func(t *testing.T) {
+		_, _, err := sign(t, "message", noMessage)
+		var badReq *model.BadReqErr
+		assert.ErrorAs(t, err, &badReq)
+
+		_, auth, err := sign(t, "", noMessage)
+		require.NoError(t, err)
+		err = validate(t, "message", noMessage, auth)
+		var signErr *model.SignValidationErr
+		require.ErrorAs(t, err, &signErr)
+		assert.ErrorContains(t, err, "message not found in body")
+	})
+}
@@ -1,14 +1,17 @@
 package handler
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"runtime/debug"
+	"sync"
+	"time"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/model"
@@ -25,6 +28,7 @@ type stdHandler struct {
 	cache            definition.Cache
 	registry         definition.RegistryLookup
 	km               definition.KeyManager
+	signRegistries   []registryKeys
 	schemaValidator  definition.SchemaValidator
 	router           definition.Router
 	publisher        definition.Publisher
@@ -35,6 +39,72 @@ type stdHandler struct {
 	role             model.Role
 	httpClient       *http.Client
 	moduleName       string
+	pubSelector      *publisherSelector
+	pubRetrier       *publishRetrier
+	metrics          *HandlerMetrics
+	pluginCfg        PluginCfg
+	bodySampler      *bodySampler
+	redactor         *bodyRedactor
+	// warmedUp is closed once warm-up completes; nil when no warm-up is configured.
+	warmedUp   <-chan struct{}
+	bodyBuffer BodyBufferConfig
+	// keepEncoding keeps Content-Encoding when a step modifies the body.
+	keepEncoding bool
+	deadline     RequestDeadlineConfig
+	// headerLimits bounds the headers of incoming requests.
+	headerLimits HeaderLimitsConfig
+	// trusted reads per-request subscriber ID and role overrides; nil when not configured.
+	trusted *trustedHeaders
+	// pathRoles selects the role and subscriber by path prefix; nil when not configured.
+	pathRoles *pathRoles
+	// limiter caps concurrent requests; nil when unlimited.
+	limiter *concurrencyLimiter
+	// shadow receives a copy of routed requests; nil when mirroring is off.
+	shadow *shadowTarget
+	// maintenance answers all traffic with a fixed NACK while it is on.
+	maintenance *maintenanceMode
+	// instrumentHeaders names the internal module name and role headers.
+	instrumentHeaders InstrumentationHeadersConfig
+	// errorDetail hides NACK error detail from untrusted callers; nil when unrestricted.
+	errorDetail *errorDetail
+	// multipart sets how multipart/form-data requests are processed.
+	multipart MultipartConfig
+	// decoders decode request bodies in enabled non-JSON formats, by media type.
+	decoders map[string]BodyDecoder
+	// respCache serves repeat requests of cacheable actions; nil when caching is off.
+	respCache *responseCache
+	// noRoute answers requests that no step routed; nil acknowledges them.
+	noRoute *noRoute
+	// respValidator validates proxied responses; nil when response validation is off.
+	respValidator *responseValidator
+	// respSigner signs proxied responses; nil when response signing is off.
+	respSigner *responseSigner
+	// boundedPub publishes in proxy mode without blocking on a full queue; nil when off.
+	boundedPub *boundedPublisher
+	// pubQueue publishes in proxy mode from background workers; nil when off.
+	pubQueue *publishQueue
+	// proxyRetry retries proxied requests on retryable statuses; nil when off.
+	proxyRetry *proxyRetrier
+	// fwdTimeouts bounds requests forwarded to URL targets; nil when off.
+	fwdTimeouts *forwardTimeouts
+	// streamer streams proxied responses to the client; nil when streaming is off.
+	streamer *proxyStreamer
+	// subAccess holds the subscriber allow and deny lists; nil when the
+	// checkSubscriberAccess step is not configured.
+	subAccess *subscriberAccess
+	// auditor records every request to the Auditor plugin; nil when not configured.
+	auditor *auditor
+	// clock tells the time to time-dependent steps.
+	clock Clock
+	// resolvedCfg is the handler's config with defaults filled in, as reported by
+	// EffectiveConfig.
+	resolvedCfg Config
+
+	// drainMu guards draining; inflight counts requests whose work, including
+	// post-response hooks, has not finished.
+	drainMu  sync.RWMutex
+	draining bool
+	inflight sync.WaitGroup
 }
 
 // newHTTPClient creates a new HTTP client with a custom transport configuration.
@@ -67,87 +137,352 @@ func newHTTPClient(cfg *HttpClientConfig, wrapper definition.TransportWrapper) *
 
 // NewStdHandler initializes a new processor with plugins and steps.
 func NewStdHandler(ctx context.Context, mgr PluginManager, cfg *Config, moduleName string) (http.Handler, error) {
+	pubSelector, err := newPublisherSelector(cfg.PublisherRules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publisher rules: %w", err)
+	}
+	metrics, _ := GetHandlerMetrics(ctx)
 	h := &stdHandler{
-		steps:        []definition.Step{},
-		SubscriberID: cfg.SubscriberID,
-		role:         cfg.Role,
-		moduleName:   moduleName,
+		steps:             []definition.Step{},
+		SubscriberID:      cfg.SubscriberID,
+		role:              cfg.Role,
+		moduleName:        moduleName,
+		pubSelector:       pubSelector,
+		metrics:           metrics,
+		pluginCfg:         cfg.Plugins,
+		bodySampler:       newBodySampler(cfg.BodyLogging.SampleRate),
+		bodyBuffer:        cfg.BodyBuffer,
+		keepEncoding:      cfg.PreserveContentEncoding,
+		deadline:          cfg.RequestDeadline,
+		headerLimits:      cfg.HeaderLimits,
+		limiter:           newConcurrencyLimiter(cfg.Concurrency),
+		maintenance:       newMaintenanceMode(cfg.Maintenance),
+		instrumentHeaders: cfg.InstrumentationHeaders,
+		multipart:         cfg.Multipart,
+		clock:             SystemClock{},
+		resolvedCfg:       withDefaults(*cfg),
+	}
+	if h.trusted, err = newTrustedHeaders(cfg.TrustedHeaders); err != nil {
+		return nil, fmt.Errorf("invalid trusted headers: %w", err)
+	}
+	if h.pathRoles, err = newPathRoles(cfg.PathRoles); err != nil {
+		return nil, err
+	}
+	if h.shadow, err = newShadowTarget(cfg.Shadow); err != nil {
+		return nil, err
+	}
+	if h.redactor, err = newBodyRedactor(cfg.BodyLogging.Redact); err != nil {
+		return nil, err
+	}
+	if h.errorDetail, err = newErrorDetail(cfg.ErrorDetail); err != nil {
+		return nil, fmt.Errorf("invalid error detail config: %w", err)
+	}
+	if h.noRoute, err = newNoRoute(cfg.NoRoute); err != nil {
+		return nil, err
+	}
+	if h.decoders, err = newBodyDecoders(cfg.BodyFormats); err != nil {
+		return nil, err
 	}
 	// Initialize plugins.
 	if err := h.initPlugins(ctx, mgr, &cfg.Plugins); err != nil {
 		return nil, fmt.Errorf("failed to initialize plugins: %w", err)
 	}
+	if h.respCache, err = newResponseCache(cfg.ResponseCache, h.cache, moduleName, cfg.Steps); err != nil {
+		return nil, err
+	}
+	if h.respValidator, err = newResponseValidator(ctx, mgr, cfg.ResponseValidation, &cfg.Plugins, h.schemaValidator); err != nil {
+		return nil, fmt.Errorf("failed to initialize response validation: %w", err)
+	}
+	if h.respSigner, err = newResponseSigner(h.signer, h.km, cfg.ResponseSigning, h.clock); err != nil {
+		return nil, fmt.Errorf("failed to initialize response signing: %w", err)
+	}
+	if err := h.selfTestPlugins(ctx); err != nil {
+		return nil, fmt.Errorf("plugin self-test failed: %w", err)
+	}
+	if h.publisher != nil {
+		h.pubRetrier = newPublishRetrier(h.publisher, &cfg.PublishRetry)
+	}
+	if h.boundedPub, err = newBoundedPublisher(h.publisher, cfg.PublishBackpressure); err != nil {
+		return nil, err
+	}
+	if h.pubQueue, err = newPublishQueue(h.pubRetrier, cfg.PublishQueue, cfg.PublishBackpressure); err != nil {
+		return nil, err
+	}
+	if h.proxyRetry, err = newProxyRetrier(cfg.ProxyRetry); err != nil {
+		return nil, err
+	}
+	if h.fwdTimeouts, err = newForwardTimeouts(cfg.ForwardTimeouts); err != nil {
+		return nil, err
+	}
+	if h.streamer, err = newProxyStreamer(cfg.ProxyStreaming, cfg.ResponseValidation, cfg.ResponseSigning); err != nil {
+		return nil, err
+	}
 	// Initialize HTTP client after plugins so transport wrapper can be applied.
 	h.httpClient = newHTTPClient(&cfg.HttpClientConfig, h.transportWrapper)
 	// Initialize steps.
 	if err := h.initSteps(ctx, mgr, cfg); err != nil {
 		return nil, fmt.Errorf("failed to initialize steps: %w", err)
 	}
+	h.startWarmUp(ctx, &cfg.WarmUp)
 	return h, nil
 }
 
-// ServeHTTP processes an incoming HTTP request and executes defined processing steps.
+// ServeHTTP processes an incoming HTTP request, recording request-level metrics and
+// the access log around serve.
 func (h *stdHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.warmingUp() {
+		http.Error(w, errWarmingUp.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if !h.beginRequest() {
+		http.Error(w, errShuttingDown.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	start := time.Now()
+	r, audit := h.auditor.begin(r)
+	ow := &outcomeWriter{ResponseWriter: w}
+	var done func(outcome string)
+	if h.metrics != nil {
+		done = h.trackRequest(r.Context())
+	}
 
-	r.Header.Set("X-Module-Name", h.moduleName)
-	r.Header.Set("X-Role", string(h.role))
+	var entry log.AccessEntry
+	logAccess := func() { accessLogFunc(r.Context(), entry) }
+	hooked := RegisterPostResponseHook(r, logAccess)
 
-	// These headers are only needed for internal instrumentation; avoid leaking them downstream.
-	// Use defer to ensure cleanup regardless of return path.
+	var subID string
+	var aborted bool
+	if h.limiter.acquire() {
+		subID, aborted = h.serveRecovered(ow, r)
+		h.limiter.release()
+	} else {
+		h.limiter.reject(ow, r)
+		subID = h.subID(r.Context())
+	}
+
+	entry = log.AccessEntry{
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		Status:       ow.statusCode(),
+		Duration:     time.Since(start),
+		SubscriberID: subID,
+		Outcome:      ow.outcome(),
+	}
+	if done != nil {
+		done(entry.Outcome)
+	}
+	if !hooked {
+		logAccess()
+	}
+	h.auditor.record(r, audit, start, entry)
+	h.endRequest(r)
+	if aborted {
+		// Let net/http abort the connection, as the handler that panicked intended.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// serveRecovered runs serve, recovering from a panic in a step or the routing code.
+// The panic is logged with its stack and, if nothing was written yet, answered with an
+// internal-server NACK. aborted reports a panic with http.ErrAbortHandler, which the
+// caller re-raises once the request is accounted for.
+func (h *stdHandler) serveRecovered(w *outcomeWriter, r *http.Request) (subID string, aborted bool) {
 	defer func() {
-		r.Header.Del("X-Module-Name")
-		r.Header.Del("X-Role")
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		subID = h.subID(r.Context())
+		if rec == http.ErrAbortHandler {
+			aborted = true
+			return
+		}
+		err := fmt.Errorf("panic while serving request: %v", rec)
+		log.Errorf(r.Context(), err, "Recovered from panic: %v\n%s", rec, debug.Stack())
+		if w.status == 0 {
+			response.SendNack(r.Context(), w, err)
+		}
 	}()
+	return h.serve(w, r), false
+}
+
+var (
+	accessLogFunc       = log.Access
+	requestLogFunc      = log.Request
+	requestErrorLogFunc = log.RequestError
+)
+
+// serve executes the defined processing steps and routes the request.
+// It returns the subscriber ID the request was processed for.
+func (h *stdHandler) serve(w http.ResponseWriter, r *http.Request) string {
+	// ACKs and NACKs are sent in the format the client accepts, JSON by default.
+	r = r.WithContext(response.WithAccept(r.Context(), r.Header.Get("Accept")))
+	if h.maintenance.active() {
+		h.maintenance.respond(r.Context(), w)
+		return h.subID(r.Context())
+	}
+	r = h.errorDetail.scope(r)
+	// Headers are checked as the client sent them, before the adapter adds its own.
+	if err := checkHeaderLimits(r.Header, h.headerLimits); err != nil {
+		log.Warnf(r.Context(), "Rejecting request: %v", err)
+		response.SendNack(r.Context(), w, model.NewBadReqErr(err))
+		return h.subID(r.Context())
+	}
+	// Trusted headers take precedence over the role and subscriber of the path.
+	r = h.pathRoles.apply(r)
+	r, err := h.trusted.apply(r)
+	if err != nil {
+		log.Errorf(r.Context(), err, "Invalid trusted headers")
+		response.SendNack(r.Context(), w, err)
+		return h.subID(r.Context())
+	}
+
+	h.setInstrumentHeaders(r, string(h.requestRole(r.Context())))
+
+	// These headers are only needed for internal instrumentation; avoid leaking them downstream.
+	// Use defer to ensure cleanup regardless of return path.
+	defer h.clearInstrumentHeaders(r)
+
+	r, cancel := h.withRequestDeadline(r)
+	defer cancel()
 
 	ctx, err := h.stepCtx(r, w.Header())
 	if err != nil {
 		log.Errorf(r.Context(), err, "stepCtx(r):%v", err)
 		response.SendNack(r.Context(), w, err)
-		return
+		return h.subID(r.Context())
+	}
+	noteAudit(ctx, nil)
+	if c, ok := ctx.BodyReader.(io.Closer); ok {
+		// Deferred so that the body is released after any async hooks registered below.
+		defer closeAfterResponse(r, c)
+	}
+	ctx.Context = withRedaction(ctx.Context, h.redactor)
+	if h.bodySampler.sample() {
+		ctx.Context = withBodyLogging(ctx.Context)
 	}
-	log.Request(r.Context(), r, ctx.Body)
+	requestLogFunc(ctx, r, loggedBody(ctx, ctx.Body))
 
-	// Execute processing steps.
-	for _, step := range h.steps {
-		if err := step.Run(ctx); err != nil {
-			log.Errorf(ctx, err, "%T.run():%v", step, err)
-			response.SendNack(ctx, w, err)
-			return
+	// Steps reading the body bytes load a spilled body themselves, see withBody.
+	received := ctx.Body
+
+	// Execute processing steps. Repeat requests of cacheable actions are answered from the
+	// cache once the steps authenticating the caller have run, without running the rest.
+	gated := h.respCache.gatedSteps()
+	if !h.runSteps(ctx, r, w, h.steps[:gated]) {
+		return ctx.SubID
+	}
+	if key, ok := h.respCache.key(ctx); ok {
+		if h.respCache.serve(ctx, w, key) {
+			return ctx.SubID
 		}
+		rec := newResponseRecorder(w)
+		defer h.respCache.store(ctx, r, key, rec)
+		w = rec
+	}
+	if !h.runSteps(ctx, r, w, h.steps[gated:]) {
+		return ctx.SubID
 	}
 	// Restore request body before forwarding or publishing.
-	r.Body = io.NopCloser(bytes.NewReader(ctx.Body))
+	body, err := forwardBody(ctx)
+	if err != nil {
+		log.Errorf(ctx, err, "Failed to restore request body")
+		response.SendNack(ctx, w, err)
+		return ctx.SubID
+	}
+	r.Body = io.NopCloser(body)
+	if err := setBodyFraming(r, body); err != nil {
+		log.Errorf(ctx, err, "Failed to frame request body")
+		response.SendNack(ctx, w, err)
+		return ctx.SubID
+	}
+	h.syncBodyHeaders(r, ctx, received)
 	if ctx.Route == nil {
-		response.SendAck(w)
-		return
+		h.noRoute.respond(ctx, w)
+		return ctx.SubID
 	}
 
 	// These headers are only needed for internal instrumentation; avoid leaking them downstream.
-	r.Header.Del("X-Module-Name")
-	r.Header.Del("X-Role")
+	h.clearInstrumentHeaders(r)
+	// Publishers take the body bytes, so a spilled body is loaded first.
+	if ctx.Route.TargetType == "publisher" {
+		if _, err := ctx.BodyBytes(); err != nil {
+			log.Errorf(ctx, err, "Failed to load request body")
+			response.SendNack(ctx, w, err)
+			return ctx.SubID
+		}
+	}
+	h.mirror(ctx, r)
 	// Handle routing based on the defined route type.
-	route(ctx, r, w, h.publisher, h.httpClient)
+	h.route(ctx, r, w)
+	return ctx.SubID
+}
+
+// runSteps runs steps in order, answering the request when one fails or acks it early.
+// It reports whether processing should continue.
+func (h *stdHandler) runSteps(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, steps []definition.Step) bool {
+	for _, step := range steps {
+		if err := step.Run(ctx); err != nil {
+			noteAudit(ctx, err)
+			if errors.Is(err, errAcked) {
+				response.SendAck(ctx, w)
+				return false
+			}
+			log.Errorf(ctx, err, "%T.run():%v", step, err)
+			requestErrorLogFunc(ctx, r, redactedBody(ctx, ctx.Body), err)
+			response.SendNack(ctx, w, err)
+			return false
+		}
+	}
+	return true
 }
 
 // stepCtx creates a new StepContext for processing an HTTP request.
 func (h *stdHandler) stepCtx(r *http.Request, rh http.Header) (*model.StepContext, error) {
-	var bodyBuffer bytes.Buffer
-	if _, err := io.Copy(&bodyBuffer, r.Body); err != nil {
+	body, err := bufferBody(r.Body, &h.bodyBuffer)
+	if err != nil {
 		return nil, model.NewBadReqErr(err)
 	}
 	r.Body.Close()
+	// Empty bodies are rejected here, rather than by whichever step first parses them.
+	if body.empty() {
+		h.recordBodySize(r.Context(), body.size, nil)
+		return nil, model.NewBadReqErr(errEmptyBody)
+	}
 	subID := h.subID(r.Context())
+	mp, err := h.readMultipart(r, body)
+	if err == nil && mp == nil {
+		mp, err = h.decodeBody(r, body)
+	}
+	if err != nil {
+		if body.spill != nil {
+			body.spill.Close()
+		}
+		return nil, model.NewBadReqErr(err)
+	}
+	var bCtx *model.BecknContext
+	if mp != nil && mp.payload != nil {
+		bCtx, err = model.ParseBecknContext(mp.payload)
+	} else {
+		bCtx, err = body.context()
+	}
+	if err != nil {
+		log.Debugf(r.Context(), "Request body has no parsable context: %v", err)
+	}
+	h.recordBodySize(r.Context(), body.size, bCtx)
 	return &model.StepContext{
-		Context:    r.Context(),
-		Request:    r,
-		Body:       bodyBuffer.Bytes(),
-		Role:       h.role,
-		SubID:      subID,
-		RespHeader: rh,
+		Context:      withPayloadBody(r.Context(), mp),
+		Request:      r,
+		Body:         body.mem,
+		Role:         h.requestRole(r.Context()),
+		SubID:        subID,
+		RespHeader:   rh,
+		BecknContext: bCtx,
+		BodyReader:   body.reader(),
 	}, nil
 }
 
-// subID retrieves the subscriber ID from the request context.
+// subID retrieves the subscriber ID from the request context, falling back to the handler's.
 func (h *stdHandler) subID(ctx context.Context) string {
 	rSubID, ok := ctx.Value(model.ContextKeySubscriberID).(string)
 	if ok {
@@ -158,8 +493,17 @@ func (h *stdHandler) subID(ctx context.Context) string {
 
 var proxyFunc = proxy
 
+// publisherID returns the publisher chosen by the router, falling back to the configured
+// publisher rules. An empty ID leaves the choice to the publisher's default.
+func (h *stdHandler) publisherID(ctx *model.StepContext) string {
+	if ctx.Route.PublisherID != "" {
+		return ctx.Route.PublisherID
+	}
+	return h.pubSelector.resolve(ctx.BecknContext)
+}
+
 // route handles request forwarding or message publishing based on the routing type.
-func route(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, pb definition.Publisher, httpClient *http.Client) {
+func (h *stdHandler) route(ctx *model.StepContext, r *http.Request, w http.ResponseWriter) {
 	log.Debugf(ctx, "Routing to ctx.Route to %#v", ctx.Route)
 
 	if ctx.Route.ActAsProxy {
@@ -167,22 +511,62 @@ func route(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, pb de
 		switch ctx.Route.TargetType {
 		case "url":
 			log.Infof(ctx.Context, "Forwarding request to URL: %s", ctx.Route.URL)
-			proxyFunc(ctx, r, w, httpClient) // Fixed: was proxyFunc
+			forward := func(w http.ResponseWriter) {
+				tctx, cancel := h.fwdTimeouts.withTimeout(r.Context(), ctx)
+				defer cancel()
+				proxyFunc(ctx, r.WithContext(tctx), w, h.httpClient, h.streamer)
+			}
+			if h.proxyRetry != nil {
+				attempt := forward
+				forward = func(w http.ResponseWriter) { h.proxyRetry.forward(ctx, r, w, attempt) }
+			}
+			if h.respValidator != nil {
+				unvalidated := forward
+				forward = func(w http.ResponseWriter) { h.respValidator.forward(ctx, w, unvalidated) }
+			}
+			if h.respSigner != nil {
+				h.respSigner.forward(ctx, w, forward)
+				return
+			}
+			forward(w)
 			return
 		case "publisher":
-			if pb == nil {
+			if h.publisher == nil {
 				err := fmt.Errorf("publisher plugin not configured")
 				log.Errorf(ctx.Context, err, "Invalid configuration: %v", err)
 				response.SendNack(ctx, w, err)
 				return
 			}
-			log.Infof(ctx.Context, "Publishing message to: %s", ctx.Route.PublisherID)
-			if err := pb.Publish(ctx, ctx.Route.PublisherID, ctx.Body); err != nil {
+			pubID := h.publisherID(ctx)
+			if h.pubQueue != nil {
+				if !h.pubQueue.enqueue(ctx, pubID, ctx.Body) {
+					log.Warnf(ctx.Context, "Rejecting message for %s: %v", pubID, errPublishQueueFull)
+					rejectPublishQueueFull(ctx, w)
+					return
+				}
+				log.Infof(ctx.Context, "Queued message for: %s", pubID)
+				response.SendAck(ctx, w)
+				return
+			}
+			log.Infof(ctx.Context, "Publishing message to: %s", pubID)
+			var err error
+			if h.boundedPub != nil {
+				err = h.boundedPub.publish(ctx, pubID, ctx.Body)
+			} else {
+				err = h.publisher.Publish(ctx, pubID, ctx.Body)
+			}
+			if errors.Is(err, errPublishQueueFull) {
+				log.Warnf(ctx.Context, "Rejecting message for %s: %v", pubID, err)
+				rejectPublishQueueFull(ctx, w)
+				return
+			}
+			if err != nil {
 				log.Errorf(ctx.Context, err, "Failed to publish message")
+				requestErrorLogFunc(ctx, r, redactedBody(ctx, ctx.Body), err)
 				response.SendNack(ctx, w, err)
 				return
 			}
-			response.SendAck(w)
+			response.SendAck(ctx, w)
 		default:
 			err := fmt.Errorf("unknown route type: %s", ctx.Route.TargetType)
 			log.Errorf(ctx.Context, err, "Invalid configuration: %v", err)
@@ -192,22 +576,29 @@ func route(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, pb de
 	} else {
 
 		RegisterPostResponseHook(r, func() {
+			// Async work outlives the response, so it is not bound by the request deadline.
+			actx := context.WithoutCancel(ctx.Context)
 			switch ctx.Route.TargetType {
 
 			case "url":
 				log.Infof(ctx, "Making async request to URL: %s", ctx.Route.URL)
-				if err := makeAsyncRequest(ctx, ctx, httpClient); err != nil {
+				actx, cancel := h.fwdTimeouts.withTimeout(actx, ctx)
+				defer cancel()
+				if err := makeAsyncRequest(actx, ctx, h.httpClient); err != nil {
 					log.Errorf(ctx, err, "Async request failed")
+					requestErrorLogFunc(ctx, r, redactedBody(ctx, ctx.Body), err)
 				}
 
 			case "publisher":
-				if pb == nil {
+				if h.publisher == nil {
 					log.Errorf(ctx, nil, "Publisher plugin not configured")
 					return
 				}
-				log.Infof(ctx, "Publishing message asynchronously to: %s", ctx.Route.PublisherID)
-				if err := pb.Publish(ctx, ctx.Route.PublisherID, ctx.Body); err != nil {
+				pubID := h.publisherID(ctx)
+				log.Infof(ctx, "Publishing message asynchronously to: %s", pubID)
+				if err := h.pubRetrier.publish(actx, pubID, ctx.Body); err != nil {
 					log.Errorf(ctx, err, "Failed to publish message asynchronously")
+					requestErrorLogFunc(ctx, r, redactedBody(ctx, ctx.Body), err)
 				}
 			}
 		})
@@ -224,7 +615,11 @@ func route(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, pb de
 			response.SendBody(ctx, w, json.RawMessage(decodedValue))
 			return
 		}
-		response.SendAck(w)
+		if ctx.Route.ResponseBody != nil {
+			response.SendBody(ctx, w, ctx.Route.ResponseBody)
+			return
+		}
+		response.SendAck(ctx, w)
 	}
 }
 
@@ -232,16 +627,25 @@ func route(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, pb de
 func makeAsyncRequest(ctx context.Context, stepCtx *model.StepContext, httpClient *http.Client) error {
 	target := stepCtx.Route.URL
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(stepCtx.Body))
+	body, err := forwardBody(stepCtx)
+	if err != nil {
+		return fmt.Errorf("failed to restore request body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	if err := setBodyFraming(req, body); err != nil {
+		return err
+	}
 
 	// Copy relevant headers from original request
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Forwarded-Host", stepCtx.Route.URL.Host)
+	setRouteHeaders(ctx, req.Header, stepCtx.Route)
+	removeHopByHopHeaders(req.Header)
 
-	log.Request(ctx, req, stepCtx.Body)
+	requestLogFunc(ctx, req, loggedBody(stepCtx, stepCtx.Body))
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -249,29 +653,48 @@ func makeAsyncRequest(ctx context.Context, stepCtx *model.StepContext, httpClien
 	}
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	log.Infof(ctx, "Async request completed with status %d: %s", resp.StatusCode, string(body))
+	respBody, _ := io.ReadAll(resp.Body)
+	log.Infof(ctx, "Async request completed with status %d: %s", resp.StatusCode, redactedBody(stepCtx, respBody))
 
 	return nil
 }
-func proxy(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, httpClient *http.Client) {
+
+// proxy forwards r to the URL of the route and copies the response back to w, through
+// streamer when it is not nil.
+func proxy(ctx *model.StepContext, r *http.Request, w http.ResponseWriter, httpClient *http.Client, streamer *proxyStreamer) {
 	target := ctx.Route.URL
 	r.Header.Set("X-Forwarded-Host", r.Host)
 	director := func(req *http.Request) {
 		req.URL = target
 		req.Host = target.Host
+		setRouteHeaders(req.Context(), req.Header, ctx.Route)
+		removeHopByHopHeaders(req.Header)
 
-		log.Request(req.Context(), req, ctx.Body)
+		requestLogFunc(req.Context(), req, loggedBody(ctx, ctx.Body))
 	}
 
 	proxy := &httputil.ReverseProxy{
-		Director:  director,
-		Transport: httpClient.Transport,
+		Director:       director,
+		Transport:      httpClient.Transport,
+		ModifyResponse: modifyProxyResponse(ctx.Route),
 	}
+	streamer.configure(proxy)
 
 	proxy.ServeHTTP(w, r)
 }
 
+// setRouteHeaders sets the fixed headers of route on header. Headers the adapter sets
+// itself, such as the signature headers, are left unchanged.
+func setRouteHeaders(ctx context.Context, header http.Header, route *model.Route) {
+	for name, value := range route.Headers {
+		if model.IsProtectedHeader(name) {
+			log.Warnf(ctx, "Ignoring route header %s: it is set by the adapter", name)
+			continue
+		}
+		header.Set(name, value)
+	}
+}
+
 // loadPlugin is a generic function to load and validate plugins.
 
 func loadPlugin[T any](ctx context.Context, name string, cfg *plugin.Config, mgrFunc func(context.Context, *plugin.Config) (T, error)) (T, error) {
@@ -283,7 +706,7 @@ func loadPlugin[T any](ctx context.Context, name string, cfg *plugin.Config, mgr
 
 	plugin, err := mgrFunc(ctx, cfg)
 	if err != nil {
-		return zero, fmt.Errorf("failed to load %s plugin (%s): %w", name, cfg.ID, err)
+		return zero, newPluginLoadError(name, cfg, err)
 	}
 
 	log.Debugf(ctx, "Loaded %s plugin: %s", name, cfg.ID)
@@ -297,12 +720,12 @@ func loadKeyManager(ctx context.Context, mgr PluginManager, cache definition.Cac
 		return nil, nil
 	}
 	if cache == nil {
-		return nil, fmt.Errorf("failed to load KeyManager plugin (%s): Cache plugin not configured", cfg.ID)
+		return nil, newPluginLoadError("KeyManager", cfg, fmt.Errorf("%w: Cache plugin not configured", errMissingDependency))
 	}
 
 	km, err := mgr.KeyManager(ctx, cache, registry, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load KeyManager plugin (%s): %w", cfg.ID, err)
+		return nil, newPluginLoadError("KeyManager", cfg, err)
 	}
 
 	log.Debugf(ctx, "Loaded Keymanager plugin: %s", cfg.ID)
@@ -317,13 +740,28 @@ func loadOndcValidator(ctx context.Context, mgr PluginManager, cache definition.
 	}
 	ov, err := mgr.OndcValidator(ctx, cache, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load OndcValidator plugin (%s): %w", cfg.ID, err)
+		return nil, newPluginLoadError("OndcValidator", cfg, err)
 	}
 
 	log.Debugf(ctx, "Loaded OndcValidator plugin: %s", cfg.ID)
 	return ov, nil
 }
 
+// selfTestPlugins runs the startup self-test of the ONDC plugins that implement definition.SelfTester.
+func (h *stdHandler) selfTestPlugins(ctx context.Context) error {
+	if st, ok := h.ondcValidator.(definition.SelfTester); ok {
+		if err := st.SelfTest(ctx); err != nil {
+			return fmt.Errorf("OndcValidator: %w", err)
+		}
+	}
+	if st, ok := h.ondcWorkbench.(definition.SelfTester); ok {
+		if err := st.SelfTest(ctx); err != nil {
+			return fmt.Errorf("OndcWorkbench: %w", err)
+		}
+	}
+	return nil
+}
+
 // loadOndcWorkbench loads the OndcWorkbench plugin using the provided PluginManager and cache.
 func loadOndcWorkbench(ctx context.Context, mgr PluginManager, cache definition.Cache, cfg *plugin.Config) (definition.OndcWorkbench, error) {
 	if cfg == nil {
@@ -332,7 +770,7 @@ func loadOndcWorkbench(ctx context.Context, mgr PluginManager, cache definition.
 	}
 	ow, err := mgr.OndcWorkbench(ctx, cache, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load OndcWorkbench plugin (%s): %w", cfg.ID, err)
+		return nil, newPluginLoadError("OndcWorkbench", cfg, err)
 	}
 
 	log.Debugf(ctx, "Loaded OndcWorkbench plugin: %s", cfg.ID)
@@ -351,10 +789,13 @@ func (h *stdHandler) initPlugins(ctx context.Context, mgr PluginManager, cfg *Pl
 	if h.km, err = loadKeyManager(ctx, mgr, h.cache, h.registry, cfg.KeyManager); err != nil {
 		return err
 	}
+	if h.signRegistries, err = loadSignRegistries(ctx, mgr, h.cache, cfg.KeyManager, cfg.SignValidationRegistries); err != nil {
+		return err
+	}
 	if h.signValidator, err = loadPlugin(ctx, "SignValidator", cfg.SignValidator, mgr.SignValidator); err != nil {
 		return err
 	}
-	if h.schemaValidator, err = loadPlugin(ctx, "SchemaValidator", cfg.SchemaValidator, mgr.SchemaValidator); err != nil {
+	if h.schemaValidator, err = loadSchemaValidators(ctx, mgr, cfg.SchemaValidator, cfg.FallbackSchemaValidators); err != nil {
 		return err
 	}
 	if h.router, err = loadPlugin(ctx, "Router", cfg.Router, mgr.Router); err != nil {
@@ -369,12 +810,17 @@ func (h *stdHandler) initPlugins(ctx context.Context, mgr PluginManager, cfg *Pl
 	if h.transportWrapper, err = loadPlugin(ctx, "TransportWrapper", cfg.TransportWrapper, mgr.TransportWrapper); err != nil {
 		return err
 	}
-	if h.ondcValidator, err = loadOndcValidator(ctx, mgr, h.cache, cfg.OndcValidator); err != nil {
+	if h.ondcValidator, err = loadOndcValidators(ctx, mgr, h.cache, cfg.OndcValidator, cfg.OndcValidators); err != nil {
 		return err
 	}
 	if h.ondcWorkbench, err = loadOndcWorkbench(ctx, mgr, h.cache, cfg.OndcWorkbench); err != nil {
 		return err
 	}
+	auditSink, err := loadPlugin(ctx, "Auditor", cfg.Auditor, mgr.Auditor)
+	if err != nil {
+		return err
+	}
+	h.auditor = newAuditor(auditSink, h.moduleName)
 
 	log.Debugf(ctx, "All required plugins successfully loaded for stdHandler")
 	return nil
@@ -393,6 +839,7 @@ func (h *stdHandler) initSteps(ctx context.Context, mgr PluginManager, cfg *Conf
 		steps[c.ID] = step
 	}
 
+	cookies := cfg.Cookies.withDefaults()
 	// Register processing steps
 	for _, step := range cfg.Steps {
 		var s definition.Step
@@ -400,24 +847,68 @@ func (h *stdHandler) initSteps(ctx context.Context, mgr PluginManager, cfg *Conf
 
 		switch step {
 		case "sign":
-			s, err = newSignStep(h.signer, h.km)
+			s, err = newSignStep(h.signer, h.km, cfg.Signature, h.clock)
 		case "validateSign":
-			s, err = newValidateSignStep(h.signValidator, h.km)
+			var status *subscriberStatusCheck
+			if status, err = newSubscriberStatusCheck(h.registry, h.cache, cfg.Signature.SubscriberStatus); err == nil {
+				s, err = newValidateSignStep(h.signValidator, h.km, cookies.HeaderValidation, cfg.Signature, h.clock, status, h.signRegistries...)
+			}
 		case "validateSchema":
 			s, err = newValidateSchemaStep(h.schemaValidator)
 		case "addRoute":
 			s, err = newAddRouteStep(h.router)
+		case "validateTimestamp":
+			s, err = newValidateTimestampStep(cfg.Timestamp, h.clock)
+		case "enrichRegistry":
+			s, err = newEnrichRegistryStep(h.registry, h.cache, cfg.EnrichRegistry)
+		case "validateContentType":
+			s, err = newValidateContentTypeStep(cfg.ContentType)
+		case "checkSubscriberAccess":
+			if h.subAccess == nil {
+				h.subAccess, err = newSubscriberAccess(cfg.SubscriberAccess)
+			}
+			s = newCheckSubscriberAccessStep(h.subAccess)
+		case "injectFault":
+			s, err = newInjectFaultStep(cfg.FaultInjection)
+		case "validateServiceability":
+			s, err = newValidateServiceabilityStep(cfg.Serviceability)
+		case "validateActionForRole":
+			s, err = newValidateActionForRoleStep(cfg.RoleActions)
+		case "validateSubscriberConsistency":
+			s, err = newValidateSubscriberConsistencyStep(h.registry, h.cache, cfg.SubscriberConsistency)
+		case "assignMessageID":
+			s = assignMessageIDStep{}
+		case "normalizeContext":
+			s, err = newNormalizeContextStep(cfg.NormalizeContext)
+		case "normalizeTimestamp":
+			s, err = newNormalizeTimestampStep(cfg.NormalizeTimestamp)
+		case "globalRateLimit":
+			s, err = newGlobalRateLimitStep(cfg.GlobalRateLimit, h.clock)
+		case "dedup":
+			s, err = newDedupStep(h.cache, cfg.Dedup, h.moduleName)
+		case "validateCallbackCorrelation":
+			s, err = newValidateCallbackCorrelationStep(h.cache, cfg.CallbackCorrelation)
 		case "validateOndcPayload":
-			s, err = newValidateOndcStep(h.ondcValidator)
+			s, err = newValidateOndcStep(h.ondcValidator, cookies.ProtocolValidation)
 		case "validateOndcCallSave":
-			s, err = newValidateOndcCallSaveStep(h.ondcValidator)
+			s, err = newValidateOndcCallSaveStep(h.ondcValidator, cfg.Ondc.SaveMode)
+		case "validateOndcAndSave":
+			s, err = newValidateOndcAndSaveStep(h.ondcValidator, &cfg.Ondc, cookies.ProtocolValidation)
 		case "ondcWorkbenchReceiver":
-			s, err = newWorkbenchReceiveStep(h.ondcWorkbench)
+			s, err = newWorkbenchReceiveStep(h.ondcWorkbench, cookies.SubscriberID, h.moduleName, &cfg.Workbench)
+			if err == nil {
+				s = withWorkbenchCondition(s, cfg.Workbench.When)
+			}
 		case "ondcWorkbenchValidateContext":
-			s, err = newWorkbenchValidateContextStep(h.ondcWorkbench)
+			s, err = newWorkbenchValidateContextStep(h.ondcWorkbench, h.moduleName)
+			if err == nil {
+				s = withWorkbenchCondition(s, cfg.Workbench.When)
+			}
 		default:
 			if customStep, exists := steps[step]; exists {
 				s = customStep
+			} else if sharedStep, exists := sharedStep(mgr, step); exists {
+				s = sharedStep
 			} else {
 				return fmt.Errorf("unrecognized step: %s", step)
 			}
@@ -426,6 +917,10 @@ func (h *stdHandler) initSteps(ctx context.Context, mgr PluginManager, cfg *Conf
 		if err != nil {
 			return err
 		}
+		if skip, ok := payloadSteps[step]; ok {
+			s = withPayload(s, skip)
+		}
+		s = withBody(step, s)
 		instrumentedStep, wrapErr := NewInstrumentedStep(s, step, h.moduleName)
 		if wrapErr != nil {
 			log.Warnf(ctx, "Failed to instrument step %s: %v", step, wrapErr)
@@ -1,9 +1,23 @@
 package handler
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
 )
 
 func TestNewHTTPClient(t *testing.T) {
@@ -193,3 +207,346 @@ type mockRoundTripper struct{}
 func (m *mockRoundTripper) RoundTrip(_ *http.Request) (*http.Response, error) {
 	return nil, nil
 }
+
+// stubPluginManager is a PluginManager that returns the configured schema and ONDC plugins and nil for everything else.
+type stubPluginManager struct {
+	schemaValidator definition.SchemaValidator
+	ondcValidator   definition.OndcValidator
+	ondcWorkbench   definition.OndcWorkbench
+}
+
+func (m *stubPluginManager) Middleware(context.Context, *plugin.Config) (func(http.Handler) http.Handler, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) SignValidator(context.Context, *plugin.Config) (definition.SignValidator, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Validator(context.Context, *plugin.Config) (definition.SchemaValidator, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Router(context.Context, *plugin.Config) (definition.Router, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Publisher(context.Context, *plugin.Config) (definition.Publisher, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Signer(context.Context, *plugin.Config) (definition.Signer, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Step(context.Context, *plugin.Config) (definition.Step, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Cache(context.Context, *plugin.Config) (definition.Cache, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) Registry(context.Context, *plugin.Config) (definition.RegistryLookup, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) KeyManager(context.Context, definition.Cache, definition.RegistryLookup, *plugin.Config) (definition.KeyManager, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) TransportWrapper(context.Context, *plugin.Config) (definition.TransportWrapper, error) {
+	return nil, nil
+}
+
+func (m *stubPluginManager) SchemaValidator(context.Context, *plugin.Config) (definition.SchemaValidator, error) {
+	return m.schemaValidator, nil
+}
+
+func (m *stubPluginManager) OndcValidator(context.Context, definition.Cache, *plugin.Config) (definition.OndcValidator, error) {
+	return m.ondcValidator, nil
+}
+
+func (m *stubPluginManager) OndcWorkbench(context.Context, definition.Cache, *plugin.Config) (definition.OndcWorkbench, error) {
+	return m.ondcWorkbench, nil
+}
+
+func (m *stubPluginManager) Auditor(context.Context, *plugin.Config) (definition.Auditor, error) {
+	return nil, nil
+}
+
+// selfTestingValidator is an OndcValidator with a configurable self-test result.
+type selfTestingValidator struct {
+	mockOndcValidator
+	selfTestErr error
+}
+
+func (v *selfTestingValidator) SelfTest(context.Context) error {
+	return v.selfTestErr
+}
+
+// selfTestingWorkbench is an OndcWorkbench with a configurable self-test result.
+type selfTestingWorkbench struct {
+	mockWorkbench
+	selfTestErr error
+}
+
+func (w *selfTestingWorkbench) SelfTest(context.Context) error {
+	return w.selfTestErr
+}
+
+func TestNewStdHandlerSelfTest(t *testing.T) {
+	errMisconfigured := errors.New("rules file not loaded")
+	tests := []struct {
+		name    string
+		mgr     *stubPluginManager
+		plugins PluginCfg
+		wantErr string
+	}{
+		{
+			name:    "passing self-tests",
+			mgr:     &stubPluginManager{ondcValidator: &selfTestingValidator{}, ondcWorkbench: &selfTestingWorkbench{}},
+			plugins: PluginCfg{OndcValidator: &plugin.Config{ID: "ondcvalidator"}, OndcWorkbench: &plugin.Config{ID: "ondcworkbench"}},
+		},
+		{
+			name:    "plugins without self-test",
+			mgr:     &stubPluginManager{ondcValidator: &mockOndcValidator{}, ondcWorkbench: &mockWorkbench{}},
+			plugins: PluginCfg{OndcValidator: &plugin.Config{ID: "ondcvalidator"}, OndcWorkbench: &plugin.Config{ID: "ondcworkbench"}},
+		},
+		{
+			name:    "failing validator self-test",
+			mgr:     &stubPluginManager{ondcValidator: &selfTestingValidator{selfTestErr: errMisconfigured}},
+			plugins: PluginCfg{OndcValidator: &plugin.Config{ID: "ondcvalidator"}},
+			wantErr: "OndcValidator",
+		},
+		{
+			name:    "failing per-domain validator self-test",
+			mgr:     &stubPluginManager{ondcValidator: &selfTestingValidator{selfTestErr: errMisconfigured}},
+			plugins: PluginCfg{OndcValidators: map[string]*plugin.Config{"ONDC:RET10": {ID: "ondcvalidator"}}},
+			wantErr: "domain ONDC:RET10",
+		},
+		{
+			name:    "failing workbench self-test",
+			mgr:     &stubPluginManager{ondcWorkbench: &selfTestingWorkbench{selfTestErr: errMisconfigured}},
+			plugins: PluginCfg{OndcWorkbench: &plugin.Config{ID: "ondcworkbench"}},
+			wantErr: "OndcWorkbench",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewStdHandler(context.Background(), tt.mgr, &Config{Plugins: tt.plugins}, "test-module")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, h)
+				return
+			}
+			require.ErrorIs(t, err, errMisconfigured)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Nil(t, h)
+		})
+	}
+}
+
+func TestStepCtxParsesBecknContext(t *testing.T) {
+	h := &stdHandler{role: model.RoleBAP}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"context":{"action":"on_select","domain":"ONDC:RET10"}}`))
+	ctx, err := h.stepCtx(r, http.Header{})
+	require.NoError(t, err)
+	require.NotNil(t, ctx.BecknContext)
+	assert.Equal(t, "on_select", ctx.BecknContext.Action)
+	assert.Equal(t, "ONDC:RET10", ctx.BecknContext.Domain)
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`not json`))
+	ctx, err = h.stepCtx(r, http.Header{})
+	require.NoError(t, err)
+	assert.Nil(t, ctx.BecknContext)
+}
+
+func TestServeHTTPRecoversFromPanic(t *testing.T) {
+	panicking := stepFunc(func(ctx *model.StepContext) error {
+		panic("step exploded")
+	})
+	proxying := stepFunc(func(ctx *model.StepContext) error {
+		ctx.Route = &model.Route{TargetType: "url", ActAsProxy: true}
+		return nil
+	})
+
+	tests := []struct {
+		name     string
+		step     definition.Step
+		proxy    func(*model.StepContext, *http.Request, http.ResponseWriter, *http.Client, *proxyStreamer)
+		wantCode int
+		wantNack bool
+	}{
+		{
+			name:     "panicking step",
+			step:     panicking,
+			wantCode: http.StatusInternalServerError,
+			wantNack: true,
+		},
+		{
+			name: "panicking route",
+			step: proxying,
+			proxy: func(*model.StepContext, *http.Request, http.ResponseWriter, *http.Client, *proxyStreamer) {
+				panic(errors.New("route exploded"))
+			},
+			wantCode: http.StatusInternalServerError,
+			wantNack: true,
+		},
+		{
+			name: "panic after response written",
+			step: proxying,
+			proxy: func(_ *model.StepContext, _ *http.Request, w http.ResponseWriter, _ *http.Client, _ *proxyStreamer) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("late panic")
+			},
+			wantCode: http.StatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.proxy != nil {
+				orig := proxyFunc
+				proxyFunc = tt.proxy
+				t.Cleanup(func() { proxyFunc = orig })
+			}
+			h := &stdHandler{moduleName: "test", role: model.RoleBAP, steps: []definition.Step{tt.step}}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+			rec := httptest.NewRecorder()
+
+			require.NotPanics(t, func() { h.ServeHTTP(rec, req) })
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantNack, strings.Contains(rec.Body.String(), `"NACK"`))
+			assert.Empty(t, req.Header.Get("X-Module-Name"))
+			assert.Empty(t, req.Header.Get("X-Role"))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			assert.NoError(t, h.Shutdown(ctx), "request must be accounted for after a panic")
+		})
+	}
+}
+
+func TestServeHTTPReraisesAbortHandler(t *testing.T) {
+	orig := proxyFunc
+	proxyFunc = func(*model.StepContext, *http.Request, http.ResponseWriter, *http.Client, *proxyStreamer) {
+		panic(http.ErrAbortHandler)
+	}
+	t.Cleanup(func() { proxyFunc = orig })
+	h := &stdHandler{steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+		ctx.Route = &model.Route{TargetType: "url", ActAsProxy: true}
+		return nil
+	})}}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { h.ServeHTTP(httptest.NewRecorder(), req) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, h.Shutdown(ctx))
+}
+
+func TestServeHTTPInjectsRouteHeaders(t *testing.T) {
+	const signature = `Signature keyId="bap.example.com|k1|ed25519",signature="sig"`
+	tests := []struct {
+		name  string
+		proxy bool
+	}{
+		{name: "proxied", proxy: true},
+		{name: "async"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(chan http.Header, 1)
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got <- r.Header.Clone()
+				_, _ = w.Write([]byte(`{"message":{"ack":{"status":"ACK"}}}`))
+			}))
+			defer upstream.Close()
+			target, err := url.Parse(upstream.URL)
+			require.NoError(t, err)
+
+			h := &stdHandler{
+				httpClient: upstream.Client(),
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.Route = &model.Route{
+						TargetType: "url",
+						URL:        target,
+						ActAsProxy: tt.proxy,
+						Headers: map[string]string{
+							"X-Api-Key":                "backend-key",
+							"X-Tenant-Id":              "tenant-1",
+							model.AuthHeaderSubscriber: "Bearer clobbered",
+						},
+					}
+					ctx.Request.Header.Set(model.AuthHeaderSubscriber, signature)
+					return nil
+				})},
+			}
+			req, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`)))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			for _, hook := range *hooks {
+				hook()
+			}
+
+			var header http.Header
+			select {
+			case header = <-got:
+			case <-time.After(5 * time.Second):
+				t.Fatal("request was not forwarded")
+			}
+			assert.Equal(t, "backend-key", header.Get("X-Api-Key"))
+			assert.Equal(t, "tenant-1", header.Get("X-Tenant-Id"))
+			if tt.proxy {
+				assert.Equal(t, signature, header.Get(model.AuthHeaderSubscriber), "signature header must not be clobbered")
+			} else {
+				assert.NotEqual(t, "Bearer clobbered", header.Get(model.AuthHeaderSubscriber))
+			}
+		})
+	}
+}
+
+func TestServeHTTPAsyncRouteResponseBody(t *testing.T) {
+	const defaultBody = `{"message":{"ack":{"status":"ACK"},"tags":[{"code":"queued"}]}}`
+	tests := []struct {
+		name         string
+		responseBody json.RawMessage
+		cookie       string
+		want         string
+	}{
+		{name: "ack fallback", want: `{"message":{"ack":{"status":"ACK"}}}`},
+		{name: "route default body", responseBody: json.RawMessage(defaultBody), want: defaultBody},
+		{
+			name:         "cookie overrides route default",
+			responseBody: json.RawMessage(defaultBody),
+			cookie:       base64.StdEncoding.EncodeToString([]byte(`{"custom":true}`)),
+			want:         `{"custom":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &stdHandler{
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					ctx.Route = &model.Route{TargetType: "publisher", PublisherID: "q", ResponseBody: tt.responseBody}
+					return nil
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "custom-response-body", Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.want, rec.Body.String())
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
@@ -20,18 +25,62 @@ import (
 type signStep struct {
 	signer definition.Signer
 	km     definition.KeyManager
+	// canonical signs and forwards the canonical JSON form of the body.
+	canonical bool
+	// part selects the signed sub-document of the body; nil signs the whole body.
+	part  signedPart
+	clock Clock
+	// algorithms negotiates the signature algorithm of each request.
+	algorithms *signAlgorithms
+	// jwsHeader carries the detached JWS signature in jws mode; empty in beckn mode.
+	jwsHeader string
+	jwsSigner definition.DetachedJWSSigner
 }
 
-// newSignStep initializes and returns a new signing step.
-func newSignStep(signer definition.Signer, km definition.KeyManager) (definition.Step, error) {
+// signatureValidity is how long a generated signature stays valid.
+const signatureValidity = 5 * time.Minute
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg SignatureConfig) withDefaults() SignatureConfig {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = defaultSignAlgorithm
+	}
+	cfg.SubscriberStatus = cfg.SubscriberStatus.withDefaults()
+	return cfg
+}
+
+// newSignStep initializes and returns a new signing step. With cfg.CanonicalJSON set, the
+// body is replaced by its canonical JSON form (RFC 8785) before it is signed. With
+// cfg.Path set, only that sub-document of the body is signed. Requests are signed with
+// the first algorithm in their Accept-Signature header that the signer supports, and
+// with cfg.Algorithm otherwise. In jws mode requests are signed with a detached JWS
+// instead, which the signer must support. Signature validity is measured from clock,
+// which defaults to the system clock when nil.
+func newSignStep(signer definition.Signer, km definition.KeyManager, cfg SignatureConfig, clock Clock) (definition.Step, error) {
 	if signer == nil {
 		return nil, fmt.Errorf("invalid config: Signer plugin not configured")
 	}
 	if km == nil {
 		return nil, fmt.Errorf("invalid config: KeyManager plugin not configured")
 	}
+	part, err := newSignedPart(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	algorithms, err := newSignAlgorithms(signer, cfg.withDefaults().Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	jwsHeader, err := jwsHeaderName(cfg)
+	if err != nil {
+		return nil, err
+	}
+	jwsSigner, ok := signer.(definition.DetachedJWSSigner)
+	if jwsHeader != "" && !ok {
+		return nil, fmt.Errorf("invalid config: signature mode %s requires a Signer plugin supporting detached JWS", cfg.Mode)
+	}
 
-	return &signStep{signer: signer, km: km}, nil
+	return &signStep{signer: signer, km: km, canonical: cfg.CanonicalJSON, part: part, clock: orSystemClock(clock), algorithms: algorithms, jwsHeader: jwsHeader, jwsSigner: jwsSigner}, nil
 }
 
 // Run executes the signing step.
@@ -43,14 +92,30 @@ func (s *signStep) Run(ctx *model.StepContext) error {
 	if err != nil {
 		return fmt.Errorf("failed to get signing key: %w", err)
 	}
-	createdAt := time.Now().Unix()
-	validTill := time.Now().Add(5 * time.Minute).Unix()
-	sign, err := s.signer.Sign(ctx, ctx.Body, keySet.SigningPrivate, createdAt, validTill)
+	if s.canonical {
+		body, err := canonicalJSON(ctx.Body)
+		if err != nil {
+			return model.NewBadReqErr(fmt.Errorf("failed to canonicalize body: %w", err))
+		}
+		ctx.Body = body
+	}
+	if s.jwsHeader != "" {
+		return s.signJWS(ctx, keySet)
+	}
+	signed, err := s.part.extract(ctx.Body)
+	if err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to select signed part: %w", err))
+	}
+	now := s.clock.Now()
+	createdAt := now.Unix()
+	validTill := now.Add(signatureValidity).Unix()
+	algorithm := s.algorithms.negotiate(ctx.Request.Header.Get(acceptSignatureHeader))
+	sign, err := s.algorithms.sign(ctx, algorithm, signed, keySet, createdAt, validTill)
 	if err != nil {
 		return fmt.Errorf("failed to sign request: %w", err)
 	}
 
-	authHeader := s.generateAuthHeader(ctx.SubID, keySet.UniqueKeyID, createdAt, validTill, sign)
+	authHeader := generateAuthHeader(ctx.SubID, keySet.UniqueKeyID, algorithm, createdAt, validTill, sign)
 	log.Debugf(ctx, "Signature generated: %v", sign)
 	header := model.AuthHeaderSubscriber
 	if ctx.Role == model.RoleGateway {
@@ -60,85 +125,294 @@ func (s *signStep) Run(ctx *model.StepContext) error {
 	return nil
 }
 
-// generateAuthHeader constructs the authorization header for the signed request.
+// generateAuthHeader constructs the authorization header for a signed request or response.
 // It includes key ID, algorithm, creation time, expiration time, required headers, and signature.
-func (s *signStep) generateAuthHeader(subID, keyID string, createdAt, validTill int64, signature string) string {
+func generateAuthHeader(subID, keyID, algorithm string, createdAt, validTill int64, signature string) string {
 	return fmt.Sprintf(
-		"Signature keyId=\"%s|%s|ed25519\",algorithm=\"ed25519\",created=\"%d\",expires=\"%d\",headers=\"(created) (expires) digest\",signature=\"%s\"",
-		subID, keyID, createdAt, validTill, signature,
+		"Signature keyId=\"%s|%s|%s\",algorithm=\"%s\",created=\"%d\",expires=\"%d\",headers=\"(created) (expires) digest\",signature=\"%s\"",
+		subID, keyID, algorithm, algorithm, createdAt, validTill, signature,
 	)
 }
 
 // validateSignStep represents the signature validation step.
 type validateSignStep struct {
 	validator definition.SignValidator
-	km        definition.KeyManager
+	keys      []registryKeys
 	metrics   *HandlerMetrics
+	cookie    string
+	// canonical also accepts a signature over the canonical JSON form of the body.
+	canonical bool
+	// part selects the signed sub-document of the body; nil validates the whole body.
+	part signedPart
+	// status rejects signers whose registry status is not allowed; nil skips the check.
+	status *subscriberStatusCheck
+	// failures tracks validation failures per subscriber; nil when tracking is off.
+	failures *signFailureTracker
+	// signerSubID makes the validated signer the subscriber of the request.
+	signerSubID bool
+	// jwsHeader carries detached JWS signatures in jws mode; empty in beckn mode.
+	jwsHeader    string
+	jwsValidator definition.DetachedJWSValidator
+	// algorithms holds the algorithms the validator supports, keyed by their lower-cased name.
+	algorithms map[string]string
+}
+
+// defaultAllowedSubscriberStatuses are the registry statuses accepted when
+// SubscriberStatusConfig.Allowed is unset.
+var defaultAllowedSubscriberStatuses = []string{"SUBSCRIBED"}
+
+// subscriberStatusCheck verifies that a signer's registry record is in an allowed status.
+type subscriberStatusCheck struct {
+	subscribers *subscriberLookup
+	allowed     []string
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg SubscriberStatusConfig) withDefaults() SubscriberStatusConfig {
+	if len(cfg.Allowed) == 0 {
+		cfg.Allowed = defaultAllowedSubscriberStatuses
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultEnrichRegistryTTL
+	}
+	return cfg
+}
+
+// newSubscriberStatusCheck creates a subscriberStatusCheck from cfg. It returns nil when
+// the check is disabled.
+func newSubscriberStatusCheck(registry definition.RegistryLookup, cache definition.Cache, cfg SubscriberStatusConfig) (*subscriberStatusCheck, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	cfg = cfg.withDefaults()
+	subscribers, err := newSubscriberLookup(registry, cache, cfg.TTL)
+	if err != nil {
+		return nil, err
+	}
+	return &subscriberStatusCheck{subscribers: subscribers, allowed: cfg.Allowed}, nil
+}
+
+// check returns a SignValidationErr when subscriberID is unknown to the registry or its
+// status is not allowed.
+func (c *subscriberStatusCheck) check(ctx context.Context, subscriberID string) error {
+	sub, err := c.subscribers.lookup(ctx, subscriberID)
+	var notFound *model.NotFoundErr
+	if errors.As(err, &notFound) {
+		return model.NewSignValidationErr(notFound)
+	}
+	if err != nil {
+		return err
+	}
+	for _, a := range c.allowed {
+		if strings.EqualFold(sub.Status, a) {
+			return nil
+		}
+	}
+	return model.NewSignValidationErr(fmt.Errorf("subscriber %s has registry status %q, want one of %s",
+		subscriberID, sub.Status, strings.Join(c.allowed, ", ")))
 }
 
 // newValidateSignStep initializes and returns a new validate sign step.
-func newValidateSignStep(signValidator definition.SignValidator, km definition.KeyManager) (definition.Step, error) {
+// cookie names the request cookie that disables header validation when set to "false".
+// With cfg.CanonicalJSON set, a signature that does not match the body as received is
+// checked against its canonical JSON form (RFC 8785). With cfg.Path set, the signature is
+// checked against that sub-document of the body.
+// In jws mode, requests carrying a detached JWS in the configured header are validated
+// against it instead of the Authorization header.
+// A non-nil status also rejects signers whose registry status it does not allow.
+// cfg.FailureTracking windows are measured with clock, which defaults to the system clock.
+// Keys are looked up through km first and then through each fallback in order.
+func newValidateSignStep(signValidator definition.SignValidator, km definition.KeyManager, cookie string, cfg SignatureConfig, clock Clock, status *subscriberStatusCheck, fallbacks ...registryKeys) (definition.Step, error) {
 	if signValidator == nil {
 		return nil, fmt.Errorf("invalid config: SignValidator plugin not configured")
 	}
 	if km == nil {
 		return nil, fmt.Errorf("invalid config: KeyManager plugin not configured")
 	}
+	part, err := newSignedPart(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
 	metrics, _ := GetHandlerMetrics(context.Background())
+	failures, err := newSignFailureTracker(cfg.FailureTracking, clock, metrics)
+	if err != nil {
+		return nil, err
+	}
+	jwsHeader, err := jwsHeaderName(cfg)
+	if err != nil {
+		return nil, err
+	}
+	jwsValidator, ok := signValidator.(definition.DetachedJWSValidator)
+	if jwsHeader != "" && !ok {
+		return nil, fmt.Errorf("invalid config: signature mode %s requires a SignValidator plugin supporting detached JWS", cfg.Mode)
+	}
 	return &validateSignStep{
-		validator: signValidator,
-		km:        km,
-		metrics:   metrics,
+		validator:    signValidator,
+		keys:         append([]registryKeys{{registry: defaultRegistryName, km: km}}, fallbacks...),
+		metrics:      metrics,
+		cookie:       cookie,
+		canonical:    cfg.CanonicalJSON,
+		part:         part,
+		status:       status,
+		failures:     failures,
+		signerSubID:  cfg.SubscriberFromSignature,
+		jwsHeader:    jwsHeader,
+		jwsValidator: jwsValidator,
+		algorithms:   validateAlgorithms(signValidator),
 	}, nil
 }
 
 // Run executes the validation step.
 func (s *validateSignStep) Run(ctx *model.StepContext) error {
-	err := s.validateHeaders(ctx)
-	s.recordMetrics(ctx, err)
+	registry, err := s.validateHeaders(ctx)
+	s.recordMetrics(ctx, registry, err)
 	return err
 }
 
-func (s *validateSignStep) validateHeaders(ctx *model.StepContext) error {
-	headerValCookie , err := ctx.Request.Cookie("header_validation")
+// validateHeaders validates the signature header, returning the registry whose keys
+// validated it, or "" when there was nothing to validate.
+func (s *validateSignStep) validateHeaders(ctx *model.StepContext) (string, error) {
+	headerValCookie , err := ctx.Request.Cookie(s.cookie)
 	if err != nil {
 		headerValCookie = &http.Cookie{Value: "true"}
 	}
 	if(headerValCookie.Value == "false"){
-		log.Debug(ctx,"Skipping Signature validation step as per header_validation cookie")
-		return nil
+		log.Debugf(ctx,"Skipping Signature validation step as per %s cookie", s.cookie)
+		return "", nil
 	}
 	unauthHeader := fmt.Sprintf("Signature realm=\"%s\",headers=\"(created) (expires) digest\"", ctx.SubID)
+	if s.jwsHeader != "" {
+		if jws := ctx.Request.Header.Get(s.jwsHeader); jws != "" {
+			log.Debugf(ctx, "Validating %v Header", s.jwsHeader)
+			return s.validateSigner(ctx, s.jwsHeader, jws, unauthHeader, parseJWSKeyID, s.validateJWS)
+		}
+	}
 	headerValue := ctx.Request.Header.Get(model.AuthHeaderSubscriber)
+	registry := ""
 	if len(headerValue) != 0 {
 		log.Debugf(ctx, "Validating %v Header", model.AuthHeaderSubscriber)
-		if err := s.validate(ctx, headerValue); err != nil {
-			ctx.RespHeader.Set(model.UnaAuthorizedHeaderGateway, unauthHeader)
-			return model.NewSignValidationErr(fmt.Errorf("failed to validate %s: %w", model.AuthHeaderSubscriber, err))
+		if registry, err = s.validateSigner(ctx, model.AuthHeaderSubscriber, headerValue, unauthHeader, parseHeader, s.validate); err != nil {
+			return "", err
 		}
 	}
 	log.Debugf(ctx, "Header validated successfully for %v", model.AuthHeaderSubscriber)
-	return nil
+	return registry, nil
+}
+
+// validateSigner validates the signature in header name, whose value is parsed with parse
+// and checked with validate, returning the registry whose keys validated it. The
+// subscriber of a valid signature becomes the signer of the request.
+func (s *validateSignStep) validateSigner(ctx *model.StepContext, name, value, unauthHeader string, parse func(string) (*authHeader, error), validate func(*model.StepContext, *authHeader, string) (string, error)) (string, error) {
+	headerVals, err := parse(value)
+	if err != nil {
+		ctx.RespHeader.Set(model.UnaAuthorizedHeaderGateway, unauthHeader)
+		return "", model.NewSignValidationErr(fmt.Errorf("failed to validate %s: failed to parse header", name))
+	}
+	signer := newSignerSource(headerVals.SubscriberID, ctx.Request.RemoteAddr)
+	if s.failures.blocked(ctx, signer) {
+		ctx.RespHeader.Set(model.UnaAuthorizedHeaderGateway, unauthHeader)
+		return "", model.NewSignValidationErr(fmt.Errorf("subscriber %s is temporarily blocked from %s after repeated signature validation failures", signer.subscriberID, signer.source))
+	}
+	registry, err := validate(ctx, headerVals, value)
+	if err != nil {
+		s.failures.record(ctx, signer)
+		ctx.RespHeader.Set(model.UnaAuthorizedHeaderGateway, unauthHeader)
+		return "", model.NewSignValidationErr(fmt.Errorf("failed to validate %s: %w", name, err))
+	}
+	if s.status != nil {
+		if err := s.status.check(ctx, headerVals.SubscriberID); err != nil {
+			return "", err
+		}
+	}
+	ctx.Signer = headerVals.SubscriberID
+	if s.signerSubID {
+		ctx.SubID = headerVals.SubscriberID
+	}
+	return registry, nil
 }
 
-// validate checks the validity of the provided signature header.
-func (s *validateSignStep) validate(ctx *model.StepContext, value string) error {
-	headerVals, err := parseHeader(value)
+// validate checks the validity of the provided signature header, parsed into headerVals,
+// against the keys of each registry in order, returning the first registry whose keys
+// validate it. The signature is checked with the algorithm named in its keyId. When it
+// covers (request-target), the request target is passed to the validator in the context.
+func (s *validateSignStep) validate(ctx *model.StepContext, headerVals *authHeader, value string) (string, error) {
+	algorithm, err := s.signatureAlgorithm(headerVals)
+	if err != nil {
+		return "", err
+	}
+	bodies, err := s.signedBodies(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to parse header")
+		return "", err
 	}
+	vctx := withRequestTarget(ctx, ctx.Request, value)
+	return s.validateKeys(ctx, headerVals, func(publicKey string) error {
+		var err error
+		for _, body := range bodies {
+			if err = s.verify(vctx, algorithm, body, value, publicKey); err == nil {
+				return nil
+			}
+		}
+		return err
+	})
+}
+
+// validateKeys checks a signature by headerVals' subscriber with check, against its
+// signing key looked up through the keys of each registry in order, returning the first
+// registry whose key validates it.
+func (s *validateSignStep) validateKeys(ctx *model.StepContext, headerVals *authHeader, check func(publicKey string) error) (string, error) {
 	log.Debugf(ctx, "Validating Signature for subscriberID: %v", headerVals.SubscriberID)
-	signingPublicKey, _, err := s.km.LookupNPKeys(ctx, headerVals.SubscriberID, headerVals.UniqueID)
+	var errs []error
+	for _, k := range s.keys {
+		err := s.validateWith(ctx, k.km, headerVals, check)
+		if err == nil {
+			log.Debugf(ctx, "Signature of %s validated with keys from registry %s", headerVals.SubscriberID, k.registry)
+			return k.registry, nil
+		}
+		if len(s.keys) > 1 {
+			err = fmt.Errorf("registry %s: %w", k.registry, err)
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+// signedBodies returns the forms of the body a signature may cover: the body as
+// received and, in canonical mode, its canonical JSON form when that differs. With a
+// signed part configured, each form is narrowed to that sub-document.
+func (s *validateSignStep) signedBodies(ctx *model.StepContext) ([][]byte, error) {
+	bodies := [][]byte{ctx.Body}
+	if s.canonical {
+		canonical, err := canonicalJSON(ctx.Body)
+		if err != nil {
+			log.Debugf(ctx, "Body cannot be canonicalized, validating it as received: %v", err)
+		} else if !bytes.Equal(canonical, ctx.Body) {
+			bodies = append(bodies, canonical)
+		}
+	}
+	for i, body := range bodies {
+		part, err := s.part.extract(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select signed part: %w", err)
+		}
+		bodies[i] = part
+	}
+	return bodies, nil
+}
+
+// validateWith checks the signature with check and the signing key looked up through km.
+func (s *validateSignStep) validateWith(ctx *model.StepContext, km definition.KeyManager, headerVals *authHeader, check func(publicKey string) error) error {
+	signingPublicKey, _, err := km.LookupNPKeys(ctx, headerVals.SubscriberID, headerVals.UniqueID)
 	if err != nil {
 		return fmt.Errorf("failed to get validation key: %w", err)
 	}
-	if err := s.validator.Validate(ctx, ctx.Body, value, signingPublicKey); err != nil {
+	if err := check(signingPublicKey); err != nil {
 		return fmt.Errorf("sign validation failed: %w", err)
 	}
 	return nil
 }
 
-func (s *validateSignStep) recordMetrics(ctx *model.StepContext, err error) {
+// recordMetrics counts the validation, attributed to the registry that satisfied it.
+func (s *validateSignStep) recordMetrics(ctx *model.StepContext, registry string, err error) {
 	if s.metrics == nil {
 		return
 	}
@@ -146,8 +420,11 @@ func (s *validateSignStep) recordMetrics(ctx *model.StepContext, err error) {
 	if err != nil {
 		status = "failed"
 	}
-	s.metrics.SignatureValidationsTotal.Add(ctx.Context, 1,
-		metric.WithAttributes(telemetry.AttrStatus.String(status)))
+	attrs := []attribute.KeyValue{telemetry.AttrStatus.String(status)}
+	if registry != "" {
+		attrs = append(attrs, telemetry.AttrRegistry.String(registry))
+	}
+	s.metrics.SignatureValidationsTotal.Add(ctx.Context, 1, metric.WithAttributes(attrs...))
 }
 
 // ParsedKeyID holds the components from the parsed Authorization header's keyId.
@@ -257,16 +534,22 @@ func newAddRouteStep(router definition.Router) (definition.Step, error) {
 
 // Run executes the routing step.
 func (s *addRouteStep) Run(ctx *model.StepContext) error {
-
+	// An earlier step (e.g. the workbench receiver) has already decided the route.
+	if ctx.Route != nil {
+		log.Debugf(ctx, "Route already set to %s, skipping router", ctx.Route.TargetType)
+		return nil
+	}
 	route, err := s.router.Route(ctx, ctx.Request.URL, ctx.Body,ctx.Request)
 	if err != nil {
 		return fmt.Errorf("failed to determine route: %w", err)
 	}
 	ctx.Route = &model.Route{
-		TargetType:  route.TargetType,
-		PublisherID: route.PublisherID,
-		URL:         route.URL,
-		ActAsProxy:  route.ActAsProxy,
+		TargetType:   route.TargetType,
+		PublisherID:  route.PublisherID,
+		URL:          route.URL,
+		ActAsProxy:   route.ActAsProxy,
+		Headers:      route.Headers,
+		ResponseBody: route.ResponseBody,
 	}
 	if s.metrics != nil && ctx.Route != nil {
 		s.metrics.RoutingDecisionsTotal.Add(ctx.Context, 1,
@@ -292,61 +575,304 @@ func extractSchemaVersion(body []byte) string {
 	return "unknown"
 }
 
+// Defaults of the validateTimestamp step bounds.
+const (
+	defaultTimestampMaxAge  = 5 * time.Minute
+	defaultTimestampMaxSkew = 30 * time.Second
+)
+
+// validateTimestampStep rejects requests whose context timestamp is stale or too far in the future.
+type validateTimestampStep struct {
+	maxAge  time.Duration
+	maxSkew time.Duration
+	clock   Clock
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg TimestampConfig) withDefaults() TimestampConfig {
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultTimestampMaxAge
+	}
+	if cfg.MaxSkew <= 0 {
+		cfg.MaxSkew = defaultTimestampMaxSkew
+	}
+	return cfg
+}
+
+// newValidateTimestampStep creates and returns the validateTimestamp step. Timestamps are
+// checked against clock, which defaults to the system clock when nil.
+func newValidateTimestampStep(cfg TimestampConfig, clock Clock) (definition.Step, error) {
+	if cfg.MaxAge < 0 || cfg.MaxSkew < 0 {
+		return nil, fmt.Errorf("invalid config: timestamp maxAge and maxSkew must not be negative")
+	}
+	cfg = cfg.withDefaults()
+	return &validateTimestampStep{maxAge: cfg.MaxAge, maxSkew: cfg.MaxSkew, clock: orSystemClock(clock)}, nil
+}
+
+// stepBecknContext returns the context parsed when the request was read, or parses ctx.Body
+// when none was, reporting a body without a valid context as a bad request.
+func stepBecknContext(ctx *model.StepContext) (*model.BecknContext, error) {
+	if ctx.BecknContext != nil {
+		return ctx.BecknContext, nil
+	}
+	bCtx, err := model.ParseBecknContext(ctx.Body)
+	if err != nil {
+		return nil, model.NewBadReqErr(err)
+	}
+	return bCtx, nil
+}
+
+// Run checks context.timestamp against the configured max age and skew.
+func (s *validateTimestampStep) Run(ctx *model.StepContext) error {
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	if bCtx.Timestamp == "" {
+		return model.NewBadReqErr(errors.New("context.timestamp is missing"))
+	}
+	ts, err := time.Parse(time.RFC3339Nano, bCtx.Timestamp)
+	if err != nil {
+		return model.NewBadReqErr(fmt.Errorf("context.timestamp %q is not an RFC 3339 time", bCtx.Timestamp))
+	}
+	age := s.clock.Now().Sub(ts)
+	if age > s.maxAge {
+		return model.NewBadReqErr(fmt.Errorf("context.timestamp %s is older than %s", bCtx.Timestamp, s.maxAge))
+	}
+	if -age > s.maxSkew {
+		return model.NewBadReqErr(fmt.Errorf("context.timestamp %s is more than %s in the future", bCtx.Timestamp, s.maxSkew))
+	}
+	return nil
+}
+
+// defaultEnrichRegistryTTL is how long a looked-up subscriber record is cached when
+// EnrichRegistryConfig.TTL is unset.
+const defaultEnrichRegistryTTL = 5 * time.Minute
+
+// subscriberLookup looks up registry records of subscribers, caching them in the Cache plugin.
+type subscriberLookup struct {
+	registry definition.RegistryLookup
+	cache    definition.Cache
+	ttl      time.Duration
+}
+
+// newSubscriberLookup creates a subscriberLookup caching records for ttl after validation.
+func newSubscriberLookup(registry definition.RegistryLookup, cache definition.Cache, ttl time.Duration) (*subscriberLookup, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("invalid config: Registry plugin not configured")
+	}
+	if cache == nil {
+		return nil, fmt.Errorf("invalid config: Cache plugin not configured")
+	}
+	return &subscriberLookup{registry: registry, cache: cache, ttl: ttl}, nil
+}
+
+// lookup returns the registry record of subscriberID, from the cache when possible. When
+// the registry holds several records, the SUBSCRIBED one is preferred.
+func (l *subscriberLookup) lookup(ctx context.Context, subscriberID string) (*model.Subscription, error) {
+	key := "onix:registry:subscriber:" + subscriberID
+	if v, err := l.cache.Get(ctx, key); err == nil && v != "" {
+		var sub model.Subscription
+		if err := json.Unmarshal([]byte(v), &sub); err == nil {
+			return &sub, nil
+		}
+		log.Warnf(ctx, "Discarding unreadable cached registry record of %s", subscriberID)
+	}
+	subs, err := l.registry.Lookup(ctx, &model.Subscription{Subscriber: model.Subscriber{SubscriberID: subscriberID}})
+	if err != nil {
+		return nil, fmt.Errorf("registry lookup of %s failed: %w", subscriberID, err)
+	}
+	if len(subs) == 0 {
+		return nil, model.NewNotFoundErr(fmt.Errorf("subscriber %s not found in registry", subscriberID))
+	}
+	sub := subs[0]
+	for _, c := range subs {
+		if c.Status == "SUBSCRIBED" {
+			sub = c
+			break
+		}
+	}
+	if b, err := json.Marshal(sub); err == nil {
+		if err := l.cache.Set(ctx, key, string(b), l.ttl); err != nil {
+			log.Warnf(ctx, "Failed to cache registry record of %s: %v", subscriberID, err)
+		}
+	}
+	return &sub, nil
+}
+
+// enrichRegistryStep attaches the registry record of the request's subscriber to the step context.
+type enrichRegistryStep struct {
+	subscribers *subscriberLookup
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg EnrichRegistryConfig) withDefaults() EnrichRegistryConfig {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultEnrichRegistryTTL
+	}
+	return cfg
+}
+
+// newEnrichRegistryStep creates and returns the enrichRegistry step after validation.
+func newEnrichRegistryStep(registry definition.RegistryLookup, cache definition.Cache, cfg EnrichRegistryConfig) (definition.Step, error) {
+	subscribers, err := newSubscriberLookup(registry, cache, cfg.withDefaults().TTL)
+	if err != nil {
+		return nil, err
+	}
+	return &enrichRegistryStep{subscribers: subscribers}, nil
+}
+
+// Run looks up the subscriber record for ctx.SubID, from the cache when possible, and
+// stores it in ctx.Subscriber.
+func (s *enrichRegistryStep) Run(ctx *model.StepContext) error {
+	if ctx.SubID == "" {
+		return model.NewBadReqErr(fmt.Errorf("subscriberID not set"))
+	}
+	sub, err := s.subscribers.lookup(ctx, ctx.SubID)
+	if err != nil {
+		return err
+	}
+	ctx.Subscriber = sub
+	return nil
+}
+
 // ============================================================================
 // region ONDC VALIDATOR STEPS
 // ============================================================================
 
+// Reasons recorded with the ONDC validation skip decision.
+const (
+	ondcReasonCookieDisabled = "protocol_validation_disabled"
+	ondcReasonCookieEnabled  = "protocol_validation_enabled"
+	ondcReasonDefault        = "default"
+)
+
 // Run executes the ONDC validation step.
 func (s *validateOndcStep) Run(ctx *model.StepContext) error {
-	skipCookie, err := ctx.Request.Cookie("protocol_validation")
+	reason := ondcReasonDefault
+	skipCookie, err := ctx.Request.Cookie(s.cookie)
 	if err != nil {
 		skipCookie = &http.Cookie{Value: "true"}
+	} else {
+		reason = ondcReasonCookieEnabled
 	}
-	log.Debugf(ctx,"Executing ONDC validation step with protocol_validation header value: %s", skipCookie.Value)
+	log.Debugf(ctx,"Executing ONDC validation step with %s header value: %s", s.cookie, skipCookie.Value)
 	if(skipCookie.Value == "false"){
-		log.Debug(ctx,"Skipping ONDC validation step as per protocol_validation cookie")
+		log.Debugf(ctx,"Skipping ONDC validation step as per %s cookie", s.cookie)
+		s.recordMetrics(ctx, "skipped", ondcReasonCookieDisabled)
 		return nil
 	}
+	s.recordMetrics(ctx, "executed", reason)
 	if err := s.validator.ValidatePayload(ctx, ctx.Request.URL, ctx.Body); err != nil {
 		return fmt.Errorf("ondc validation failed: %w", err)
 	}
 	return nil
 }
 
+func (s *validateOndcStep) recordMetrics(ctx *model.StepContext, status, reason string) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.OndcValidationsTotal.Add(ctx.Context, 1,
+		metric.WithAttributes(
+			telemetry.AttrStatus.String(status),
+			telemetry.AttrReason.String(reason),
+		))
+}
+
 // newValidateOndcStep creates and returns the validateOndc step after validation.
-func newValidateOndcStep(ondcValidator definition.OndcValidator) (definition.Step, error) {
+// cookie names the request cookie that disables validation when set to "false".
+func newValidateOndcStep(ondcValidator definition.OndcValidator, cookie string) (definition.Step, error) {
 	if ondcValidator == nil {
 		return nil, fmt.Errorf("invalid config: OndcValidator plugin not configured")
 	}
 	log.Debug(context.Background(), "adding ondc validator")
-	return &validateOndcStep{validator: ondcValidator}, nil
+	metrics, _ := GetHandlerMetrics(context.Background())
+	return &validateOndcStep{validator: ondcValidator, metrics: metrics, cookie: cookie}, nil
 }
 
 // validateOndcStep represents the ONDC validation step.
 type validateOndcStep struct {
 	validator definition.OndcValidator
+	metrics   *HandlerMetrics
+	cookie    string
 }
 
 // validateOndcCallSaveStep represents the ONDC call save validation step.
 type validateOndcCallSaveStep struct {
 	validator definition.OndcValidator
+	mode      OndcSaveMode
 }
 
 // Run executes the ONDC call save validation step.
+// In async mode the save is deferred until after the response has been sent.
 func (s *validateOndcCallSaveStep) Run(ctx *model.StepContext) error {
-	if err := s.validator.SaveValidationData(ctx.Context, ctx.Request.URL, ctx.Body); err != nil {
+	if s.mode == OndcSaveModeAsync {
+		url, body := ctx.Request.URL, ctx.Body
+		saveCtx := withBecknContext(context.WithoutCancel(ctx.Context), ctx.BecknContext)
+		RegisterPostResponseHook(ctx.Request, func() {
+			if err := s.validator.SaveValidationData(saveCtx, url, body); err != nil {
+				log.Errorf(saveCtx, err, "ondc call save validation failed")
+			}
+		})
+		return nil
+	}
+	if err := s.validator.SaveValidationData(ctx, ctx.Request.URL, ctx.Body); err != nil {
 		return fmt.Errorf("ondc call save validation failed: %w", err)
 	}
 	return nil
 }
 
 // newValidateOndcCallSaveStep creates and returns the validateOndcCallSave step after validation.
-func newValidateOndcCallSaveStep(ondcValidator definition.OndcValidator) (definition.Step, error) {
+func newValidateOndcCallSaveStep(ondcValidator definition.OndcValidator, mode OndcSaveMode) (definition.Step, error) {
 	if ondcValidator == nil {
 		return nil, fmt.Errorf("invalid config: OndcValidator plugin not configured")
 	}
-	log.Debug(context.Background(), "adding ondc call save validator")
-	return &validateOndcCallSaveStep{validator: ondcValidator}, nil
+	switch mode {
+	case "":
+		mode = OndcSaveModeSync
+	case OndcSaveModeSync, OndcSaveModeAsync:
+	default:
+		return nil, fmt.Errorf("invalid config: unknown ondc save mode %q", mode)
+	}
+	log.Debugf(context.Background(), "adding ondc call save validator in %s mode", mode)
+	return &validateOndcCallSaveStep{validator: ondcValidator, mode: mode}, nil
+}
+// validateOndcAndSaveStep validates the ONDC payload and then persists the validation data.
+type validateOndcAndSaveStep struct {
+	validate      *validateOndcStep
+	save          *validateOndcCallSaveStep
+	saveOnFailure bool
+}
+
+// newValidateOndcAndSaveStep creates and returns the combined validateOndcAndSave step after validation.
+func newValidateOndcAndSaveStep(ondcValidator definition.OndcValidator, cfg *OndcConfig, cookie string) (definition.Step, error) {
+	validate, err := newValidateOndcStep(ondcValidator, cookie)
+	if err != nil {
+		return nil, err
+	}
+	save, err := newValidateOndcCallSaveStep(ondcValidator, cfg.SaveMode)
+	if err != nil {
+		return nil, err
+	}
+	return &validateOndcAndSaveStep{
+		validate:      validate.(*validateOndcStep),
+		save:          save.(*validateOndcCallSaveStep),
+		saveOnFailure: cfg.SaveOnValidationFailure,
+	}, nil
+}
+
+// Run validates the payload and saves the validation data. The save is skipped when
+// validation fails unless saveOnFailure is set; the validation error is returned either way.
+func (s *validateOndcAndSaveStep) Run(ctx *model.StepContext) error {
+	validateErr := s.validate.Run(ctx)
+	if validateErr != nil && !s.saveOnFailure {
+		return validateErr
+	}
+	if err := s.save.Run(ctx); err != nil && validateErr == nil {
+		return err
+	}
+	return validateErr
 }
 // endregion 
 
@@ -356,51 +882,233 @@ func newValidateOndcCallSaveStep(ondcValidator definition.OndcValidator) (defini
 // ============================================================================
 // region WORKBENCH STEPS
 // ============================================================================
+
+// Operations recorded with the workbench metrics.
+const (
+	workbenchOpReceive         = "receive"
+	workbenchOpValidateContext = "validate_context"
+)
+
+// recordWorkbenchMetrics records the outcome and latency of a workbench operation.
+func recordWorkbenchMetrics(ctx *model.StepContext, m *HandlerMetrics, moduleName, operation string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "failed"
+	}
+	attrs := metric.WithAttributes(
+		telemetry.AttrModule.String(moduleName),
+		telemetry.AttrOperation.String(operation),
+		telemetry.AttrStatus.String(status),
+	)
+	m.WorkbenchOperationsTotal.Add(ctx.Context, 1, attrs)
+	m.WorkbenchDuration.Record(ctx.Context, time.Since(start).Seconds(), attrs)
+}
+
+// newWorkbenchInput collects the parsed request for the workbench steps.
+func newWorkbenchInput(ctx *model.StepContext) *definition.WorkbenchInput {
+	in := &definition.WorkbenchInput{
+		Request: ctx.Request,
+		Body:    ctx.Body,
+		Context: ctx.BecknContext,
+		Cookies: make(map[string]string),
+	}
+	if in.Context == nil {
+		log.Debugf(ctx, "Workbench input without parsed context")
+	}
+	for _, c := range ctx.Request.Cookies() {
+		in.Cookies[c.Name] = c.Value
+	}
+	return in
+}
+
 type workbenchReceiveStep struct {
-	workbench definition.OndcWorkbench
+	workbench     definition.OndcWorkbench
+	cookie        string
+	moduleName    string
+	metrics       *HandlerMetrics
+	failure       string
+	failureStatus int
 }
 
 // newWorkbenchReceiveStep creates and returns the workbench receive step after validation.
-func newWorkbenchReceiveStep(workbench definition.OndcWorkbench) (definition.Step, error) {
+// cookie names the request cookie carrying the subscriber ID.
+func newWorkbenchReceiveStep(workbench definition.OndcWorkbench, cookie, moduleName string, cfg *WorkbenchConfig) (definition.Step, error) {
 	if workbench == nil {
 		return nil, fmt.Errorf("invalid config: OndcWorkbench plugin not configured")
 	}
+	failure, err := workbenchFailure(cfg.ReceiverFailure)
+	if err != nil {
+		return nil, err
+	}
+	failureStatus := cfg.ReceiverFailureStatus
+	if failureStatus == 0 {
+		failureStatus = http.StatusInternalServerError
+	}
+	if failureStatus < 400 || failureStatus > 599 {
+		return nil, fmt.Errorf("invalid config: workbench receiverFailureStatus %d is not an error status", failureStatus)
+	}
 	log.Debug(context.Background(), "adding ondc workbench receive step")
-	return &workbenchReceiveStep{workbench: workbench}, nil
+	metrics, _ := GetHandlerMetrics(context.Background())
+	return &workbenchReceiveStep{
+		workbench:     workbench,
+		cookie:        cookie,
+		moduleName:    moduleName,
+		metrics:       metrics,
+		failure:       failure,
+		failureStatus: failureStatus,
+	}, nil
+}
+
+// workbenchFailure maps a configured failure behavior to the matching WorkbenchErr behavior.
+func workbenchFailure(b WorkbenchFailureBehavior) (string, error) {
+	switch b {
+	case "":
+		return "", nil
+	case WorkbenchFailureAck:
+		return model.WorkbenchBehaviorACK, nil
+	case WorkbenchFailureNack:
+		return model.WorkbenchBehaviorNACK, nil
+	case WorkbenchFailureHTTP:
+		return model.WorkbenchBehaviorHTTP, nil
+	default:
+		return "", fmt.Errorf("invalid config: unknown workbench receiverFailure %q", b)
+	}
 }
 
 // Run executes the workbench receive step.
 func (s *workbenchReceiveStep) Run(ctx *model.StepContext) error {
+	start := time.Now()
+	err := s.receive(ctx)
+	recordWorkbenchMetrics(ctx, s.metrics, s.moduleName, workbenchOpReceive, start, err)
+	return err
+}
+
+func (s *workbenchReceiveStep) receive(ctx *model.StepContext) error {
 	log.Debugf(ctx,"Executing ONDC workbench receive step")
-	if err := s.workbench.WorkbenchReceiver(ctx,ctx.Request,ctx.Body); err != nil {
-		return fmt.Errorf("ondc workbench receive step failed: %w", err)
+	in := newWorkbenchInput(ctx)
+	var err error
+	if sw, ok := s.workbench.(definition.StructuredWorkbench); ok {
+		err = sw.WorkbenchReceiveInput(ctx, in)
+	} else {
+		err = s.workbench.WorkbenchReceiver(ctx,ctx.Request,ctx.Body)
+	}
+	if err != nil {
+		return s.receiveErr(err)
 	}
-	subscriberIDCookie , err := ctx.Request.Cookie("subscriber_id")
-	log.Debugf(ctx,"Extracted subscriber_id cookie: %v", subscriberIDCookie)
-	if err == nil {
-		ctx.SubID = subscriberIDCookie.Value
+	// The workbench may set the cookie on the request, so it is read back from there.
+	if c, err := ctx.Request.Cookie(s.cookie); err == nil {
+		log.Debugf(ctx,"Extracted %s cookie: %v", s.cookie, c.Value)
+		ctx.SubID = c.Value
+	}
+	router, ok := s.workbench.(definition.WorkbenchRouter)
+	if !ok {
+		return nil
+	}
+	route, err := router.WorkbenchRoute(ctx, ctx.Request, ctx.Body)
+	if err != nil {
+		return fmt.Errorf("ondc workbench route resolution failed: %w", err)
+	}
+	if route != nil {
+		log.Debugf(ctx, "Workbench set route: %s", route.TargetType)
+		ctx.Route = route
 	}
 	return nil
 }
 
+// receiveErr maps a receiver error to the configured failure behavior. Errors that are
+// already a WorkbenchErr keep the behavior chosen by the workbench.
+func (s *workbenchReceiveStep) receiveErr(err error) error {
+	var wbErr *model.WorkbenchErr
+	if s.failure == "" || errors.As(err, &wbErr) {
+		return fmt.Errorf("ondc workbench receive step failed: %w", err)
+	}
+	return &model.WorkbenchErr{
+		Err: model.Error{
+			Code:    strconv.Itoa(s.failureStatus),
+			Message: fmt.Sprintf("ondc workbench receive step failed: %v", err),
+		},
+		Behavior: s.failure,
+	}
+}
+
+// conditionalWorkbenchStep runs a workbench step only for requests matching the condition.
+type conditionalWorkbenchStep struct {
+	step definition.Step
+	cond WorkbenchCondition
+}
+
+// withWorkbenchCondition wraps step so it only runs for requests matching cond.
+// The step is returned unchanged when cond matches every request.
+func withWorkbenchCondition(step definition.Step, cond WorkbenchCondition) definition.Step {
+	if len(cond.Actions) == 0 && len(cond.Domains) == 0 && len(cond.ExcludeActions) == 0 {
+		return step
+	}
+	return &conditionalWorkbenchStep{step: step, cond: cond}
+}
+
+// Run executes the wrapped step if the request matches the condition.
+func (s *conditionalWorkbenchStep) Run(ctx *model.StepContext) error {
+	var action, domain string
+	if bCtx := ctx.BecknContext; bCtx != nil {
+		action, domain = bCtx.Action, bCtx.Domain
+	}
+	if !s.cond.matches(action, domain) {
+		log.Debugf(ctx, "Bypassing workbench for action %q, domain %q", action, domain)
+		return nil
+	}
+	return s.step.Run(ctx)
+}
+
+// matches reports whether the action and domain satisfy the condition.
+func (c WorkbenchCondition) matches(action, domain string) bool {
+	if slices.Contains(c.ExcludeActions, action) {
+		return false
+	}
+	if len(c.Actions) > 0 && !slices.Contains(c.Actions, action) {
+		return false
+	}
+	if len(c.Domains) > 0 && !slices.Contains(c.Domains, domain) {
+		return false
+	}
+	return true
+}
+
 type workbenchValidateContextStep struct {
-	workbench definition.OndcWorkbench
+	workbench  definition.OndcWorkbench
+	moduleName string
+	metrics    *HandlerMetrics
 }
 
 // newWorkbenchValidateContextStep creates and returns the workbench validate context step after validation.
-func newWorkbenchValidateContextStep(workbench definition.OndcWorkbench) (definition.Step, error) {
+func newWorkbenchValidateContextStep(workbench definition.OndcWorkbench, moduleName string) (definition.Step, error) {
 	if workbench == nil {
 		return nil, fmt.Errorf("invalid config: OndcWorkbench plugin not configured")
 	}
 	log.Debug(context.Background(), "adding ondc workbench process step")
-	return &workbenchValidateContextStep{workbench: workbench}, nil
+	metrics, _ := GetHandlerMetrics(context.Background())
+	return &workbenchValidateContextStep{
+		workbench:  workbench,
+		moduleName: moduleName,
+		metrics:    metrics,
+	}, nil
 }
 
 // Run executes the workbench process step.
 func (s *workbenchValidateContextStep) Run(ctx *model.StepContext) error {
-	if err := s.workbench.WorkbenchValidateContext(ctx,ctx.Request,ctx.Body); err != nil {
-		return fmt.Errorf("ondc workbench context validation step failed: %w", err)
+	start := time.Now()
+	var err error
+	if sw, ok := s.workbench.(definition.StructuredWorkbench); ok {
+		err = sw.WorkbenchValidateContextInput(ctx, newWorkbenchInput(ctx))
+	} else {
+		err = s.workbench.WorkbenchValidateContext(ctx,ctx.Request,ctx.Body)
 	}
-	return nil
+	if err != nil {
+		err = fmt.Errorf("ondc workbench context validation step failed: %w", err)
+	}
+	recordWorkbenchMetrics(ctx, s.metrics, s.moduleName, workbenchOpValidateContext, start, err)
+	return err
 }
 // endregion
\ No newline at end of file
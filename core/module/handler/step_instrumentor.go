@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/model"
@@ -25,8 +29,12 @@ type InstrumentedStep struct {
 	stepName   string
 	moduleName string
 	metrics    *StepMetrics
+	tracer     trace.Tracer
 }
 
+// stepTracerName is the instrumentation scope of the per-step spans.
+const stepTracerName = "github.com/beckn-one/beckn-onix/handler"
+
 // NewInstrumentedStep returns a telemetry enabled wrapper around a definition.Step.
 func NewInstrumentedStep(step StepRunner, stepName, moduleName string) (*InstrumentedStep, error) {
 	metrics, err := GetStepMetrics(context.Background())
@@ -39,6 +47,7 @@ func NewInstrumentedStep(step StepRunner, stepName, moduleName string) (*Instrum
 		stepName:   stepName,
 		moduleName: moduleName,
 		metrics:    metrics,
+		tracer:     otel.Tracer(stepTracerName),
 	}, nil
 }
 
@@ -46,14 +55,17 @@ type becknError interface {
 	BecknError() *model.Error
 }
 
-// Run executes the underlying step and records RED style metrics.
+// Run executes the underlying step within a span and records RED style metrics.
+// Metrics are recorded with the step span's context so a sampled span is attached
+// to them as an exemplar.
 func (is *InstrumentedStep) Run(ctx *model.StepContext) error {
 	if is.metrics == nil {
-		return is.step.Run(ctx)
+		_, err := is.runWithSpan(ctx)
+		return err
 	}
 
 	start := time.Now()
-	err := is.step.Run(ctx)
+	spanCtx, err := is.runWithSpan(ctx)
 	duration := time.Since(start).Seconds()
 
 	attrs := []attribute.KeyValue{
@@ -61,11 +73,12 @@ func (is *InstrumentedStep) Run(ctx *model.StepContext) error {
 		telemetry.AttrStep.String(is.stepName),
 		telemetry.AttrRole.String(string(ctx.Role)),
 	}
+	attrs = append(attrs, becknAttrs(ctx.BecknContext)...)
 
-	is.metrics.StepExecutionTotal.Add(ctx.Context, 1, metric.WithAttributes(attrs...))
-	is.metrics.StepExecutionDuration.Record(ctx.Context, duration, metric.WithAttributes(attrs...))
+	is.metrics.StepExecutionTotal.Add(spanCtx, 1, metric.WithAttributes(attrs...))
+	is.metrics.StepExecutionDuration.Record(spanCtx, duration, metric.WithAttributes(attrs...))
 
-	if err != nil {
+	if err != nil && !errors.Is(err, errAcked) {
 		errorType := fmt.Sprintf("%T", err)
 		var becknErr becknError
 		if errors.As(err, &becknErr) {
@@ -75,10 +88,90 @@ func (is *InstrumentedStep) Run(ctx *model.StepContext) error {
 		}
 
 		errorAttrs := append(attrs, telemetry.AttrErrorType.String(errorType))
-		is.metrics.StepErrorsTotal.Add(ctx.Context, 1, metric.WithAttributes(errorAttrs...))
+		is.metrics.StepErrorsTotal.Add(spanCtx, 1, metric.WithAttributes(errorAttrs...))
 		log.Errorf(ctx.Context, err, "Step %s failed", is.stepName)
 	}
 
 	return err
 }
 
+// runWithSpan runs the step inside a span named after it, recording any error on the span.
+// The step context carries the span while the step runs and is restored afterwards unless
+// the step replaced it. It returns the context holding the span.
+func (is *InstrumentedStep) runWithSpan(ctx *model.StepContext) (context.Context, error) {
+	if is.tracer == nil {
+		err := is.step.Run(ctx)
+		return ctx.Context, err
+	}
+	parent := ctx.Context
+	spanCtx, span := is.tracer.Start(parent, "step."+is.stepName,
+		trace.WithAttributes(
+			telemetry.AttrModule.String(is.moduleName),
+			telemetry.AttrStep.String(is.stepName),
+		))
+	defer span.End()
+
+	ctx.WithContext(spanCtx)
+	err := is.step.Run(ctx)
+	if ctx.Context == spanCtx {
+		ctx.WithContext(parent)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return spanCtx, err
+}
+
+// Attribute values used to bound the cardinality of the action and domain attributes.
+const (
+	attrValueUnknown = "unknown"
+	attrValueOther   = "other"
+)
+
+// becknActions lists the actions recorded verbatim; any other action is recorded as "other".
+var becknActions = map[string]bool{
+	"search": true, "select": true, "init": true, "confirm": true,
+	"status": true, "track": true, "cancel": true, "update": true,
+	"rating": true, "support": true, "issue": true, "issue_status": true,
+	"on_search": true, "on_select": true, "on_init": true, "on_confirm": true,
+	"on_status": true, "on_track": true, "on_cancel": true, "on_update": true,
+	"on_rating": true, "on_support": true, "on_issue": true, "on_issue_status": true,
+}
+
+// domainPattern matches well-formed domain codes such as "ONDC:RET10" or "nic2004:52110".
+var domainPattern = regexp.MustCompile(`^[A-Za-z0-9:._-]{1,32}$`)
+
+// becknAttrs returns the action and domain attributes for bCtx. Missing values are
+// recorded as "unknown" and unexpected ones as "other" to keep cardinality bounded.
+func becknAttrs(bCtx *model.BecknContext) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		telemetry.AttrAction.String(boundedAction(bCtx)),
+		telemetry.AttrDomain.String(boundedDomain(bCtx)),
+	}
+}
+
+// boundedAction returns the action of bCtx if it is a known Beckn action.
+func boundedAction(bCtx *model.BecknContext) string {
+	switch {
+	case bCtx == nil || bCtx.Action == "":
+		return attrValueUnknown
+	case becknActions[bCtx.Action]:
+		return bCtx.Action
+	default:
+		return attrValueOther
+	}
+}
+
+// boundedDomain returns the domain of bCtx if it is a well-formed domain code.
+func boundedDomain(bCtx *model.BecknContext) string {
+	switch {
+	case bCtx == nil || bCtx.Domain == "":
+		return attrValueUnknown
+	case domainPattern.MatchString(bCtx.Domain):
+		return bCtx.Domain
+	default:
+		return attrValueOther
+	}
+}
@@ -3,10 +3,19 @@ package handler
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/beckn-one/beckn-onix/pkg/model"
 	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,3 +59,130 @@ func TestInstrumentedStepError(t *testing.T) {
 	require.Error(t, step.Run(stepCtx))
 }
 
+
+func TestInstrumentedStepBecknAttributes(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	metrics, err := newStepMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	require.NoError(t, err)
+	step := &InstrumentedStep{step: stubStep{}, stepName: "validateSchema", moduleName: "bppTxnReceiver", metrics: metrics}
+
+	stepCtx := &model.StepContext{
+		Context:      context.Background(),
+		Role:         model.RoleBPP,
+		BecknContext: &model.BecknContext{Action: "search", Domain: "ONDC:RET10"},
+	}
+	require.NoError(t, step.Run(stepCtx))
+
+	require.Equal(t, int64(1), counterValue(t, reader, "onix_step_executions_total",
+		telemetry.AttrModule.String("bppTxnReceiver"),
+		telemetry.AttrStep.String("validateSchema"),
+		telemetry.AttrRole.String(string(model.RoleBPP)),
+		telemetry.AttrAction.String("search"),
+		telemetry.AttrDomain.String("ONDC:RET10"),
+	))
+}
+
+func TestBecknAttrs(t *testing.T) {
+	tests := []struct {
+		name       string
+		bCtx       *model.BecknContext
+		wantAction string
+		wantDomain string
+	}{
+		{name: "no context", wantAction: "unknown", wantDomain: "unknown"},
+		{name: "empty values", bCtx: &model.BecknContext{}, wantAction: "unknown", wantDomain: "unknown"},
+		{name: "known values", bCtx: &model.BecknContext{Action: "on_search", Domain: "ONDC:TRV10"}, wantAction: "on_search", wantDomain: "ONDC:TRV10"},
+		{name: "unexpected action", bCtx: &model.BecknContext{Action: "search-" + strings.Repeat("x", 8), Domain: "nic2004:52110"}, wantAction: "other", wantDomain: "nic2004:52110"},
+		{name: "malformed domain", bCtx: &model.BecknContext{Action: "init", Domain: "ONDC RET10 <script>"}, wantAction: "init", wantDomain: "other"},
+		{name: "overlong domain", bCtx: &model.BecknContext{Action: "init", Domain: strings.Repeat("a", 33)}, wantAction: "init", wantDomain: "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := becknAttrs(tt.bCtx)
+			require.Equal(t, []attribute.KeyValue{
+				telemetry.AttrAction.String(tt.wantAction),
+				telemetry.AttrDomain.String(tt.wantDomain),
+			}, attrs)
+		})
+	}
+}
+
+func TestInstrumentedStepSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := tp.Tracer(stepTracerName)
+
+	var spanInStep trace.SpanContext
+	okStep := &InstrumentedStep{
+		step: stepFunc(func(ctx *model.StepContext) error {
+			spanInStep = trace.SpanContextFromContext(ctx)
+			return nil
+		}),
+		stepName:   "validateSign",
+		moduleName: "bapTxnReceiver",
+		tracer:     tracer,
+	}
+	failStep := &InstrumentedStep{step: stubStep{err: errors.New("boom")}, stepName: "validateSchema", moduleName: "bapTxnReceiver", tracer: tracer}
+
+	parent := context.Background()
+	stepCtx := &model.StepContext{Context: parent, Role: model.RoleBAP}
+	require.NoError(t, okStep.Run(stepCtx))
+	require.Error(t, failStep.Run(stepCtx))
+	require.Equal(t, parent, stepCtx.Context, "step context should be restored after the span ends")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "step.validateSign", spans[0].Name())
+	require.Equal(t, spans[0].SpanContext().SpanID(), spanInStep.SpanID())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Empty(t, spans[0].Events())
+	require.Contains(t, spans[0].Attributes(), telemetry.AttrStep.String("validateSign"))
+	require.Contains(t, spans[0].Attributes(), telemetry.AttrModule.String("bapTxnReceiver"))
+
+	require.Equal(t, "step.validateSchema", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Equal(t, "boom", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+	require.Equal(t, "exception", spans[1].Events()[0].Name)
+}
+
+func TestInstrumentedStepExemplars(t *testing.T) {
+	tests := []struct {
+		name    string
+		sampler sdktrace.Sampler
+		want    bool
+	}{
+		{name: "sampled span", sampler: sdktrace.AlwaysSample(), want: true},
+		{name: "unsampled span", sampler: sdktrace.NeverSample()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			metrics, err := newStepMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+			require.NoError(t, err)
+
+			var traceID trace.TraceID
+			step := &InstrumentedStep{
+				step: stepFunc(func(ctx *model.StepContext) error {
+					traceID = trace.SpanContextFromContext(ctx).TraceID()
+					return nil
+				}),
+				stepName:   "validateSign",
+				moduleName: "bapTxnReceiver",
+				metrics:    metrics,
+				tracer:     testTracer(t, tt.sampler),
+			}
+			require.NoError(t, step.Run(&model.StepContext{Context: context.Background(), Role: model.RoleBAP}))
+
+			ids := exemplarTraceIDs(t, reader, "onix_step_execution_duration_seconds")
+			if !tt.want {
+				assert.Empty(t, ids)
+				return
+			}
+			assert.Equal(t, []string{traceID.String()}, ids)
+		})
+	}
+}
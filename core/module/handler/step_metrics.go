@@ -25,13 +25,13 @@ var (
 // GetStepMetrics lazily initializes step metric instruments and returns a cached reference.
 func GetStepMetrics(ctx context.Context) (*StepMetrics, error) {
 	stepMetricsOnce.Do(func() {
-		stepMetricsInstance, stepMetricsErr = newStepMetrics()
+		stepMetricsInstance, stepMetricsErr = newStepMetrics(otel.GetMeterProvider())
 	})
 	return stepMetricsInstance, stepMetricsErr
 }
 
-func newStepMetrics() (*StepMetrics, error) {
-	meter := otel.GetMeterProvider().Meter(
+func newStepMetrics(mp metric.MeterProvider) (*StepMetrics, error) {
+	meter := mp.Meter(
 		"github.com/beckn-one/beckn-onix/telemetry",
 		metric.WithInstrumentationVersion("1.0.0"),
 	)
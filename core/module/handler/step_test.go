@@ -0,0 +1,890 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/beckn-one/beckn-onix/pkg/response"
+	"github.com/beckn-one/beckn-onix/pkg/telemetry"
+)
+
+// mockOndcValidator records calls made to the OndcValidator interface.
+type mockOndcValidator struct {
+	validateErr error
+	saveErr     error
+	calls       []string
+}
+
+func (m *mockOndcValidator) ValidatePayload(_ context.Context, _ *url.URL, _ []byte) error {
+	m.calls = append(m.calls, "validate")
+	return m.validateErr
+}
+
+func (m *mockOndcValidator) SaveValidationData(_ context.Context, _ *url.URL, _ []byte) error {
+	m.calls = append(m.calls, "save")
+	return m.saveErr
+}
+
+// withPostResponseHooks seeds the request context with a post-response hook list,
+// mirroring what PostResponseMiddleware does.
+func withPostResponseHooks(r *http.Request) (*http.Request, *[]PostResponseHook) {
+	hooks := &[]PostResponseHook{}
+	return r.WithContext(context.WithValue(r.Context(), PostResponseKey{}, hooks)), hooks
+}
+
+func newTestStepCtx(r *http.Request, body []byte) *model.StepContext {
+	bCtx, _ := model.ParseBecknContext(body)
+	return &model.StepContext{
+		Context:      r.Context(),
+		Request:      r,
+		Body:         body,
+		RespHeader:   http.Header{},
+		BecknContext: bCtx,
+	}
+}
+
+func TestValidateOndcCallSaveStepModes(t *testing.T) {
+	body := testBody("search", "ONDC:RET10")
+
+	t.Run("sync saves inline", func(t *testing.T) {
+		v := &mockOndcValidator{}
+		step, err := newValidateOndcCallSaveStep(v, OndcSaveModeSync)
+		require.NoError(t, err)
+		r, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body))))
+
+		require.NoError(t, step.Run(newTestStepCtx(r, body)))
+
+		assert.Equal(t, []string{"save"}, v.calls)
+		assert.Empty(t, *hooks)
+	})
+
+	t.Run("default mode is sync", func(t *testing.T) {
+		v := &mockOndcValidator{}
+		step, err := newValidateOndcCallSaveStep(v, "")
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body)))
+
+		require.NoError(t, step.Run(newTestStepCtx(r, body)))
+
+		assert.Equal(t, []string{"save"}, v.calls)
+	})
+
+	t.Run("async saves post response", func(t *testing.T) {
+		v := &mockOndcValidator{}
+		step, err := newValidateOndcCallSaveStep(v, OndcSaveModeAsync)
+		require.NoError(t, err)
+		r, hooks := withPostResponseHooks(httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body))))
+
+		require.NoError(t, step.Run(newTestStepCtx(r, body)))
+		assert.Empty(t, v.calls, "save must not run before the response is sent")
+		require.Len(t, *hooks, 1)
+
+		(*hooks)[0]()
+		assert.Equal(t, []string{"save"}, v.calls)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := newValidateOndcCallSaveStep(&mockOndcValidator{}, "later")
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateOndcAndSaveStep(t *testing.T) {
+	body := testBody("search", "ONDC:RET10")
+	tests := []struct {
+		name          string
+		validateErr   error
+		saveOnFailure bool
+		wantCalls     []string
+		wantErr       bool
+	}{
+		{name: "validate pass then save", wantCalls: []string{"validate", "save"}},
+		{name: "validate fail skips save", validateErr: assert.AnError, wantCalls: []string{"validate"}, wantErr: true},
+		{name: "validate fail saves anyway", validateErr: assert.AnError, saveOnFailure: true, wantCalls: []string{"validate", "save"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &mockOndcValidator{validateErr: tt.validateErr}
+			step, err := newValidateOndcAndSaveStep(v, &OndcConfig{SaveOnValidationFailure: tt.saveOnFailure}, DefaultProtocolValidationCookie)
+			require.NoError(t, err)
+			r := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body)))
+
+			err = step.Run(newTestStepCtx(r, body))
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.validateErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, v.calls)
+		})
+	}
+}
+
+func TestValidateOndcAndSaveStepSaveError(t *testing.T) {
+	body := testBody("search", "ONDC:RET10")
+	v := &mockOndcValidator{saveErr: assert.AnError}
+	step, err := newValidateOndcAndSaveStep(v, &OndcConfig{}, DefaultProtocolValidationCookie)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body)))
+
+	assert.ErrorIs(t, step.Run(newTestStepCtx(r, body)), assert.AnError)
+}
+
+func TestValidateOndcStepMetrics(t *testing.T) {
+	body := testBody("search", "ONDC:RET10")
+	tests := []struct {
+		name       string
+		cookie     *http.Cookie
+		wantStatus string
+		wantReason string
+		wantCalls  []string
+	}{
+		{name: "no cookie executes", wantStatus: "executed", wantReason: ondcReasonDefault, wantCalls: []string{"validate"}},
+		{name: "cookie true executes", cookie: &http.Cookie{Name: "protocol_validation", Value: "true"}, wantStatus: "executed", wantReason: ondcReasonCookieEnabled, wantCalls: []string{"validate"}},
+		{name: "cookie false skips", cookie: &http.Cookie{Name: "protocol_validation", Value: "false"}, wantStatus: "skipped", wantReason: ondcReasonCookieDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, reader := newTestHandlerMetrics(t)
+			v := &mockOndcValidator{}
+			step := &validateOndcStep{validator: v, metrics: metrics, cookie: DefaultProtocolValidationCookie}
+			r := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(string(body)))
+			if tt.cookie != nil {
+				r.AddCookie(tt.cookie)
+			}
+
+			require.NoError(t, step.Run(newTestStepCtx(r, body)))
+
+			assert.Equal(t, tt.wantCalls, v.calls)
+			assert.Equal(t, int64(1), counterValue(t, reader, "onix_ondc_validations_total",
+				telemetry.AttrStatus.String(tt.wantStatus),
+				telemetry.AttrReason.String(tt.wantReason),
+			))
+		})
+	}
+}
+
+// mockWorkbench implements OndcWorkbench without routing.
+type mockWorkbench struct {
+	receiveErr  error
+	validateErr error
+}
+
+func (m *mockWorkbench) WorkbenchReceiver(_ context.Context, _ *http.Request, _ []byte) error {
+	return m.receiveErr
+}
+
+func (m *mockWorkbench) WorkbenchValidateContext(_ context.Context, _ *http.Request, _ []byte) error {
+	return m.validateErr
+}
+
+// mockRoutingWorkbench additionally implements WorkbenchRouter.
+type mockRoutingWorkbench struct {
+	mockWorkbench
+	route    *model.Route
+	routeErr error
+}
+
+func (m *mockRoutingWorkbench) WorkbenchRoute(_ context.Context, _ *http.Request, _ []byte) (*model.Route, error) {
+	return m.route, m.routeErr
+}
+
+// mockRouter counts calls to the Router interface.
+type mockRouter struct {
+	route *model.Route
+	calls int
+}
+
+func (m *mockRouter) Route(_ context.Context, _ *url.URL, _ []byte, _ *http.Request) (*model.Route, error) {
+	m.calls++
+	return m.route, nil
+}
+
+func TestWorkbenchReceiveStepRouting(t *testing.T) {
+	wbRoute := &model.Route{TargetType: "url", URL: &url.URL{Scheme: "https", Host: "bpp.example.com"}}
+	routerRoute := &model.Route{TargetType: "publisher", PublisherID: "topic"}
+
+	tests := []struct {
+		name       string
+		workbench  definition.OndcWorkbench
+		wantRoute  *model.Route
+		wantRouter int
+	}{
+		{
+			name:       "workbench dictates route",
+			workbench:  &mockRoutingWorkbench{route: wbRoute},
+			wantRoute:  wbRoute,
+			wantRouter: 0,
+		},
+		{
+			name:       "routing workbench defers to router",
+			workbench:  &mockRoutingWorkbench{},
+			wantRoute:  routerRoute,
+			wantRouter: 1,
+		},
+		{
+			name:       "workbench without routing",
+			workbench:  &mockWorkbench{},
+			wantRoute:  routerRoute,
+			wantRouter: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receive, err := newWorkbenchReceiveStep(tt.workbench, DefaultSubscriberIDCookie, "test-module", &WorkbenchConfig{})
+			require.NoError(t, err)
+			router := &mockRouter{route: routerRoute}
+			addRoute, err := newAddRouteStep(router)
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodPost, "/bpp/caller/search", nil)
+			r.AddCookie(&http.Cookie{Name: "subscriber_id", Value: "bap.example.com"})
+			ctx := newTestStepCtx(r, []byte(`{}`))
+
+			require.NoError(t, receive.Run(ctx))
+			require.NoError(t, addRoute.Run(ctx))
+
+			assert.Equal(t, "bap.example.com", ctx.SubID)
+			assert.Equal(t, tt.wantRouter, router.calls)
+			require.NotNil(t, ctx.Route)
+			assert.Equal(t, tt.wantRoute.TargetType, ctx.Route.TargetType)
+			assert.Equal(t, tt.wantRoute.URL, ctx.Route.URL)
+		})
+	}
+}
+
+func TestWorkbenchReceiveStepRouteError(t *testing.T) {
+	step, err := newWorkbenchReceiveStep(&mockRoutingWorkbench{routeErr: assert.AnError}, DefaultSubscriberIDCookie, "test-module", &WorkbenchConfig{})
+	require.NoError(t, err)
+
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{}`))
+	err = step.Run(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, ctx.Route)
+}
+
+func TestCookieConfigWithDefaults(t *testing.T) {
+	got := CookieConfig{HeaderValidation: "hv"}.withDefaults()
+
+	assert.Equal(t, CookieConfig{
+		SubscriberID:       DefaultSubscriberIDCookie,
+		HeaderValidation:   "hv",
+		ProtocolValidation: DefaultProtocolValidationCookie,
+	}, got)
+}
+
+func TestStepsHonorCustomCookieNames(t *testing.T) {
+	cookies := CookieConfig{
+		SubscriberID:       "wb_sub",
+		HeaderValidation:   "wb_headers",
+		ProtocolValidation: "wb_protocol",
+	}
+
+	t.Run("validateSign", func(t *testing.T) {
+		step := &validateSignStep{cookie: cookies.HeaderValidation}
+		newReq := func(cookie string) *model.StepContext {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Header.Set(model.AuthHeaderSubscriber, "malformed")
+			r.AddCookie(&http.Cookie{Name: cookie, Value: "false"})
+			return newTestStepCtx(r, []byte(`{}`))
+		}
+
+		assert.NoError(t, step.Run(newReq(cookies.HeaderValidation)))
+		assert.Error(t, step.Run(newReq(DefaultHeaderValidationCookie)))
+	})
+
+	t.Run("validateOndcPayload", func(t *testing.T) {
+		v := &mockOndcValidator{}
+		step, err := newValidateOndcStep(v, cookies.ProtocolValidation)
+		require.NoError(t, err)
+
+		for _, name := range []string{cookies.ProtocolValidation, DefaultProtocolValidationCookie} {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.AddCookie(&http.Cookie{Name: name, Value: "false"})
+			require.NoError(t, step.Run(newTestStepCtx(r, []byte(`{}`))))
+		}
+
+		assert.Equal(t, []string{"validate"}, v.calls)
+	})
+
+	t.Run("ondcWorkbenchReceiver", func(t *testing.T) {
+		step, err := newWorkbenchReceiveStep(&mockWorkbench{}, cookies.SubscriberID, "test-module", &WorkbenchConfig{})
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.AddCookie(&http.Cookie{Name: DefaultSubscriberIDCookie, Value: "ignored.example.com"})
+		r.AddCookie(&http.Cookie{Name: cookies.SubscriberID, Value: "bap.example.com"})
+		ctx := newTestStepCtx(r, []byte(`{}`))
+		require.NoError(t, step.Run(ctx))
+
+		assert.Equal(t, "bap.example.com", ctx.SubID)
+	})
+}
+
+func TestWorkbenchStepMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		wb        *mockWorkbench
+		wantErr   bool
+		newStep   func(wb *mockWorkbench, m *HandlerMetrics) definition.Step
+		operation string
+	}{
+		{
+			name:      "receive success",
+			wb:        &mockWorkbench{},
+			operation: workbenchOpReceive,
+			newStep: func(wb *mockWorkbench, m *HandlerMetrics) definition.Step {
+				return &workbenchReceiveStep{workbench: wb, cookie: DefaultSubscriberIDCookie, moduleName: "bapTxnReceiver", metrics: m}
+			},
+		},
+		{
+			name:      "receive failure",
+			wb:        &mockWorkbench{receiveErr: assert.AnError},
+			wantErr:   true,
+			operation: workbenchOpReceive,
+			newStep: func(wb *mockWorkbench, m *HandlerMetrics) definition.Step {
+				return &workbenchReceiveStep{workbench: wb, cookie: DefaultSubscriberIDCookie, moduleName: "bapTxnReceiver", metrics: m}
+			},
+		},
+		{
+			name:      "validate context success",
+			wb:        &mockWorkbench{},
+			operation: workbenchOpValidateContext,
+			newStep: func(wb *mockWorkbench, m *HandlerMetrics) definition.Step {
+				return &workbenchValidateContextStep{workbench: wb, moduleName: "bapTxnReceiver", metrics: m}
+			},
+		},
+		{
+			name:      "validate context failure",
+			wb:        &mockWorkbench{validateErr: assert.AnError},
+			wantErr:   true,
+			operation: workbenchOpValidateContext,
+			newStep: func(wb *mockWorkbench, m *HandlerMetrics) definition.Step {
+				return &workbenchValidateContextStep{workbench: wb, moduleName: "bapTxnReceiver", metrics: m}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, reader := newTestHandlerMetrics(t)
+			step := tt.newStep(tt.wb, metrics)
+
+			err := step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{}`)))
+
+			status := "success"
+			if tt.wantErr {
+				require.ErrorIs(t, err, assert.AnError)
+				status = "failed"
+			} else {
+				require.NoError(t, err)
+			}
+			attrs := []attribute.KeyValue{
+				telemetry.AttrModule.String("bapTxnReceiver"),
+				telemetry.AttrOperation.String(tt.operation),
+				telemetry.AttrStatus.String(status),
+			}
+			assert.Equal(t, int64(1), counterValue(t, reader, "onix_workbench_operations_total", attrs...))
+			assert.Equal(t, uint64(1), histogramCount(t, reader, "onix_workbench_duration_seconds", attrs...))
+		})
+	}
+}
+
+// mockStructuredWorkbench implements StructuredWorkbench and records the inputs it receives.
+type mockStructuredWorkbench struct {
+	mockWorkbench
+	inputs []*definition.WorkbenchInput
+}
+
+func (m *mockStructuredWorkbench) WorkbenchReceiver(_ context.Context, _ *http.Request, _ []byte) error {
+	return errors.New("legacy WorkbenchReceiver should not be called")
+}
+
+func (m *mockStructuredWorkbench) WorkbenchValidateContext(_ context.Context, _ *http.Request, _ []byte) error {
+	return errors.New("legacy WorkbenchValidateContext should not be called")
+}
+
+func (m *mockStructuredWorkbench) WorkbenchReceiveInput(_ context.Context, in *definition.WorkbenchInput) error {
+	m.inputs = append(m.inputs, in)
+	return m.receiveErr
+}
+
+func (m *mockStructuredWorkbench) WorkbenchValidateContextInput(_ context.Context, in *definition.WorkbenchInput) error {
+	m.inputs = append(m.inputs, in)
+	return m.validateErr
+}
+
+func TestWorkbenchStepsPassParsedInput(t *testing.T) {
+	wb := &mockStructuredWorkbench{}
+	receive, err := newWorkbenchReceiveStep(wb, DefaultSubscriberIDCookie, "test-module", &WorkbenchConfig{})
+	require.NoError(t, err)
+	validate, err := newWorkbenchValidateContextStep(wb, "test-module")
+	require.NoError(t, err)
+
+	body := testBody("search", "ONDC:RET10")
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(&http.Cookie{Name: DefaultSubscriberIDCookie, Value: "bap.example.com"})
+	r.AddCookie(&http.Cookie{Name: DefaultProtocolValidationCookie, Value: "false"})
+	ctx := newTestStepCtx(r, body)
+
+	require.NoError(t, receive.Run(ctx))
+	require.NoError(t, validate.Run(ctx))
+
+	require.Len(t, wb.inputs, 2)
+	for _, in := range wb.inputs {
+		require.NotNil(t, in.Context)
+		assert.Equal(t, "search", in.Context.Action)
+		assert.Equal(t, "ONDC:RET10", in.Context.Domain)
+		assert.Equal(t, map[string]string{
+			DefaultSubscriberIDCookie:       "bap.example.com",
+			DefaultProtocolValidationCookie: "false",
+		}, in.Cookies)
+		assert.Equal(t, body, in.Body)
+		assert.Same(t, r, in.Request)
+	}
+	assert.Equal(t, "bap.example.com", ctx.SubID)
+}
+
+func TestWorkbenchInputWithUnparsableBody(t *testing.T) {
+	wb := &mockStructuredWorkbench{}
+	step, err := newWorkbenchReceiveStep(wb, DefaultSubscriberIDCookie, "test-module", &WorkbenchConfig{})
+	require.NoError(t, err)
+
+	require.NoError(t, step.Run(newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte("not json"))))
+
+	require.Len(t, wb.inputs, 1)
+	assert.Nil(t, wb.inputs[0].Context)
+	assert.Empty(t, wb.inputs[0].Cookies)
+}
+
+// cookieSettingWorkbench sets the subscriber cookie on the request it receives.
+type cookieSettingWorkbench struct {
+	mockWorkbench
+	subscriberID string
+}
+
+func (m *cookieSettingWorkbench) WorkbenchReceiver(_ context.Context, r *http.Request, _ []byte) error {
+	r.AddCookie(&http.Cookie{Name: DefaultSubscriberIDCookie, Value: m.subscriberID})
+	return nil
+}
+
+func TestWorkbenchReceiveStepReadsCookieSetByWorkbench(t *testing.T) {
+	step, err := newWorkbenchReceiveStep(&cookieSettingWorkbench{subscriberID: "bpp.example.com"}, DefaultSubscriberIDCookie, "test-module", &WorkbenchConfig{})
+	require.NoError(t, err)
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), testBody("search", "ONDC:RET10"))
+	ctx.SubID = "bap.example.com"
+
+	require.NoError(t, step.Run(ctx))
+
+	assert.Equal(t, "bpp.example.com", ctx.SubID)
+}
+
+func TestWorkbenchReceiveStepFailureBehavior(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        WorkbenchConfig
+		receiveErr error
+		wantStatus int
+		wantAck    model.Status
+		wantCode   string
+	}{
+		{
+			name:       "default is internal error",
+			receiveErr: assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+			wantAck:    model.StatusNACK,
+			wantCode:   http.StatusText(http.StatusInternalServerError),
+		},
+		{
+			name:       "ack and drop",
+			cfg:        WorkbenchConfig{ReceiverFailure: WorkbenchFailureAck},
+			receiveErr: assert.AnError,
+			wantStatus: http.StatusOK,
+			wantAck:    model.StatusACK,
+		},
+		{
+			name:       "nack",
+			cfg:        WorkbenchConfig{ReceiverFailure: WorkbenchFailureNack, ReceiverFailureStatus: http.StatusBadRequest},
+			receiveErr: assert.AnError,
+			wantStatus: http.StatusOK,
+			wantAck:    model.StatusNACK,
+			wantCode:   "400",
+		},
+		{
+			name:       "http status",
+			cfg:        WorkbenchConfig{ReceiverFailure: WorkbenchFailureHTTP, ReceiverFailureStatus: http.StatusPreconditionFailed},
+			receiveErr: assert.AnError,
+			wantStatus: http.StatusPreconditionFailed,
+			wantAck:    model.StatusNACK,
+			wantCode:   "412",
+		},
+		{
+			name:       "http status defaults to 500",
+			cfg:        WorkbenchConfig{ReceiverFailure: WorkbenchFailureHTTP},
+			receiveErr: assert.AnError,
+			wantStatus: http.StatusInternalServerError,
+			wantAck:    model.StatusNACK,
+			wantCode:   "500",
+		},
+		{
+			name:       "workbench error keeps its own behavior",
+			cfg:        WorkbenchConfig{ReceiverFailure: WorkbenchFailureAck},
+			receiveErr: model.NewWorkbenchErr("UNAUTHORIZED", "unknown subscriber", model.WorkbenchBehaviorHTTP, nil),
+			wantStatus: http.StatusUnauthorized,
+			wantAck:    model.StatusNACK,
+			wantCode:   "401",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newWorkbenchReceiveStep(&mockWorkbench{receiveErr: tt.receiveErr}, DefaultSubscriberIDCookie, "test-module", &tt.cfg)
+			require.NoError(t, err)
+
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{}`))
+			err = step.Run(ctx)
+			require.Error(t, err)
+
+			rec := httptest.NewRecorder()
+			response.SendNack(ctx, rec, err)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var resp model.Response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantAck, resp.Message.Ack.Status)
+			if tt.wantCode == "" {
+				assert.Nil(t, resp.Error)
+				return
+			}
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, tt.wantCode, resp.Error.Code)
+		})
+	}
+}
+
+func TestNewWorkbenchReceiveStepInvalidFailureConfig(t *testing.T) {
+	for _, cfg := range []WorkbenchConfig{
+		{ReceiverFailure: "retry"},
+		{ReceiverFailure: WorkbenchFailureHTTP, ReceiverFailureStatus: http.StatusOK},
+	} {
+		_, err := newWorkbenchReceiveStep(&mockWorkbench{}, DefaultSubscriberIDCookie, "test-module", &cfg)
+		assert.Error(t, err)
+	}
+}
+
+// countingStep counts how often it runs.
+type countingStep struct {
+	runs int
+}
+
+func (s *countingStep) Run(_ *model.StepContext) error {
+	s.runs++
+	return nil
+}
+
+func TestWithWorkbenchCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		cond     WorkbenchCondition
+		action   string
+		domain   string
+		wantRuns int
+	}{
+		{name: "no condition runs", action: "search", domain: "ONDC:RET10", wantRuns: 1},
+		{name: "included action runs", cond: WorkbenchCondition{Actions: []string{"search", "select"}}, action: "select", domain: "ONDC:RET10", wantRuns: 1},
+		{name: "other action bypassed", cond: WorkbenchCondition{Actions: []string{"search"}}, action: "confirm", domain: "ONDC:RET10", wantRuns: 0},
+		{name: "excluded action bypassed", cond: WorkbenchCondition{ExcludeActions: []string{"on_status"}}, action: "on_status", domain: "ONDC:RET10", wantRuns: 0},
+		{name: "non-excluded action runs", cond: WorkbenchCondition{ExcludeActions: []string{"on_status"}}, action: "status", domain: "ONDC:RET10", wantRuns: 1},
+		{name: "included domain runs", cond: WorkbenchCondition{Domains: []string{"ONDC:RET10"}}, action: "search", domain: "ONDC:RET10", wantRuns: 1},
+		{name: "other domain bypassed", cond: WorkbenchCondition{Domains: []string{"ONDC:RET10"}}, action: "search", domain: "ONDC:LOG10", wantRuns: 0},
+		{name: "exclude wins over include", cond: WorkbenchCondition{Actions: []string{"search"}, ExcludeActions: []string{"search"}}, action: "search", domain: "ONDC:RET10", wantRuns: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countingStep{}
+			step := withWorkbenchCondition(inner, tt.cond)
+
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/", nil), testBody(tt.action, tt.domain))
+			require.NoError(t, step.Run(ctx))
+
+			assert.Equal(t, tt.wantRuns, inner.runs)
+		})
+	}
+}
+
+func TestWithWorkbenchConditionNoCondition(t *testing.T) {
+	inner := &countingStep{}
+	assert.Same(t, definition.Step(inner), withWorkbenchCondition(inner, WorkbenchCondition{}))
+}
+
+func TestStepBecknContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	parsed := &model.BecknContext{Action: "select"}
+	ctx := newTestStepCtx(r, testBody("search", "ONDC:RET10"))
+	ctx.BecknContext = parsed
+	bCtx, err := stepBecknContext(ctx)
+	require.NoError(t, err)
+	assert.Same(t, parsed, bCtx, "the context parsed with the request is reused")
+
+	ctx.BecknContext = nil
+	bCtx, err = stepBecknContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "search", bCtx.Action)
+
+	_, err = stepBecknContext(newTestStepCtx(r, []byte("not json")))
+	var badReq *model.BadReqErr
+	assert.ErrorAs(t, err, &badReq)
+}
+
+func TestValidateTimestampStep(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		timestamp string
+		wantErr   string
+	}{
+		{name: "fresh", timestamp: "2026-10-16T11:59:00.000Z"},
+		{name: "fresh with offset", timestamp: "2026-10-16T17:29:00+05:30"},
+		{name: "within skew", timestamp: "2026-10-16T12:00:20Z"},
+		{name: "stale", timestamp: "2026-10-16T11:50:00Z", wantErr: "is older than 5m0s"},
+		{name: "future", timestamp: "2026-10-16T12:05:00Z", wantErr: "is more than 30s in the future"},
+		{name: "malformed", timestamp: "16/10/2026 12:00", wantErr: "is not an RFC 3339 time"},
+		{name: "missing", wantErr: "context.timestamp is missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newValidateTimestampStep(TimestampConfig{}, fakeClock{now})
+			require.NoError(t, err)
+			body := fmt.Sprintf(`{"context":{"action":"search","timestamp":%q}}`, tt.timestamp)
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bap/caller/search", nil), []byte(body))
+
+			err = step.Run(ctx)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+			var badReq *model.BadReqErr
+			assert.ErrorAs(t, err, &badReq)
+		})
+	}
+}
+
+func TestNewValidateTimestampStep(t *testing.T) {
+	step, err := newValidateTimestampStep(TimestampConfig{MaxAge: time.Minute}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, step.(*validateTimestampStep).maxAge)
+	assert.Equal(t, defaultTimestampMaxSkew, step.(*validateTimestampStep).maxSkew)
+
+	_, err = newValidateTimestampStep(TimestampConfig{MaxSkew: -time.Second}, nil)
+	assert.Error(t, err)
+}
+
+// fakeRegistry serves subscriptions from a map keyed by subscriber ID and counts lookups.
+type fakeRegistry struct {
+	subs    map[string][]model.Subscription
+	err     error
+	lookups int
+}
+
+func (r *fakeRegistry) Lookup(_ context.Context, req *model.Subscription) ([]model.Subscription, error) {
+	r.lookups++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.subs[req.SubscriberID], nil
+}
+
+func TestEnrichRegistryStep(t *testing.T) {
+	registry := &fakeRegistry{subs: map[string][]model.Subscription{
+		"bpp.example.com": {
+			{Subscriber: model.Subscriber{SubscriberID: "bpp.example.com", Type: "BPP"}, KeyID: "old", Status: "EXPIRED"},
+			{Subscriber: model.Subscriber{SubscriberID: "bpp.example.com", URL: "https://bpp.example.com/beckn", Type: "BPP"}, KeyID: "k1", Status: "SUBSCRIBED"},
+		},
+	}}
+	cache := newMemCache()
+	step, err := newEnrichRegistryStep(registry, cache, EnrichRegistryConfig{})
+	require.NoError(t, err)
+	run := func(subID string) (*model.StepContext, error) {
+		ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil), []byte(`{}`))
+		ctx.SubID = subID
+		return ctx, step.Run(ctx)
+	}
+
+	ctx, err := run("bpp.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, ctx.Subscriber)
+	assert.Equal(t, "k1", ctx.Subscriber.KeyID, "the subscribed record is preferred")
+	assert.Equal(t, "https://bpp.example.com/beckn", ctx.Subscriber.URL)
+	assert.Equal(t, "BPP", ctx.Subscriber.Type)
+
+	ctx, err = run("bpp.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", ctx.Subscriber.KeyID)
+	assert.Equal(t, 1, registry.lookups, "the second run is served from the cache")
+
+	cache.now = cache.now.Add(defaultEnrichRegistryTTL)
+	_, err = run("bpp.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, registry.lookups, "an expired record is looked up again")
+
+	ctx, err = run("unknown.example.com")
+	var notFound *model.NotFoundErr
+	assert.ErrorAs(t, err, &notFound)
+	assert.Nil(t, ctx.Subscriber)
+
+	registry.err = errors.New("registry unavailable")
+	_, err = run("other.example.com")
+	assert.ErrorContains(t, err, "registry unavailable")
+}
+
+func TestValidateSignStepSubscriberStatus(t *testing.T) {
+	registry := &fakeRegistry{subs: map[string][]model.Subscription{
+		"active.example.com":  {{Subscriber: model.Subscriber{SubscriberID: "active.example.com"}, Status: "SUBSCRIBED"}},
+		"revoked.example.com": {{Subscriber: model.Subscriber{SubscriberID: "revoked.example.com"}, Status: "UNSUBSCRIBED"}},
+	}}
+	cache := newMemCache()
+	status, err := newSubscriberStatusCheck(registry, cache, SubscriberStatusConfig{Enabled: true})
+	require.NoError(t, err)
+	km := &mapKeyManager{keys: map[string]string{
+		"active.example.com":  "good",
+		"revoked.example.com": "good",
+		"unknown.example.com": "good",
+		"other.example.com":   "good",
+	}}
+	step, err := newValidateSignStep(&keySignValidator{valid: "good"}, km, DefaultHeaderValidationCookie, SignatureConfig{}, nil, status)
+	require.NoError(t, err)
+	run := func(subscriberID string) error {
+		r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+		r.Header.Set(model.AuthHeaderSubscriber, `Signature keyId="`+subscriberID+`|k1|ed25519",signature="sig"`)
+		return step.Run(newTestStepCtx(r, []byte(`{}`)))
+	}
+
+	tests := []struct {
+		name         string
+		subscriberID string
+		wantErr      string
+	}{
+		{name: "active", subscriberID: "active.example.com"},
+		{name: "revoked", subscriberID: "revoked.example.com", wantErr: `subscriber revoked.example.com has registry status "UNSUBSCRIBED", want one of SUBSCRIBED`},
+		{name: "unknown", subscriberID: "unknown.example.com", wantErr: "subscriber unknown.example.com not found in registry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(tt.subscriberID)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var signErr *model.SignValidationErr
+			require.ErrorAs(t, err, &signErr)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	lookups := registry.lookups
+	require.NoError(t, run("active.example.com"))
+	assert.Equal(t, lookups, registry.lookups, "the status is served from the cache")
+
+	registry.err = errors.New("registry unavailable")
+	err = run("other.example.com")
+	var signErr *model.SignValidationErr
+	assert.False(t, errors.As(err, &signErr), "registry failures are not signature failures")
+	assert.ErrorContains(t, err, "registry unavailable")
+}
+
+func TestValidateSignStepSubscriberFromSignature(t *testing.T) {
+	km := &mapKeyManager{keys: map[string]string{"bap.example.com": "good"}}
+	tests := []struct {
+		name      string
+		enabled   bool
+		auth      string
+		wantSubID string
+	}{
+		{name: "validated signer", enabled: true, auth: `Signature keyId="bap.example.com|k1|ed25519",signature="sig"`, wantSubID: "bap.example.com"},
+		{name: "disabled", auth: `Signature keyId="bap.example.com|k1|ed25519",signature="sig"`, wantSubID: "bpp.example.com"},
+		{name: "failed validation", enabled: true, auth: `Signature keyId="evil.example.com|k1|ed25519",signature="sig"`, wantSubID: "bpp.example.com"},
+		{name: "unsigned", enabled: true, wantSubID: "bpp.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newValidateSignStep(&keySignValidator{valid: "good"}, km, DefaultHeaderValidationCookie, SignatureConfig{SubscriberFromSignature: tt.enabled}, nil, nil)
+			require.NoError(t, err)
+			r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+			if tt.auth != "" {
+				r.Header.Set(model.AuthHeaderSubscriber, tt.auth)
+			}
+			ctx := newTestStepCtx(r, []byte(`{}`))
+			ctx.SubID = "bpp.example.com"
+
+			_ = step.Run(ctx)
+
+			assert.Equal(t, tt.wantSubID, ctx.SubID)
+		})
+	}
+
+	t.Run("later steps sign as the validated subscriber", func(t *testing.T) {
+		validate, err := newValidateSignStep(&keySignValidator{valid: "good"}, km, DefaultHeaderValidationCookie, SignatureConfig{SubscriberFromSignature: true}, nil, nil)
+		require.NoError(t, err)
+		sign, err := newSignStep(&windowSigner{}, newEd25519KeyManager(t), SignatureConfig{}, nil)
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+		r.Header.Set(model.AuthHeaderSubscriber, `Signature keyId="bap.example.com|k1|ed25519",signature="sig"`)
+		ctx := newTestStepCtx(r, []byte(`{}`))
+
+		require.NoError(t, validate.Run(ctx))
+		require.NoError(t, sign.Run(ctx))
+
+		assert.Contains(t, ctx.Request.Header.Get(model.AuthHeaderSubscriber), `keyId="bap.example.com|k1|ed25519"`)
+	})
+}
+
+func TestNewSubscriberStatusCheck(t *testing.T) {
+	check, err := newSubscriberStatusCheck(nil, nil, SubscriberStatusConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, check)
+
+	_, err = newSubscriberStatusCheck(nil, newMemCache(), SubscriberStatusConfig{Enabled: true})
+	assert.ErrorContains(t, err, "Registry plugin not configured")
+	_, err = newSubscriberStatusCheck(&fakeRegistry{}, nil, SubscriberStatusConfig{Enabled: true})
+	assert.ErrorContains(t, err, "Cache plugin not configured")
+
+	check, err = newSubscriberStatusCheck(&fakeRegistry{subs: map[string][]model.Subscription{
+		"bpp.example.com": {{Status: "active"}},
+	}}, newMemCache(), SubscriberStatusConfig{Enabled: true, Allowed: []string{"ACTIVE"}})
+	require.NoError(t, err)
+	assert.NoError(t, check.check(context.Background(), "bpp.example.com"), "statuses compare case-insensitively")
+}
+
+func TestNewEnrichRegistryStep(t *testing.T) {
+	_, err := newEnrichRegistryStep(nil, newMemCache(), EnrichRegistryConfig{})
+	assert.ErrorContains(t, err, "Registry plugin not configured")
+	_, err = newEnrichRegistryStep(&fakeRegistry{}, nil, EnrichRegistryConfig{})
+	assert.ErrorContains(t, err, "Cache plugin not configured")
+
+	step, err := newEnrichRegistryStep(&fakeRegistry{}, newMemCache(), EnrichRegistryConfig{TTL: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, step.(*enrichRegistryStep).subscribers.ttl)
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// SubscriberAccessControl is implemented by handlers whose subscriber allow and deny
+// lists can be replaced at runtime.
+type SubscriberAccessControl interface {
+	// SetSubscriberAccess replaces the allow and deny lists. It fails if a pattern is
+	// invalid or the module does not run the checkSubscriberAccess step.
+	SetSubscriberAccess(cfg SubscriberAccessConfig) error
+	// SubscriberAccess returns the lists in effect, reporting false if the module does
+	// not run the checkSubscriberAccess step.
+	SubscriberAccess() (SubscriberAccessConfig, bool)
+}
+
+// errSubscriberAccessOff is returned when updating the lists of a module that does not
+// run the checkSubscriberAccess step.
+var errSubscriberAccessOff = errors.New("module does not run the checkSubscriberAccess step")
+
+// subscriberAccess holds the subscriber allow and deny lists of a module. The lists are
+// replaced as a whole, so that requests never see a half-applied update.
+type subscriberAccess struct {
+	lists atomic.Pointer[SubscriberAccessConfig]
+}
+
+// newSubscriberAccess creates a subscriberAccess holding the lists of cfg.
+func newSubscriberAccess(cfg SubscriberAccessConfig) (*subscriberAccess, error) {
+	a := &subscriberAccess{}
+	if err := a.set(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// set replaces the lists with those of cfg after checking their patterns.
+func (a *subscriberAccess) set(cfg SubscriberAccessConfig) error {
+	for _, list := range [][]string{cfg.Allow, cfg.Deny} {
+		for _, pattern := range list {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid config: subscriberAccess pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	lists := SubscriberAccessConfig{
+		Allow: append([]string{}, cfg.Allow...),
+		Deny:  append([]string{}, cfg.Deny...),
+	}
+	a.lists.Store(&lists)
+	return nil
+}
+
+// get returns the lists in effect.
+func (a *subscriberAccess) get() SubscriberAccessConfig {
+	return *a.lists.Load()
+}
+
+// check returns an error naming the first of ids that the lists reject. A subscriber
+// matching the deny list is always rejected; when the allow list is not empty, so is
+// every subscriber not matching it.
+func (a *subscriberAccess) check(ids []string) error {
+	lists := a.lists.Load()
+	if len(lists.Allow) > 0 && len(ids) == 0 {
+		return errors.New("request names no subscriber to check against the allow list")
+	}
+	for _, id := range ids {
+		if matchSubscriber(lists.Deny, id) {
+			return fmt.Errorf("subscriber %s is denied", id)
+		}
+		if len(lists.Allow) > 0 && !matchSubscriber(lists.Allow, id) {
+			return fmt.Errorf("subscriber %s is not on the allow list", id)
+		}
+	}
+	return nil
+}
+
+// matchSubscriber reports whether id matches any of the glob patterns.
+func matchSubscriber(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSubscriberAccessStep rejects requests from subscribers the module's allow and
+// deny lists shut out.
+type checkSubscriberAccessStep struct {
+	access *subscriberAccess
+}
+
+// Run checks the sender claimed by the context, bap_id for requests and bpp_id for on_*
+// callbacks, and the subscriber validateSign authenticated, if any. Rejected requests
+// fail with a ForbiddenErr, which is answered with HTTP 403.
+func (s *checkSubscriberAccessStep) Run(ctx *model.StepContext) error {
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, id := range []string{contextSender(bCtx), ctx.Signer} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if err := s.access.check(ids); err != nil {
+		return model.NewForbiddenErr(err)
+	}
+	return nil
+}
+
+// contextSender returns the subscriber bCtx claims sent the request: bap_id for requests
+// and bpp_id for on_* callbacks.
+func contextSender(bCtx *model.BecknContext) string {
+	if strings.HasPrefix(bCtx.Action, "on_") {
+		return bCtx.BppID
+	}
+	return bCtx.BapID
+}
+
+// newCheckSubscriberAccessStep creates and returns the checkSubscriberAccess step, which
+// reads the lists of access.
+func newCheckSubscriberAccessStep(access *subscriberAccess) definition.Step {
+	return &checkSubscriberAccessStep{access: access}
+}
+
+// SetSubscriberAccess replaces the allow and deny lists.
+func (h *stdHandler) SetSubscriberAccess(cfg SubscriberAccessConfig) error {
+	if h.subAccess == nil {
+		return errSubscriberAccessOff
+	}
+	return h.subAccess.set(cfg)
+}
+
+// SubscriberAccess returns the allow and deny lists in effect.
+func (h *stdHandler) SubscriberAccess() (SubscriberAccessConfig, bool) {
+	if h.subAccess == nil {
+		return SubscriberAccessConfig{}, false
+	}
+	return h.subAccess.get(), true
+}
+
+// subscriberAccessLists are the lists of a module reported and set by the
+// /debug/subscriber-access endpoint.
+type subscriberAccessLists struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// subscriberAccessRequest is the body of a request to the /debug/subscriber-access
+// endpoint.
+type subscriberAccessRequest struct {
+	subscriberAccessLists
+	// Module limits the change to one module; empty applies it to all modules running
+	// the checkSubscriberAccess step.
+	Module string `json:"module,omitempty"`
+}
+
+// SubscriberAccessHandler returns an http.Handler for the /debug/subscriber-access
+// endpoint. GET reports the allow and deny lists of each module running the
+// checkSubscriberAccess step; POST replaces them for one or all of those modules and
+// reports the resulting lists.
+func SubscriberAccessHandler(controls map[string]SubscriberAccessControl) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req subscriberAccessRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			cfg := SubscriberAccessConfig{Allow: req.Allow, Deny: req.Deny}
+			// Patterns are checked up front so that a bad one changes no module.
+			if _, err := newSubscriberAccess(cfg); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Module != "" {
+				c, ok := controls[req.Module]
+				if !ok {
+					http.Error(w, "unknown module: "+req.Module, http.StatusNotFound)
+					return
+				}
+				if err := c.SetSubscriberAccess(cfg); err != nil {
+					http.Error(w, req.Module+": "+err.Error(), http.StatusConflict)
+					return
+				}
+				break
+			}
+			for _, c := range controls {
+				if err := c.SetSubscriberAccess(cfg); err != nil && !errors.Is(err, errSubscriberAccessOff) {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		resp := make(map[string]subscriberAccessLists, len(controls))
+		for name, c := range controls {
+			if cfg, ok := c.SubscriberAccess(); ok {
+				resp[name] = subscriberAccessLists{Allow: cfg.Allow, Deny: cfg.Deny}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+func TestCheckSubscriberAccessStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SubscriberAccessConfig
+		body    string
+		signer  string
+		wantErr string
+	}{
+		{
+			name: "no lists",
+			body: `{"context":{"action":"search","bap_id":"bap.example.com"}}`,
+		},
+		{
+			name: "allowed",
+			cfg:  SubscriberAccessConfig{Allow: []string{"*.example.com"}},
+			body: `{"context":{"action":"search","bap_id":"bap.example.com"}}`,
+		},
+		{
+			name:    "denied",
+			cfg:     SubscriberAccessConfig{Deny: []string{"*.blocked.com"}},
+			body:    `{"context":{"action":"search","bap_id":"bap.blocked.com"}}`,
+			wantErr: "subscriber bap.blocked.com is denied",
+		},
+		{
+			name:    "deny takes precedence over allow",
+			cfg:     SubscriberAccessConfig{Allow: []string{"*"}, Deny: []string{"bap.example.com"}},
+			body:    `{"context":{"action":"search","bap_id":"bap.example.com"}}`,
+			wantErr: "subscriber bap.example.com is denied",
+		},
+		{
+			name:    "not on allow list",
+			cfg:     SubscriberAccessConfig{Allow: []string{"bap.example.com"}},
+			body:    `{"context":{"action":"search","bap_id":"bap.other.com"}}`,
+			wantErr: "subscriber bap.other.com is not on the allow list",
+		},
+		{
+			name:    "callback sender is the BPP",
+			cfg:     SubscriberAccessConfig{Deny: []string{"bpp.example.com"}},
+			body:    `{"context":{"action":"on_search","bap_id":"bap.example.com","bpp_id":"bpp.example.com"}}`,
+			wantErr: "subscriber bpp.example.com is denied",
+		},
+		{
+			name:    "signer not on allow list",
+			cfg:     SubscriberAccessConfig{Allow: []string{"bap.example.com"}},
+			body:    `{"context":{"action":"search","bap_id":"bap.example.com"}}`,
+			signer:  "bap.other.com",
+			wantErr: "subscriber bap.other.com is not on the allow list",
+		},
+		{
+			name:    "no subscriber with allow list",
+			cfg:     SubscriberAccessConfig{Allow: []string{"bap.example.com"}},
+			body:    `{"context":{"action":"search"}}`,
+			wantErr: "names no subscriber",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := newSubscriberAccess(tt.cfg)
+			require.NoError(t, err)
+			r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+			ctx := newTestStepCtx(r, []byte(tt.body))
+			ctx.Signer = tt.signer
+
+			err = newCheckSubscriberAccessStep(access).Run(ctx)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var forbidden *model.ForbiddenErr
+			require.ErrorAs(t, err, &forbidden)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewSubscriberAccess(t *testing.T) {
+	_, err := newSubscriberAccess(SubscriberAccessConfig{Deny: []string{"bap[.example.com"}})
+	assert.ErrorContains(t, err, `subscriberAccess pattern "bap[.example.com"`)
+}
+
+func TestServeHTTPSubscriberAccess(t *testing.T) {
+	access, err := newSubscriberAccess(SubscriberAccessConfig{Deny: []string{"bap.blocked.com"}})
+	require.NoError(t, err)
+	ran := 0
+	h := &stdHandler{
+		subAccess: access,
+		steps: []definition.Step{newCheckSubscriberAccessStep(access), stepFunc(func(*model.StepContext) error {
+			ran++
+			return nil
+		})},
+	}
+	serve := func(bapID string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		body := `{"context":{"action":"search","bap_id":"` + bapID + `"}}`
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve("bap.blocked.com")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"NACK"`)
+	assert.Zero(t, ran, "later steps must not run for a rejected subscriber")
+
+	assert.Equal(t, http.StatusOK, serve("bap.example.com").Code)
+
+	require.NoError(t, h.SetSubscriberAccess(SubscriberAccessConfig{Allow: []string{"bap.blocked.com"}}))
+	assert.Equal(t, http.StatusOK, serve("bap.blocked.com").Code, "runtime updates take effect at once")
+	assert.Equal(t, http.StatusForbidden, serve("bap.example.com").Code)
+}
+
+func TestSubscriberAccessHandler(t *testing.T) {
+	newControls := func() map[string]SubscriberAccessControl {
+		access, err := newSubscriberAccess(SubscriberAccessConfig{Deny: []string{"bap.blocked.com"}})
+		require.NoError(t, err)
+		return map[string]SubscriberAccessControl{
+			"bppTxnReceiver": &stdHandler{subAccess: access},
+			"bppTxnCaller":   &stdHandler{},
+		}
+	}
+	tests := []struct {
+		name     string
+		method   string
+		body     string
+		wantCode int
+		want     map[string]subscriberAccessLists
+	}{
+		{
+			name:     "get",
+			method:   http.MethodGet,
+			wantCode: http.StatusOK,
+			want:     map[string]subscriberAccessLists{"bppTxnReceiver": {Allow: []string{}, Deny: []string{"bap.blocked.com"}}},
+		},
+		{
+			name:     "replace all",
+			method:   http.MethodPost,
+			body:     `{"allow":["*.example.com"]}`,
+			wantCode: http.StatusOK,
+			want:     map[string]subscriberAccessLists{"bppTxnReceiver": {Allow: []string{"*.example.com"}, Deny: []string{}}},
+		},
+		{
+			name:     "replace one module",
+			method:   http.MethodPost,
+			body:     `{"module":"bppTxnReceiver","deny":["bap.other.com"]}`,
+			wantCode: http.StatusOK,
+			want:     map[string]subscriberAccessLists{"bppTxnReceiver": {Allow: []string{}, Deny: []string{"bap.other.com"}}},
+		},
+		{
+			name:     "module without the step",
+			method:   http.MethodPost,
+			body:     `{"module":"bppTxnCaller","deny":["bap.other.com"]}`,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "unknown module",
+			method:   http.MethodPost,
+			body:     `{"module":"nope"}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid pattern",
+			method:   http.MethodPost,
+			body:     `{"deny":["bap[.example.com"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid body",
+			method:   http.MethodPost,
+			body:     `deny`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodDelete,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SubscriberAccessHandler(newControls()).ServeHTTP(rec, httptest.NewRequest(tt.method, "/debug/subscriber-access", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.want == nil {
+				return
+			}
+			var got map[string]subscriberAccessLists
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// validateSubscriberConsistencyStep rejects correctly signed requests whose context
+// claims to come from a subscriber other than the signer.
+type validateSubscriberConsistencyStep struct {
+	// subscribers looks up signers at the registry level; nil at the id level.
+	subscribers   *subscriberLookup
+	reportOnly    bool
+	requireSigner bool
+}
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg SubscriberConsistencyConfig) withDefaults() SubscriberConsistencyConfig {
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultEnrichRegistryTTL
+	}
+	return cfg
+}
+
+// newValidateSubscriberConsistencyStep creates and returns the validateSubscriberConsistency
+// step. The registry level requires the registry and cache plugins.
+func newValidateSubscriberConsistencyStep(registry definition.RegistryLookup, cache definition.Cache, cfg SubscriberConsistencyConfig) (definition.Step, error) {
+	s := &validateSubscriberConsistencyStep{reportOnly: cfg.ReportOnly, requireSigner: cfg.RequireSigner}
+	switch cfg.Level {
+	case "", SubscriberConsistencyID:
+	case SubscriberConsistencyRegistry:
+		var err error
+		if s.subscribers, err = newSubscriberLookup(registry, cache, cfg.withDefaults().TTL); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid config: unknown subscriberConsistency level %q", cfg.Level)
+	}
+	return s, nil
+}
+
+// Run checks the subscriber validateSign authenticated against the sender claimed by the
+// context: bap_id and bap_uri for requests, bpp_id and bpp_uri for on_* callbacks.
+func (s *validateSubscriberConsistencyStep) Run(ctx *model.StepContext) error {
+	if ctx.Signer == "" {
+		if s.requireSigner {
+			return model.NewSignValidationErr(errors.New("request has no validated signature to check the subscriber against"))
+		}
+		log.Debugf(ctx, "Skipping subscriber consistency check of request without a validated signature")
+		return nil
+	}
+	bCtx, err := stepBecknContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.check(ctx, ctx.Signer, bCtx)
+	if err == nil {
+		return nil
+	}
+	var signErr *model.SignValidationErr
+	if s.reportOnly && errors.As(err, &signErr) {
+		log.Warnf(ctx, "Subscriber consistency check failed: %v", err)
+		return nil
+	}
+	return err
+}
+
+// check returns a SignValidationErr naming the first way in which the sender claimed by
+// bCtx differs from signer.
+func (s *validateSubscriberConsistencyStep) check(ctx *model.StepContext, signer string, bCtx *model.BecknContext) error {
+	role, id, uri := "BAP", bCtx.BapID, bCtx.BapURI
+	if strings.HasPrefix(bCtx.Action, "on_") {
+		role, id, uri = "BPP", bCtx.BppID, bCtx.BppURI
+	}
+	field := strings.ToLower(role)
+	if id != signer {
+		return model.NewSignValidationErr(fmt.Errorf("context.%s_id %q does not match signing subscriber %s", field, id, signer))
+	}
+	if s.subscribers == nil {
+		return nil
+	}
+	sub, err := s.subscribers.lookup(ctx, signer)
+	if err != nil {
+		var notFound *model.NotFoundErr
+		if errors.As(err, &notFound) {
+			return model.NewSignValidationErr(err)
+		}
+		return err
+	}
+	if sub.Type != "" && !strings.EqualFold(sub.Type, role) {
+		return model.NewSignValidationErr(fmt.Errorf("signing subscriber %s is registered as %s, not %s", signer, sub.Type, role))
+	}
+	if strings.TrimSuffix(uri, "/") != strings.TrimSuffix(sub.URL, "/") {
+		return model.NewSignValidationErr(fmt.Errorf("context.%s_uri %q does not match the registered URL of %s", field, uri, signer))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+func TestValidateSubscriberConsistencyStep(t *testing.T) {
+	registry := &fakeRegistry{subs: map[string][]model.Subscription{
+		"bap.example.com": {{Subscriber: model.Subscriber{SubscriberID: "bap.example.com", URL: "https://bap.example.com/beckn/", Type: "BAP"}, Status: "SUBSCRIBED"}},
+		"bpp.example.com": {{Subscriber: model.Subscriber{SubscriberID: "bpp.example.com", URL: "https://bpp.example.com/beckn", Type: "BPP"}, Status: "SUBSCRIBED"}},
+	}}
+	search := `{"context":{"action":"search","bap_id":"bap.example.com","bap_uri":"https://bap.example.com/beckn"}}`
+	onSearch := `{"context":{"action":"on_search","bap_id":"bap.example.com","bpp_id":"bpp.example.com","bpp_uri":"https://bpp.example.com/beckn"}}`
+
+	tests := []struct {
+		name    string
+		cfg     SubscriberConsistencyConfig
+		signer  string
+		body    string
+		wantErr string
+	}{
+		{name: "request from signer", signer: "bap.example.com", body: search},
+		{name: "callback from signer", signer: "bpp.example.com", body: onSearch},
+		{name: "request claiming another bap", signer: "bpp.example.com", body: search, wantErr: `context.bap_id "bap.example.com" does not match signing subscriber bpp.example.com`},
+		{name: "callback signed by bap", signer: "bap.example.com", body: onSearch, wantErr: `context.bpp_id "bpp.example.com" does not match`},
+		{name: "report only", cfg: SubscriberConsistencyConfig{ReportOnly: true}, signer: "evil.example.com", body: search},
+		{name: "no signer", body: search},
+		{name: "no signer required", cfg: SubscriberConsistencyConfig{RequireSigner: true}, body: search, wantErr: "no validated signature"},
+		{name: "registry match", cfg: SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry}, signer: "bap.example.com", body: search},
+		{name: "registry callback match", cfg: SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry}, signer: "bpp.example.com", body: onSearch},
+		{
+			name:    "registry uri mismatch",
+			cfg:     SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry},
+			signer:  "bap.example.com",
+			body:    `{"context":{"action":"search","bap_id":"bap.example.com","bap_uri":"https://evil.example.com/beckn"}}`,
+			wantErr: `context.bap_uri "https://evil.example.com/beckn" does not match the registered URL of bap.example.com`,
+		},
+		{
+			name:    "registry role mismatch",
+			cfg:     SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry},
+			signer:  "bpp.example.com",
+			body:    `{"context":{"action":"search","bap_id":"bpp.example.com","bap_uri":"https://bpp.example.com/beckn"}}`,
+			wantErr: "registered as BPP, not BAP",
+		},
+		{
+			name:    "registry unknown signer",
+			cfg:     SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry},
+			signer:  "new.example.com",
+			body:    `{"context":{"action":"search","bap_id":"new.example.com"}}`,
+			wantErr: "subscriber new.example.com not found in registry",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, err := newValidateSubscriberConsistencyStep(registry, newMemCache(), tt.cfg)
+			require.NoError(t, err)
+			ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil), []byte(tt.body))
+			ctx.Signer = tt.signer
+
+			err = step.Run(ctx)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var signErr *model.SignValidationErr
+			assert.ErrorAs(t, err, &signErr)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateSubscriberConsistencyStepRegistryError(t *testing.T) {
+	registry := &fakeRegistry{err: errors.New("registry down")}
+	step, err := newValidateSubscriberConsistencyStep(registry, newMemCache(), SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry, ReportOnly: true})
+	require.NoError(t, err)
+	ctx := newTestStepCtx(httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil), []byte(`{"context":{"action":"search","bap_id":"bap.example.com"}}`))
+	ctx.Signer = "bap.example.com"
+
+	err = step.Run(ctx)
+
+	assert.ErrorContains(t, err, "registry down", "lookup failures are not mismatches and are never only reported")
+}
+
+func TestNewValidateSubscriberConsistencyStep(t *testing.T) {
+	_, err := newValidateSubscriberConsistencyStep(nil, nil, SubscriberConsistencyConfig{})
+	assert.NoError(t, err, "the id level needs no plugins")
+
+	_, err = newValidateSubscriberConsistencyStep(nil, newMemCache(), SubscriberConsistencyConfig{Level: SubscriberConsistencyRegistry})
+	assert.ErrorContains(t, err, "Registry plugin not configured")
+
+	_, err = newValidateSubscriberConsistencyStep(nil, nil, SubscriberConsistencyConfig{Level: "loose"})
+	assert.ErrorContains(t, err, `unknown subscriberConsistency level "loose"`)
+}
+
+func TestValidateSignStepSetsSigner(t *testing.T) {
+	sv := &keySignValidator{valid: "good"}
+	km := &mapKeyManager{keys: map[string]string{"bap.example.com": "good"}}
+	step, err := newValidateSignStep(sv, km, DefaultHeaderValidationCookie, SignatureConfig{}, nil, nil)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/bpp/receiver/search", nil)
+	r.Header.Set(model.AuthHeaderSubscriber, `Signature keyId="bap.example.com|k1|ed25519",signature="sig"`)
+	ctx := newTestStepCtx(r, []byte(`{}`))
+
+	require.NoError(t, step.Run(ctx))
+
+	assert.Equal(t, "bap.example.com", ctx.Signer)
+}
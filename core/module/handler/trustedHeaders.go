@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// trustedHeaders reads per-request subscriber ID, role and schema version overrides
+// from headers set by a trusted upstream proxy.
+type trustedHeaders struct {
+	subscriberID  string
+	role          string
+	schemaVersion string
+	networks      []netip.Prefix
+}
+
+// newTrustedHeaders creates trustedHeaders from cfg, validating the trusted networks.
+// It returns nil when no override header is configured.
+func newTrustedHeaders(cfg TrustedHeadersConfig) (*trustedHeaders, error) {
+	if cfg.SubscriberID == "" && cfg.Role == "" && cfg.SchemaVersion == "" {
+		return nil, nil
+	}
+	if len(cfg.TrustedNetworks) == 0 {
+		return nil, fmt.Errorf("trustedNetworks is required when trusted headers are configured")
+	}
+	networks, err := parseTrustedNetworks(cfg.TrustedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	return &trustedHeaders{subscriberID: cfg.SubscriberID, role: cfg.Role, schemaVersion: cfg.SchemaVersion, networks: networks}, nil
+}
+
+// parseTrustedNetworks parses the CIDRs of trusted sources.
+func parseTrustedNetworks(cidrs []string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, n := range cidrs {
+		p, err := netip.ParsePrefix(n)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted network %q: %w", n, err)
+		}
+		networks = append(networks, p.Masked())
+	}
+	return networks, nil
+}
+
+// roleKey is the context key of a role set by a trusted header.
+type roleKey struct{}
+
+// apply returns r with the subscriber ID, role and schema version from its override
+// headers stored in its context. Headers from a source outside the trusted networks are ignored and
+// removed so that they are not forwarded.
+func (t *trustedHeaders) apply(r *http.Request) (*http.Request, error) {
+	if t == nil {
+		return r, nil
+	}
+	subID, role, version := t.header(r, t.subscriberID), t.header(r, t.role), t.header(r, t.schemaVersion)
+	if subID == "" && role == "" && version == "" {
+		return r, nil
+	}
+	if !inNetworks(t.networks, r.RemoteAddr) {
+		log.Warnf(r.Context(), "Ignoring subscriber, role and schema version headers from untrusted source %s", r.RemoteAddr)
+		r.Header.Del(t.subscriberID)
+		r.Header.Del(t.role)
+		r.Header.Del(t.schemaVersion)
+		return r, nil
+	}
+	ctx := r.Context()
+	if role != "" {
+		if !model.Role(role).Valid() {
+			return r, model.NewBadReqErr(fmt.Errorf("invalid role in %s header: %s", t.role, role))
+		}
+		ctx = context.WithValue(ctx, roleKey{}, model.Role(role))
+	}
+	if subID != "" {
+		ctx = context.WithValue(ctx, model.ContextKeySubscriberID, subID)
+	}
+	if version != "" {
+		ctx = context.WithValue(ctx, model.ContextKeySchemaVersion, version)
+	}
+	return r.WithContext(ctx), nil
+}
+
+// header returns the value of header name, or "" when name is not configured.
+func (t *trustedHeaders) header(r *http.Request, name string) string {
+	if name == "" {
+		return ""
+	}
+	return r.Header.Get(name)
+}
+
+// inNetworks reports whether remoteAddr is inside one of networks.
+func inNetworks(networks []netip.Prefix, remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, n := range networks {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestRole returns the role set for the request by a trusted header, falling back
+// to the handler's role.
+func (h *stdHandler) requestRole(ctx context.Context) model.Role {
+	if role, ok := ctx.Value(roleKey{}).(model.Role); ok {
+		return role
+	}
+	return h.role
+}
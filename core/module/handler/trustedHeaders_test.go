@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeHTTPTrustedHeaders(t *testing.T) {
+	cfg := TrustedHeadersConfig{
+		SubscriberID:    "X-Tenant-Subscriber",
+		Role:            "X-Tenant-Role",
+		SchemaVersion:   "X-Schema-Version",
+		TrustedNetworks: []string{"10.0.0.0/8", "::1/128"},
+	}
+	tests := []struct {
+		name        string
+		remoteAddr  string
+		headers     map[string]string
+		wantSubID   string
+		wantRole    model.Role
+		wantVersion string
+		wantCode    int
+	}{
+		{
+			name:       "trusted source overrides",
+			remoteAddr: "10.1.2.3:4567",
+			headers:    map[string]string{"X-Tenant-Subscriber": "tenant.example.com", "X-Tenant-Role": "bpp"},
+			wantSubID:  "tenant.example.com",
+			wantRole:   model.RoleBPP,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "trusted IPv6 source",
+			remoteAddr: "[::1]:4567",
+			headers:    map[string]string{"X-Tenant-Subscriber": "tenant.example.com"},
+			wantSubID:  "tenant.example.com",
+			wantRole:   model.RoleBAP,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:        "trusted source pins schema version",
+			remoteAddr:  "10.1.2.3:4567",
+			headers:     map[string]string{"X-Schema-Version": "2.0.1"},
+			wantSubID:   "bap.example.com",
+			wantRole:    model.RoleBAP,
+			wantVersion: "2.0.1",
+			wantCode:    http.StatusOK,
+		},
+		{
+			name:       "untrusted source is ignored",
+			remoteAddr: "192.0.2.1:4567",
+			headers:    map[string]string{"X-Tenant-Subscriber": "tenant.example.com", "X-Tenant-Role": "bpp", "X-Schema-Version": "2.0.1"},
+			wantSubID:  "bap.example.com",
+			wantRole:   model.RoleBAP,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "no headers fall back to defaults",
+			remoteAddr: "10.1.2.3:4567",
+			wantSubID:  "bap.example.com",
+			wantRole:   model.RoleBAP,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "invalid role",
+			remoteAddr: "10.1.2.3:4567",
+			headers:    map[string]string{"X-Tenant-Role": "admin"},
+			wantCode:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trusted, err := newTrustedHeaders(cfg)
+			require.NoError(t, err)
+			var got *model.StepContext
+			var roleHeader string
+			h := &stdHandler{
+				SubscriberID: "bap.example.com",
+				role:         model.RoleBAP,
+				trusted:      trusted,
+				steps: []definition.Step{stepFunc(func(ctx *model.StepContext) error {
+					got, roleHeader = ctx, ctx.Request.Header.Get("X-Role")
+					return nil
+				})},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode != http.StatusOK {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.wantSubID, got.SubID)
+			assert.Equal(t, tt.wantRole, got.Role)
+			assert.Equal(t, string(tt.wantRole), roleHeader)
+			version, _ := got.Value(model.ContextKeySchemaVersion).(string)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestServeHTTPUntrustedHeadersNotForwarded(t *testing.T) {
+	trusted, err := newTrustedHeaders(TrustedHeadersConfig{SubscriberID: "X-Tenant-Subscriber", TrustedNetworks: []string{"10.0.0.0/8"}})
+	require.NoError(t, err)
+	h := &stdHandler{trusted: trusted}
+	req := httptest.NewRequest(http.MethodPost, "/bap/caller/search", strings.NewReader(`{}`))
+	req.RemoteAddr = "192.0.2.1:4567"
+	req.Header.Set("X-Tenant-Subscriber", "tenant.example.com")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, req.Header.Get("X-Tenant-Subscriber"))
+}
+
+func TestNewTrustedHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     TrustedHeadersConfig
+		wantNil bool
+		wantErr string
+	}{
+		{name: "not configured", cfg: TrustedHeadersConfig{TrustedNetworks: []string{"10.0.0.0/8"}}, wantNil: true},
+		{name: "valid", cfg: TrustedHeadersConfig{Role: "X-Role-Override", TrustedNetworks: []string{"10.0.0.0/8"}}},
+		{name: "missing networks", cfg: TrustedHeadersConfig{Role: "X-Role-Override"}, wantErr: "trustedNetworks is required"},
+		{name: "invalid network", cfg: TrustedHeadersConfig{Role: "X-Role-Override", TrustedNetworks: []string{"10.0.0.1"}}, wantErr: "invalid trusted network"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTrustedHeaders(tt.cfg)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNil, got == nil)
+		})
+	}
+}
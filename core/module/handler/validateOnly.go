@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// validateOnlySteps are the steps ValidateFile can run.
+var validateOnlySteps = []string{"validateSchema", "validateOndcPayload"}
+
+// ValidationResult is the outcome of one step run by ValidateFile.
+type ValidationResult struct {
+	Step  string       `json:"step"`
+	Valid bool         `json:"valid"`
+	Error *model.Error `json:"error,omitempty"`
+}
+
+// ValidationReport is the outcome of validating a payload file with ValidateFile.
+type ValidationReport struct {
+	File    string             `json:"file"`
+	Path    string             `json:"path"`
+	Valid   bool               `json:"valid"`
+	Results []ValidationResult `json:"results"`
+}
+
+// ValidateFile runs the validateSchema and validateOndcPayload steps configured in cfg on
+// the payload in file, as they would run for a request to path, without starting a
+// server. An empty path is taken to be "/" followed by the payload's context.action.
+// Only the plugins these steps need are loaded. A payload that fails validation is
+// reported in the returned ValidationReport; the error is for failures to run the steps.
+func ValidateFile(ctx context.Context, mgr PluginManager, cfg *Config, file, path string) (*ValidationReport, error) {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	var steps []string
+	for _, s := range cfg.Steps {
+		if slices.Contains(validateOnlySteps, s) {
+			steps = append(steps, s)
+		}
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no validation steps configured, want one of %v", validateOnlySteps)
+	}
+	bCtx, err := model.ParseBecknContext(body)
+	if err != nil {
+		log.Debugf(ctx, "Payload has no parsable context: %v", err)
+	}
+	if path == "" {
+		if bCtx == nil || bCtx.Action == "" {
+			return nil, errors.New("path is required when the payload has no context.action")
+		}
+		path = "/" + bCtx.Action
+	}
+
+	h := &stdHandler{}
+	if err := h.initValidationPlugins(ctx, mgr, &cfg.Plugins, steps); err != nil {
+		return nil, err
+	}
+	report := &ValidationReport{File: file, Path: path, Valid: true}
+	for _, name := range steps {
+		var step definition.Step
+		switch name {
+		case "validateSchema":
+			step, err = newValidateSchemaStep(h.schemaValidator)
+		case "validateOndcPayload":
+			step, err = newValidateOndcStep(h.ondcValidator, DefaultProtocolValidationCookie)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("step %s: %w", name, err)
+		}
+		r, err := http.NewRequestWithContext(ctx, http.MethodPost, path, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", path, err)
+		}
+		result := ValidationResult{Step: name, Valid: true}
+		if err := step.Run(&model.StepContext{
+			Context:      ctx,
+			Request:      r,
+			Body:         body,
+			Role:         cfg.Role,
+			RespHeader:   http.Header{},
+			BecknContext: bCtx,
+		}); err != nil {
+			result.Valid = false
+			result.Error = validationError(err)
+			report.Valid = false
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// initValidationPlugins loads the plugins that steps need.
+func (h *stdHandler) initValidationPlugins(ctx context.Context, mgr PluginManager, cfg *PluginCfg, steps []string) error {
+	var err error
+	if slices.Contains(steps, "validateSchema") {
+		if h.schemaValidator, err = loadSchemaValidators(ctx, mgr, cfg.SchemaValidator, cfg.FallbackSchemaValidators); err != nil {
+			return err
+		}
+	}
+	if slices.Contains(steps, "validateOndcPayload") {
+		if h.cache, err = loadPlugin(ctx, "Cache", cfg.Cache, mgr.Cache); err != nil {
+			return err
+		}
+		if h.ondcValidator, err = loadOndcValidators(ctx, mgr, h.cache, cfg.OndcValidator, cfg.OndcValidators); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validationError returns the NACK error a step failure would produce, or one carrying
+// the error message for failures that have none.
+func validationError(err error) *model.Error {
+	var be interface{ BecknError() *model.Error }
+	if errors.As(err, &be) {
+		return be.BecknError()
+	}
+	return &model.Error{Code: strconv.Itoa(http.StatusInternalServerError), Message: err.Error()}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/implementation/schemavalidator"
+)
+
+// writeFile writes content to name under dir and returns its path.
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestValidateFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "schemas/retail/v1.1.0/search.json", `{
+		"type": "object",
+		"properties": {"message": {"type": "object", "required": ["intent"]}},
+		"required": ["context", "message"]
+	}`)
+	sv, _, err := schemavalidator.New(context.Background(), &schemavalidator.Config{SchemaDir: filepath.Join(dir, "schemas")})
+	require.NoError(t, err)
+	valid := writeFile(t, dir, "valid.json", `{"context": {"domain": "retail", "version": "1.1.0", "action": "search"}, "message": {"intent": {}}}`)
+	invalid := writeFile(t, dir, "invalid.json", `{"context": {"domain": "retail", "version": "1.1.0", "action": "search"}, "message": {}}`)
+	cfg := &Config{
+		Steps: []string{"validateSign", "validateSchema", "validateOndcPayload", "addRoute"},
+		Plugins: PluginCfg{
+			SchemaValidator: &plugin.Config{ID: "schemavalidator"},
+			OndcValidator:   &plugin.Config{ID: "ondcvalidator"},
+		},
+	}
+	ondcErr := &model.OndcValidationErr{Errors: []model.Error{{Code: "30000", Paths: "message.intent", Message: "intent is required"}}}
+
+	tests := []struct {
+		name        string
+		file        string
+		ondcErr     error
+		wantValid   bool
+		wantResults []ValidationResult
+	}{
+		{
+			name:      "valid payload",
+			file:      valid,
+			wantValid: true,
+			wantResults: []ValidationResult{
+				{Step: "validateSchema", Valid: true},
+				{Step: "validateOndcPayload", Valid: true},
+			},
+		},
+		{
+			name:    "invalid payload",
+			file:    invalid,
+			ondcErr: ondcErr,
+			wantResults: []ValidationResult{
+				{Step: "validateSchema"},
+				{Step: "validateOndcPayload", Error: ondcErr.BecknError()},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ov := &mockOndcValidator{validateErr: tt.ondcErr}
+			mgr := &stubPluginManager{schemaValidator: sv, ondcValidator: ov}
+
+			report, err := ValidateFile(context.Background(), mgr, cfg, tt.file, "")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.file, report.File)
+			assert.Equal(t, "/search", report.Path)
+			assert.Equal(t, tt.wantValid, report.Valid)
+			require.Len(t, report.Results, len(tt.wantResults))
+			for i, want := range tt.wantResults {
+				got := report.Results[i]
+				assert.Equal(t, want.Step, got.Step)
+				assert.Equal(t, want.Valid, got.Valid)
+				if want.Valid {
+					assert.Nil(t, got.Error)
+				}
+				if want.Error != nil {
+					assert.Equal(t, want.Error, got.Error)
+				}
+			}
+			if !tt.wantValid {
+				require.NotNil(t, report.Results[0].Error)
+				assert.Contains(t, report.Results[0].Error.Paths, "message")
+			}
+			assert.Equal(t, []string{"validate"}, ov.calls)
+		})
+	}
+}
+
+func TestValidateFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	noAction := writeFile(t, dir, "no-action.json", `{"context": {"domain": "retail"}, "message": {}}`)
+	cfg := &Config{Steps: []string{"validateSchema"}, Plugins: PluginCfg{SchemaValidator: &plugin.Config{ID: "schemavalidator"}}}
+	mgr := &stubPluginManager{schemaValidator: &warmUpSchemaValidator{}}
+
+	_, err := ValidateFile(context.Background(), mgr, cfg, filepath.Join(dir, "missing.json"), "/search")
+	assert.ErrorContains(t, err, "failed to read payload")
+
+	_, err = ValidateFile(context.Background(), mgr, &Config{Steps: []string{"addRoute"}}, noAction, "/search")
+	assert.ErrorContains(t, err, "no validation steps configured")
+
+	_, err = ValidateFile(context.Background(), mgr, cfg, noAction, "")
+	assert.ErrorContains(t, err, "path is required")
+
+	report, err := ValidateFile(context.Background(), mgr, cfg, noAction, "/search")
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+
+	_, err = ValidateFile(context.Background(), &stubPluginManager{}, &Config{Steps: []string{"validateSchema"}}, noAction, "/search")
+	assert.ErrorContains(t, err, "SchemaValidator plugin not configured")
+}
+
+func TestValidationError(t *testing.T) {
+	assert.Equal(t, &model.Error{Code: "500", Message: "schema file missing"}, validationError(errors.New("schema file missing")))
+
+	badReq := model.NewBadReqErr(errors.New("invalid context"))
+	assert.Equal(t, badReq.BecknError(), validationError(fmt.Errorf("step failed: %w", badReq)))
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// defaultWarmUpTimeout bounds the warm-up when WarmUpConfig.Timeout is unset.
+const defaultWarmUpTimeout = 30 * time.Second
+
+// errWarmingUp is reported for requests and readiness checks until warm-up completes.
+var errWarmingUp = errors.New("handler is warming up")
+
+// withDefaults returns cfg with the defaults for unset settings filled in.
+func (cfg WarmUpConfig) withDefaults() WarmUpConfig {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultWarmUpTimeout
+	}
+	return cfg
+}
+
+// startWarmUp runs the configured warm-up in the background. The handler is gated
+// until it completes; without warm-up targets it is open immediately.
+func (h *stdHandler) startWarmUp(ctx context.Context, cfg *WarmUpConfig) {
+	if len(cfg.Schemas) == 0 && len(cfg.Keys) == 0 {
+		return
+	}
+	timeout := cfg.withDefaults().Timeout
+	done := make(chan struct{})
+	h.warmedUp = done
+	go func() {
+		defer close(done)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		h.warmUp(ctx, cfg)
+	}()
+}
+
+// warmUp compiles the configured schemas and prefetches the configured keys,
+// logging the targets that fail.
+func (h *stdHandler) warmUp(ctx context.Context, cfg *WarmUpConfig) {
+	if len(cfg.Schemas) > 0 {
+		if w, ok := h.schemaValidator.(definition.Warmer); ok {
+			if err := w.WarmUp(ctx, cfg.Schemas); err != nil {
+				log.Warnf(ctx, "Schema warm-up failed: %v", err)
+			}
+		} else {
+			log.Warnf(ctx, "Skipping schema warm-up: SchemaValidator plugin does not support it")
+		}
+	}
+	if len(cfg.Keys) > 0 && h.km == nil {
+		log.Warnf(ctx, "Skipping key warm-up: KeyManager plugin not configured")
+		return
+	}
+	for _, k := range cfg.Keys {
+		if _, _, err := h.km.LookupNPKeys(ctx, k.SubscriberID, k.KeyID); err != nil {
+			log.Warnf(ctx, "Key warm-up failed for %s (%s): %v", k.SubscriberID, k.KeyID, err)
+		}
+	}
+	log.Infof(ctx, "Warm-up completed for module %s", h.moduleName)
+}
+
+// warmingUp reports whether the handler is still warming up.
+func (h *stdHandler) warmingUp() bool {
+	if h.warmedUp == nil {
+		return false
+	}
+	select {
+	case <-h.warmedUp:
+		return false
+	default:
+		return true
+	}
+}
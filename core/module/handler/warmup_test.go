@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// warmUpKeyManager is a KeyManager whose key lookups block until release is closed.
+type warmUpKeyManager struct {
+	definition.KeyManager
+	release chan struct{}
+	mu      sync.Mutex
+	lookups []string
+}
+
+func (m *warmUpKeyManager) LookupNPKeys(ctx context.Context, subscriberID, keyID string) (string, string, error) {
+	m.mu.Lock()
+	m.lookups = append(m.lookups, subscriberID+"/"+keyID)
+	m.mu.Unlock()
+	select {
+	case <-m.release:
+		return "signing", "encr", nil
+	case <-ctx.Done():
+		return "", "", ctx.Err()
+	}
+}
+
+// warmUpSchemaValidator is a SchemaValidator that records its warm-up targets.
+type warmUpSchemaValidator struct {
+	targets []string
+	err     error
+}
+
+func (v *warmUpSchemaValidator) Validate(context.Context, *url.URL, []byte) error { return nil }
+
+func (v *warmUpSchemaValidator) WarmUp(_ context.Context, targets []string) error {
+	v.targets = targets
+	return v.err
+}
+
+// serveStatus returns the status code h responds with to a search request.
+func serveStatus(h http.Handler) int {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/bap/receiver/search", strings.NewReader(`{}`)))
+	return rec.Code
+}
+
+func TestWarmUpGatesTraffic(t *testing.T) {
+	km := &warmUpKeyManager{release: make(chan struct{})}
+	sv := &warmUpSchemaValidator{err: errors.New("schema unreachable")}
+	h := &stdHandler{km: km, schemaValidator: sv}
+	h.startWarmUp(context.Background(), &WarmUpConfig{
+		Schemas: []string{"https://schemas.example.com/retail/context.jsonld"},
+		Keys:    []WarmUpKey{{SubscriberID: "bpp.example.com", KeyID: "key-1"}},
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, serveStatus(h))
+	assert.ErrorIs(t, h.Ready(context.Background()), errWarmingUp)
+
+	close(km.release)
+	<-h.warmedUp
+
+	assert.Equal(t, http.StatusOK, serveStatus(h))
+	assert.NoError(t, h.Ready(context.Background()))
+	assert.Equal(t, []string{"https://schemas.example.com/retail/context.jsonld"}, sv.targets)
+	assert.Equal(t, []string{"bpp.example.com/key-1"}, km.lookups)
+}
+
+func TestWarmUpTimeout(t *testing.T) {
+	km := &warmUpKeyManager{release: make(chan struct{})}
+	h := &stdHandler{km: km}
+	h.startWarmUp(context.Background(), &WarmUpConfig{
+		Keys:    []WarmUpKey{{SubscriberID: "bpp.example.com", KeyID: "key-1"}},
+		Timeout: 10 * time.Millisecond,
+	})
+
+	select {
+	case <-h.warmedUp:
+	case <-time.After(time.Second):
+		t.Fatal("warm-up did not end after its timeout")
+	}
+	assert.Equal(t, http.StatusOK, serveStatus(h))
+}
+
+func TestWarmUpNotConfigured(t *testing.T) {
+	h := &stdHandler{}
+	h.startWarmUp(context.Background(), &WarmUpConfig{})
+
+	require.Nil(t, h.warmedUp)
+	assert.Equal(t, http.StatusOK, serveStatus(h))
+}
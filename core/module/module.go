@@ -2,8 +2,10 @@ package module
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/beckn-one/beckn-onix/core/module/handler"
 	"github.com/beckn-one/beckn-onix/pkg/log"
@@ -27,33 +29,89 @@ var handlerProviders = map[handler.Type]Provider{
 
 // Register initializes and registers handlers based on the provided configuration.
 // It iterates over the module configurations, retrieves appropriate handler providers,
-// and registers the handlers with the HTTP multiplexer.
-func Register(ctx context.Context, mCfgs []Config, mux *http.ServeMux, mgr handler.PluginManager) error {
+// and registers the handlers with the HTTP multiplexer. The returned Drainer shuts down
+// every registered handler that supports draining.
+func Register(ctx context.Context, mCfgs []Config, mux *http.ServeMux, mgr handler.PluginManager) (handler.Drainer, error) {
 
 	mux.Handle("/health", http.HandlerFunc(handler.HealthHandler))
 
 	log.Debugf(ctx, "Registering modules with config: %#v", mCfgs)
+	readiness := make(map[string]handler.ReadinessChecker)
+	plugins := make(map[string]handler.PluginReporter)
+	drain := make(drainers)
+	maintenance := make(map[string]handler.MaintenanceSwitch)
+	subscriberAccess := make(map[string]handler.SubscriberAccessControl)
+	configs := make(map[string]handler.ConfigReporter)
 	// Iterate over the handlers in the configuration.
 	for _, c := range mCfgs {
 		rmp, ok := handlerProviders[c.Handler.Type]
 		if !ok {
-			return fmt.Errorf("invalid module : %s", c.Name)
+			return nil, fmt.Errorf("invalid module : %s", c.Name)
 		}
 		h, err := rmp(ctx, mgr, &c.Handler, c.Name)
 		if err != nil {
-			return fmt.Errorf("%s : %w", c.Name, err)
+			return nil, fmt.Errorf("%s : %w", c.Name, err)
+		}
+		if rc, ok := h.(handler.ReadinessChecker); ok {
+			readiness[c.Name] = rc
+		}
+		if pr, ok := h.(handler.PluginReporter); ok {
+			plugins[c.Name] = pr
+		}
+		if d, ok := h.(handler.Drainer); ok {
+			drain[c.Name] = d
+		}
+		if ms, ok := h.(handler.MaintenanceSwitch); ok {
+			maintenance[c.Name] = ms
+		}
+		if sa, ok := h.(handler.SubscriberAccessControl); ok {
+			subscriberAccess[c.Name] = sa
+		}
+		if cr, ok := h.(handler.ConfigReporter); ok {
+			configs[c.Name] = cr
 		}
 		h, err = addMiddleware(ctx, mgr, h, &c.Handler)
 		if err != nil {
-			return fmt.Errorf("failed to add middleware: %w", err)
+			return nil, fmt.Errorf("failed to add middleware: %w", err)
 
 		}
 		h = moduleCtxMiddleware(c.Name, h)
-		h = PostResponseMiddleware()(h)
+		h = handler.PostResponseMiddleware()(h)
 		log.Debugf(ctx, "Registering handler %s, of type %s @ %s", c.Name, c.Handler.Type, c.Path)
 		mux.Handle(c.Path, h)
 	}
-	return nil
+	mux.Handle("/readyz", handler.ReadyHandler(readiness))
+	mux.Handle("/debug/plugins", handler.PluginsHandler(plugins))
+	mux.Handle("/debug/maintenance", handler.MaintenanceHandler(maintenance))
+	mux.Handle("/debug/subscriber-access", handler.SubscriberAccessHandler(subscriberAccess))
+	mux.Handle("/debug/config", handler.ConfigHandler(configs))
+	return drain, nil
+}
+
+// drainers shuts down a set of handlers, keyed by module name, together.
+type drainers map[string]handler.Drainer
+
+// Shutdown drains all handlers concurrently so that none keeps accepting requests while
+// another is waiting, and returns the errors of the handlers that did not drain cleanly.
+func (d drainers) Shutdown(ctx context.Context) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for name, dr := range d {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := dr.Shutdown(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("module %s: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
 // addMiddleware applies middleware plugins to the provided handler in reverse order.
@@ -84,29 +142,3 @@ func moduleCtxMiddleware(moduleName string, next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
-
-func PostResponseMiddleware() func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			var hooks []handler.PostResponseHook
-
-			// store hook list pointer in request context
-			ctx := context.WithValue(r.Context(), handler.PostResponseKey{}, &hooks)
-			r = r.WithContext(ctx)
-
-			next.ServeHTTP(w, r)
-
-			// 🔥 EXTREME LAST POINT 🔥
-			for _, hook := range hooks {
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							log.Errorf(ctx, nil, "post-response hook panic: %v", r)
-						}
-					}()
-					hook()
-				}()
-			}
-		})
-	}
-}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/beckn-one/beckn-onix/core/module/handler"
@@ -89,6 +90,11 @@ func (m *mockPluginManager) OndcWorkbench(ctx context.Context, cache definition.
 	return nil, nil
 }
 
+// Auditor returns a mock auditor implementation.
+func (m *mockPluginManager) Auditor(ctx context.Context, cfg *plugin.Config) (definition.Auditor, error) {
+	return nil, nil
+}
+
 // TestRegisterSuccess tests scenarios where the handler registration should succeed.
 func TestRegisterSuccess(t *testing.T) {
 	mCfgs := []Config{
@@ -115,7 +121,7 @@ func TestRegisterSuccess(t *testing.T) {
 	}
 
 	mux := http.NewServeMux()
-	err := Register(context.Background(), mCfgs, mux, mockManager)
+	drainer, err := Register(context.Background(), mCfgs, mux, mockManager)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -148,6 +154,38 @@ func TestRegisterSuccess(t *testing.T) {
 		t.Errorf("handler for /health returned wrong status code: got %v want %v",
 			status, http.StatusOK)
 	}
+
+	// After draining, the module rejects new requests.
+	if err := drainer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected drain error: %v", err)
+	}
+	recDrained := httptest.NewRecorder()
+	mux.ServeHTTP(recDrained, httptest.NewRequest(http.MethodPost, "/test", nil))
+	if status := recDrained.Code; status != http.StatusServiceUnavailable {
+		t.Errorf("drained handler returned wrong status code: got %v want %v",
+			status, http.StatusServiceUnavailable)
+	}
+}
+
+// drainerFunc adapts a function to handler.Drainer.
+type drainerFunc func(ctx context.Context) error
+
+func (f drainerFunc) Shutdown(ctx context.Context) error { return f(ctx) }
+
+func TestDrainersShutdown(t *testing.T) {
+	errPending := errors.New("pending hooks")
+	d := drainers{
+		"ok":      drainerFunc(func(context.Context) error { return nil }),
+		"pending": drainerFunc(func(context.Context) error { return errPending }),
+	}
+
+	err := d.Shutdown(context.Background())
+	if !errors.Is(err, errPending) {
+		t.Fatalf("expected error wrapping %v, got %v", errPending, err)
+	}
+	if want := "module pending"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error to mention %q, got %v", want, err)
+	}
 }
 
 // TestRegisterFailure tests scenarios where the handler registration should fail.
@@ -195,7 +233,7 @@ func TestRegisterFailure(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mux := http.NewServeMux()
-			err := Register(context.Background(), tt.mCfgs, mux, tt.mockManager)
+			_, err := Register(context.Background(), tt.mCfgs, mux, tt.mockManager)
 			if err == nil {
 				t.Errorf("expected an error but got nil")
 			}
@@ -266,9 +266,21 @@ func Request(ctx context.Context, r *http.Request, body []byte) {
 	addCtx(ctx, event)
 	event.Str("method", r.Method).
 		Str("url", r.URL.String()).
-		// Str("body", string(body)).
-		Str("remoteAddr", r.RemoteAddr).
-		Msg("HTTP Request")
+		Str("remoteAddr", r.RemoteAddr)
+	if len(body) > 0 {
+		event.Str("body", string(body))
+	}
+	event.Msg("HTTP Request")
+}
+
+// RequestError logs a failed HTTP request at error level, always including its body.
+func RequestError(ctx context.Context, r *http.Request, body []byte, err error) {
+	event := logger.Error().Err(err)
+	addCtx(ctx, event)
+	event.Str("method", r.Method).
+		Str("url", r.URL.String()).
+		Str("body", string(body)).
+		Msg("HTTP Request Failed")
 }
 
 // addCtx adds context values to the log event based on configured context keys.
@@ -293,3 +305,26 @@ func Response(ctx context.Context, r *http.Request, statusCode int, responseTime
 		Dur("responseTime", responseTime).
 		Msg("HTTP Response")
 }
+
+// AccessEntry describes a completed request for the access log.
+type AccessEntry struct {
+	Method       string
+	Path         string
+	Status       int
+	Duration     time.Duration
+	SubscriberID string
+	Outcome      string
+}
+
+// Access logs a single structured entry for a completed request.
+func Access(ctx context.Context, e AccessEntry) {
+	event := logger.Info()
+	addCtx(ctx, event)
+	event.Str("method", e.Method).
+		Str("path", e.Path).
+		Int("statusCode", e.Status).
+		Dur("duration", e.Duration).
+		Str("subscriberId", e.SubscriberID).
+		Str("outcome", e.Outcome).
+		Msg("HTTP Access")
+}
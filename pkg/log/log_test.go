@@ -247,6 +247,50 @@ func TestRequest(t *testing.T) {
 	}
 }
 
+func TestRequestBody(t *testing.T) {
+	logPath := setupLogger(t, InfoLevel)
+	ctx := context.Background()
+	req, _ := http.NewRequest("POST", "/api/body", nil)
+	Request(ctx, req, nil)
+	Request(ctx, req, []byte(`{"sampled":true}`))
+	var bodies []interface{}
+	for _, line := range readLogFile(t, logPath) {
+		if line == "" {
+			continue
+		}
+		logEntry := parseLogLine(t, line)
+		if logEntry["message"] == "HTTP Request" && logEntry["url"] == "/api/body" {
+			bodies = append(bodies, logEntry["body"])
+		}
+	}
+	want := []interface{}{nil, `{"sampled":true}`}
+	if len(bodies) != len(want) || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("logged bodies = %v, want %v", bodies, want)
+	}
+}
+
+func TestRequestError(t *testing.T) {
+	logPath := setupLogger(t, InfoLevel)
+	req, _ := http.NewRequest("POST", "/api/failed", nil)
+	RequestError(context.Background(), req, []byte(`{"key":"value"}`), errors.New("step failed"))
+	var entry map[string]interface{}
+	for _, line := range readLogFile(t, logPath) {
+		if line == "" {
+			continue
+		}
+		logEntry := parseLogLine(t, line)
+		if logEntry["message"] == "HTTP Request Failed" {
+			entry = logEntry
+		}
+	}
+	if entry == nil {
+		t.Fatal("expected failed request log entry, but it was not found in logs")
+	}
+	if entry["body"] != `{"key":"value"}` || entry["error"] != "step failed" || entry["level"] != "error" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
+
 func TestResponse(t *testing.T) {
 	logPath := setupLogger(t, InfoLevel)
 	ctx := context.WithValue(context.Background(), requestID, "abc-123")
@@ -281,6 +325,47 @@ func TestResponse(t *testing.T) {
 	}
 }
 
+func TestAccess(t *testing.T) {
+	logPath := setupLogger(t, InfoLevel)
+	ctx := context.WithValue(context.Background(), model.ContextKeyTxnID, "txn-1")
+	Access(ctx, AccessEntry{
+		Method:       "POST",
+		Path:         "/bap/receiver/on_search",
+		Status:       200,
+		Duration:     time.Millisecond * 42,
+		SubscriberID: "bap.example.com",
+		Outcome:      "nack",
+	})
+	lines := readLogFile(t, logPath)
+	var entry map[string]interface{}
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		logEntry := parseLogLine(t, line)
+		if logEntry["message"] == "HTTP Access" {
+			entry = logEntry
+		}
+	}
+	if entry == nil {
+		t.Fatal("expected access log entry, but it was not found in logs")
+	}
+	want := map[string]interface{}{
+		"method":         "POST",
+		"path":           "/bap/receiver/on_search",
+		"statusCode":     float64(200),
+		"duration":       float64(42),
+		"subscriberId":   "bap.example.com",
+		"outcome":        "nack",
+		"transaction_id": "txn-1",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("entry[%q] = %v, want %v", k, entry[k], v)
+		}
+	}
+}
+
 func TestFatal(t *testing.T) {
 	logPath := setupLogger(t, FatalLevel)
 	ctx := context.WithValue(context.Background(), model.ContextKeySubscriberID, "12345")
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// SignatureStatus is the outcome of validating the signature of a request.
+type SignatureStatus string
+
+const (
+	// SignatureValid marks requests whose signature was validated.
+	SignatureValid SignatureStatus = "valid"
+	// SignatureInvalid marks requests rejected for their signature.
+	SignatureInvalid SignatureStatus = "invalid"
+	// SignatureUnchecked marks requests whose signature was not validated.
+	SignatureUnchecked SignatureStatus = "unchecked"
+)
+
+// AuditRecord is the audit trail entry of a request processed by a module.
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Module string    `json:"module"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+	// SubscriberID is the subscriber the request was processed for.
+	SubscriberID  string `json:"subscriber_id,omitempty"`
+	Action        string `json:"action,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	MessageID     string `json:"message_id,omitempty"`
+	// Status is the HTTP status of the response and Outcome is ack or nack.
+	Status  int    `json:"status"`
+	Outcome string `json:"outcome"`
+	// Error is the error the request was NACKed with by a step, if any.
+	Error     string          `json:"error,omitempty"`
+	Signature SignatureStatus `json:"signature"`
+	// Signer is the subscriber whose signature was validated.
+	Signer   string        `json:"signer,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
@@ -13,6 +13,8 @@ type Error struct {
 	Paths   string `json:"paths,omitempty"`
 	Message string `json:"message"`
 	Context any    `json:"context,omitempty"`
+	// Suggestion hints at a fix for the error, such as the values a field accepts.
+	Suggestion string `json:"suggestion,omitempty"`
 }
 
 // This implements the error interface for the Error struct.
@@ -50,7 +52,11 @@ func (e *SchemaValidationErr) BecknError() *Error {
 		if err.Paths != "" {
 			paths = append(paths, err.Paths)
 		}
-		messages = append(messages, err.Message)
+		message := err.Message
+		if err.Suggestion != "" {
+			message = fmt.Sprintf("%s (%s)", message, err.Suggestion)
+		}
+		messages = append(messages, message)
 	}
 
 	return &Error{
@@ -60,6 +66,53 @@ func (e *SchemaValidationErr) BecknError() *Error {
 	}
 }
 
+// OndcValidationErr occurs when ONDC protocol validation errors are encountered.
+type OndcValidationErr struct {
+	Errors []Error
+}
+
+// This implements the error interface for OndcValidationErr.
+func (e *OndcValidationErr) Error() string {
+	var errorMessages []string
+	for _, err := range e.Errors {
+		errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", err.Paths, err.Message))
+	}
+	return strings.Join(errorMessages, "; ")
+}
+
+// BecknError converts the OndcValidationErr to an instance of Error.
+// If every entry carries the same code, that code is used; otherwise Bad Request.
+func (e *OndcValidationErr) BecknError() *Error {
+	if len(e.Errors) == 0 {
+		return &Error{
+			Code:    http.StatusText(http.StatusBadRequest),
+			Message: "ONDC validation error.",
+		}
+	}
+
+	code := e.Errors[0].Code
+	var paths []string
+	var messages []string
+	for _, err := range e.Errors {
+		if err.Code != code {
+			code = ""
+		}
+		if err.Paths != "" {
+			paths = append(paths, err.Paths)
+		}
+		messages = append(messages, err.Message)
+	}
+	if code == "" {
+		code = http.StatusText(http.StatusBadRequest)
+	}
+
+	return &Error{
+		Code:    code,
+		Paths:   strings.Join(paths, ";"),
+		Message: strings.Join(messages, ";\n "),
+	}
+}
+
 // SignValidationErr occurs when signature validation fails.
 type SignValidationErr struct {
 	error
@@ -88,6 +141,11 @@ func NewBadReqErr(err error) *BadReqErr {
 	return &BadReqErr{err}
 }
 
+// Unwrap returns the error the BadReqErr was created from.
+func (e *BadReqErr) Unwrap() error {
+	return e.error
+}
+
 // BecknError converts the BadReqErr to an instance of Error.
 func (e *BadReqErr) BecknError() *Error {
 	return &Error{
@@ -114,6 +172,52 @@ func (e *NotFoundErr) BecknError() *Error {
 	}
 }
 
+// TooManyRequestsErr occurs when a request is throttled by a rate limit.
+type TooManyRequestsErr struct {
+	error
+}
+
+// NewTooManyRequestsErr creates a new instance of TooManyRequestsErr from an error.
+func NewTooManyRequestsErr(err error) *TooManyRequestsErr {
+	return &TooManyRequestsErr{err}
+}
+
+// BecknError converts the TooManyRequestsErr to an instance of Error.
+func (e *TooManyRequestsErr) BecknError() *Error {
+	return &Error{
+		Code:    http.StatusText(http.StatusTooManyRequests),
+		Message: "Too many requests: " + e.Error(),
+	}
+}
+
+// ForbiddenErr occurs when a request comes from a subscriber that is not allowed access.
+type ForbiddenErr struct {
+	error
+}
+
+// NewForbiddenErr creates a new instance of ForbiddenErr from an error.
+func NewForbiddenErr(err error) *ForbiddenErr {
+	return &ForbiddenErr{err}
+}
+
+// BecknError converts the ForbiddenErr to an instance of Error.
+func (e *ForbiddenErr) BecknError() *Error {
+	return &Error{
+		Code:    http.StatusText(http.StatusForbidden),
+		Message: "Forbidden: " + e.Error(),
+	}
+}
+
+// Behaviors supported by WorkbenchErr.
+const (
+	// WorkbenchBehaviorNACK responds with a NACK and HTTP 200.
+	WorkbenchBehaviorNACK = "NACK"
+	// WorkbenchBehaviorHTTP responds with a NACK using the error code as HTTP status.
+	WorkbenchBehaviorHTTP = "HTTP"
+	// WorkbenchBehaviorACK acknowledges the request and drops it.
+	WorkbenchBehaviorACK = "ACK"
+)
+
 // WorkbenchErr represents an error occurring in the workbench processing.
 type WorkbenchErr struct {
 	Err Error
@@ -134,7 +238,7 @@ func (e *WorkbenchErr) BecknError() *Error {
 
 /* NewWorkbenchErr creates a new instance of workbenchErr.
 valid errType values: BAD_REQUEST, UNAUTHORIZED, NOT_FOUND, INTERNAL
-valid behavior values: NACK or LOG or HTTP
+valid behavior values: NACK, ACK, LOG or HTTP
 */
 func NewWorkbenchErr(errType, message, behavior string,context any) *WorkbenchErr {
 	return &WorkbenchErr{
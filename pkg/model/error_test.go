@@ -74,6 +74,66 @@ func TestSchemaValidationErr_BecknError(t *testing.T) {
 	}
 }
 
+func TestSchemaValidationErr_BecknErrorSuggestions(t *testing.T) {
+	schemaErr := &SchemaValidationErr{
+		Errors: []Error{
+			{Paths: "context.action", Message: "value must be one of 'search', 'select'", Suggestion: `did you mean one of ["search", "select"]?`},
+			{Paths: "context", Message: "missing property 'bap_id'"},
+		},
+	}
+
+	beErr := schemaErr.BecknError()
+
+	assert.Equal(t, "value must be one of 'search', 'select' (did you mean one of [\"search\", \"select\"]?);\n missing property 'bap_id'", beErr.Message)
+}
+
+func TestOndcValidationErr_BecknError(t *testing.T) {
+	tests := []struct {
+		name     string
+		errs     []Error
+		expected Error
+	}{
+		{
+			name:     "no errors",
+			expected: Error{Code: "Bad Request", Message: "ONDC validation error."},
+		},
+		{
+			name: "shared code is kept",
+			errs: []Error{
+				{Code: "30000", Paths: "message.order.items", Message: "items are required"},
+				{Code: "30000", Paths: "context.city", Message: "invalid city"},
+			},
+			expected: Error{Code: "30000", Paths: "message.order.items;context.city", Message: "items are required;\n invalid city"},
+		},
+		{
+			name: "mixed codes fall back to bad request",
+			errs: []Error{
+				{Code: "30000", Paths: "message.order.items", Message: "items are required"},
+				{Code: "30001", Message: "provider not found"},
+			},
+			expected: Error{Code: "Bad Request", Paths: "message.order.items", Message: "items are required;\n provider not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beErr := (&OndcValidationErr{Errors: tt.errs}).BecknError()
+			assert.Equal(t, tt.expected, *beErr)
+		})
+	}
+}
+
+func TestOndcValidationErr_Error(t *testing.T) {
+	ondcErr := &OndcValidationErr{
+		Errors: []Error{
+			{Paths: "context.city", Message: "invalid city"},
+			{Paths: "message.order", Message: "order is required"},
+		},
+	}
+
+	assert.Equal(t, "context.city: invalid city; message.order: order is required", ondcErr.Error())
+}
+
 func TestSignValidationErr_BecknError(t *testing.T) {
 	signErr := NewSignValidationErr(errors.New("signature failed"))
 	beErr := signErr.BecknError()
@@ -2,7 +2,9 @@ package model
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
@@ -53,6 +55,14 @@ const (
 
 	// ContextKeyModuleID is the context key for storing and retrieving the model ID from a request context.
 	ContextKeyModuleID ContextKey = "module_id"
+
+	// ContextKeySchemaVersion is the context key of a schema version pinned for a request by a trusted
+	// header, which schema validators use instead of the version in the request body.
+	ContextKeySchemaVersion ContextKey = "schema_version"
+
+	// ContextKeyRequestTarget is the context key of the request target, such as "post /bap/caller/search",
+	// which sign validators use to verify signatures covering (request-target).
+	ContextKeyRequestTarget ContextKey = "request_target"
 )
 
 var contextKeys = map[string]ContextKey{
@@ -109,6 +119,11 @@ var validRoles = map[Role]bool{
 	RoleRegistery: true,
 }
 
+// Valid reports whether r is a known participant role.
+func (r Role) Valid() bool {
+	return validRoles[r]
+}
+
 // UnmarshalYAML implements custom YAML unmarshalling for Role to ensure only valid values are accepted.
 func (r *Role) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var roleName string
@@ -131,6 +146,26 @@ type Route struct {
 	URL         *url.URL // For API calls
 	ActAsProxy  bool     // Whether to act as a proxy for this route
 	JsonPath	string   // JSONPath to extract URL from http request -> internal use only
+	Headers     map[string]string // Fixed headers set on requests forwarded to URL, e.g. API keys
+	ResponseBody json.RawMessage // Response to requests not proxied, when no custom-response-body cookie is set; ACK when nil
+	ResponseHeaders map[string]string // Headers set on responses proxied back from URL, e.g. CORS or cache-control; an empty value removes the header
+	ResponseStatus map[int]int // Status codes of responses proxied back from URL replaced by others, e.g. 202 by 200
+}
+
+// protectedHeaders are set by the adapter itself, e.g. when signing, and cannot be
+// overridden through Route.Headers.
+var protectedHeaders = map[string]bool{
+	AuthHeaderSubscriber: true,
+	AuthHeaderGateway:    true,
+	"Content-Type":       true,
+	"Content-Length":     true,
+	"Content-Encoding":   true,
+	"Host":               true,
+}
+
+// IsProtectedHeader reports whether name is a header that Route.Headers cannot override.
+func IsProtectedHeader(name string) bool {
+	return protectedHeaders[http.CanonicalHeaderKey(name)]
 }
 
 // Keyset represents a collection of cryptographic keys used for signing and encryption.
@@ -138,6 +173,7 @@ type Keyset struct {
 	SubscriberID   string
 	UniqueKeyID    string // UniqueKeyID is the identifier for the key pair.
 	SigningPrivate string // SigningPrivate is the private key used for signing operations.
+	SigningKeyRef  string // SigningKeyRef references a signing key held in an HSM or KMS; set instead of SigningPrivate when the key cannot be exported.
 	SigningPublic  string // SigningPublic is the public key corresponding to the signing private key.
 	EncrPrivate    string // EncrPrivate is the private key used for encryption operations.
 	EncrPublic     string // EncrPublic is the public key corresponding to the encryption private key.
@@ -152,6 +188,16 @@ type StepContext struct {
 	SubID      string
 	Role       Role
 	RespHeader http.Header
+	// BecknContext is the context parsed once from Body, or nil if Body has no valid context.
+	BecknContext *BecknContext
+	// BodyReader gives seekable access to the request body as received. Large bodies may be
+	// backed by a temporary file, in which case Body is nil until BodyBytes loads it.
+	BodyReader io.ReadSeeker
+	// Subscriber is the registry record of SubID, set by the enrichRegistry step.
+	Subscriber *Subscription
+	// Signer is the subscriber ID whose signature the validateSign step verified, or
+	// empty when no signature was validated.
+	Signer string
 }
 
 // WithContext updates the existing StepContext with a new context.
@@ -159,6 +205,26 @@ func (ctx *StepContext) WithContext(newCtx context.Context) {
 	ctx.Context = newCtx
 }
 
+// BodyBytes returns Body, loading it from BodyReader first if it is not populated.
+func (ctx *StepContext) BodyBytes() ([]byte, error) {
+	if ctx.Body != nil || ctx.BodyReader == nil {
+		return ctx.Body, nil
+	}
+	size, err := ctx.BodyReader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to size body: %w", err)
+	}
+	if _, err := ctx.BodyReader.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind body: %w", err)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(ctx.BodyReader, body); err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	ctx.Body = body
+	return body, nil
+}
+
 // Status represents the acknowledgment status in a response.
 type Status string
 
@@ -188,4 +254,75 @@ type Response struct {
 	Context  any `json:"context,omitempty"`
 	Message Message `json:"message"`
 	Error	*Error  `json:"error,omitempty"`
-}
\ No newline at end of file
+}
+// BecknContext holds the commonly used fields of the Beckn `context` object.
+type BecknContext struct {
+	Domain        string `json:"domain"`
+	Action        string `json:"action"`
+	Version       string `json:"version,omitempty"`
+	CoreVersion   string `json:"core_version,omitempty"`
+	BapID         string `json:"bap_id,omitempty"`
+	BapURI        string `json:"bap_uri,omitempty"`
+	BppID         string `json:"bpp_id,omitempty"`
+	BppURI        string `json:"bpp_uri,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	MessageID     string `json:"message_id,omitempty"`
+	Timestamp     string `json:"timestamp,omitempty"`
+}
+
+// ParseBecknContext extracts the Beckn context object from a request body.
+func ParseBecknContext(body []byte) (*BecknContext, error) {
+	var payload struct {
+		Context BecknContext `json:"context"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse context: %w", err)
+	}
+	return &payload.Context, nil
+}
+
+// DecodeBecknContext extracts the Beckn context object from a request body read from r.
+// Unlike ParseBecknContext it does not hold the rest of the body in memory.
+func DecodeBecknContext(r io.Reader) (*BecknContext, error) {
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("failed to parse context: body is not a JSON object")
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse context: %w", err)
+		}
+		if key == "context" {
+			var bCtx BecknContext
+			if err := dec.Decode(&bCtx); err != nil {
+				return nil, fmt.Errorf("failed to parse context: %w", err)
+			}
+			return &bCtx, nil
+		}
+		if err := skipJSONValue(dec); err != nil {
+			return nil, fmt.Errorf("failed to parse context: %w", err)
+		}
+	}
+	return &BecknContext{}, nil
+}
+
+// skipJSONValue consumes the next value from dec token by token.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package definition
+
+import (
+	"context"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// Auditor records an audit trail of the requests processed by a module, for example in
+// a database or an append-only log.
+type Auditor interface {
+	// Audit records a processed request. It is called after the response has been sent.
+	Audit(ctx context.Context, record model.AuditRecord) error
+}
+
+// AuditorProvider is the interface for creating new Auditor instances.
+type AuditorProvider interface {
+	// New initializes a new auditor instance with the given configuration.
+	New(ctx context.Context, config map[string]string) (Auditor, func() error, error)
+}
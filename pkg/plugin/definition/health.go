@@ -0,0 +1,24 @@
+package definition
+
+import "context"
+
+// HealthChecker is an optional interface implemented by plugins that can report
+// whether they are currently able to serve requests.
+type HealthChecker interface {
+	// HealthCheck returns an error if the plugin is not healthy.
+	HealthCheck(ctx context.Context) error
+}
+
+// SelfTester is an optional interface implemented by plugins that can verify at
+// startup that their configuration and backing resources are usable.
+type SelfTester interface {
+	// SelfTest returns an error describing why the plugin is misconfigured.
+	SelfTest(ctx context.Context) error
+}
+
+// Warmer is an optional interface implemented by plugins that do expensive work
+// lazily, such as compiling schemas, and can do it ahead of the first request.
+type Warmer interface {
+	// WarmUp prepares the given targets, whose meaning is defined by the plugin.
+	WarmUp(ctx context.Context, targets []string) error
+}
@@ -5,6 +5,10 @@ import (
 	"net/url"
 )
 
+// OndcValidator validates payloads against ONDC protocol rules.
+// ValidatePayload should return a *model.OndcValidationErr carrying per-field
+// details so they can be reported back in the NACK.
+// Implementations may also implement SelfTester to be checked at handler startup.
 type OndcValidator interface {
 	ValidatePayload(ctx context.Context, url *url.URL, payload []byte) error
 	SaveValidationData(ctx context.Context, url *url.URL, payload []byte) error
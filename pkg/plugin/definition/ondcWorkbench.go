@@ -3,14 +3,44 @@ package definition
 import (
 	"context"
 	"net/http"
-)
 
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
 
+// OndcWorkbench intercepts requests for the ONDC workbench.
+// Implementations may also implement SelfTester to be checked at handler startup.
 type OndcWorkbench interface {
 	WorkbenchReceiver(context.Context,*http.Request,[]byte) (error)
 	WorkbenchValidateContext(context.Context,*http.Request,[]byte) (error)
 }
 
+// WorkbenchInput carries request data already parsed by the handler, so workbench
+// implementations do not need to re-read the request.
+type WorkbenchInput struct {
+	Request *http.Request
+	Body    []byte
+	// Context is the parsed Beckn context, or nil if the body could not be parsed.
+	Context *model.BecknContext
+	// Cookies maps request cookie names to their values.
+	Cookies map[string]string
+}
+
+// StructuredWorkbench is an optional interface implemented by OndcWorkbench plugins
+// that accept pre-parsed input. When implemented, the handler calls these methods
+// instead of WorkbenchReceiver and WorkbenchValidateContext.
+type StructuredWorkbench interface {
+	WorkbenchReceiveInput(context.Context, *WorkbenchInput) error
+	WorkbenchValidateContextInput(context.Context, *WorkbenchInput) error
+}
+
+// WorkbenchRouter is an optional interface implemented by OndcWorkbench plugins
+// that determine the downstream target while receiving a request.
+type WorkbenchRouter interface {
+	// WorkbenchRoute returns the route for the request, or nil if the workbench
+	// leaves routing to the Router plugin.
+	WorkbenchRoute(context.Context, *http.Request, []byte) (*model.Route, error)
+}
+
 type OndcWorkbenchProvider interface {
 	New(context.Context,Cache,map[string]string) (OndcWorkbench, func() error, error)
 }
\ No newline at end of file
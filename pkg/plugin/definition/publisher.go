@@ -1,13 +1,36 @@
 package definition
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // Publisher defines the general publisher interface for messaging plugins.
+// Publishers may also implement HealthChecker to take part in readiness checks.
 type Publisher interface {
 	// Publish sends a message (as a byte slice) using the underlying messaging system.
 	Publish(context.Context, string, []byte) error
 }
 
+// Flusher is an optional interface implemented by publishers that buffer messages,
+// such as batching publishers, so pending messages can be sent before shutdown.
+type Flusher interface {
+	// Flush sends all buffered messages.
+	Flush(ctx context.Context) error
+}
+
+// ErrPublisherFull is returned by TryPublish when the publisher's queue has no room for
+// the message.
+var ErrPublisherFull = errors.New("publisher queue is full")
+
+// BackpressurePublisher is an optional interface implemented by publishers with a bounded
+// queue, so callers can give up instead of blocking while it is full.
+type BackpressurePublisher interface {
+	// TryPublish sends a message like Publish without waiting for room in the queue. It
+	// returns ErrPublisherFull, leaving the message unpublished, when the queue is full.
+	TryPublish(ctx context.Context, topic string, msg []byte) error
+}
+
 // PublisherProvider is the interface for creating new Publisher instances.
 type PublisherProvider interface {
 	// New initializes a new publisher instance with the given configuration.
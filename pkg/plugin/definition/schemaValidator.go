@@ -2,6 +2,7 @@ package definition
 
 import (
 	"context"
+	"errors"
 	"net/url"
 )
 
@@ -10,6 +11,25 @@ type SchemaValidator interface {
 	Validate(ctx context.Context, url *url.URL, payload []byte) error
 }
 
+// ErrSchemaNotFound is wrapped by the errors a SchemaValidator returns when it has no
+// schema for a payload, as opposed to one the payload fails.
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// SchemaBatchItem is a payload validated by SchemaBatchValidator, with the URL it
+// is sent to.
+type SchemaBatchItem struct {
+	URL     *url.URL
+	Payload []byte
+}
+
+// SchemaBatchValidator is an optional interface implemented by SchemaValidators that
+// can validate many payloads at once, faster than one Validate call after another.
+type SchemaBatchValidator interface {
+	// ValidateBatch validates each item as Validate would and returns the outcome of
+	// each, in the order of items. An invalid item does not affect the others.
+	ValidateBatch(ctx context.Context, items []SchemaBatchItem) []error
+}
+
 // SchemaValidatorProvider interface for creating validators.
 type SchemaValidatorProvider interface {
 	New(ctx context.Context, config map[string]string) (SchemaValidator, func() error, error)
@@ -10,6 +10,34 @@ type Signer interface {
 	Sign(ctx context.Context, body []byte, privateKeyBase64 string, createdAt, expiresAt int64) (string, error)
 }
 
+// AlgorithmSigner is an optional interface implemented by Signers that support more
+// than one signature algorithm, so the algorithm can be negotiated per request.
+type AlgorithmSigner interface {
+	// Algorithms lists the supported algorithms as they appear in the keyId of the
+	// Authorization header, such as "ed25519".
+	Algorithms() []string
+	// SignWithAlgorithm generates a signature like Sign, using the named algorithm.
+	SignWithAlgorithm(ctx context.Context, algorithm string, body []byte, privateKeyBase64 string, createdAt, expiresAt int64) (string, error)
+}
+
+// KeyRefSigner is an optional interface implemented by Signers that sign with keys held
+// in an HSM or KMS, so the private key never leaves it. It is used for keysets whose
+// SigningKeyRef is set.
+type KeyRefSigner interface {
+	// SignWithKeyRef generates a signature like Sign, remotely with the key that keyRef
+	// references, using the named algorithm.
+	SignWithKeyRef(ctx context.Context, algorithm string, body []byte, keyRef string, createdAt, expiresAt int64) (string, error)
+}
+
+// DetachedJWSSigner is an optional interface implemented by Signers that can sign with
+// detached JWS signatures (RFC 7515, appendix F) instead of the Beckn Signature header.
+type DetachedJWSSigner interface {
+	// SignDetachedJWS returns the compact JWS over payload with the payload left out,
+	// in the form "<protected header>..<signature>". keyID is set as the kid of the
+	// protected header, along with the creation and expiry times as iat and exp.
+	SignDetachedJWS(ctx context.Context, payload []byte, privateKeyBase64, keyID string, createdAt, expiresAt int64) (string, error)
+}
+
 // SignerProvider initializes a new signer instance with the given config.
 type SignerProvider interface {
 	// New creates a new signer instance based on the provided config.
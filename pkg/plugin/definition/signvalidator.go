@@ -8,6 +8,26 @@ type SignValidator interface {
 	Validate(ctx context.Context, body []byte, header string, publicKeyBase64 string) error
 }
 
+// AlgorithmValidator is an optional interface implemented by SignValidators that support
+// more than one signature algorithm, so each signature is checked with the primitive of
+// the algorithm named in its keyId.
+type AlgorithmValidator interface {
+	// Algorithms lists the supported algorithms as they appear in the keyId of the
+	// Authorization header, such as "ed25519".
+	Algorithms() []string
+	// ValidateWithAlgorithm checks the signature like Validate, using the named algorithm.
+	// It fails if the public key is not a key of that algorithm.
+	ValidateWithAlgorithm(ctx context.Context, algorithm string, body []byte, header string, publicKeyBase64 string) error
+}
+
+// DetachedJWSValidator is an optional interface implemented by SignValidators that can
+// validate detached JWS signatures (RFC 7515, appendix F).
+type DetachedJWSValidator interface {
+	// ValidateDetachedJWS checks that jws, a compact JWS with the payload left out, signs
+	// payload with the given public key and is within its validity period.
+	ValidateDetachedJWS(ctx context.Context, payload []byte, jws string, publicKeyBase64 string) error
+}
+
 // SignValidatorProvider initializes a new Verifier instance with the given config.
 type SignValidatorProvider interface {
 	// New creates a new Verifier instance based on the provided config.
@@ -8,13 +8,9 @@ import (
 	"sync"
 	"time"
 
-	clientprom "github.com/prometheus/client_golang/prometheus"
-	clientpromhttp "github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
-	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
@@ -101,22 +97,11 @@ func (Setup) New(ctx context.Context, cfg *Config) (*telemetry.Provider, error)
 		return nil, fmt.Errorf("failed to create telemetry resource: %w", err)
 	}
 
-	registry := clientprom.NewRegistry()
-
-	exporter, err := otelprom.New(
-		otelprom.WithRegisterer(registry),
-		otelprom.WithoutUnits(),
-		otelprom.WithoutScopeInfo(),
-	)
+	meterProvider, metricsHandler, err := telemetry.NewPrometheusMeterProvider(res)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+		return nil, err
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(exporter),
-		metric.WithResource(res),
-	)
-
 	otel.SetMeterProvider(meterProvider)
 	log.Infof(ctx, "OpenTelemetry metrics initialized for service=%s version=%s env=%s",
 		cfg.ServiceName, cfg.ServiceVersion, cfg.Environment)
@@ -125,9 +110,6 @@ func (Setup) New(ctx context.Context, cfg *Config) (*telemetry.Provider, error)
 		log.Warnf(ctx, "Failed to start Go runtime instrumentation: %v", err)
 	}
 
-	// Create metrics handler
-	metricsHandler := clientpromhttp.HandlerFor(registry, clientpromhttp.HandlerOpts{})
-
 	// Create and start metrics HTTP server
 	metricsMux := http.NewServeMux()
 	metricsMux.Handle("/metrics", metricsHandler)
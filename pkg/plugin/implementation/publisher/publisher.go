@@ -125,6 +125,14 @@ func (p *Publisher) Publish(ctx context.Context, routingKey string, msg []byte)
 	return nil
 }
 
+// HealthCheck reports whether the RabbitMQ connection is open.
+func (p *Publisher) HealthCheck(ctx context.Context) error {
+	if p.Conn == nil || p.Conn.IsClosed() {
+		return ErrConnectionFailed
+	}
+	return nil
+}
+
 // DialFunc is a function variable used to establish a connection to RabbitMQ.
 var DialFunc = amqp091.Dial
 
@@ -360,3 +360,11 @@ func TestNewPublisherFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestHealthCheckWithoutConnection(t *testing.T) {
+	p := &Publisher{Config: &Config{Exchange: "mock.exchange"}}
+
+	if err := p.HealthCheck(context.Background()); err != ErrConnectionFailed {
+		t.Errorf("expected ErrConnectionFailed, got: %v", err)
+	}
+}
@@ -44,6 +44,22 @@ type target struct {
 	URL         string `yaml:"url,omitempty"`         // URL for "url" or gateway endpoint for "bpp"/"bap"
 	PublisherID string `yaml:"publisherId,omitempty"` // For "msgq" type
 	ExcludeAction bool `yaml:"excludeAction,omitempty"` // For "url" type to exclude appending action to URL path
+	Headers map[string]string `yaml:"headers,omitempty"` // Fixed headers set on requests forwarded to the target
+	ResponseBody any `yaml:"responseBody,omitempty"` // Response to requests that are not proxied, instead of ACK
+	ResponseHeaders map[string]string `yaml:"responseHeaders,omitempty"` // Headers set on responses proxied back from the target
+	ResponseStatus map[int]int `yaml:"responseStatus,omitempty"` // Status codes of responses proxied back from the target replaced by others
+}
+
+// responseBody returns the JSON form of the configured response body, or nil if none is set.
+func (t target) responseBody() (json.RawMessage, error) {
+	if t.ResponseBody == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(t.ResponseBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule: responseBody cannot be encoded as JSON: %w", err)
+	}
+	return b, nil
 }
 
 // TargetType defines possible target destinations.
@@ -112,14 +128,20 @@ func (r *Router) loadRules(configPath string) error {
 			r.rules[domain][rule.Version] = make(map[string]*model.Route)
 		}
 
+		responseBody, err := rule.Target.responseBody()
+		if err != nil {
+			return err
+		}
+
 		// Add all endpoints for this rule
 		for _, endpoint := range rule.Endpoints {
 			var route *model.Route
 			switch rule.TargetType {
 			case targetTypePublisher:
 				route = &model.Route{
-					TargetType:  rule.TargetType,
-					PublisherID: rule.Target.PublisherID,
+					TargetType:   rule.TargetType,
+					PublisherID:  rule.Target.PublisherID,
+					ResponseBody: responseBody,
 				}
 			case targetTypeURL:
 				parsedURL, err := url.Parse(rule.Target.URL)
@@ -130,8 +152,12 @@ func (r *Router) loadRules(configPath string) error {
 					parsedURL.Path = joinPath(parsedURL, endpoint)
 				}
 				route = &model.Route{
-					TargetType: rule.TargetType,
-					URL:        parsedURL,
+					TargetType:      rule.TargetType,
+					URL:             parsedURL,
+					Headers:         rule.Target.Headers,
+					ResponseBody:    responseBody,
+					ResponseHeaders: rule.Target.ResponseHeaders,
+					ResponseStatus:  rule.Target.ResponseStatus,
 				}
 			case targetTypeBPP, targetTypeBAP:
 				var parsedURL *url.URL
@@ -143,8 +169,12 @@ func (r *Router) loadRules(configPath string) error {
 					parsedURL.Path = joinPath(parsedURL, endpoint)
 				}
 				route = &model.Route{
-					TargetType: rule.TargetType,
-					URL:        parsedURL,
+					TargetType:      rule.TargetType,
+					URL:             parsedURL,
+					Headers:         rule.Target.Headers,
+					ResponseBody:    responseBody,
+					ResponseHeaders: rule.Target.ResponseHeaders,
+					ResponseStatus:  rule.Target.ResponseStatus,
 				}
 			}
 			// Check for conflicting v2 rules
@@ -173,6 +203,22 @@ func validateRules(rules []routingRule) error {
 			return fmt.Errorf("invalid rule: domain is required for version %s", rule.Version)
 		}
 
+		for name := range rule.Target.Headers {
+			if model.IsProtectedHeader(name) {
+				return fmt.Errorf("invalid rule: header %s is set by the adapter and cannot be configured", name)
+			}
+		}
+		for name := range rule.Target.ResponseHeaders {
+			if model.IsProtectedHeader(name) {
+				return fmt.Errorf("invalid rule: response header %s is set by the adapter and cannot be configured", name)
+			}
+		}
+		for from, to := range rule.Target.ResponseStatus {
+			if from < 100 || from > 599 || to < 100 || to > 599 {
+				return fmt.Errorf("invalid rule: responseStatus must map HTTP status codes, got %d: %d", from, to)
+			}
+		}
+
 		// Validate based on TargetType
 		switch rule.TargetType {
 		case targetTypeURL:
@@ -183,9 +229,8 @@ func validateRules(rules []routingRule) error {
 				return fmt.Errorf("invalid URL - %s: %w", rule.Target.URL, err)
 			}
 		case targetTypePublisher:
-			if rule.Target.PublisherID == "" {
-				return fmt.Errorf("invalid rule: publisherID is required for targetType 'publisher'")
-			}
+			// PublisherID may be left unset for the handler's publisher rules or
+			// the publisher's default to choose it.
 		case targetTypeBPP, targetTypeBAP:
 			if rule.Target.URL != "" {
 				if _, err := url.Parse(rule.Target.URL); err != nil {
@@ -267,8 +312,12 @@ func handleProtocolMapping(route *model.Route, npURI, endpoint string) (*model.R
 			return nil, fmt.Errorf("could not determine destination for endpoint '%s': neither request contained a %s URI nor was a default URL configured in routing rules", endpoint, strings.ToUpper(route.TargetType))
 		}
 		return &model.Route{
-			TargetType: targetTypeURL,
-			URL:        route.URL,
+			TargetType:      targetTypeURL,
+			URL:             route.URL,
+			Headers:         route.Headers,
+			ResponseBody:    route.ResponseBody,
+			ResponseHeaders: route.ResponseHeaders,
+			ResponseStatus:  route.ResponseStatus,
 		}, nil
 	}
 	targetURL, err := url.Parse(target)
@@ -277,8 +326,12 @@ func handleProtocolMapping(route *model.Route, npURI, endpoint string) (*model.R
 	}
 	targetURL.Path = joinPath(targetURL, endpoint)
 	return &model.Route{
-		TargetType: targetTypeURL,
-		URL:        targetURL,
+		TargetType:      targetTypeURL,
+		URL:             targetURL,
+		Headers:         route.Headers,
+		ResponseBody:    route.ResponseBody,
+		ResponseHeaders: route.ResponseHeaders,
+		ResponseStatus:  route.ResponseStatus,
 	}, nil
 }
 
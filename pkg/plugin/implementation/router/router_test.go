@@ -224,6 +224,17 @@ func TestValidateRulesSuccess(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Valid rules with publisher routing without publisherID",
+			rules: []routingRule{
+				{
+					Domain:     "retail",
+					Version:    "1.0.0",
+					TargetType: "publisher",
+					Endpoints:  []string{"search", "select"},
+				},
+			},
+		},
 		{
 			name: "Valid rules with bpp routing to gateway",
 			rules: []routingRule{
@@ -293,6 +304,54 @@ func TestValidateRulesFailure(t *testing.T) {
 			},
 			wantErr: "invalid rule: domain is required for version 1.0.0",
 		},
+		{
+			name: "Protected route header",
+			rules: []routingRule{
+				{
+					Domain:     "retail",
+					Version:    "1.0.0",
+					TargetType: "url",
+					Target: target{
+						URL:     "https://example.com/api",
+						Headers: map[string]string{"authorization": "Bearer token"},
+					},
+					Endpoints: []string{"search"},
+				},
+			},
+			wantErr: "invalid rule: header authorization is set by the adapter and cannot be configured",
+		},
+		{
+			name: "Protected response header",
+			rules: []routingRule{
+				{
+					Domain:     "retail",
+					Version:    "1.0.0",
+					TargetType: "url",
+					Target: target{
+						URL:             "https://example.com/api",
+						ResponseHeaders: map[string]string{"Content-Length": "0"},
+					},
+					Endpoints: []string{"search"},
+				},
+			},
+			wantErr: "invalid rule: response header Content-Length is set by the adapter and cannot be configured",
+		},
+		{
+			name: "Invalid response status",
+			rules: []routingRule{
+				{
+					Domain:     "retail",
+					Version:    "1.0.0",
+					TargetType: "url",
+					Target: target{
+						URL:            "https://example.com/api",
+						ResponseStatus: map[int]int{202: 2000},
+					},
+					Endpoints: []string{"search"},
+				},
+			},
+			wantErr: "invalid rule: responseStatus must map HTTP status codes, got 202: 2000",
+		},
 		{
 			name: "Missing version",
 			rules: []routingRule{
@@ -366,21 +425,6 @@ func TestValidateRulesFailure(t *testing.T) {
 			},
 			wantErr: `invalid URL - htp:// invalid-url.com: parse "htp:// invalid-url.com": invalid character " " in host name`,
 		},
-		{
-			name: "Missing topic_id for targetType: publisher",
-			rules: []routingRule{
-				{
-					Domain:     "retail",
-					Version:    "1.0.0",
-					TargetType: "publisher",
-					Target:     target{
-						// PublisherID is missing
-					},
-					Endpoints: []string{"search", "select"},
-				},
-			},
-			wantErr: "invalid rule: publisherID is required for targetType 'publisher'",
-		},
 		{
 			name: "Invalid URL for BPP targetType",
 			rules: []routingRule{
@@ -791,3 +835,93 @@ func TestV1DomainRequired(t *testing.T) {
 		t.Errorf("loadRules() error = %v, want error containing %q", err, expectedErr)
 	}
 }
+
+// TestRouteHeaders tests that target headers and response rewrites are carried on the
+// resolved route.
+func TestRouteHeaders(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		body            string
+		wantHeaders     map[string]string
+		wantRespHeaders map[string]string
+		wantRespStatus  map[int]int
+	}{
+		{
+			name:            "url target",
+			url:             "https://example.com/v1/ondc/search",
+			body:            `{"context": {"domain": "ONDC:TRV10", "version": "1.1.0"}}`,
+			wantHeaders:     map[string]string{"X-Api-Key": "backend-key", "X-Tenant-Id": "tenant-1"},
+			wantRespHeaders: map[string]string{"Access-Control-Allow-Origin": "*", "Cache-Control": "no-store"},
+			wantRespStatus:  map[int]int{202: 200},
+		},
+		{
+			name:        "bpp target resolved from bpp_uri",
+			url:         "https://example.com/v1/ondc/select",
+			body:        `{"context": {"domain": "ONDC:TRV10", "version": "1.1.0", "bpp_uri": "https://bpp1.example.com"}}`,
+			wantHeaders: map[string]string{"X-Tenant-Id": "tenant-2"},
+		},
+	}
+
+	router, _, _ := setupRouter(t, "route_headers.yaml")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsedURL, _ := url.Parse(tt.url)
+			route, err := router.Route(context.Background(), parsedURL, []byte(tt.body), nil)
+			if err != nil {
+				t.Fatalf("router.Route() err = %v, want nil", err)
+			}
+			if !reflect.DeepEqual(route.Headers, tt.wantHeaders) {
+				t.Errorf("route.Headers = %v, want %v", route.Headers, tt.wantHeaders)
+			}
+			if !reflect.DeepEqual(route.ResponseHeaders, tt.wantRespHeaders) {
+				t.Errorf("route.ResponseHeaders = %v, want %v", route.ResponseHeaders, tt.wantRespHeaders)
+			}
+			if !reflect.DeepEqual(route.ResponseStatus, tt.wantRespStatus) {
+				t.Errorf("route.ResponseStatus = %v, want %v", route.ResponseStatus, tt.wantRespStatus)
+			}
+		})
+	}
+}
+
+// TestRouteResponseBody tests that a configured response body is attached to routes as JSON.
+func TestRouteResponseBody(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		body string
+		want string
+	}{
+		{
+			name: "publisher target",
+			url:  "https://example.com/v1/ondc/search",
+			body: `{"context": {"domain": "ONDC:TRV10", "version": "1.1.0"}}`,
+			want: `{"message":{"ack":{"status":"ACK"},"tags":[{"code":"queued"}]}}`,
+		},
+		{
+			name: "bpp target resolved from bpp_uri",
+			url:  "https://example.com/v1/ondc/select",
+			body: `{"context": {"domain": "ONDC:TRV10", "version": "1.1.0", "bpp_uri": "https://bpp1.example.com"}}`,
+			want: `{"message":{"ack":{"status":"ACK"}}}`,
+		},
+		{
+			name: "not configured",
+			url:  "https://example.com/v1/ondc/init",
+			body: `{"context": {"domain": "ONDC:TRV10", "version": "1.1.0"}}`,
+		},
+	}
+
+	router, _, _ := setupRouter(t, "route_response_body.yaml")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsedURL, _ := url.Parse(tt.url)
+			route, err := router.Route(context.Background(), parsedURL, []byte(tt.body), nil)
+			if err != nil {
+				t.Fatalf("router.Route() err = %v, want nil", err)
+			}
+			if string(route.ResponseBody) != tt.want {
+				t.Errorf("route.ResponseBody = %s, want %s", route.ResponseBody, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -31,6 +32,43 @@ type referencedObject struct {
 	Data    map[string]interface{}
 }
 
+// extendedSchemaSettings returns the cache TTL, download timeout and allowed domains
+// for extended schemas, applying defaults.
+func (v *schemav2Validator) extendedSchemaSettings() (ttl, timeout time.Duration, allowedDomains []string) {
+	ttl = 86400 * time.Second // 24 hours default
+	timeout = 30 * time.Second
+
+	refConfig := v.config.ExtendedSchemaConfig
+	if refConfig.CacheTTL > 0 {
+		ttl = time.Duration(refConfig.CacheTTL) * time.Second
+	}
+	if refConfig.DownloadTimeout > 0 {
+		timeout = time.Duration(refConfig.DownloadTimeout) * time.Second
+	}
+	return ttl, timeout, refConfig.AllowedDomains
+}
+
+// WarmUp loads the extended schemas for the given @context URLs into the cache so the
+// first requests referencing them do not pay the download and compile cost.
+// It is a no-op when extended schema validation is disabled.
+func (v *schemav2Validator) WarmUp(ctx context.Context, contexts []string) error {
+	if v.schemaCache == nil {
+		return nil
+	}
+	ttl, timeout, allowedDomains := v.extendedSchemaSettings()
+	var errs []error
+	for _, c := range contexts {
+		if !isAllowedDomain(c, allowedDomains) {
+			errs = append(errs, fmt.Errorf("domain not allowed: %s", c))
+			continue
+		}
+		if _, err := v.schemaCache.loadSchemaFromPath(ctx, transformContextToSchemaURL(c), ttl, timeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // schemaCache caches loaded domain schemas with LRU eviction.
 type schemaCache struct {
 	mu      sync.RWMutex
@@ -75,19 +113,7 @@ func (v *schemav2Validator) validateExtendedSchemas(ctx context.Context, body in
 
 	log.Debugf(ctx, "Found %d domain-specific objects with @context for Extended Schema validation", len(objects))
 
-	// Get config with defaults
-	ttl := 86400 * time.Second // 24 hours default
-	timeout := 30 * time.Second
-	var allowedDomains []string
-
-	refConfig := v.config.ExtendedSchemaConfig
-	if refConfig.CacheTTL > 0 {
-		ttl = time.Duration(refConfig.CacheTTL) * time.Second
-	}
-	if refConfig.DownloadTimeout > 0 {
-		timeout = time.Duration(refConfig.DownloadTimeout) * time.Second
-	}
-	allowedDomains = refConfig.AllowedDomains
+	ttl, timeout, allowedDomains := v.extendedSchemaSettings()
 
 	log.Debugf(ctx, "Extended Schema config: ttl=%v, timeout=%v, allowedDomains=%v",
 		ttl, timeout, allowedDomains)
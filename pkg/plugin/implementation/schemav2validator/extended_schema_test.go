@@ -3,6 +3,7 @@ package schemav2validator
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -707,3 +708,24 @@ func TestValidateExtendedSchemas_MissingMessage(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "missing 'message' field")
 }
+
+func TestWarmUp(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "attributes.yaml")
+	assert.NoError(t, os.WriteFile(schemaPath, []byte(`openapi: 3.1.0
+info:
+  title: Test Schema
+  version: 1.0.0`), 0o600))
+	contextURL := filepath.Join(dir, "context.jsonld")
+
+	v := &schemav2Validator{config: &Config{}, schemaCache: newSchemaCache(10)}
+	assert.NoError(t, v.WarmUp(context.Background(), []string{contextURL}))
+	_, found := v.schemaCache.get(hashURL(schemaPath))
+	assert.True(t, found, "warmed-up schema should be cached")
+
+	err := v.WarmUp(context.Background(), []string{filepath.Join(dir, "missing", "context.jsonld")})
+	assert.Error(t, err)
+
+	disabled := &schemav2Validator{config: &Config{}}
+	assert.NoError(t, disabled.WarmUp(context.Background(), []string{contextURL}))
+}
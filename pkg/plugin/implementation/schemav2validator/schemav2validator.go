@@ -11,6 +11,7 @@ import (
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -115,7 +116,7 @@ func (v *schemav2Validator) Validate(ctx context.Context, reqURL *url.URL, data
 	// O(1) lookup from action index
 	schema := spec.actionSchemas[action]
 	if schema == nil || schema.Value == nil {
-		return model.NewBadReqErr(fmt.Errorf("unsupported action: %s", action))
+		return model.NewBadReqErr(fmt.Errorf("%w: unsupported action: %s", definition.ErrSchemaNotFound, action))
 	}
 
 	log.Debugf(ctx, "Validating action: %s", action)
@@ -0,0 +1,24 @@
+package schemavalidator
+
+import (
+	"context"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// ValidateBatch validates each item as Validate would, using up to ValidateConcurrency
+// workers, and returns the outcome of each in the order of items. Compiled schemas are
+// shared by all items, so each schema is compiled at most once per batch. Items not
+// validated because ctx is done before a worker takes them fail with the context error.
+func (v *schemaValidator) ValidateBatch(ctx context.Context, items []definition.SchemaBatchItem) []error {
+	errs := make([]error, len(items))
+	validated := runParallel(ctx, len(items), v.config.ValidateConcurrency, func() func(int) {
+		return func(i int) {
+			errs[i] = v.Validate(ctx, items[i].URL, items[i].Payload)
+		}
+	})
+	for i := validated; i < len(items); i++ {
+		errs[i] = ctx.Err()
+	}
+	return errs
+}
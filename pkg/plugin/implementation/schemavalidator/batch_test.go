@@ -0,0 +1,104 @@
+package schemavalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+// batchItem returns an item for endpoint<n> whose payload carries field<n> when valid.
+func batchItem(t *testing.T, n int, valid bool) definition.SchemaBatchItem {
+	t.Helper()
+	u, err := url.Parse(fmt.Sprintf("http://example.com/endpoint%d", n))
+	if err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	field := "other"
+	if valid {
+		field = fmt.Sprintf("field%d", n)
+	}
+	payload := fmt.Sprintf(`{"context": {"domain": "example", "version": "1.0"}, "%s": true}`, field)
+	return definition.SchemaBatchItem{URL: u, Payload: []byte(payload)}
+}
+
+func TestValidator_ValidateBatch(t *testing.T) {
+	for _, concurrency := range []int{0, 1, 4, 64} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			v, _, err := New(context.Background(), &Config{SchemaDir: writeSchemas(t, 5), ValidateConcurrency: concurrency})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var items []definition.SchemaBatchItem
+			var wantValid []bool
+			for i := 0; i < 40; i++ {
+				valid := i%3 != 0
+				items = append(items, batchItem(t, i%5, valid))
+				wantValid = append(wantValid, valid)
+			}
+			malformed := batchItem(t, 0, true)
+			malformed.Payload = []byte(`{not json`)
+			items = append(items, malformed, batchItem(t, 9, true))
+			wantValid = append(wantValid, false, false)
+
+			errs := v.ValidateBatch(context.Background(), items)
+
+			if len(errs) != len(items) {
+				t.Fatalf("Expected %d results, got %d", len(items), len(errs))
+			}
+			for i, err := range errs {
+				if wantValid[i] && err != nil {
+					t.Errorf("Item %d: unexpected error: %v", i, err)
+				}
+				if !wantValid[i] && err == nil {
+					t.Errorf("Item %d: expected an error", i)
+				}
+			}
+			if !errors.Is(errs[len(errs)-1], definition.ErrSchemaNotFound) {
+				t.Errorf("Expected the item without a schema to fail with ErrSchemaNotFound, got %v", errs[len(errs)-1])
+			}
+			if len(v.schemaCache) != 5 {
+				t.Errorf("Expected 5 compiled schemas shared by the batch, got %d", len(v.schemaCache))
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateBatchEmpty(t *testing.T) {
+	v, _, err := New(context.Background(), &Config{SchemaDir: writeSchemas(t, 1)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if errs := v.ValidateBatch(context.Background(), nil); len(errs) != 0 {
+		t.Errorf("Expected no results, got %v", errs)
+	}
+}
+
+func TestValidator_ValidateBatchCanceled(t *testing.T) {
+	v, _, err := New(context.Background(), &Config{SchemaDir: writeSchemas(t, 1), ValidateConcurrency: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := v.ValidateBatch(ctx, []definition.SchemaBatchItem{batchItem(t, 0, true), batchItem(t, 0, true)})
+
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Item %d: expected context.Canceled, got %v", i, err)
+		}
+	}
+}
+
+func TestValidatorNew_NegativeValidateConcurrency(t *testing.T) {
+	_, _, err := New(context.Background(), &Config{SchemaDir: writeSchemas(t, 1), ValidateConcurrency: -1})
+	if err == nil {
+		t.Fatal("Expected an error for a negative validateConcurrency")
+	}
+}
@@ -3,6 +3,10 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
@@ -19,16 +23,74 @@ func (vp schemaValidatorProvider) New(ctx context.Context, config map[string]str
 		return nil, nil, errors.New("context cannot be nil")
 	}
 
-	// Extract schemaDir from the config map
-	schemaDir, ok := config["schemaDir"]
-	if !ok || schemaDir == "" {
-		return nil, nil, errors.New("config must contain 'schemaDir'")
+	// Extract schemaDir, or openAPISpec in its place, from the config map
+	schemaDir, openAPISpec := config["schemaDir"], config["openAPISpec"]
+	if schemaDir == "" && openAPISpec == "" {
+		return nil, nil, errors.New("config must contain 'schemaDir' or 'openAPISpec'")
+	}
+
+	tenantDirs, err := parseTenantDirs(config["tenantDirs"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := &schemavalidator.Config{
+		SchemaDir:   schemaDir,
+		TenantField: config["tenantField"],
+		TenantDirs:  tenantDirs,
+		Composition: schemavalidator.Composition(config["composition"]),
+		OpenAPISpec: openAPISpec,
+	}
+	if ttlStr := config["resultCacheTTL"]; ttlStr != "" {
+		if cfg.ResultCacheTTL, err = time.ParseDuration(ttlStr); err != nil {
+			return nil, nil, fmt.Errorf("invalid resultCacheTTL value '%s': %w", ttlStr, err)
+		}
+	}
+	if suggestionsStr := config["suggestions"]; suggestionsStr != "" {
+		if cfg.Suggestions, err = strconv.ParseBool(suggestionsStr); err != nil {
+			return nil, nil, fmt.Errorf("invalid suggestions value '%s': %w", suggestionsStr, err)
+		}
+	}
+	if eagerStr := config["eagerCompile"]; eagerStr != "" {
+		if cfg.EagerCompile, err = strconv.ParseBool(eagerStr); err != nil {
+			return nil, nil, fmt.Errorf("invalid eagerCompile value '%s': %w", eagerStr, err)
+		}
+	}
+	if concurrencyStr := config["compileConcurrency"]; concurrencyStr != "" {
+		if cfg.CompileConcurrency, err = strconv.Atoi(concurrencyStr); err != nil {
+			return nil, nil, fmt.Errorf("invalid compileConcurrency value '%s': %w", concurrencyStr, err)
+		}
+	}
+	if concurrencyStr := config["validateConcurrency"]; concurrencyStr != "" {
+		if cfg.ValidateConcurrency, err = strconv.Atoi(concurrencyStr); err != nil {
+			return nil, nil, fmt.Errorf("invalid validateConcurrency value '%s': %w", concurrencyStr, err)
+		}
+	}
+	if sizeStr := config["resultCacheSize"]; sizeStr != "" {
+		if cfg.ResultCacheSize, err = strconv.Atoi(sizeStr); err != nil {
+			return nil, nil, fmt.Errorf("invalid resultCacheSize value '%s': %w", sizeStr, err)
+		}
 	}
 
 	// Create a new schemaValidator instance with the provided configuration
-	return schemavalidator.New(ctx, &schemavalidator.Config{
-		SchemaDir: schemaDir,
-	})
+	return schemavalidator.New(ctx, cfg)
+}
+
+// parseTenantDirs parses a comma-separated list of tenant=dir pairs.
+func parseTenantDirs(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	dirs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		tenant, dir, ok := strings.Cut(pair, "=")
+		tenant, dir = strings.TrimSpace(tenant), strings.TrimSpace(dir)
+		if !ok || tenant == "" || dir == "" {
+			return nil, fmt.Errorf("invalid tenantDirs entry %q, expected tenant=dir", pair)
+		}
+		dirs[tenant] = dir
+	}
+	return dirs, nil
 }
 
 // Provider is the exported symbol that the plugin manager will look for.
@@ -52,6 +52,11 @@ func setupTestSchema(t *testing.T) string {
 func TestValidatorProviderSuccess(t *testing.T) {
 	schemaDir := setupTestSchema(t)
 	defer os.RemoveAll(schemaDir)
+	openAPISpec := filepath.Join(t.TempDir(), "openapi.yaml")
+	spec := "openapi: 3.1.0\npaths:\n  /search:\n    post:\n      requestBody:\n        content:\n          application/json:\n            schema:\n              type: object\n"
+	if err := os.WriteFile(openAPISpec, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write OpenAPI spec: %v", err)
+	}
 
 	// Define test cases.
 	tests := []struct {
@@ -66,6 +71,30 @@ func TestValidatorProviderSuccess(t *testing.T) {
 			config:        map[string]string{"schemaDir": schemaDir},
 			expectedError: "",
 		},
+		{
+			name:          "With result cache",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "resultCacheTTL": "5s", "resultCacheSize": "100"},
+			expectedError: "",
+		},
+		{
+			name:          "With suggestions",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "suggestions": "true"},
+			expectedError: "",
+		},
+		{
+			name:          "With eager compilation",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "eagerCompile": "true", "compileConcurrency": "4"},
+			expectedError: "",
+		},
+		{
+			name:          "With OpenAPI spec",
+			ctx:           context.Background(),
+			config:        map[string]string{"openAPISpec": openAPISpec},
+			expectedError: "",
+		},
 	}
 
 	// Test using table-driven tests
@@ -104,7 +133,7 @@ func TestValidatorProviderFailure(t *testing.T) {
 			name:          "Config is empty",
 			ctx:           context.Background(),
 			config:        map[string]string{},
-			expectedError: "config must contain 'schemaDir'",
+			expectedError: "config must contain 'schemaDir' or 'openAPISpec'",
 		},
 		{
 			name:          "schemaDir is empty",
@@ -118,6 +147,54 @@ func TestValidatorProviderFailure(t *testing.T) {
 			config:        map[string]string{"schemaDir": "/invalid/dir"},
 			expectedError: "failed to initialise schemaValidator: schema directory does not exist: /invalid/dir",
 		},
+		{
+			name:          "Invalid tenantDirs",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "tenantField": "network_id", "tenantDirs": "ondc"},
+			expectedError: `invalid tenantDirs entry "ondc"`,
+		},
+		{
+			name:          "tenantDirs without tenantField",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "tenantDirs": "ondc=" + schemaDir},
+			expectedError: "tenantField is required",
+		},
+		{
+			name:          "Invalid resultCacheTTL",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "resultCacheTTL": "5"},
+			expectedError: "invalid resultCacheTTL value '5'",
+		},
+		{
+			name:          "Invalid resultCacheSize",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "resultCacheTTL": "5s", "resultCacheSize": "many"},
+			expectedError: "invalid resultCacheSize value 'many'",
+		},
+		{
+			name:          "Invalid suggestions",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "suggestions": "maybe"},
+			expectedError: "invalid suggestions value 'maybe'",
+		},
+		{
+			name:          "Invalid eagerCompile",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "eagerCompile": "maybe"},
+			expectedError: "invalid eagerCompile value 'maybe'",
+		},
+		{
+			name:          "Invalid compileConcurrency",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "compileConcurrency": "many"},
+			expectedError: "invalid compileConcurrency value 'many'",
+		},
+		{
+			name:          "Unknown composition",
+			ctx:           context.Background(),
+			config:        map[string]string{"schemaDir": schemaDir, "composition": "someOf"},
+			expectedError: `unknown composition "someOf"`,
+		},
 		{
 			name:          "Nil context",
 			ctx:           nil, // Nil context
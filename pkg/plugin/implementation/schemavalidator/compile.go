@@ -0,0 +1,114 @@
+package schemavalidator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// WarmUp compiles the schemas of the given schema keys, such as ondc_trv10_v2.0.0_search,
+// or OpenAPI operations, such as "POST /search", so the first requests validated against
+// them do not pay the compile cost. The target "*" compiles every indexed schema. Keys
+// are looked up in SchemaDir, every tenant directory and OpenAPISpec. The errors of all unknown keys and schemas that do not compile are returned
+// together.
+func (v *schemaValidator) WarmUp(ctx context.Context, targets []string) error {
+	var errs []error
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(files []string) {
+		for _, f := range files {
+			if !seen[f] {
+				seen[f] = true
+				paths = append(paths, f)
+			}
+		}
+	}
+	for _, target := range targets {
+		if target == "*" {
+			add(v.allSchemaFiles())
+			continue
+		}
+		found := false
+		for _, files := range v.schemaFileSets() {
+			if candidates, ok := files[target]; ok {
+				add(candidates)
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%w: %s", errSchemaKeyNotFound, target))
+		}
+	}
+	return errors.Join(append(errs, v.compileSchemas(ctx, paths))...)
+}
+
+// schemaFileSets returns the indexed schema files of SchemaDir, of OpenAPISpec and of
+// each tenant.
+func (v *schemaValidator) schemaFileSets() []map[string][]string {
+	sets := []map[string][]string{v.schemaFiles, v.operations}
+	for _, files := range v.tenantFiles {
+		sets = append(sets, files)
+	}
+	return sets
+}
+
+// allSchemaFiles returns the paths of every indexed schema file, sorted.
+func (v *schemaValidator) allSchemaFiles() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, files := range v.schemaFileSets() {
+		for _, candidates := range files {
+			for _, f := range candidates {
+				if !seen[f] {
+					seen[f] = true
+					paths = append(paths, f)
+				}
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// compileSchemas compiles the schema files at paths that are not compiled yet into the
+// cache, using up to CompileConcurrency workers. A jsonschema.Compiler is not safe for
+// concurrent use, so each worker compiles with its own. The errors of all files that do
+// not compile are returned together, in the order of paths, followed by the context
+// error if ctx is done before every file is handed to a worker.
+func (v *schemaValidator) compileSchemas(ctx context.Context, paths []string) error {
+	errs := make([]error, len(paths))
+	compiled := runParallel(ctx, len(paths), v.config.CompileConcurrency, func() func(int) {
+		compiler := newCompiler()
+		return func(i int) {
+			errs[i] = v.compileInto(compiler, paths[i])
+		}
+	})
+	if compiled < len(paths) {
+		errs = append(errs, ctx.Err())
+	}
+	return errors.Join(errs...)
+}
+
+// compileInto compiles the schema file at schemaPath with compiler and caches it, unless
+// it is cached already.
+func (v *schemaValidator) compileInto(compiler *jsonschema.Compiler, schemaPath string) error {
+	v.cacheMu.RLock()
+	_, ok := v.schemaCache[schemaPath]
+	v.cacheMu.RUnlock()
+	if ok {
+		return nil
+	}
+	schema, err := compiler.Compile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to compile JSON schema from file %s: %w", schemaPath, err)
+	}
+	v.cacheMu.Lock()
+	if _, ok := v.schemaCache[schemaPath]; !ok {
+		v.schemaCache[schemaPath] = schema
+	}
+	v.cacheMu.Unlock()
+	return nil
+}
@@ -0,0 +1,122 @@
+package schemavalidator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSchemas writes n endpoint schemas, endpoint0.json to endpoint<n-1>.json, under
+// example/v1.0 of a temporary directory. The schemas numbered in invalid are not JSON.
+func writeSchemas(t *testing.T, n int, invalid ...int) string {
+	t.Helper()
+	dir := t.TempDir()
+	versionDir := filepath.Join(dir, "example", "v1.0")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatalf("Failed to create schema directory structure: %v", err)
+	}
+	broken := make(map[int]bool)
+	for _, i := range invalid {
+		broken[i] = true
+	}
+	for i := 0; i < n; i++ {
+		content := fmt.Sprintf(`{"type": "object", "required": ["field%d"]}`, i)
+		if broken[i] {
+			content = `{invalid json}`
+		}
+		if err := os.WriteFile(filepath.Join(versionDir, fmt.Sprintf("endpoint%d.json", i)), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write schema file: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestValidatorNew_EagerCompile(t *testing.T) {
+	for _, concurrency := range []int{0, 1, 4, 64} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			dir := writeSchemas(t, 20)
+
+			v, _, err := New(context.Background(), &Config{SchemaDir: dir, EagerCompile: true, CompileConcurrency: concurrency})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(v.schemaCache) != 20 {
+				t.Fatalf("Expected 20 compiled schemas, got %d", len(v.schemaCache))
+			}
+			for _, path := range v.allSchemaFiles() {
+				if v.schemaCache[path] == nil {
+					t.Errorf("Schema %s was not compiled", path)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorNew_EagerCompileErrors(t *testing.T) {
+	dir := writeSchemas(t, 20, 3, 11, 17)
+
+	_, _, err := New(context.Background(), &Config{SchemaDir: dir, EagerCompile: true, CompileConcurrency: 4})
+	if err == nil {
+		t.Fatal("Expected compile errors, got nil")
+	}
+	for _, name := range []string{"endpoint3.json", "endpoint11.json", "endpoint17.json"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected error to report %s, got: %v", name, err)
+		}
+	}
+	if got := strings.Count(err.Error(), "failed to compile JSON schema"); got != 3 {
+		t.Errorf("Expected 3 compile errors, got %d: %v", got, err)
+	}
+}
+
+func TestValidatorNew_NegativeCompileConcurrency(t *testing.T) {
+	_, _, err := New(context.Background(), &Config{SchemaDir: writeSchemas(t, 1), CompileConcurrency: -1})
+	if err == nil || !strings.Contains(err.Error(), "compileConcurrency -1 is negative") {
+		t.Fatalf("Expected negative concurrency error, got: %v", err)
+	}
+}
+
+func TestValidator_WarmUp(t *testing.T) {
+	dir := writeSchemas(t, 10, 7)
+	v, _, err := New(context.Background(), &Config{SchemaDir: dir, CompileConcurrency: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := v.WarmUp(context.Background(), []string{"example_v1.0_endpoint1", "example_v1.0_endpoint2"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(v.schemaCache) != 2 {
+		t.Fatalf("Expected 2 compiled schemas, got %d", len(v.schemaCache))
+	}
+
+	err = v.WarmUp(context.Background(), []string{"*", "example_v1.0_missing"})
+	if err == nil {
+		t.Fatal("Expected warm-up errors, got nil")
+	}
+	for _, want := range []string{"endpoint7.json", "schema key not found: example_v1.0_missing"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %v", want, err)
+		}
+	}
+	if len(v.schemaCache) != 9 {
+		t.Errorf("Expected the 9 valid schemas to be compiled, got %d", len(v.schemaCache))
+	}
+}
+
+func TestValidator_WarmUpCanceled(t *testing.T) {
+	v, _, err := New(context.Background(), &Config{SchemaDir: writeSchemas(t, 5), CompileConcurrency: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := v.WarmUp(ctx, []string{"*"}); err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("Expected context error, got: %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package schemavalidator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/log"
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods are the operations of an OpenAPI path item, by their key in the document.
+var openAPIMethods = map[string]string{
+	"get":     http.MethodGet,
+	"put":     http.MethodPut,
+	"post":    http.MethodPost,
+	"delete":  http.MethodDelete,
+	"options": http.MethodOptions,
+	"head":    http.MethodHead,
+	"patch":   http.MethodPatch,
+	"trace":   http.MethodTrace,
+}
+
+// specLoader loads schema resources from files, decoding .yaml and .yml files as YAML
+// and all others as JSON, so that OpenAPI documents and the files they reference may
+// be written in either.
+type specLoader struct{}
+
+// Load loads the file at the file URL u.
+func (specLoader) Load(u string) (any, error) {
+	filePath, err := jsonschema.FileLoader{}.ToFile(u)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		var doc any
+		if err := yaml.NewDecoder(f).Decode(&doc); err != nil {
+			return nil, err
+		}
+		return jsonValue(doc), nil
+	default:
+		return jsonschema.UnmarshalJSON(f)
+	}
+}
+
+// newCompiler returns a schema compiler that loads files with specLoader.
+func newCompiler() *jsonschema.Compiler {
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{"file": specLoader{}})
+	return c
+}
+
+// jsonValue converts a decoded YAML value to the values decoded from JSON, turning maps
+// with non-string keys, such as response codes, into maps keyed by strings.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = jsonValue(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = jsonValue(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = jsonValue(val)
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// operationKey returns the key under which the operation of method on the OpenAPI path
+// template is indexed, e.g. "POST /search".
+func operationKey(method, template string) string {
+	return method + " " + template
+}
+
+// indexOpenAPISpec records the request body schema of each operation of the OpenAPI
+// document at specPath in v.operations, keyed by operationKey, as a location within
+// the document that compiles to the schema.
+func (v *schemaValidator) indexOpenAPISpec(specPath string) error {
+	absPath, err := filepath.Abs(specPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve OpenAPI spec path: %v", err)
+	}
+	doc, err := specLoader{}.Load("file://" + filepath.ToSlash(absPath))
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI spec %s: %v", specPath, err)
+	}
+	root, _ := doc.(map[string]any)
+	paths, _ := root["paths"].(map[string]any)
+	if len(paths) == 0 {
+		return fmt.Errorf("OpenAPI spec %s defines no paths", specPath)
+	}
+
+	v.operations = make(map[string][]string)
+	for template, item := range paths {
+		ops, _ := item.(map[string]any)
+		for key, method := range openAPIMethods {
+			op, ok := ops[key].(map[string]any)
+			if !ok || op["requestBody"] == nil {
+				continue
+			}
+			pointer := []string{"paths", template, key, "requestBody"}
+			body, pointer, err := resolveLocalRef(root, op["requestBody"], pointer)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", method, template, err)
+			}
+			mediaType, ok := jsonMediaType(body)
+			if !ok {
+				continue
+			}
+			pointer = append(pointer, "content", mediaType, "schema")
+			v.operations[operationKey(method, template)] = []string{absPath + "#" + jsonPointer(pointer)}
+		}
+	}
+	if len(v.operations) == 0 {
+		return fmt.Errorf("OpenAPI spec %s defines no operations with a JSON request body", specPath)
+	}
+	v.templates = make([]string, 0, len(paths))
+	for template := range paths {
+		v.templates = append(v.templates, template)
+	}
+	// Prefer the most specific templates: longer ones first, then literal segments
+	// before parameters.
+	sort.Slice(v.templates, func(i, j int) bool {
+		si, sj := strings.Count(v.templates[i], "/"), strings.Count(v.templates[j], "/")
+		if si != sj {
+			return si > sj
+		}
+		return strings.Count(v.templates[i], "{") < strings.Count(v.templates[j], "{")
+	})
+	return nil
+}
+
+// resolveLocalRef returns value, or the object its $ref points to within root, along
+// with the pointer segments of the returned object. Only references within the
+// document are supported.
+func resolveLocalRef(root map[string]any, value any, pointer []string) (map[string]any, []string, error) {
+	obj, _ := value.(map[string]any)
+	ref, ok := obj["$ref"].(string)
+	if !ok {
+		return obj, pointer, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, nil, fmt.Errorf("unsupported requestBody $ref %q: only references within the spec are supported", ref)
+	}
+	var target any = root
+	pointer = nil
+	for _, tok := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		m, _ := target.(map[string]any)
+		if target, ok = m[tok]; !ok {
+			return nil, nil, fmt.Errorf("requestBody $ref %q not found", ref)
+		}
+		pointer = append(pointer, tok)
+	}
+	obj, _ = target.(map[string]any)
+	return obj, pointer, nil
+}
+
+// jsonMediaType returns the media type of the JSON content of a request body:
+// application/json if present, or else the first JSON media type such as
+// application/ld+json.
+func jsonMediaType(body map[string]any) (string, bool) {
+	content, _ := body["content"].(map[string]any)
+	if _, ok := content["application/json"]; ok {
+		return "application/json", true
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		if strings.HasSuffix(t, "+json") {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return "", false
+	}
+	sort.Strings(types)
+	return types[0], true
+}
+
+// jsonPointer returns the URL fragment of the JSON pointer made of segments.
+func jsonPointer(segments []string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		s = strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+		b.WriteString("/" + url.PathEscape(s))
+	}
+	return b.String()
+}
+
+// matchTemplate reports whether the OpenAPI path template matches the end of
+// requestPath, so that /search matches requests to /bap/receiver/search. Template
+// parameters such as {id} match any single segment.
+func matchTemplate(template, requestPath string) bool {
+	tmpl := strings.Split(strings.Trim(template, "/"), "/")
+	req := strings.Split(strings.Trim(requestPath, "/"), "/")
+	if len(tmpl) > len(req) {
+		return false
+	}
+	req = req[len(req)-len(tmpl):]
+	for i, seg := range tmpl {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			continue
+		}
+		if seg != req[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateOperation validates data against the request body schema of the POST
+// operation of the OpenAPI path matching u, as Beckn APIs are all POST.
+func (v *schemaValidator) validateOperation(ctx context.Context, u *url.URL, data []byte) error {
+	var jsonData any
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to parse JSON data: %v", err))
+	}
+	for _, template := range v.templates {
+		if !matchTemplate(template, u.Path) {
+			continue
+		}
+		key := operationKey(http.MethodPost, template)
+		log.Debugf(ctx, "Validating request against OpenAPI operation %s", key)
+		candidates, err := v.getCompiledSchemas(v.operations, key)
+		if errors.Is(err, errSchemaKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return model.NewBadReqErr(err)
+		}
+		return v.validateCandidates(candidates, jsonData)
+	}
+	return model.NewBadReqErr(fmt.Errorf("%w for operation: %s %s", definition.ErrSchemaNotFound, http.MethodPost, u.Path))
+}
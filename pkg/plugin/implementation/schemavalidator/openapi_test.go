@@ -0,0 +1,241 @@
+package schemavalidator
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
+)
+
+const testOpenAPISpec = `openapi: 3.1.0
+info:
+  title: Test API
+  version: 1.0.0
+paths:
+  /search:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Search'
+      responses:
+        200:
+          description: ACK
+  /select:
+    post:
+      requestBody:
+        $ref: '#/components/requestBodies/Select'
+      responses:
+        200:
+          description: ACK
+  /orders/{id}:
+    post:
+      requestBody:
+        content:
+          application/ld+json:
+            schema:
+              type: object
+              required: [status]
+    get:
+      responses:
+        200:
+          description: Order
+components:
+  requestBodies:
+    Select:
+      content:
+        application/json:
+          schema:
+            type: object
+            required: [context, message]
+  schemas:
+    Context:
+      type: object
+      properties:
+        action:
+          enum: [search]
+      required: [action]
+    Search:
+      type: object
+      properties:
+        context:
+          $ref: '#/components/schemas/Context'
+      required: [context]
+`
+
+// writeOpenAPISpec writes spec to a file named name in a temporary directory.
+func writeOpenAPISpec(t *testing.T, name, spec string) string {
+	t.Helper()
+	specPath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(specPath, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write OpenAPI spec: %v", err)
+	}
+	return specPath
+}
+
+func TestValidator_Validate_OpenAPISpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		payload string
+		wantErr string
+	}{
+		{
+			name:    "valid search",
+			url:     "http://example.com/bap/receiver/search",
+			payload: `{"context": {"action": "search"}}`,
+		},
+		{
+			name:    "invalid search",
+			url:     "http://example.com/bap/receiver/search",
+			payload: `{"context": {"action": "select"}}`,
+			wantErr: "value must be 'search'",
+		},
+		{
+			name:    "missing required property",
+			url:     "http://example.com/search",
+			payload: `{}`,
+			wantErr: "missing property 'context'",
+		},
+		{
+			name:    "request body reference",
+			url:     "http://example.com/bpp/receiver/select",
+			payload: `{"context": {}}`,
+			wantErr: "missing property 'message'",
+		},
+		{
+			name:    "path parameter",
+			url:     "http://example.com/orders/o-1",
+			payload: `{"status": "PLACED"}`,
+		},
+		{
+			name:    "path parameter invalid",
+			url:     "http://example.com/orders/o-1",
+			payload: `{}`,
+			wantErr: "missing property 'status'",
+		},
+	}
+
+	v, _, err := New(context.Background(), &Config{OpenAPISpec: writeOpenAPISpec(t, "openapi.yaml", testOpenAPISpec)})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse(tt.url)
+			err := v.Validate(context.Background(), u, []byte(tt.payload))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var schemaErr *model.SchemaValidationErr
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("Expected SchemaValidationErr, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_OpenAPISpecUnknownOperation(t *testing.T) {
+	v, _, err := New(context.Background(), &Config{OpenAPISpec: writeOpenAPISpec(t, "openapi.yaml", testOpenAPISpec)})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	u, _ := url.Parse("http://example.com/bap/receiver/confirm")
+
+	err = v.Validate(context.Background(), u, []byte(`{}`))
+
+	if !errors.Is(err, definition.ErrSchemaNotFound) {
+		t.Fatalf("Expected ErrSchemaNotFound, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "for operation: POST /bap/receiver/confirm") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidator_OpenAPISpecJSONAndWarmUp(t *testing.T) {
+	spec := `{
+		"openapi": "3.1.0",
+		"paths": {
+			"/search": {"post": {"requestBody": {"content": {"application/json": {"schema": {"type": "object", "required": ["context"]}}}}}}
+		}
+	}`
+	v, _, err := New(context.Background(), &Config{OpenAPISpec: writeOpenAPISpec(t, "openapi.json", spec), EagerCompile: true})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	if len(v.schemaCache) != 1 {
+		t.Errorf("Expected the operation schema to be compiled eagerly, got %d schemas", len(v.schemaCache))
+	}
+	if err := v.WarmUp(context.Background(), []string{"POST /search"}); err != nil {
+		t.Errorf("Unexpected warm-up error: %v", err)
+	}
+	u, _ := url.Parse("http://example.com/search")
+	if err := v.Validate(context.Background(), u, []byte(`{"message": {}}`)); err == nil || !strings.Contains(err.Error(), "missing property 'context'") {
+		t.Errorf("Expected missing context error, got: %v", err)
+	}
+}
+
+func TestValidatorNew_OpenAPISpecFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(t *testing.T) *Config
+		wantErr string
+	}{
+		{
+			name: "combined with schemaDir",
+			config: func(t *testing.T) *Config {
+				return &Config{SchemaDir: t.TempDir(), OpenAPISpec: writeOpenAPISpec(t, "openapi.yaml", testOpenAPISpec)}
+			},
+			wantErr: "openAPISpec cannot be combined with schemaDir or tenantDirs",
+		},
+		{
+			name:    "missing file",
+			config:  func(t *testing.T) *Config { return &Config{OpenAPISpec: filepath.Join(t.TempDir(), "missing.yaml")} },
+			wantErr: "failed to load OpenAPI spec",
+		},
+		{
+			name: "no paths",
+			config: func(t *testing.T) *Config {
+				return &Config{OpenAPISpec: writeOpenAPISpec(t, "openapi.yaml", "openapi: 3.1.0\n")}
+			},
+			wantErr: "defines no paths",
+		},
+		{
+			name: "no request bodies",
+			config: func(t *testing.T) *Config {
+				return &Config{OpenAPISpec: writeOpenAPISpec(t, "openapi.yaml", "openapi: 3.1.0\npaths:\n  /status:\n    get: {}\n")}
+			},
+			wantErr: "defines no operations with a JSON request body",
+		},
+		{
+			name: "external request body reference",
+			config: func(t *testing.T) *Config {
+				spec := "openapi: 3.1.0\npaths:\n  /search:\n    post:\n      requestBody:\n        $ref: 'bodies.yaml#/Search'\n"
+				return &Config{OpenAPISpec: writeOpenAPISpec(t, "openapi.yaml", spec)}
+			},
+			wantErr: `unsupported requestBody $ref "bodies.yaml#/Search"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := New(context.Background(), tt.config(t))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package schemavalidator
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// runParallel calls a worker function for each index in [0, n) on up to workers
+// goroutines, or GOMAXPROCS of them when workers is not positive. newWorker is called once
+// per goroutine, so that each can hold state that is not safe for concurrent use. Indexes
+// stop being handed out once ctx is done; it returns how many were, which are all run
+// before it returns.
+func runParallel(ctx context.Context, n, workers int, newWorker func() func(i int)) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work := newWorker()
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+	next := 0
+feed:
+	for ; next < n; next++ {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return next
+}
@@ -0,0 +1,61 @@
+package schemavalidator
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunParallel(t *testing.T) {
+	var running, peak, workers atomic.Int32
+	var mu sync.Mutex
+	seen := map[int]int{}
+
+	n := runParallel(context.Background(), 20, 3, func() func(int) {
+		workers.Add(1)
+		return func(i int) {
+			now := running.Add(1)
+			for {
+				if p := peak.Load(); now <= p || peak.CompareAndSwap(p, now) {
+					break
+				}
+			}
+			mu.Lock()
+			seen[i]++
+			mu.Unlock()
+			running.Add(-1)
+		}
+	})
+
+	if n != 20 {
+		t.Fatalf("Expected 20 indexes run, got %d", n)
+	}
+	if len(seen) != 20 {
+		t.Fatalf("Expected every index to run once, got %v", seen)
+	}
+	for i, c := range seen {
+		if c != 1 {
+			t.Fatalf("Index %d ran %d times", i, c)
+		}
+	}
+	if w := workers.Load(); w != 3 {
+		t.Fatalf("Expected 3 workers, got %d", w)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Fatalf("Expected at most 3 concurrent calls, got %d", p)
+	}
+}
+
+func TestRunParallelCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n := runParallel(ctx, 5, 2, func() func(int) {
+		return func(i int) { t.Errorf("Index %d ran after cancellation", i) }
+	})
+
+	if n != 0 {
+		t.Fatalf("Expected no index handed out, got %d", n)
+	}
+}
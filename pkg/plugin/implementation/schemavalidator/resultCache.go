@@ -0,0 +1,100 @@
+package schemavalidator
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// defaultResultCacheSize is used when Config.ResultCacheSize is unset.
+const defaultResultCacheSize = 1000
+
+// resultCache holds the outcomes of recent validations, so that byte-identical payloads
+// sent again within the TTL, such as health pings and repeated polls, are not validated
+// again. The least recently used outcome is evicted once the cache is full.
+type resultCache struct {
+	ttl time.Duration
+	max int
+	now func() time.Time
+
+	mu sync.Mutex
+	// lru orders the entries from most to least recently used.
+	lru     *list.List
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+// resultEntry is the outcome of validating a payload.
+type resultEntry struct {
+	key       [sha256.Size]byte
+	err       error
+	expiresAt time.Time
+}
+
+// newResultCache creates a resultCache keeping outcomes for ttl, up to size entries. It
+// returns nil when ttl is not positive.
+func newResultCache(ttl time.Duration, size int) *resultCache {
+	if ttl <= 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = defaultResultCacheSize
+	}
+	return &resultCache{
+		ttl:     ttl,
+		max:     size,
+		now:     time.Now,
+		lru:     list.New(),
+		entries: make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// resultKey hashes the endpoint and body of a validation. The schema key is derived from
+// the endpoint and the body's context, so equal keys are validated against the same schema.
+func resultKey(endpoint string, body []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte(endpoint))
+	h.Write([]byte{0})
+	h.Write(body)
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
+
+// get returns the cached outcome of key, reporting false when there is none or it has expired.
+func (c *resultCache) get(key [sha256.Size]byte) (error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*resultEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.err, true
+}
+
+// put caches err as the outcome of key, evicting the least recently used outcome if the
+// cache is full.
+func (c *resultCache) put(key [sha256.Size]byte, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*resultEntry)
+		entry.err, entry.expiresAt = err, expiresAt
+		c.lru.MoveToFront(elem)
+		return
+	}
+	if c.lru.Len() >= c.max {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultEntry).key)
+	}
+	c.entries[key] = c.lru.PushFront(&resultEntry{key: key, err: err, expiresAt: expiresAt})
+}
@@ -11,9 +11,11 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/model"
+	"github.com/beckn-one/beckn-onix/pkg/plugin/definition"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -29,19 +31,81 @@ type payload struct {
 
 var errSchemaKeyNotFound = errors.New("schema key not found")
 
+// Composition decides how a payload is validated against the candidate schemas of its
+// schema key.
+type Composition string
+
+const (
+	// CompositionAnyOf accepts payloads matching at least one candidate.
+	CompositionAnyOf Composition = "anyOf"
+	// CompositionOneOf accepts payloads matching exactly one candidate.
+	CompositionOneOf Composition = "oneOf"
+	// CompositionAllOf accepts payloads matching every candidate.
+	CompositionAllOf Composition = "allOf"
+)
+
+// candidateSchema is a compiled schema registered for a schema key.
+type candidateSchema struct {
+	name   string
+	schema *jsonschema.Schema
+}
+
 // schemaValidator implements the Validator interface.
 type schemaValidator struct {
-	config      *Config
+	config *Config
+	// schemaCache holds compiled schemas by file path.
 	schemaCache map[string]*jsonschema.Schema
-	schemaFiles map[string]string
-	compiler    *jsonschema.Compiler
-	cacheMu     sync.RWMutex
-	compileMu   sync.Mutex
+	// schemaFiles maps schema keys to their candidate files under SchemaDir.
+	schemaFiles map[string][]string
+	// tenantFiles maps each tenant to the schema keys and files under its directory.
+	tenantFiles map[string]map[string][]string
+	// operations maps the operations of OpenAPISpec, such as "POST /search", to the
+	// location of their request body schema.
+	operations map[string][]string
+	// templates are the path templates of OpenAPISpec, most specific first.
+	templates []string
+	compiler  *jsonschema.Compiler
+	cacheMu   sync.RWMutex
+	compileMu sync.Mutex
+	// results holds recent validation outcomes; nil when result caching is off.
+	results *resultCache
 }
 
 // Config struct for SchemaValidator.
 type Config struct {
 	SchemaDir string
+	// TenantField is the context field, e.g. network_id or domain, whose value selects
+	// a schema directory from TenantDirs.
+	TenantField string
+	// TenantDirs maps tenants to their schema directories. A tenant matches a
+	// TenantField value that it equals or is a prefix of; the longest match wins.
+	// Requests matching no tenant are validated against SchemaDir.
+	TenantDirs map[string]string
+	// ResultCacheTTL is how long the outcome of validating a payload is reused for
+	// byte-identical payloads sent to the same endpoint. Zero disables result caching.
+	ResultCacheTTL time.Duration
+	// ResultCacheSize caps the number of cached outcomes. Defaults to 1000.
+	ResultCacheSize int
+	// Composition decides how payloads are validated when their schema key has several
+	// candidate schemas. Defaults to anyOf.
+	Composition Composition
+	// Suggestions augments enum, const and missing required property errors with a
+	// suggested correction derived from the schema.
+	Suggestions bool
+	// EagerCompile compiles every indexed schema when the validator is created instead
+	// of on first use, failing creation if any of them does not compile.
+	EagerCompile bool
+	// CompileConcurrency is the number of schemas compiled at once by eager compilation
+	// and WarmUp. Defaults to the number of CPUs.
+	CompileConcurrency int
+	// ValidateConcurrency is the number of payloads validated at once by ValidateBatch.
+	// Defaults to the number of CPUs.
+	ValidateConcurrency int
+	// OpenAPISpec is the path of an OpenAPI 3.1 document, in JSON or YAML, whose
+	// operations' request body schemas are validated against instead of the schemas in
+	// SchemaDir. Requests are matched to the POST operation of the path whose template
+	// matches the end of their URL path.
+	OpenAPISpec string
 }
 
 // New creates a new ValidatorProvider instance.
@@ -53,19 +117,51 @@ func New(ctx context.Context, config *Config) (*schemaValidator, func() error, e
 	v := &schemaValidator{
 		config:      config,
 		schemaCache: make(map[string]*jsonschema.Schema),
-		schemaFiles: make(map[string]string),
-		compiler:    jsonschema.NewCompiler(),
+		schemaFiles: make(map[string][]string),
+		compiler:    newCompiler(),
+		results:     newResultCache(config.ResultCacheTTL, config.ResultCacheSize),
 	}
 
 	// Call Initialise function to load schemas and get validators
 	if err := v.initialise(); err != nil {
 		return nil, nil, fmt.Errorf("failed to initialise schemaValidator: %v", err)
 	}
+	if config.EagerCompile {
+		if err := v.compileSchemas(ctx, v.allSchemaFiles()); err != nil {
+			return nil, nil, fmt.Errorf("failed to compile schemas: %w", err)
+		}
+	}
 	return v, nil, nil
 }
 
-// Validate validates the given data against the schema.
+// Validate validates the given data against the schema. With result caching on, the
+// outcome of a recent validation of the same data for the same endpoint is reused.
 func (v *schemaValidator) Validate(ctx context.Context, url *url.URL, data []byte) error {
+	if v.results == nil {
+		return v.validate(ctx, url, data)
+	}
+	endpoint := path.Base(url.String())
+	if v.operations != nil {
+		endpoint = url.Path
+	}
+	if pinned := pinnedVersion(ctx); pinned != "" {
+		endpoint += "@" + pinned
+	}
+	key := resultKey(endpoint, data)
+	if err, ok := v.results.get(key); ok {
+		log.Debugf(ctx, "Reusing cached schema validation outcome")
+		return err
+	}
+	err := v.validate(ctx, url, data)
+	v.results.put(key, err)
+	return err
+}
+
+// validate validates the given data against the schema of its domain, version and endpoint.
+func (v *schemaValidator) validate(ctx context.Context, url *url.URL, data []byte) error {
+	if v.operations != nil {
+		return v.validateOperation(ctx, url, data)
+	}
 	var payloadData payload
 	err := json.Unmarshal(data, &payloadData)
 	if err != nil {
@@ -87,6 +183,10 @@ func (v *schemaValidator) Validate(ctx context.Context, url *url.URL, data []byt
 	// Extract domain, version, and endpoint from the payload and uri.
 	cxtDomain := payloadData.Context.Domain
 	version := payloadData.Context.Version
+	if pinned := pinnedVersion(ctx); pinned != "" {
+		log.Debugf(ctx, "Using pinned schema version %s instead of %s", pinned, version)
+		version = pinned
+	}
 	version = fmt.Sprintf("v%s", version)
 
 	endpoint := path.Base(url.String())
@@ -94,65 +194,148 @@ func (v *schemaValidator) Validate(ctx context.Context, url *url.URL, data []byt
 	domain := strings.ToLower(cxtDomain)
 	domain = strings.ReplaceAll(domain, ":", "_")
 
+	var jsonData any
+	if err := json.Unmarshal(data, &jsonData); err != nil {
+		return model.NewBadReqErr(fmt.Errorf("failed to parse JSON data: %v", err))
+	}
+
 	// Construct the schema file name.
 	schemaFileName := fmt.Sprintf("%s_%s_%s", domain, version, endpoint)
-	schema, err := v.getCompiledSchema(schemaFileName)
+	candidates, err := v.getCompiledSchemas(v.filesFor(ctx, jsonData), schemaFileName)
 	if err != nil {
 		if errors.Is(err, errSchemaKeyNotFound) {
-			return model.NewBadReqErr(fmt.Errorf("schema not found for domain: %s", domain))
+			return model.NewBadReqErr(fmt.Errorf("%w for domain: %s", definition.ErrSchemaNotFound, domain))
 		}
 		return model.NewBadReqErr(err)
 	}
+	return v.validateCandidates(candidates, jsonData)
+}
 
-	var jsonData any
-	if err := json.Unmarshal(data, &jsonData); err != nil {
-		return model.NewBadReqErr(fmt.Errorf("failed to parse JSON data: %v", err))
-	}
-	err = schema.Validate(jsonData)
-	if err != nil {
+// pinnedVersion returns the schema version pinned for the request in ctx, without a
+// leading v, or "" when the version in the payload is used.
+func pinnedVersion(ctx context.Context) string {
+	version, _ := ctx.Value(model.ContextKeySchemaVersion).(string)
+	return strings.TrimPrefix(version, "v")
+}
+
+// validateCandidates validates jsonData against the candidate schemas of its schema key
+// as set by the configured composition. The errors of failed candidates are aggregated,
+// each prefixed with the candidate's name when there are several.
+func (v *schemaValidator) validateCandidates(candidates []candidateSchema, jsonData any) error {
+	var schemaErrors []model.Error
+	var matched []string
+	for _, c := range candidates {
+		err := c.schema.Validate(jsonData)
+		if err == nil {
+			if v.config.Composition == CompositionAnyOf || v.config.Composition == "" {
+				return nil
+			}
+			matched = append(matched, c.name)
+			continue
+		}
 		// Handle schema validation errors
-		if validationErr, ok := err.(*jsonschema.ValidationError); ok {
-			// Convert validation errors into an array of SchemaValError
-			var schemaErrors []model.Error
-			for _, cause := range validationErr.Causes {
-				// Extract the path and message from the validation error
-				path := strings.Join(cause.InstanceLocation, ".") // JSON path to the invalid field
-				message := cause.Error()                          // Validation error message
-
-				// Append the error to the schemaErrors array
-				schemaErrors = append(schemaErrors, model.Error{
-					Paths:   path,
-					Message: message,
-				})
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return fmt.Errorf("validation failed: %v", err)
+		}
+		// Convert validation errors into an array of SchemaValError
+		for _, cause := range validationErr.Causes {
+			// Extract the path and message from the validation error
+			path := strings.Join(cause.InstanceLocation, ".") // JSON path to the invalid field
+			message := cause.Error()                          // Validation error message
+			if len(candidates) > 1 {
+				message = fmt.Sprintf("%s: %s", c.name, message)
 			}
-			// Return the array of schema validation errors
-			return &model.SchemaValidationErr{Errors: schemaErrors}
+			schemaErr := model.Error{
+				Paths:   path,
+				Message: message,
+			}
+			if v.config.Suggestions {
+				schemaErr.Suggestion = suggest(cause, jsonData)
+			}
+			schemaErrors = append(schemaErrors, schemaErr)
 		}
-		return fmt.Errorf("validation failed: %v", err)
 	}
 
-	// Return nil if validation succeeds
-	return nil
+	switch v.config.Composition {
+	case CompositionAllOf:
+		if len(matched) == len(candidates) {
+			return nil
+		}
+	case CompositionOneOf:
+		if len(matched) == 1 {
+			return nil
+		}
+		if len(matched) > 1 {
+			return &model.SchemaValidationErr{Errors: []model.Error{{
+				Message: fmt.Sprintf("payload matches %d candidate schemas (%s), expected exactly one", len(matched), strings.Join(matched, ", ")),
+			}}}
+		}
+	}
+	// Return the array of schema validation errors
+	return &model.SchemaValidationErr{Errors: schemaErrors}
+}
+
+// filesFor returns the schema files of the tenant selected by the payload's TenantField,
+// or those under SchemaDir when no tenant matches.
+func (v *schemaValidator) filesFor(ctx context.Context, jsonData any) map[string][]string {
+	if v.config.TenantField == "" || len(v.tenantFiles) == 0 {
+		return v.schemaFiles
+	}
+	value := ""
+	if doc, ok := jsonData.(map[string]any); ok {
+		if cxt, ok := doc["context"].(map[string]any); ok {
+			value, _ = cxt[v.config.TenantField].(string)
+		}
+	}
+	tenant := ""
+	for t := range v.tenantFiles {
+		if strings.HasPrefix(value, t) && len(t) > len(tenant) {
+			tenant = t
+		}
+	}
+	if tenant == "" {
+		return v.schemaFiles
+	}
+	log.Debugf(ctx, "Using schemas of tenant %s for %s %q", tenant, v.config.TenantField, value)
+	return v.tenantFiles[tenant]
 }
 
-func (v *schemaValidator) getCompiledSchema(schemaKey string) (*jsonschema.Schema, error) {
+// getCompiledSchemas returns the compiled candidate schemas of schemaKey among files.
+func (v *schemaValidator) getCompiledSchemas(files map[string][]string, schemaKey string) ([]candidateSchema, error) {
+	schemaPaths, ok := files[schemaKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errSchemaKeyNotFound, schemaKey)
+	}
+	candidates := make([]candidateSchema, 0, len(schemaPaths))
+	for _, schemaPath := range schemaPaths {
+		schema, err := v.getCompiledSchema(schemaPath)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidateSchema{
+			name:   strings.TrimSuffix(filepath.Base(schemaPath), ".json"),
+			schema: schema,
+		})
+	}
+	return candidates, nil
+}
+
+// getCompiledSchema returns the compiled schema of the file schemaPath, compiling it on first use.
+func (v *schemaValidator) getCompiledSchema(schemaPath string) (*jsonschema.Schema, error) {
 	v.cacheMu.RLock()
-	if schema, ok := v.schemaCache[schemaKey]; ok {
+	if schema, ok := v.schemaCache[schemaPath]; ok {
 		v.cacheMu.RUnlock()
 		return schema, nil
 	}
-	schemaPath, ok := v.schemaFiles[schemaKey]
 	v.cacheMu.RUnlock()
-	if !ok {
-		return nil, fmt.Errorf("%w: %s", errSchemaKeyNotFound, schemaKey)
-	}
 
 	// Serialize first-time compiles to avoid concurrent compiler use and duplicate work.
 	v.compileMu.Lock()
 	defer v.compileMu.Unlock()
 
 	v.cacheMu.RLock()
-	if schema, ok := v.schemaCache[schemaKey]; ok {
+	if schema, ok := v.schemaCache[schemaPath]; ok {
 		v.cacheMu.RUnlock()
 		return schema, nil
 	}
@@ -164,15 +347,55 @@ func (v *schemaValidator) getCompiledSchema(schemaKey string) (*jsonschema.Schem
 	}
 
 	v.cacheMu.Lock()
-	v.schemaCache[schemaKey] = compiledSchema
+	v.schemaCache[schemaPath] = compiledSchema
 	v.cacheMu.Unlock()
 	return compiledSchema, nil
 }
 
 // Initialise initialises the validator provider by indexing all JSON schema files
-// from the specified directory for lazy compilation on first use.
+// from the schema directory and each tenant directory for lazy compilation on first use.
 func (v *schemaValidator) initialise() error {
-	schemaDir := v.config.SchemaDir
+	switch v.config.Composition {
+	case "", CompositionAnyOf, CompositionOneOf, CompositionAllOf:
+	default:
+		return fmt.Errorf("unknown composition %q, expected anyOf, oneOf or allOf", v.config.Composition)
+	}
+	if v.config.CompileConcurrency < 0 {
+		return fmt.Errorf("compileConcurrency %d is negative", v.config.CompileConcurrency)
+	}
+	if v.config.ValidateConcurrency < 0 {
+		return fmt.Errorf("validateConcurrency %d is negative", v.config.ValidateConcurrency)
+	}
+	if v.config.OpenAPISpec != "" {
+		if v.config.SchemaDir != "" || len(v.config.TenantDirs) > 0 {
+			return errors.New("openAPISpec cannot be combined with schemaDir or tenantDirs")
+		}
+		return v.indexOpenAPISpec(v.config.OpenAPISpec)
+	}
+	if err := indexSchemaDir(v.config.SchemaDir, v.schemaFiles); err != nil {
+		return err
+	}
+	if len(v.config.TenantDirs) > 0 && v.config.TenantField == "" {
+		return errors.New("tenantField is required when tenant schema directories are configured")
+	}
+	v.tenantFiles = make(map[string]map[string][]string, len(v.config.TenantDirs))
+	for tenant, dir := range v.config.TenantDirs {
+		if tenant == "" {
+			return fmt.Errorf("empty tenant for schema directory %s", dir)
+		}
+		files := make(map[string][]string)
+		if err := indexSchemaDir(dir, files); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		v.tenantFiles[tenant] = files
+	}
+	return nil
+}
+
+// indexSchemaDir records the JSON schema files under schemaDir in files, keyed by
+// domain, version and schema name. The files in a directory named after a schema, as in
+// domain/version/schema/candidate.json, are all candidates of that schema.
+func indexSchemaDir(schemaDir string, files map[string][]string) error {
 	// Check if the directory exists and is accessible.
 	info, err := os.Stat(schemaDir)
 	if err != nil {
@@ -228,7 +451,7 @@ func (v *schemaValidator) initialise() error {
 				// Construct a unique key combining domain, version, and schema name (e.g., ondc_trv10_v2.0.0_schema).
 				uniqueKey := fmt.Sprintf("%s_%s_%s", domain, version, schemaFileName)
 				// Store schema path for lazy compilation on first use.
-				v.schemaFiles[uniqueKey] = entryPath
+				files[uniqueKey] = append(files[uniqueKey], entryPath)
 			}
 		}
 		return nil
@@ -2,11 +2,16 @@ package schemavalidator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -263,7 +268,7 @@ func TestValidator_Initialise(t *testing.T) {
 			v := &schemaValidator{
 				config:      config,
 				schemaCache: make(map[string]*jsonschema.Schema),
-				schemaFiles: make(map[string]string),
+				schemaFiles: make(map[string][]string),
 				compiler:    jsonschema.NewCompiler(),
 			}
 
@@ -373,3 +378,316 @@ func TestValidatorNewFailure(t *testing.T) {
 		})
 	}
 }
+
+// writeTenantSchema writes an example/v1.0/endpoint.json schema under a new temporary
+// directory that requires the given message field.
+func writeTenantSchema(t *testing.T, requiredField string) string {
+	t.Helper()
+	dir := t.TempDir()
+	schemaFilePath := filepath.Join(dir, "example", "v1.0", "endpoint.json")
+	if err := os.MkdirAll(filepath.Dir(schemaFilePath), 0755); err != nil {
+		t.Fatalf("Failed to create schema directory structure: %v", err)
+	}
+	schemaContent := `{
+		"type": "object",
+		"properties": {
+			"message": {"type": "object", "required": ["` + requiredField + `"]}
+		},
+		"required": ["context", "message"]
+	}`
+	if err := os.WriteFile(schemaFilePath, []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+	return dir
+}
+
+func TestValidator_Validate_Tenants(t *testing.T) {
+	config := &Config{
+		SchemaDir:   writeTenantSchema(t, "common"),
+		TenantField: "network_id",
+		TenantDirs: map[string]string{
+			"ondc":      writeTenantSchema(t, "ondc"),
+			"ondc:prod": writeTenantSchema(t, "prod"),
+			"uei":       writeTenantSchema(t, "uei"),
+		},
+	}
+	v, _, err := New(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	payload := func(networkID, field string) string {
+		return `{"context": {"domain": "example", "version": "1.0", "network_id": "` + networkID +
+			`"}, "message": {"` + field + `": true}}`
+	}
+	tests := []struct {
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{name: "ondc tenant schema", payload: payload("ondc", "ondc")},
+		{name: "ondc tenant rejects uei payload", payload: payload("ondc", "uei"), wantErr: true},
+		{name: "uei tenant schema", payload: payload("uei", "uei")},
+		{name: "uei tenant rejects ondc payload", payload: payload("uei", "ondc"), wantErr: true},
+		{name: "longest prefix wins", payload: payload("ondc:prod:1", "prod")},
+		{name: "unmatched tenant falls back to schemaDir", payload: payload("other", "common")},
+		{name: "missing tenant field falls back to schemaDir", payload: `{"context": {"domain": "example", "version": "1.0"}, "message": {"common": true}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse("http://example.com/endpoint")
+			err := v.Validate(context.Background(), u, []byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatorNew_TenantFailure(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *Config
+		expectedError string
+	}{
+		{
+			name:          "Tenant dirs without tenant field",
+			config:        &Config{SchemaDir: writeTenantSchema(t, "common"), TenantDirs: map[string]string{"ondc": writeTenantSchema(t, "ondc")}},
+			expectedError: "tenantField is required",
+		},
+		{
+			name:          "Missing tenant directory",
+			config:        &Config{SchemaDir: writeTenantSchema(t, "common"), TenantField: "network_id", TenantDirs: map[string]string{"ondc": "/invalid/dir"}},
+			expectedError: "tenant ondc: schema directory does not exist: /invalid/dir",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := New(context.Background(), tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_ResultCache(t *testing.T) {
+	schemaDir := setupTestSchema(t)
+	defer os.RemoveAll(schemaDir)
+
+	v, _, err := New(context.Background(), &Config{SchemaDir: schemaDir, ResultCacheTTL: time.Minute, ResultCacheSize: 2})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	v.results.now = func() time.Time { return now }
+	u, _ := url.Parse("http://example.com/endpoint")
+	invalid := []byte(`{"context": {"domain": "example", "version": "1.0"}}`)
+
+	first := v.Validate(context.Background(), u, invalid)
+	if first == nil {
+		t.Fatal("expected a validation error")
+	}
+
+	// A hit returns the cached outcome without validating again.
+	if err := v.Validate(context.Background(), u, invalid); err != first {
+		t.Errorf("repeat validation returned %v, want the cached error %v", err, first)
+	}
+
+	// A changed body bypasses the cached outcome.
+	valid := []byte(`{"context": {"domain": "example", "version": "1.0", "action": "endpoint"}}`)
+	if err := v.Validate(context.Background(), u, valid); err != nil {
+		t.Errorf("changed body returned %v, want nil", err)
+	}
+	if len(v.results.entries) != 2 {
+		t.Errorf("cached outcomes = %d, want 2", len(v.results.entries))
+	}
+
+	// A miss after the TTL validates again.
+	now = now.Add(time.Minute)
+	if err := v.Validate(context.Background(), u, invalid); err == nil || err == first {
+		t.Errorf("validation after the TTL returned %v, want a new validation error", err)
+	}
+}
+
+func TestResultCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newResultCache(time.Minute, 2)
+	a, b, d := resultKey("search", []byte("a")), resultKey("search", []byte("b")), resultKey("search", []byte("d"))
+	errA := errors.New("a")
+
+	c.put(a, errA)
+	c.put(b, nil)
+	if _, ok := c.get(a); !ok {
+		t.Fatal("expected a hit for a")
+	}
+	c.put(d, nil)
+
+	if err, ok := c.get(a); !ok || err != errA {
+		t.Errorf("get(a) = %v, %v; want the recently used outcome kept", err, ok)
+	}
+	if _, ok := c.get(b); ok {
+		t.Error("expected the least recently used outcome to be evicted")
+	}
+	if _, ok := c.get(d); !ok {
+		t.Error("expected a hit for d")
+	}
+	if resultKey("search", []byte("a")) == resultKey("select", []byte("a")) {
+		t.Error("expected keys of different endpoints to differ")
+	}
+}
+
+func TestNewResultCache_Disabled(t *testing.T) {
+	if c := newResultCache(0, 10); c != nil {
+		t.Errorf("newResultCache(0) = %v, want nil", c)
+	}
+	if c := newResultCache(time.Second, 0); c == nil || c.max != defaultResultCacheSize {
+		t.Errorf("newResultCache() size = %v, want the default %d", c, defaultResultCacheSize)
+	}
+}
+
+// writeCandidateSchemas writes example/v1.0/endpoint/<name>.json schemas under a new
+// temporary directory, each requiring the message field of the same name.
+func writeCandidateSchemas(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	candidateDir := filepath.Join(dir, "example", "v1.0", "endpoint")
+	if err := os.MkdirAll(candidateDir, 0755); err != nil {
+		t.Fatalf("Failed to create schema directory structure: %v", err)
+	}
+	for _, name := range names {
+		schemaContent := `{
+			"type": "object",
+			"properties": {
+				"message": {"type": "object", "required": ["` + name + `"]}
+			},
+			"required": ["context", "message"]
+		}`
+		if err := os.WriteFile(filepath.Join(candidateDir, name+".json"), []byte(schemaContent), 0644); err != nil {
+			t.Fatalf("Failed to write schema file: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestValidator_Validate_Candidates(t *testing.T) {
+	payload := func(fields ...string) string {
+		message := make([]string, len(fields))
+		for i, f := range fields {
+			message[i] = `"` + f + `": true`
+		}
+		return `{"context": {"domain": "example", "version": "1.0"}, "message": {` + strings.Join(message, ", ") + `}}`
+	}
+	tests := []struct {
+		name        string
+		composition Composition
+		payload     string
+		wantErrs    []string
+	}{
+		{name: "anyOf matches one candidate", payload: payload("incremental")},
+		{name: "anyOf matches several candidates", payload: payload("catalog", "incremental")},
+		{
+			name:     "anyOf matches none",
+			payload:  payload("other"),
+			wantErrs: []string{"catalog: ", "incremental: "},
+		},
+		{name: "oneOf matches one candidate", composition: CompositionOneOf, payload: payload("catalog")},
+		{
+			name:        "oneOf matches several candidates",
+			composition: CompositionOneOf,
+			payload:     payload("catalog", "incremental"),
+			wantErrs:    []string{"payload matches 2 candidate schemas (catalog, incremental), expected exactly one"},
+		},
+		{name: "allOf matches every candidate", composition: CompositionAllOf, payload: payload("catalog", "incremental")},
+		{
+			name:        "allOf matches one candidate",
+			composition: CompositionAllOf,
+			payload:     payload("catalog"),
+			wantErrs:    []string{"incremental: "},
+		},
+	}
+
+	schemaDir := writeCandidateSchemas(t, "catalog", "incremental")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, _, err := New(context.Background(), &Config{SchemaDir: schemaDir, Composition: tt.composition})
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+			u, _ := url.Parse("http://example.com/endpoint")
+
+			err = v.Validate(context.Background(), u, []byte(tt.payload))
+
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			schemaErr, ok := err.(*model.SchemaValidationErr)
+			if !ok {
+				t.Fatalf("Validate() error = %v, want a SchemaValidationErr", err)
+			}
+			if len(schemaErr.Errors) != len(tt.wantErrs) {
+				t.Fatalf("Validate() errors = %+v, want %d", schemaErr.Errors, len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				if !strings.HasPrefix(schemaErr.Errors[i].Message, want) {
+					t.Errorf("error %d = %q, want prefix %q", i, schemaErr.Errors[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_SingleCandidateErrorsUnprefixed(t *testing.T) {
+	v, _, err := New(context.Background(), &Config{SchemaDir: writeCandidateSchemas(t, "catalog")})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	u, _ := url.Parse("http://example.com/endpoint")
+
+	err = v.Validate(context.Background(), u, []byte(`{"context": {"domain": "example", "version": "1.0"}, "message": {}}`))
+
+	schemaErr, ok := err.(*model.SchemaValidationErr)
+	if !ok || len(schemaErr.Errors) != 1 || strings.HasPrefix(schemaErr.Errors[0].Message, "catalog: ") {
+		t.Errorf("Validate() error = %v, want one unprefixed schema error", err)
+	}
+}
+
+func TestValidator_Validate_PinnedVersion(t *testing.T) {
+	schemaDir := t.TempDir()
+	for version, field := range map[string]string{"v1.0": "old_field", "v2.0": "new_field"} {
+		dir := filepath.Join(schemaDir, "example", version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create schema directory structure: %v", err)
+		}
+		schema := fmt.Sprintf(`{"type": "object", "required": ["%s"]}`, field)
+		if err := os.WriteFile(filepath.Join(dir, "endpoint.json"), []byte(schema), 0644); err != nil {
+			t.Fatalf("Failed to write schema file: %v", err)
+		}
+	}
+	u, _ := url.Parse("http://example.com/endpoint")
+	oldPayload := []byte(`{"context": {"domain": "example", "version": "1.0"}, "old_field": true}`)
+	pinned := context.WithValue(context.Background(), model.ContextKeySchemaVersion, "2.0")
+
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		t.Run(fmt.Sprintf("result cache %v", ttl), func(t *testing.T) {
+			v, _, err := New(context.Background(), &Config{SchemaDir: schemaDir, ResultCacheTTL: ttl})
+			if err != nil {
+				t.Fatalf("Failed to create validator: %v", err)
+			}
+
+			if err := v.Validate(context.Background(), u, oldPayload); err != nil {
+				t.Errorf("Expected the body version to be used by default, got: %v", err)
+			}
+			err = v.Validate(pinned, u, oldPayload)
+			if err == nil || !strings.Contains(err.Error(), "new_field") {
+				t.Errorf("Expected validation against the pinned version, got: %v", err)
+			}
+			newPayload := []byte(`{"context": {"domain": "example", "version": "1.0"}, "new_field": true}`)
+			if err := v.Validate(context.WithValue(context.Background(), model.ContextKeySchemaVersion, "v2.0"), u, newPayload); err != nil {
+				t.Errorf("Expected a payload valid for the pinned version to pass, got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package schemavalidator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+// maxSuggestionDistance is the largest edit distance at which a property name present
+// in the payload is taken for a misspelling of a missing required property.
+const maxSuggestionDistance = 2
+
+// suggest returns a hint at fixing the validation error cause of the payload jsonData,
+// derived from the schema keyword that failed. It returns "" for errors it has no hint for.
+func suggest(cause *jsonschema.ValidationError, jsonData any) string {
+	switch k := cause.ErrorKind.(type) {
+	case *kind.Enum:
+		return suggestEnum(k.Got, k.Want)
+	case *kind.Const:
+		return fmt.Sprintf("did you mean %s?", quoteValue(k.Want))
+	case *kind.Required:
+		return suggestRequired(k.Missing, valueAt(jsonData, cause.InstanceLocation))
+	}
+	return ""
+}
+
+// suggestEnum lists the values of an enum, the ones closest to the value got first.
+func suggestEnum(got any, want []any) string {
+	gotStr, _ := got.(string)
+	values := make([]any, len(want))
+	copy(values, want)
+	sort.SliceStable(values, func(i, j int) bool {
+		return valueDistance(gotStr, values[i]) < valueDistance(gotStr, values[j])
+	})
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quoteValue(v)
+	}
+	return fmt.Sprintf("did you mean one of [%s]?", strings.Join(quoted, ", "))
+}
+
+// suggestRequired names the missing required properties, pointing out the properties of
+// the object that look like misspellings of them.
+func suggestRequired(missing []string, object any) string {
+	props, _ := object.(map[string]any)
+	var hints []string
+	for _, name := range missing {
+		if typo := closestKey(name, props); typo != "" {
+			hints = append(hints, fmt.Sprintf("did you mean %q instead of %q?", name, typo))
+		} else {
+			hints = append(hints, fmt.Sprintf("add the required property %q", name))
+		}
+	}
+	return strings.Join(hints, "; ")
+}
+
+// closestKey returns the key of props closest to name within maxSuggestionDistance,
+// or "" if there is none.
+func closestKey(name string, props map[string]any) string {
+	best, bestDist := "", maxSuggestionDistance+1
+	for key := range props {
+		if key == name {
+			continue
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(key)); d < bestDist || (d == bestDist && key < best) {
+			best, bestDist = key, d
+		}
+	}
+	if bestDist > maxSuggestionDistance {
+		return ""
+	}
+	return best
+}
+
+// valueAt returns the value at location in the decoded JSON document doc.
+func valueAt(doc any, location []string) any {
+	for _, token := range location {
+		switch v := doc.(type) {
+		case map[string]any:
+			doc = v[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			doc = v[i]
+		default:
+			return nil
+		}
+	}
+	return doc
+}
+
+// valueDistance returns the edit distance between got and want when want is a string,
+// and a distance larger than any string's otherwise.
+func valueDistance(got string, want any) int {
+	s, ok := want.(string)
+	if !ok {
+		return math.MaxInt
+	}
+	return editDistance(strings.ToLower(got), strings.ToLower(s))
+}
+
+// quoteValue formats v as JSON.
+func quoteValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
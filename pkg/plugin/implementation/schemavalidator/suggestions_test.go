@@ -0,0 +1,114 @@
+package schemavalidator
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// writeSuggestionSchema writes a search schema with an enum, a const and required
+// properties, returning its schema directory.
+func writeSuggestionSchema(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "example", "v1.0", "search.json")
+	if err := os.MkdirAll(filepath.Dir(schemaPath), 0755); err != nil {
+		t.Fatalf("Failed to create schema directory: %v", err)
+	}
+	schemaContent := `{
+		"type": "object",
+		"properties": {
+			"context": {
+				"type": "object",
+				"properties": {
+					"domain": {"type": "string"},
+					"version": {"type": "string"},
+					"action": {"const": "search"},
+					"city": {"enum": ["std:080", "std:011", "std:022"]},
+					"bap_id": {"type": "string"}
+				},
+				"required": ["domain", "version", "action", "bap_id"]
+			}
+		},
+		"required": ["context"]
+	}`
+	if err := os.WriteFile(schemaPath, []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("Failed to write schema file: %v", err)
+	}
+	return dir
+}
+
+func TestValidator_Validate_Suggestions(t *testing.T) {
+	tests := []struct {
+		name           string
+		context        string
+		wantPath       string
+		wantSuggestion string
+	}{
+		{
+			name:           "enum mismatch",
+			context:        `"action": "search", "bap_id": "bap", "city": "std:01"`,
+			wantPath:       "context.city",
+			wantSuggestion: `did you mean one of ["std:011", "std:080", "std:022"]?`,
+		},
+		{
+			name:           "const mismatch",
+			context:        `"action": "serach", "bap_id": "bap"`,
+			wantPath:       "context.action",
+			wantSuggestion: `did you mean "search"?`,
+		},
+		{
+			name:           "missing required property",
+			context:        `"action": "search"`,
+			wantPath:       "context",
+			wantSuggestion: `add the required property "bap_id"`,
+		},
+		{
+			name:           "misspelt required property",
+			context:        `"action": "search", "bapid": "bap"`,
+			wantPath:       "context",
+			wantSuggestion: `did you mean "bap_id" instead of "bapid"?`,
+		},
+	}
+
+	v, _, err := New(context.Background(), &Config{SchemaDir: writeSuggestionSchema(t), Suggestions: true})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	u, _ := url.Parse("http://example.com/search")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := `{"context": {"domain": "example", "version": "1.0", ` + tt.context + `}}`
+
+			err := v.Validate(context.Background(), u, []byte(payload))
+
+			schemaErr, ok := err.(*model.SchemaValidationErr)
+			if !ok || len(schemaErr.Errors) != 1 {
+				t.Fatalf("Validate() error = %v, want one schema error", err)
+			}
+			got := schemaErr.Errors[0]
+			if got.Paths != tt.wantPath || got.Suggestion != tt.wantSuggestion {
+				t.Errorf("Validate() error = %+v, want path %q and suggestion %q", got, tt.wantPath, tt.wantSuggestion)
+			}
+		})
+	}
+}
+
+func TestValidator_Validate_SuggestionsDisabled(t *testing.T) {
+	v, _, err := New(context.Background(), &Config{SchemaDir: writeSuggestionSchema(t)})
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+	u, _ := url.Parse("http://example.com/search")
+
+	err = v.Validate(context.Background(), u, []byte(`{"context": {"domain": "example", "version": "1.0", "action": "search"}}`))
+
+	schemaErr, ok := err.(*model.SchemaValidationErr)
+	if !ok || len(schemaErr.Errors) != 1 || schemaErr.Errors[0].Suggestion != "" {
+		t.Errorf("Validate() error = %+v, want one schema error without a suggestion", err)
+	}
+}
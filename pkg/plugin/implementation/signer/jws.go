@@ -0,0 +1,36 @@
+package signer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// jwsAlgorithm is the JWS alg of Ed25519 signatures (RFC 8037).
+const jwsAlgorithm = "EdDSA"
+
+// jwsHeader is the protected header of the detached JWS signatures.
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// SignDetachedJWS generates a detached JWS (RFC 7515, appendix F) over the provided payload.
+func (s *Signer) SignDetachedJWS(ctx context.Context, payload []byte, privateKeyBase64, keyID string, createdAt, expiresAt int64) (string, error) {
+	header, err := json.Marshal(jwsHeader{Alg: jwsAlgorithm, Kid: keyID, Iat: createdAt, Exp: expiresAt})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWS header: %w", err)
+	}
+	protected := base64.RawURLEncoding.EncodeToString(header)
+	signingInput := protected + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := generateSignature([]byte(signingInput), privateKeyBase64)
+	if err != nil {
+		return "", err
+	}
+
+	return protected + ".." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
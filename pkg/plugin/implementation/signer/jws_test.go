@@ -0,0 +1,57 @@
+package signer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestSignDetachedJWS tests that SignDetachedJWS produces a detached JWS verifiable over the payload.
+func TestSignDetachedJWS(t *testing.T) {
+	privateKey, publicKey := generateTestKeys()
+	signer, _, _ := New(context.Background(), &Config{})
+	payload := []byte(`{"context":{"action":"search"}}`)
+
+	jws, err := signer.SignDetachedJWS(context.Background(), payload, privateKey, "bap.example.com|k1", 100, 400)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		t.Fatalf("expected a detached JWS, got %q", jws)
+	}
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		t.Fatalf("failed to decode header: %v", err)
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		t.Fatalf("failed to parse header: %v", err)
+	}
+	want := jwsHeader{Alg: "EdDSA", Kid: "bap.example.com|k1", Iat: 100, Exp: 400}
+	if header != want {
+		t.Errorf("header = %+v, want %+v", header, want)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("failed to decode signature: %v", err)
+	}
+	pub, _ := base64.StdEncoding.DecodeString(publicKey)
+	signingInput := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload)
+	if !ed25519.Verify(pub, []byte(signingInput), signature) {
+		t.Error("signature does not verify over the payload")
+	}
+}
+
+// TestSignDetachedJWSInvalidKey tests that SignDetachedJWS rejects an invalid private key.
+func TestSignDetachedJWSInvalidKey(t *testing.T) {
+	signer, _, _ := New(context.Background(), &Config{})
+	if _, err := signer.SignDetachedJWS(context.Background(), []byte("{}"), "invalid_key", "k1", 100, 400); err == nil {
+		t.Error("expected an error, got none")
+	}
+}
@@ -0,0 +1,84 @@
+package signvalidator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
+)
+
+// jwsAlgorithm is the JWS alg of Ed25519 signatures (RFC 8037).
+const jwsAlgorithm = "EdDSA"
+
+// jwsHeader holds the protected header parameters of a detached JWS that are checked.
+type jwsHeader struct {
+	Alg  string   `json:"alg"`
+	Iat  int64    `json:"iat"`
+	Exp  int64    `json:"exp"`
+	Crit []string `json:"crit"`
+}
+
+// ValidateDetachedJWS checks the detached JWS (RFC 7515, appendix F) for the given payload and public key.
+func (v *validator) ValidateDetachedJWS(ctx context.Context, payload []byte, jws string, publicKeyBase64 string) error {
+	protected, encodedPayload, signature, ok := splitJWS(jws)
+	if !ok {
+		return model.NewSignValidationErr(fmt.Errorf("malformed JWS"))
+	}
+	if encodedPayload != "" {
+		return model.NewSignValidationErr(fmt.Errorf("JWS payload is not detached"))
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return model.NewSignValidationErr(fmt.Errorf("error decoding JWS header: %w", err))
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return model.NewSignValidationErr(fmt.Errorf("error parsing JWS header: %w", err))
+	}
+	if header.Alg != jwsAlgorithm {
+		return model.NewSignValidationErr(fmt.Errorf("unsupported JWS algorithm %q", header.Alg))
+	}
+	if len(header.Crit) > 0 {
+		return model.NewSignValidationErr(fmt.Errorf("unsupported critical JWS header parameters %v", header.Crit))
+	}
+
+	currentTime := time.Now().Unix()
+	if header.Iat > currentTime || (header.Exp != 0 && currentTime > header.Exp) {
+		return model.NewSignValidationErr(fmt.Errorf("signature is expired or not yet valid"))
+	}
+
+	signatureBytes, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return model.NewSignValidationErr(fmt.Errorf("error decoding signature: %w", err))
+	}
+
+	decodedPublicKey, err := base64.StdEncoding.DecodeString(publicKeyBase64)
+	if err != nil {
+		return model.NewSignValidationErr(fmt.Errorf("error decoding public key: %w", err))
+	}
+	if len(decodedPublicKey) != ed25519.PublicKeySize {
+		return model.NewSignValidationErr(fmt.Errorf("invalid public key length"))
+	}
+
+	signingInput := protected + "." + base64.RawURLEncoding.EncodeToString(payload)
+	if !ed25519.Verify(ed25519.PublicKey(decodedPublicKey), []byte(signingInput), signatureBytes) {
+		return model.NewSignValidationErr(fmt.Errorf("signature verification failed"))
+	}
+
+	return nil
+}
+
+// splitJWS splits a compact JWS into its protected header, payload and signature parts.
+func splitJWS(jws string) (string, string, string, bool) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
@@ -0,0 +1,76 @@
+package signvalidator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signTestJWS creates a detached JWS over payload with the given protected header.
+func signTestJWS(privateKeyBase64 string, header string, payload []byte) string {
+	privateKeyBytes, _ := base64.StdEncoding.DecodeString(privateKeyBase64)
+	protected := base64.RawURLEncoding.EncodeToString([]byte(header))
+	signingInput := protected + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(ed25519.PrivateKey(privateKeyBytes), []byte(signingInput))
+	return protected + ".." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// testJWSHeader returns a protected header with the given algorithm and validity period.
+func testJWSHeader(alg string, createdAt, expiresAt int64) string {
+	return `{"alg":"` + alg + `","kid":"bap.example.com|k1","iat":` + strconv.FormatInt(createdAt, 10) +
+		`,"exp":` + strconv.FormatInt(expiresAt, 10) + `}`
+}
+
+// TestValidateDetachedJWSSuccess tests that a valid detached JWS is accepted.
+func TestValidateDetachedJWSSuccess(t *testing.T) {
+	privateKeyBase64, publicKeyBase64 := generateTestKeyPair()
+	payload := []byte(`{"context":{"action":"search"}}`)
+	now := time.Now().Unix()
+	jws := signTestJWS(privateKeyBase64, testJWSHeader("EdDSA", now, now+3600), payload)
+
+	verifier, _, _ := New(context.Background(), &Config{})
+	if err := verifier.ValidateDetachedJWS(context.Background(), payload, jws, publicKeyBase64); err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+}
+
+// TestValidateDetachedJWSFailure tests all invalid detached JWS cases.
+func TestValidateDetachedJWSFailure(t *testing.T) {
+	privateKeyBase64, publicKeyBase64 := generateTestKeyPair()
+	_, wrongPublicKeyBase64 := generateTestKeyPair()
+	payload := []byte(`{"context":{"action":"search"}}`)
+	now := time.Now().Unix()
+	valid := signTestJWS(privateKeyBase64, testJWSHeader("EdDSA", now, now+3600), payload)
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name    string
+		payload []byte
+		jws     string
+		pubKey  string
+	}{
+		{name: "Malformed JWS", payload: payload, jws: "not-a-jws", pubKey: publicKeyBase64},
+		{name: "Attached Payload", payload: payload, jws: parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2], pubKey: publicKeyBase64},
+		{name: "Invalid Header Encoding", payload: payload, jws: "!!.." + parts[2], pubKey: publicKeyBase64},
+		{name: "Unsupported Algorithm", payload: payload, jws: signTestJWS(privateKeyBase64, testJWSHeader("HS256", now, now+3600), payload), pubKey: publicKeyBase64},
+		{name: "Critical Header Parameters", payload: payload, jws: signTestJWS(privateKeyBase64, `{"alg":"EdDSA","crit":["b64"],"b64":false}`, payload), pubKey: publicKeyBase64},
+		{name: "Expired Signature", payload: payload, jws: signTestJWS(privateKeyBase64, testJWSHeader("EdDSA", now-7200, now-3600), payload), pubKey: publicKeyBase64},
+		{name: "Not Yet Valid Signature", payload: payload, jws: signTestJWS(privateKeyBase64, testJWSHeader("EdDSA", now+3600, now+7200), payload), pubKey: publicKeyBase64},
+		{name: "Tampered Payload", payload: []byte(`{"context":{"action":"confirm"}}`), jws: valid, pubKey: publicKeyBase64},
+		{name: "Invalid Public Key", payload: payload, jws: valid, pubKey: wrongPublicKeyBase64},
+		{name: "Invalid Base64 Signature", payload: payload, jws: parts[0] + "..!!", pubKey: publicKeyBase64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, _, _ := New(context.Background(), &Config{})
+			if err := verifier.ValidateDetachedJWS(context.Background(), tt.payload, tt.jws, tt.pubKey); err == nil {
+				t.Fatal("Expected an error but got none")
+			}
+		})
+	}
+}
@@ -2,9 +2,15 @@ package signvalidator
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"fmt"
+	"math/big"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -29,42 +35,130 @@ func New(ctx context.Context, config *Config) (*validator, func() error, error)
 	return v, nil, nil
 }
 
-// Verify checks the signature for the given payload and public key.
+// Signature algorithms supported by the validator, as named in the keyId of the
+// Authorization header.
+const (
+	algEd25519         = "ed25519"
+	algECDSAP256SHA256 = "ecdsa-p256-sha256"
+)
+
+// verifiers check a signature over a message with a public key, by algorithm.
+var verifiers = map[string]func(publicKey, message, signature []byte) error{
+	algEd25519:         verifyEd25519,
+	algECDSAP256SHA256: verifyECDSAP256SHA256,
+}
+
+// Algorithms lists the signature algorithms the validator supports.
+func (v *validator) Algorithms() []string {
+	return []string{algEd25519, algECDSAP256SHA256}
+}
+
+// Verify checks the ed25519 signature for the given payload and public key.
 func (v *validator) Validate(ctx context.Context, body []byte, header string, publicKeyBase64 string) error {
-	createdTimestamp, expiredTimestamp, signature, err := parseAuthHeader(header)
+	return v.ValidateWithAlgorithm(ctx, algEd25519, body, header, publicKeyBase64)
+}
+
+// ValidateWithAlgorithm checks the signature for the given payload and public key using
+// the named algorithm.
+func (v *validator) ValidateWithAlgorithm(ctx context.Context, algorithm string, body []byte, header string, publicKeyBase64 string) error {
+	verify, ok := verifiers[strings.ToLower(algorithm)]
+	if !ok {
+		return model.NewSignValidationErr(fmt.Errorf("unsupported signature algorithm %q", algorithm))
+	}
+
+	params, err := parseAuthHeader(header)
 	if err != nil {
 		return model.NewSignValidationErr(fmt.Errorf("error parsing header: %w", err))
 	}
 
-	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	signatureBytes, err := base64.StdEncoding.DecodeString(params.signature)
 	if err != nil {
 		return fmt.Errorf("error decoding signature: %w", err)
 	}
 
 	currentTime := time.Now().Unix()
-	if createdTimestamp > currentTime || currentTime > expiredTimestamp {
+	if params.created > currentTime || currentTime > params.expires {
 		return model.NewSignValidationErr(fmt.Errorf("signature is expired or not yet valid"))
 	}
 
-	createdTime := time.Unix(createdTimestamp, 0)
-	expiredTime := time.Unix(expiredTimestamp, 0)
-
-	signingString := hash(body, createdTime.Unix(), expiredTime.Unix())
+	signingString, err := buildSigningString(ctx, params, body)
+	if err != nil {
+		return model.NewSignValidationErr(err)
+	}
 
 	decodedPublicKey, err := base64.StdEncoding.DecodeString(publicKeyBase64)
 	if err != nil {
 		return model.NewSignValidationErr(fmt.Errorf("error decoding public key: %w", err))
 	}
 
-	if !ed25519.Verify(ed25519.PublicKey(decodedPublicKey), []byte(signingString), signatureBytes) {
-		return model.NewSignValidationErr(fmt.Errorf("signature verification failed"))
+	if err := verify(decodedPublicKey, []byte(signingString), signatureBytes); err != nil {
+		return model.NewSignValidationErr(err)
+	}
+
+	return nil
+}
+
+// verifyEd25519 checks an ed25519 signature. The public key is either the raw 32-byte
+// key or a DER-encoded PKIX key.
+func verifyEd25519(publicKey, message, signature []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		key, err := x509.ParsePKIXPublicKey(publicKey)
+		edKey, ok := key.(ed25519.PublicKey)
+		if err != nil || !ok {
+			return fmt.Errorf("public key is not an %s key", algEd25519)
+		}
+		publicKey = edKey
 	}
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), message, signature) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
 
+// verifyECDSAP256SHA256 checks an ECDSA signature over the SHA-256 digest of message,
+// encoded as the 64-byte concatenation of r and s (RFC 9421, section 3.3.4). The public
+// key is a DER-encoded PKIX key on the P-256 curve.
+func verifyECDSAP256SHA256(publicKey, message, signature []byte) error {
+	key, err := x509.ParsePKIXPublicKey(publicKey)
+	ecKey, ok := key.(*ecdsa.PublicKey)
+	if err != nil || !ok || ecKey.Curve != elliptic.P256() {
+		return fmt.Errorf("public key is not an %s key", algECDSAP256SHA256)
+	}
+	if len(signature) != 64 {
+		return fmt.Errorf("signature verification failed")
+	}
+	digest := sha256.Sum256(message)
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(ecKey, digest[:], r, s) {
+		return fmt.Errorf("signature verification failed")
+	}
 	return nil
 }
 
+// Names in the headers parameter of the Authorization header.
+const (
+	headerCreated       = "(created)"
+	headerExpires       = "(expires)"
+	headerDigest        = "digest"
+	headerRequestTarget = "(request-target)"
+)
+
+// defaultSignedHeaders are the headers a signature covers when its header has no headers
+// parameter.
+var defaultSignedHeaders = []string{headerCreated, headerExpires, headerDigest}
+
+// authParams are the signature values of an Authorization header.
+type authParams struct {
+	created   int64
+	expires   int64
+	signature string
+	// headers lists the headers the signature covers, in signing string order.
+	headers []string
+}
+
 // parseAuthHeader extracts signature values from the Authorization header.
-func parseAuthHeader(header string) (int64, int64, string, error) {
+func parseAuthHeader(header string) (*authParams, error) {
 	header = strings.TrimPrefix(header, "Signature ")
 
 	parts := strings.Split(header, ",")
@@ -82,29 +176,68 @@ func parseAuthHeader(header string) (int64, int64, string, error) {
 	createdTimestamp, err := strconv.ParseInt(signatureMap["created"], 10, 64)
 	if err != nil {
 		// TODO: Return appropriate error code when Error Code Handling Module is ready
-		return 0, 0, "", fmt.Errorf("invalid created timestamp: %w", err)
+		return nil, fmt.Errorf("invalid created timestamp: %w", err)
 	}
 
 	expiredTimestamp, err := strconv.ParseInt(signatureMap["expires"], 10, 64)
 	if err != nil {
-		return 0, 0, "", model.NewSignValidationErr(fmt.Errorf("invalid expires timestamp: %w", err))
+		return nil, model.NewSignValidationErr(fmt.Errorf("invalid expires timestamp: %w", err))
 	}
 
 	signature := signatureMap["signature"]
 	if signature == "" {
 		// TODO: Return appropriate error code when Error Code Handling Module is ready
-		return 0, 0, "", model.NewSignValidationErr(fmt.Errorf("signature missing in header"))
+		return nil, model.NewSignValidationErr(fmt.Errorf("signature missing in header"))
 	}
 
-	return createdTimestamp, expiredTimestamp, signature, nil
+	headers := defaultSignedHeaders
+	if v, ok := signatureMap["headers"]; ok {
+		headers = strings.Fields(strings.ToLower(v))
+	}
+
+	return &authParams{
+		created:   createdTimestamp,
+		expires:   expiredTimestamp,
+		signature: signature,
+		headers:   headers,
+	}, nil
 }
 
-// hash constructs a signing string for verification.
-func hash(payload []byte, createdTimestamp, expiredTimestamp int64) string {
+// buildSigningString constructs the signing string of the headers params covers, in
+// their order. The signature must cover the timestamps and the digest of body. A
+// (request-target) is taken from ctx, where the caller puts it under
+// model.ContextKeyRequestTarget.
+func buildSigningString(ctx context.Context, params *authParams, body []byte) (string, error) {
+	for _, required := range defaultSignedHeaders {
+		if !slices.Contains(params.headers, required) {
+			return "", fmt.Errorf("signature does not cover %s", required)
+		}
+	}
+	lines := make([]string, 0, len(params.headers))
+	for _, h := range params.headers {
+		switch h {
+		case headerCreated:
+			lines = append(lines, fmt.Sprintf("(created): %d", params.created))
+		case headerExpires:
+			lines = append(lines, fmt.Sprintf("(expires): %d", params.expires))
+		case headerDigest:
+			lines = append(lines, "digest: BLAKE-512="+digest(body))
+		case headerRequestTarget:
+			target, _ := ctx.Value(model.ContextKeyRequestTarget).(string)
+			if target == "" {
+				return "", fmt.Errorf("signature covers %s, but the request target is unknown", headerRequestTarget)
+			}
+			lines = append(lines, headerRequestTarget+": "+target)
+		default:
+			return "", fmt.Errorf("signed header %q is not supported", h)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// digest returns the base64-encoded BLAKE2b-512 digest of payload.
+func digest(payload []byte) string {
 	hasher, _ := blake2b.New512(nil)
 	hasher.Write(payload)
-	hashSum := hasher.Sum(nil)
-	digestB64 := base64.StdEncoding.EncodeToString(hashSum)
-
-	return fmt.Sprintf("(created): %d\n(expires): %d\ndigest: BLAKE-512=%s", createdTimestamp, expiredTimestamp, digestB64)
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil))
 }
@@ -2,11 +2,20 @@ package signvalidator
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
+	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/beckn-one/beckn-onix/pkg/model"
 )
 
 // generateTestKeyPair generates a new ED25519 key pair for testing.
@@ -15,6 +24,11 @@ func generateTestKeyPair() (string, string) {
 	return base64.StdEncoding.EncodeToString(privateKey), base64.StdEncoding.EncodeToString(publicKey)
 }
 
+// hash constructs the signing string of a signature covering the default headers.
+func hash(payload []byte, createdTimestamp, expiredTimestamp int64) string {
+	return fmt.Sprintf("(created): %d\n(expires): %d\ndigest: BLAKE-512=%s", createdTimestamp, expiredTimestamp, digest(payload))
+}
+
 // signTestData creates a valid signature for test cases.
 func signTestData(privateKeyBase64 string, body []byte, createdAt, expiresAt int64) string {
 	privateKeyBytes, _ := base64.StdEncoding.DecodeString(privateKeyBase64)
@@ -145,3 +159,148 @@ func TestVerifyFailure(t *testing.T) {
 		})
 	}
 }
+
+// signECDSATestData creates a valid ecdsa-p256-sha256 signature for test cases.
+func signECDSATestData(t *testing.T, privateKey *ecdsa.PrivateKey, body []byte, createdAt, expiresAt int64) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(hash(body, createdAt, expiresAt)))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+// TestValidateWithAlgorithm tests signature verification with the primitive of each algorithm.
+func TestValidateWithAlgorithm(t *testing.T) {
+	edPrivateKey, edPublicKey := generateTestKeyPair()
+	ecPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+	ecPublicKeyDER, err := x509.MarshalPKIXPublicKey(&ecPrivateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal ECDSA key: %v", err)
+	}
+	ecPublicKey := base64.StdEncoding.EncodeToString(ecPublicKeyDER)
+	edPublicKeyDER, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(mustDecode(t, edPublicKey)))
+	if err != nil {
+		t.Fatalf("Failed to marshal ED25519 key: %v", err)
+	}
+
+	body := []byte("Test Payload")
+	createdAt, expiresAt := time.Now().Unix(), time.Now().Unix()+3600
+	header := func(signature string) string {
+		return `Signature created="` + strconv.FormatInt(createdAt, 10) +
+			`", expires="` + strconv.FormatInt(expiresAt, 10) +
+			`", signature="` + signature + `"`
+	}
+	edSignature := signTestData(edPrivateKey, body, createdAt, expiresAt)
+	ecSignature := signECDSATestData(t, ecPrivateKey, body, createdAt, expiresAt)
+
+	tests := []struct {
+		name      string
+		algorithm string
+		signature string
+		pubKey    string
+		wantErr   string
+	}{
+		{name: "ed25519", algorithm: "ed25519", signature: edSignature, pubKey: edPublicKey},
+		{name: "ed25519 with PKIX key", algorithm: "ED25519", signature: edSignature, pubKey: base64.StdEncoding.EncodeToString(edPublicKeyDER)},
+		{name: "ed25519 with ECDSA key", algorithm: "ed25519", signature: edSignature, pubKey: ecPublicKey, wantErr: "public key is not an ed25519 key"},
+		{name: "ecdsa-p256-sha256", algorithm: "ecdsa-p256-sha256", signature: ecSignature, pubKey: ecPublicKey},
+		{name: "ecdsa-p256-sha256 with ED25519 key", algorithm: "ecdsa-p256-sha256", signature: ecSignature, pubKey: edPublicKey, wantErr: "public key is not an ecdsa-p256-sha256 key"},
+		{name: "ecdsa-p256-sha256 with ed25519 signature", algorithm: "ecdsa-p256-sha256", signature: edSignature, pubKey: ecPublicKey, wantErr: "signature verification failed"},
+		{name: "ed25519 with ecdsa signature", algorithm: "ed25519", signature: ecSignature, pubKey: edPublicKey, wantErr: "signature verification failed"},
+		{name: "unsupported algorithm", algorithm: "rsa-pss-sha512", signature: edSignature, pubKey: edPublicKey, wantErr: `unsupported signature algorithm "rsa-pss-sha512"`},
+	}
+
+	verifier, _, _ := New(context.Background(), &Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifier.ValidateWithAlgorithm(context.Background(), tt.algorithm, body, header(tt.signature), tt.pubKey)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, but got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidate_RequestTarget(t *testing.T) {
+	privateKeyBase64, publicKeyBase64 := generateTestKeyPair()
+	privateKey := ed25519.PrivateKey(mustDecode(t, privateKeyBase64))
+	body := []byte(`{"context":{"action":"search"}}`)
+	createdAt := time.Now().Unix()
+	expiresAt := createdAt + 3600
+	signingString := func(target string) string {
+		lines := strings.Split(hash(body, createdAt, expiresAt), "\n")
+		return strings.Join([]string{lines[0], lines[1], "(request-target): " + target, lines[2]}, "\n")
+	}
+	sign := func(s string) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(s)))
+	}
+	header := func(headers, signature string) string {
+		h := `Signature created="` + strconv.FormatInt(createdAt, 10) + `", expires="` + strconv.FormatInt(expiresAt, 10) + `"`
+		if headers != "" {
+			h += `, headers="` + headers + `"`
+		}
+		return h + `, signature="` + signature + `"`
+	}
+	const withTarget = "(created) (expires) (request-target) digest"
+
+	tests := []struct {
+		name    string
+		header  string
+		target  string
+		wantErr string
+	}{
+		{name: "request target included", header: header(withTarget, sign(signingString("post /bap/caller/search"))), target: "post /bap/caller/search"},
+		{name: "request target mismatch", header: header(withTarget, sign(signingString("post /bap/caller/confirm"))), target: "post /bap/caller/search", wantErr: "signature verification failed"},
+		{name: "request target unknown", header: header(withTarget, sign(signingString("post /bap/caller/search"))), wantErr: "request target is unknown"},
+		{name: "default digest only", header: header("", signTestData(privateKeyBase64, body, createdAt, expiresAt)), target: "post /bap/caller/search"},
+		{name: "digest only listed", header: header("(created) (expires) digest", signTestData(privateKeyBase64, body, createdAt, expiresAt))},
+		{name: "digest not covered", header: header("(created) (expires) (request-target)", sign("unused")), target: "post /bap/caller/search", wantErr: "signature does not cover digest"},
+		{name: "unsupported header", header: header("(created) (expires) host digest", sign("unused")), wantErr: `signed header "host" is not supported`},
+	}
+
+	verifier, _, _ := New(context.Background(), &Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.target != "" {
+				ctx = context.WithValue(ctx, model.ContextKeyRequestTarget, tt.target)
+			}
+
+			err := verifier.Validate(ctx, body, tt.header, publicKeyBase64)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, but got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("Failed to decode %q: %v", s, err)
+	}
+	return b
+}
@@ -3,6 +3,7 @@ package plugin
 import (
 	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -105,11 +106,14 @@ func loadPlugin(ctx context.Context, path, id string) (onixPlugin, time.Duration
 	return p, elapsed, nil
 }
 
+// ErrNotFound is wrapped in the error returned when no loaded plugin has the requested ID.
+var ErrNotFound = errors.New("not found")
+
 func provider[T any](plugins map[string]onixPlugin, id string) (T, error) {
 	var zero T
 	pgn, ok := plugins[id]
 	if !ok {
-		return zero, fmt.Errorf("plugin %s not found", id)
+		return zero, fmt.Errorf("plugin %s %w", id, ErrNotFound)
 	}
 	provider, err := pgn.Lookup("Provider")
 	if err != nil {
@@ -146,6 +150,26 @@ func (m *Manager) Publisher(ctx context.Context, cfg *Config) (definition.Publis
 	return p, nil
 }
 
+// Auditor returns an Auditor instance based on the provided configuration.
+func (m *Manager) Auditor(ctx context.Context, cfg *Config) (definition.Auditor, error) {
+	ap, err := provider[definition.AuditorProvider](m.plugins, cfg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load provider for %s: %w", cfg.ID, err)
+	}
+	a, closer, err := ap.New(ctx, cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	if closer != nil {
+		m.closers = append(m.closers, func() {
+			if err := closer(); err != nil {
+				log.Errorf(context.Background(), err, "Failed to close auditor")
+			}
+		})
+	}
+	return a, nil
+}
+
 // SchemaValidator returns a SchemaValidator instance based on the provided configuration.
 // It registers a cleanup function for resource management.
 func (m *Manager) SchemaValidator(ctx context.Context, cfg *Config) (definition.SchemaValidator, error) {
@@ -0,0 +1,134 @@
+package response
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Media types of the response formats.
+const (
+	mediaTypeJSON    = "application/json"
+	mediaTypeXML     = "application/xml"
+	mediaTypeTextXML = "text/xml"
+)
+
+// xmlRootElement is the root element of XML responses.
+const xmlRootElement = "response"
+
+// contentTypeKey is the context key holding the negotiated media type of responses.
+type contentTypeKey struct{}
+
+// WithAccept returns ctx carrying the response format negotiated from the Accept header
+// of a request. ACKs and NACKs sent for ctx are XML when the client prefers
+// application/xml or text/xml to JSON, and JSON otherwise.
+func WithAccept(ctx context.Context, accept string) context.Context {
+	return context.WithValue(ctx, contentTypeKey{}, negotiate(accept))
+}
+
+// contentType returns the media type of responses sent for ctx.
+func contentType(ctx context.Context) string {
+	if ct, ok := ctx.Value(contentTypeKey{}).(string); ok {
+		return ct
+	}
+	return mediaTypeJSON
+}
+
+// negotiate returns the media type of the response format the Accept header prefers.
+// JSON wins ties and is used when the header accepts neither format.
+func negotiate(accept string) string {
+	best, bestQ := mediaTypeJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		var ct string
+		switch mediaType {
+		case mediaTypeJSON, "application/*", "*/*":
+			ct = mediaTypeJSON
+		case mediaTypeXML, mediaTypeTextXML:
+			ct = mediaType
+		default:
+			continue
+		}
+		if q > bestQ || (q == bestQ && ct == mediaTypeJSON) {
+			best, bestQ = ct, q
+		}
+	}
+	return best
+}
+
+// marshalResponse encodes resp in the media type negotiated for ctx. XML responses carry
+// the same fields as their JSON form, with the object keys as element names and each
+// array item as an element named after the array.
+func marshalResponse(ctx context.Context, resp any) ([]byte, string, error) {
+	data, err := json.Marshal(resp)
+	ct := contentType(ctx)
+	if err != nil || ct == mediaTypeJSON {
+		return data, mediaTypeJSON, err
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, "", err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := encodeXML(enc, xmlRootElement, v); err != nil {
+		return nil, "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), ct, nil
+}
+
+// encodeXML writes v, a decoded JSON value, as the element name.
+func encodeXML(enc *xml.Encoder, name string, v any) error {
+	if items, ok := v.([]any); ok {
+		for _, item := range items {
+			if err := encodeXML(enc, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := encodeXML(enc, k, v[k]); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
@@ -1,12 +1,14 @@
 package response
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/beckn-one/beckn-onix/pkg/log"
 	"github.com/beckn-one/beckn-onix/pkg/model"
@@ -50,54 +52,114 @@ func parseJSONOrDefault(str string) interface{} {
 	return result
 }
 
+// StatusTokens are the ack.status values of ACK and NACK responses. Networks running a
+// fork of the Beckn protocol may use tokens other than ACK and NACK.
+type StatusTokens struct {
+	ACK  model.Status `yaml:"ack"`
+	NACK model.Status `yaml:"nack"`
+}
+
+// defaultStatusTokens are the status values of the Beckn protocol.
+var defaultStatusTokens = StatusTokens{ACK: model.StatusACK, NACK: model.StatusNACK}
+
+// statusTokens holds the status values in use.
+var statusTokens atomic.Pointer[StatusTokens]
+
+func init() {
+	statusTokens.Store(&defaultStatusTokens)
+}
+
+// SetStatusTokens sets the ack.status values sent in ACK and NACK responses. Empty
+// tokens keep the Beckn default.
+func SetStatusTokens(t StatusTokens) error {
+	if t.ACK == "" {
+		t.ACK = model.StatusACK
+	}
+	if t.NACK == "" {
+		t.NACK = model.StatusNACK
+	}
+	if t.ACK == t.NACK {
+		return fmt.Errorf("invalid status tokens: ACK and NACK are both %q", t.ACK)
+	}
+	statusTokens.Store(&t)
+	return nil
+}
+
+// AckStatus returns the ack.status value of ACK responses.
+func AckStatus() model.Status {
+	return statusTokens.Load().ACK
+}
+
+// NackStatus returns the ack.status value of NACK responses.
+func NackStatus() model.Status {
+	return statusTokens.Load().NACK
+}
+
+// ContainsNack reports whether body holds the NACK status token as a JSON string or as
+// the text of an XML element. It is a cheap check for responses that may be NACKs,
+// without parsing them.
+func ContainsNack(body []byte) bool {
+	nack := string(NackStatus())
+	return bytes.Contains(body, []byte(strconv.Quote(nack))) || bytes.Contains(body, []byte(">"+nack+"<"))
+}
 
-// SendAck sends an acknowledgment response (ACK) to the client.
-func SendAck(w http.ResponseWriter) {
-	log.Infof(context.Background(),"Sending Ack")
+// SendAck sends an acknowledgment response (ACK) to the client, in the format
+// negotiated for ctx.
+func SendAck(ctx context.Context, w http.ResponseWriter) {
+	log.Infof(ctx,"Sending Ack")
 	resp := &model.Response{
 		Message: model.Message{
 			Ack: model.Ack{
-				Status: model.StatusACK,
+				Status: AckStatus(),
 			},
 		},
 	}
 
-	data, _ := json.Marshal(resp) //should not fail here
+	data, ct, _ := marshalResponse(ctx, resp) //should not fail here
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", ct)
 	w.WriteHeader(http.StatusOK)
 	_, err := w.Write(data)
 	if err != nil {
 		http.Error(w, "failed to write response", http.StatusInternalServerError)
 		return
 	}
-	log.Infof(context.Background(),"Ack sent successfully")
+	log.Infof(ctx,"Ack sent successfully")
 }
 
-// nack sends a negative acknowledgment (NACK) response with an error message.
+// nack sends a negative acknowledgment (NACK) response with an error message, in the
+// format negotiated for ctx.
 func nack(ctx context.Context, w http.ResponseWriter, err *model.Error, status int) {
 	log.Infof(ctx,"Sending Nack: code %s, message %s", err.Code, err.Message)
 	resp := &model.Response{
 		Message: model.Message{
 			Ack: model.Ack{
-				Status: model.StatusNACK,
+				Status: NackStatus(),
 			},
 		},
 		Error: &model.Error{
 			Code:    err.Code,
+			Paths:   err.Paths,
 			Message: err.Message,
 		},
 	}
 	if(err.Context != nil){
 		resp.Context = err.Context
 	}
-	if(err.Code == "500" && resp.Message.Error.Message == "") {
-		resp.Message.Error.Message = "INTERNAL_SERVER_ERROR"
+	if(err.Code == "500" && resp.Error.Message == "") {
+		resp.Error.Message = "INTERNAL_SERVER_ERROR"
+	}
+	if genericErrors(ctx) {
+		resp.Error.Paths = ""
+		resp.Error.Message = genericErrorMessage
+		if msgID, ok := ctx.Value(model.ContextKeyMsgID).(string); ok && msgID != "" {
+			resp.Error.Message = fmt.Sprintf("%s, MessageID: %s", genericErrorMessage, msgID)
+		}
 	}
 
-	data, _ := json.Marshal(resp) //should not fail here
+	data, ct, _ := marshalResponse(ctx, resp) //should not fail here
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", ct)
 	w.WriteHeader(status)
 	_, er := w.Write(data)
 	if er != nil {
@@ -107,6 +169,29 @@ func nack(ctx context.Context, w http.ResponseWriter, err *model.Error, status i
 	}
 }
 
+// genericErrorMessage replaces the error message of NACKs sent without error detail.
+const genericErrorMessage = "Request could not be processed"
+
+// genericErrorsKey is the context key marking requests whose NACKs omit error detail.
+type genericErrorsKey struct{}
+
+// WithGenericErrors returns ctx marked so that NACKs sent for it carry only the error
+// code and a generic message, hiding validation detail from untrusted callers.
+func WithGenericErrors(ctx context.Context) context.Context {
+	return context.WithValue(ctx, genericErrorsKey{}, true)
+}
+
+// genericErrors reports whether NACKs sent for ctx omit error detail.
+func genericErrors(ctx context.Context) bool {
+	generic, _ := ctx.Value(genericErrorsKey{}).(bool)
+	return generic
+}
+
+// SendNackStatus sends a NACK response carrying err with the given HTTP status.
+func SendNackStatus(ctx context.Context, w http.ResponseWriter, err *model.Error, status int) {
+	nack(ctx, w, err, status)
+}
+
 // internalServerError generates an internal server error response.
 func internalServerError(ctx context.Context) *model.Error {
 	return &model.Error{
@@ -118,9 +203,12 @@ func internalServerError(ctx context.Context) *model.Error {
 // SendNack processes different types of errors and sends an appropriate NACK response.
 func SendNack(ctx context.Context, w http.ResponseWriter, err error) {
 	var schemaErr *model.SchemaValidationErr
+	var ondcErr *model.OndcValidationErr
 	var signErr *model.SignValidationErr
 	var badReqErr *model.BadReqErr
 	var notFoundErr *model.NotFoundErr
+	var tooManyErr *model.TooManyRequestsErr
+	var forbiddenErr *model.ForbiddenErr
 	var workbenchErr *model.WorkbenchErr
 
 	log.Errorf(ctx,err,"Responding Error")
@@ -129,17 +217,23 @@ func SendNack(ctx context.Context, w http.ResponseWriter, err error) {
 	case errors.As(err, &workbenchErr):
 		behavior := workbenchErr.Behavior
 		switch behavior {
-		case "NACK":
+		case model.WorkbenchBehaviorNACK:
 			nack(ctx, w, workbenchErr.BecknError(), 200)
 			return
-		case "HTTP":
+		case model.WorkbenchBehaviorHTTP:
 			code, _ := strconv.Atoi(workbenchErr.Err.Code)
 			nack(ctx, w, workbenchErr.BecknError(), code)
 			return
+		case model.WorkbenchBehaviorACK:
+			SendAck(ctx, w)
+			return
 		}
 	case errors.As(err, &schemaErr):
 		nack(ctx, w, schemaErr.BecknError(), 200)
 		return
+	case errors.As(err, &ondcErr):
+		nack(ctx, w, ondcErr.BecknError(), 200)
+		return
 	case errors.As(err, &signErr):
 		nack(ctx, w, signErr.BecknError(), http.StatusUnauthorized)
 		return
@@ -149,6 +243,12 @@ func SendNack(ctx context.Context, w http.ResponseWriter, err error) {
 	case errors.As(err, &notFoundErr):
 		nack(ctx, w, notFoundErr.BecknError(), http.StatusNotFound)
 		return
+	case errors.As(err, &tooManyErr):
+		nack(ctx, w, tooManyErr.BecknError(), http.StatusTooManyRequests)
+		return
+	case errors.As(err, &forbiddenErr):
+		nack(ctx, w, forbiddenErr.BecknError(), http.StatusForbidden)
+		return
 	default:
 		nack(ctx, w, internalServerError(ctx), http.StatusInternalServerError)
 		return
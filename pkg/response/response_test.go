@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -31,7 +33,7 @@ func TestSendAck(t *testing.T) {
 	}
 	rr := httptest.NewRecorder()
 
-	SendAck(rr)
+	SendAck(context.Background(), rr)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("wanted status code %d, got %d", http.StatusOK, rr.Code)
@@ -126,6 +128,42 @@ func TestSendNack(t *testing.T) {
 	}
 }
 
+func TestSendNackOndcValidationErr(t *testing.T) {
+	ctx := context.Background()
+	ondcErr := &model.OndcValidationErr{
+		Errors: []model.Error{
+			{Code: "30000", Paths: "message.order.items", Message: "items are required"},
+			{Code: "30000", Paths: "context.city", Message: "invalid city"},
+		},
+	}
+	rr := httptest.NewRecorder()
+
+	SendNack(ctx, rr, fmt.Errorf("ondc validation failed: %w", ondcErr))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("wanted status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp model.Response
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Message.Ack.Status != model.StatusNACK {
+		t.Errorf("ack status = %s, want %s", resp.Message.Ack.Status, model.StatusNACK)
+	}
+	if resp.Error == nil {
+		t.Fatal("expected error in response")
+	}
+	if resp.Error.Code != "30000" {
+		t.Errorf("error code = %s, want 30000", resp.Error.Code)
+	}
+	if resp.Error.Paths != "message.order.items;context.city" {
+		t.Errorf("error paths = %s, want message.order.items;context.city", resp.Error.Paths)
+	}
+	if resp.Error.Message != "items are required;\n invalid city" {
+		t.Errorf("error message = %q", resp.Error.Message)
+	}
+}
+
 func compareJSON(expected, actual map[string]interface{}) bool {
 	expectedBytes, _ := json.Marshal(expected)
 	actualBytes, _ := json.Marshal(actual)
@@ -134,7 +172,7 @@ func compareJSON(expected, actual map[string]interface{}) bool {
 
 func TestSendAck_WriteError(t *testing.T) {
 	w := &errorResponseWriter{}
-	SendAck(w)
+	SendAck(context.Background(), w)
 }
 
 // Mock struct to force JSON marshalling error
@@ -239,3 +277,226 @@ func TestNack_1(t *testing.T) {
 		})
 	}
 }
+
+func TestSendNackWithGenericErrors(t *testing.T) {
+	schemaErr := &model.SchemaValidationErr{Errors: []model.Error{
+		{Paths: "message.order.items", Message: "items are required"},
+	}}
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantPaths   string
+		wantMessage string
+	}{
+		{
+			name:        "detailed",
+			ctx:         context.Background(),
+			wantPaths:   "message.order.items",
+			wantMessage: "items are required",
+		},
+		{
+			name:        "generic",
+			ctx:         WithGenericErrors(context.WithValue(context.Background(), model.ContextKeyMsgID, "msg-1")),
+			wantMessage: "Request could not be processed, MessageID: msg-1",
+		},
+		{
+			name:        "generic without message ID",
+			ctx:         WithGenericErrors(context.Background()),
+			wantMessage: "Request could not be processed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			SendNack(tt.ctx, rr, schemaErr)
+
+			var resp model.Response
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Error == nil {
+				t.Fatal("expected error in response")
+			}
+			if resp.Error.Code != http.StatusText(http.StatusBadRequest) {
+				t.Errorf("error code = %s, want %s", resp.Error.Code, http.StatusText(http.StatusBadRequest))
+			}
+			if resp.Error.Paths != tt.wantPaths {
+				t.Errorf("error paths = %q, want %q", resp.Error.Paths, tt.wantPaths)
+			}
+			if resp.Error.Message != tt.wantMessage {
+				t.Errorf("error message = %q, want %q", resp.Error.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCustomStatusTokens(t *testing.T) {
+	if err := SetStatusTokens(StatusTokens{ACK: "OK", NACK: "REJECTED"}); err != nil {
+		t.Fatalf("SetStatusTokens() error = %v", err)
+	}
+	t.Cleanup(func() { _ = SetStatusTokens(StatusTokens{}) })
+
+	rr := httptest.NewRecorder()
+	SendAck(context.Background(), rr)
+	if want := `{"message":{"ack":{"status":"OK"}}}`; rr.Body.String() != want {
+		t.Errorf("ACK body = %s, want %s", rr.Body.String(), want)
+	}
+
+	rr = httptest.NewRecorder()
+	SendNack(context.Background(), rr, model.NewBadReqErr(errors.New("bad request")))
+	var resp model.Response
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Message.Ack.Status != "REJECTED" {
+		t.Errorf("NACK status = %q, want REJECTED", resp.Message.Ack.Status)
+	}
+	if !ContainsNack(rr.Body.Bytes()) {
+		t.Error("ContainsNack() = false for a NACK with the custom token")
+	}
+	if ContainsNack([]byte(`{"message":{"ack":{"status":"NACK"}}}`)) {
+		t.Error("ContainsNack() = true for the default token")
+	}
+}
+
+func TestSetStatusTokens(t *testing.T) {
+	t.Cleanup(func() { _ = SetStatusTokens(StatusTokens{}) })
+
+	if err := SetStatusTokens(StatusTokens{NACK: "FAIL"}); err != nil {
+		t.Fatalf("SetStatusTokens() error = %v", err)
+	}
+	if AckStatus() != model.StatusACK || NackStatus() != "FAIL" {
+		t.Errorf("tokens = %q/%q, want ACK/FAIL", AckStatus(), NackStatus())
+	}
+
+	if err := SetStatusTokens(StatusTokens{ACK: "FAIL", NACK: "FAIL"}); err == nil {
+		t.Error("SetStatusTokens() accepted identical ACK and NACK tokens")
+	}
+	if NackStatus() != "FAIL" {
+		t.Errorf("rejected tokens replaced the NACK token with %q", NackStatus())
+	}
+}
+
+func TestSendNackTooManyRequests(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SendNack(context.Background(), rr, model.NewTooManyRequestsErr(errors.New("rate exceeded")))
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusTooManyRequests)
+	}
+	var resp model.Response
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != http.StatusText(http.StatusTooManyRequests) {
+		t.Errorf("error = %+v, want code %q", resp.Error, http.StatusText(http.StatusTooManyRequests))
+	}
+}
+
+func TestSendNackNegotiatesFormat(t *testing.T) {
+	nackErr := &model.SchemaValidationErr{Errors: []model.Error{{Paths: "/message/order", Message: "order is required"}}}
+	tests := []struct {
+		name            string
+		accept          string
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "no Accept header",
+			wantContentType: "application/json",
+			wantBody:        `{"message":{"ack":{"status":"NACK"}},"error":{"code":"Bad Request","paths":"/message/order","message":"order is required"}}`,
+		},
+		{
+			name:            "JSON preferred",
+			accept:          "application/json, application/xml;q=0.5",
+			wantContentType: "application/json",
+			wantBody:        `{"message":{"ack":{"status":"NACK"}},"error":{"code":"Bad Request","paths":"/message/order","message":"order is required"}}`,
+		},
+		{
+			name:            "XML preferred",
+			accept:          "application/xml, application/json;q=0.9",
+			wantContentType: "application/xml",
+			wantBody:        xml.Header + `<response><error><code>Bad Request</code><message>order is required</message><paths>/message/order</paths></error><message><ack><status>NACK</status></ack></message></response>`,
+		},
+		{
+			name:            "text/xml",
+			accept:          "text/xml",
+			wantContentType: "text/xml",
+			wantBody:        xml.Header + `<response><error><code>Bad Request</code><message>order is required</message><paths>/message/order</paths></error><message><ack><status>NACK</status></ack></message></response>`,
+		},
+		{
+			name:            "any type",
+			accept:          "*/*",
+			wantContentType: "application/json",
+			wantBody:        `{"message":{"ack":{"status":"NACK"}},"error":{"code":"Bad Request","paths":"/message/order","message":"order is required"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithAccept(context.Background(), tt.accept)
+			rr := httptest.NewRecorder()
+
+			SendNack(ctx, rr, nackErr)
+
+			if rr.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", rr.Code, http.StatusOK)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantContentType)
+			}
+			if rr.Body.String() != tt.wantBody {
+				t.Errorf("body = %s, want %s", rr.Body.String(), tt.wantBody)
+			}
+			if !ContainsNack(rr.Body.Bytes()) {
+				t.Error("ContainsNack() = false for a NACK")
+			}
+		})
+	}
+}
+
+func TestSendAckXML(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SendAck(WithAccept(context.Background(), "application/xml"), rr)
+
+	want := xml.Header + `<response><message><ack><status>ACK</status></ack></message></response>`
+	if rr.Body.String() != want {
+		t.Errorf("body = %s, want %s", rr.Body.String(), want)
+	}
+	if ContainsNack(rr.Body.Bytes()) {
+		t.Error("ContainsNack() = true for an ACK")
+	}
+}
+
+func TestSendNackXMLContext(t *testing.T) {
+	nackErr := &model.Error{
+		Code:    "30000",
+		Message: "bad <item> & more",
+		Context: map[string]any{"action": "search", "ttl": 30, "tags": []string{"a", "b"}},
+	}
+	rr := httptest.NewRecorder()
+	SendNackStatus(WithAccept(context.Background(), "application/xml"), rr, nackErr, http.StatusBadRequest)
+
+	want := xml.Header + `<response><context><action>search</action><tags>a</tags><tags>b</tags><ttl>30</ttl></context>` +
+		`<error><code>30000</code><message>bad &lt;item&gt; &amp; more</message></error><message><ack><status>NACK</status></ack></message></response>`
+	if rr.Body.String() != want {
+		t.Errorf("body = %s, want %s", rr.Body.String(), want)
+	}
+}
+
+func TestSendNackForbidden(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SendNack(context.Background(), rr, model.NewForbiddenErr(errors.New("subscriber denied")))
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusForbidden)
+	}
+	var resp model.Response
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != http.StatusText(http.StatusForbidden) {
+		t.Errorf("error = %+v, want code %q", resp.Error, http.StatusText(http.StatusForbidden))
+	}
+}
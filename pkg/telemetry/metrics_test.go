@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 )
 
 func TestNewProviderAndMetrics(t *testing.T) {
@@ -26,3 +28,19 @@ func TestNewProviderAndMetrics(t *testing.T) {
 
 	require.NoError(t, provider.Shutdown(context.Background()))
 }
+
+func TestNewPrometheusMeterProvider(t *testing.T) {
+	mp, handler, err := NewPrometheusMeterProvider(resource.Empty())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	counter, err := mp.Meter("test").Int64Counter("onix_test_events_total")
+	require.NoError(t, err)
+	counter.Add(context.Background(), 3, metric.WithAttributes(AttrStatus.String("success")))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	require.Equal(t, 200, rec.Code)
+	require.Contains(t, rec.Body.String(), `onix_test_events_total{status="success"} 3`)
+}
@@ -46,6 +46,11 @@ var (
 	AttrRouteType     = attribute.Key("route_type")
 	AttrTargetType    = attribute.Key("target_type")
 	AttrSchemaVersion = attribute.Key("schema_version")
+	AttrReason        = attribute.Key("reason")
+	AttrOutcome       = attribute.Key("outcome")
+	AttrDomain        = attribute.Key("domain")
+	AttrRegistry      = attribute.Key("registry")
+	AttrSubscriberID  = attribute.Key("subscriber_id")
 )
 
 // GetMetrics lazily initializes instruments and returns a cached reference.
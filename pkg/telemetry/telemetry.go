@@ -2,9 +2,14 @@ package telemetry
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	clientprom "github.com/prometheus/client_golang/prometheus"
+	clientpromhttp "github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
 )
 
 // Provider holds references to telemetry components that need coordinated shutdown.
@@ -13,3 +18,24 @@ type Provider struct {
 	MetricsHandler http.Handler
 	Shutdown       func(context.Context) error
 }
+
+// NewPrometheusMeterProvider creates a meter provider backed by a Prometheus exporter on a
+// dedicated registry. The returned handler serves the collected metrics and is meant to be
+// mounted at /metrics for scraping.
+func NewPrometheusMeterProvider(res *resource.Resource) (*metric.MeterProvider, http.Handler, error) {
+	registry := clientprom.NewRegistry()
+	exporter, err := otelprom.New(
+		otelprom.WithRegisterer(registry),
+		otelprom.WithoutUnits(),
+		otelprom.WithoutScopeInfo(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+	}
+
+	meterProvider := metric.NewMeterProvider(
+		metric.WithReader(exporter),
+		metric.WithResource(res),
+	)
+	return meterProvider, clientpromhttp.HandlerFor(registry, clientpromhttp.HandlerOpts{}), nil
+}
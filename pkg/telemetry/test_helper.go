@@ -3,12 +3,8 @@ package telemetry
 import (
 	"context"
 
-	clientprom "github.com/prometheus/client_golang/prometheus"
-	clientpromhttp "github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
-	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
 
@@ -27,26 +23,16 @@ func NewTestProvider(ctx context.Context) (*Provider, error) {
 		return nil, err
 	}
 
-	registry := clientprom.NewRegistry()
-	exporter, err := otelprom.New(
-		otelprom.WithRegisterer(registry),
-		otelprom.WithoutUnits(),
-		otelprom.WithoutScopeInfo(),
-	)
+	meterProvider, metricsHandler, err := NewPrometheusMeterProvider(res)
 	if err != nil {
 		return nil, err
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(exporter),
-		metric.WithResource(res),
-	)
-
 	otel.SetMeterProvider(meterProvider)
 
 	return &Provider{
 		MeterProvider:  meterProvider,
-		MetricsHandler: clientpromhttp.HandlerFor(registry, clientpromhttp.HandlerOpts{}),
+		MetricsHandler: metricsHandler,
 		Shutdown: func(ctx context.Context) error {
 			return meterProvider.Shutdown(ctx)
 		},